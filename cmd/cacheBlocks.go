@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
+	"github.com/Layr-Labs/sidecar/pkg/fetcher"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const defaultCacheBlocksBatchSize = uint64(100)
+
+var cacheBlocksCmd = &cobra.Command{
+	Use:   "cache-blocks",
+	Short: "Pre-populate the block cache for a range of blocks",
+	Long: `Fetch a range of blocks and their receipts from the Ethereum node and store them in the block cache.
+
+Blocks that are already cached are skipped. Once a range is cached, the sidecar can re-index it without talking to the Ethereum node.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initCacheBlocksCmd(cmd)
+		cfg := config.NewConfig()
+		ctx := context.Background()
+
+		l, err := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		if cfg.BlockCacheConfig.Dir == "" {
+			return fmt.Errorf("--%s is required", config.BlockCacheDir)
+		}
+
+		client := ethereum.NewClient(ethereum.ConvertGlobalConfigToEthereumConfig(&cfg.EthereumRpcConfig), l)
+		fetchr := fetcher.NewFetcher(client, cfg, l)
+
+		startBlock := cfg.CacheBlocksConfig.StartBlock
+		endBlock := cfg.CacheBlocksConfig.EndBlock
+		if startBlock == 0 {
+			startBlock = cfg.GetGenesisBlockNumber()
+		}
+		if endBlock == 0 {
+			endBlock, err = client.GetLatestSafeBlock(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get latest safe block: %w", err)
+			}
+		}
+		if endBlock < startBlock {
+			return errors.New("end block must be greater than or equal to start block")
+		}
+
+		batchSize := cfg.IndexerConfig.BackfillBatchSize
+		if batchSize == 0 {
+			batchSize = defaultCacheBlocksBatchSize
+		}
+
+		l.Sugar().Infow("Caching blocks",
+			zap.Uint64("startBlock", startBlock),
+			zap.Uint64("endBlock", endBlock),
+			zap.String("dir", cfg.BlockCacheConfig.Dir),
+		)
+
+		for batchStart := startBlock; batchStart <= endBlock; batchStart += batchSize {
+			batchEnd := min(batchStart+batchSize-1, endBlock)
+
+			// Blocks are written to the cache by the fetcher as they are fetched
+			if _, err := fetchr.FetchBlocksWithRetries(ctx, batchStart, batchEnd); err != nil {
+				l.Sugar().Errorw("Failed to fetch blocks",
+					zap.Uint64("startBlock", batchStart),
+					zap.Uint64("endBlock", batchEnd),
+					zap.Error(err),
+				)
+				return err
+			}
+			l.Sugar().Infow("Cached blocks",
+				zap.Uint64("startBlock", batchStart),
+				zap.Uint64("endBlock", batchEnd),
+				zap.Uint64("blocksRemaining", endBlock-batchEnd),
+			)
+		}
+		return nil
+	},
+}
+
+func initCacheBlocksCmd(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err := viper.BindPFlag(config.KebabToSnakeCase(f.Name), f); err != nil {
+			fmt.Printf("Failed to bind flag '%s' - %+v\n", f.Name, err)
+		}
+		if err := viper.BindEnv(f.Name); err != nil {
+			fmt.Printf("Failed to bind env '%s' - %+v\n", f.Name, err)
+		}
+	})
+}
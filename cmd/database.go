@@ -117,9 +117,9 @@ var runDatabaseCmd = &cobra.Command{
 			l.Sugar().Fatalw("Failed to create rewards calculator", zap.Error(err))
 		}
 
-		rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, l)
+		rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, grm, l)
 
-		_ = pipeline.NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, l)
+		_ = pipeline.NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, grm, l)
 
 		l.Sugar().Infow("Done")
 	},
@@ -114,10 +114,10 @@ func main() {
 		l.Sugar().Fatalw("Failed to create rewards calculator", zap.Error(err))
 	}
 
-	rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, l)
+	rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, grm, l)
 
-	p := pipeline.NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, l)
-	rps := proofs.NewRewardsProofsStore(rc, l)
+	p := pipeline.NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, grm, l)
+	rps := proofs.NewRewardsProofsStore(rc, grm, l, cfg)
 	pds := protocolDataService.NewProtocolDataService(sm, grm, l, cfg)
 	rds := rewardsDataService.NewRewardsDataService(grm, l, cfg, rc)
 
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsExport"
+	"github.com/Layr-Labs/sidecar/pkg/service/rewardsDataService"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var exportRewardsCmd = &cobra.Command{
+	Use:   "export-rewards",
+	Short: "Export the rewards up to a snapshot to a CSV, JSON Lines or Parquet file",
+	Long: `Export the rewards of every earner up to and including a snapshot date.
+
+Datasets:
+  gold             the gold table, one row per earner, token and reward per snapshot
+  staker_operator  the per-operator and per-strategy breakdown of the gold table
+  claim_status     the cumulative earned, claimed and unclaimed amounts of every earner and token
+
+Rows are streamed from the database, so exports of any size can be written.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initExportRewardsCmd(cmd)
+		cfg := config.NewConfig()
+		exportCfg := cfg.ExportRewardsConfig
+
+		l, err := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		if exportCfg.SnapshotDate == "" {
+			return fmt.Errorf("--%s is required", config.ExportRewardsSnapshotDate)
+		}
+		dataset, err := rewardsExport.ParseDataset(exportCfg.Dataset)
+		if err != nil {
+			return err
+		}
+		format, err := rewardsExport.ParseFormat(exportCfg.Format)
+		if err != nil {
+			return err
+		}
+
+		pgConfig := postgres.PostgresConfigFromDbConfig(&cfg.DatabaseConfig)
+
+		pg, err := postgres.NewPostgres(pgConfig)
+		if err != nil {
+			l.Fatal("Failed to setup postgres connection", zap.Error(err))
+		}
+
+		grm, err := postgres.NewGormFromPostgresConnection(pg.Db)
+		if err != nil {
+			l.Fatal("Failed to create gorm instance", zap.Error(err))
+		}
+
+		rds := rewardsDataService.NewRewardsDataService(grm, l, cfg, nil)
+
+		var out io.Writer = os.Stdout
+		if exportCfg.Output != "" {
+			f, err := os.Create(exportCfg.Output)
+			if err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		count, err := rds.ExportRewards(context.Background(), exportCfg.SnapshotDate, dataset, format, exportCfg.Gzip, out)
+		if err != nil {
+			return fmt.Errorf("failed to export rewards: %w", err)
+		}
+		if exportCfg.Output != "" {
+			l.Sugar().Infow("Wrote rewards export",
+				zap.String("output", exportCfg.Output),
+				zap.Uint64("rows", count),
+			)
+		}
+		return nil
+	},
+}
+
+func initExportRewardsCmd(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err := viper.BindPFlag(config.KebabToSnakeCase(f.Name), f); err != nil {
+			fmt.Printf("Failed to bind flag '%s' - %+v\n", f.Name, err)
+		}
+		if err := viper.BindEnv(f.Name); err != nil {
+			fmt.Printf("Failed to bind env '%s' - %+v\n", f.Name, err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	sidecarClient "github.com/Layr-Labs/sidecar/pkg/clients/sidecar"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/postgres/migrations"
+	"github.com/Layr-Labs/sidecar/pkg/replay"
+	"github.com/Layr-Labs/sidecar/pkg/stateRootDivergence"
+	pgStorage "github.com/Layr-Labs/sidecar/pkg/storage/postgres"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var findStateRootDivergenceCmd = &cobra.Command{
+	Use:   "find-state-root-divergence",
+	Short: "Find the first block where the state root differs from a remote sidecar",
+	Long: `Compare the local state roots with those of a remote sidecar to find the first diverging block.
+
+The search is linear: every block in the range is compared, in order, using the remote sidecar's GetStateRoot RPC.
+State roots only cover the changes made in their own block, so a divergence does not necessarily persist into later
+blocks and the range can't be binary searched. To bound how long that takes, the range is compared --window-size
+blocks at a time with up to --concurrency blocks being compared at once, and the search stops after the first window
+that contains a divergence.
+
+Once the diverging block is found, its state is rebuilt locally from the stored transaction logs and the per-model
+roots and slots are compared with those served by the remote sidecar at --remote-http-url. If no HTTP url is given,
+the local per-model roots and slots are written instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initFindStateRootDivergenceCmd(cmd)
+		cfg := config.NewConfig()
+		ctx := context.Background()
+
+		l, err := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		divergenceCfg := cfg.StateRootDivergenceConfig
+		if divergenceCfg.RemoteUrl == "" {
+			return fmt.Errorf("--%s is required", config.StateRootDivergenceRemoteUrl)
+		}
+
+		pgConfig := postgres.PostgresConfigFromDbConfig(&cfg.DatabaseConfig)
+
+		pg, err := postgres.NewPostgres(pgConfig)
+		if err != nil {
+			l.Fatal("Failed to setup postgres connection", zap.Error(err))
+		}
+
+		grm, err := postgres.NewGormFromPostgresConnection(pg.Db)
+		if err != nil {
+			l.Fatal("Failed to create gorm instance", zap.Error(err))
+		}
+
+		migrator := migrations.NewMigrator(pg.Db, grm, l, cfg)
+		if err = migrator.MigrateAll(); err != nil {
+			l.Fatal("Failed to migrate", zap.Error(err))
+		}
+
+		sm := stateManager.NewEigenStateManager(l, grm)
+		if err := eigenState.LoadEigenStateModels(sm, grm, l, cfg); err != nil {
+			l.Sugar().Fatalw("Failed to load eigen state models", zap.Error(err))
+		}
+
+		rpcClient, err := sidecarClient.NewSidecarRpcClient(divergenceCfg.RemoteUrl, divergenceCfg.RemoteInsecure)
+		if err != nil {
+			return fmt.Errorf("failed to create remote sidecar client: %w", err)
+		}
+
+		startBlock := divergenceCfg.StartBlock
+		if startBlock == 0 {
+			startBlock = cfg.GetGenesisBlockNumber()
+		}
+		endBlock := divergenceCfg.EndBlock
+		if endBlock == 0 {
+			mds := pgStorage.NewPostgresBlockStore(grm, l, cfg)
+			latestBlock, err := mds.GetLatestBlock()
+			if err != nil {
+				return fmt.Errorf("failed to get latest indexed block: %w", err)
+			}
+			endBlock = latestBlock.Number
+		}
+
+		scanner := stateRootDivergence.NewDivergenceScanner(
+			stateRootDivergence.NewLocalStateRoots(sm),
+			stateRootDivergence.NewRemoteStateRoots(rpcClient),
+			divergenceCfg.WindowSize,
+			divergenceCfg.Concurrency,
+			l,
+		)
+
+		divergence, err := scanner.FindFirstDivergence(ctx, startBlock, endBlock)
+		if err != nil {
+			l.Sugar().Errorw("Failed to compare state roots", zap.Error(err))
+			return err
+		}
+		if divergence == nil {
+			l.Sugar().Infow("No diverging state roots found",
+				zap.Uint64("startBlock", startBlock),
+				zap.Uint64("endBlock", endBlock),
+			)
+			return nil
+		}
+		l.Sugar().Infow("Found diverging state root",
+			zap.Uint64("blockNumber", divergence.BlockNumber),
+			zap.String("localStateRoot", divergence.LocalStateRoot),
+			zap.String("remoteStateRoot", divergence.RemoteStateRoot),
+		)
+
+		local, err := replay.NewReplayer(sm, grm, l).RebuildBlockStateRoots(divergence.BlockNumber)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild local state roots for block %d: %w", divergence.BlockNumber, err)
+		}
+		if !strings.EqualFold(local.StateRoot, divergence.LocalStateRoot) {
+			l.Sugar().Warnw("Rebuilt state root does not match the stored local state root",
+				zap.Uint64("blockNumber", divergence.BlockNumber),
+				zap.String("storedStateRoot", divergence.LocalStateRoot),
+				zap.String("rebuiltStateRoot", local.StateRoot),
+			)
+		}
+
+		var remote *replay.BlockStateRoots
+		if divergenceCfg.RemoteHttpUrl != "" {
+			remote, err = stateRootDivergence.NewRemoteBlockStateRoots(divergenceCfg.RemoteHttpUrl).GetBlockStateRoots(ctx, divergence.BlockNumber)
+			if err != nil {
+				return fmt.Errorf("failed to get remote state roots for block %d: %w", divergence.BlockNumber, err)
+			}
+		} else {
+			l.Sugar().Warnw("No remote HTTP url provided; writing local model state roots only")
+		}
+
+		report, err := json.MarshalIndent(stateRootDivergence.NewDivergenceReport(divergence, local, remote), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode divergence report: %w", err)
+		}
+		if divergenceCfg.Output == "" {
+			fmt.Println(string(report))
+			return nil
+		}
+		if err := os.WriteFile(divergenceCfg.Output, report, 0644); err != nil {
+			return fmt.Errorf("failed to write divergence report: %w", err)
+		}
+		l.Sugar().Infow("Wrote divergence report", zap.String("output", divergenceCfg.Output))
+		return nil
+	},
+}
+
+func initFindStateRootDivergenceCmd(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err := viper.BindPFlag(config.KebabToSnakeCase(f.Name), f); err != nil {
+			fmt.Printf("Failed to bind flag '%s' - %+v\n", f.Name, err)
+		}
+		if err := viper.BindEnv(f.Name); err != nil {
+			fmt.Printf("Failed to bind env '%s' - %+v\n", f.Name, err)
+		}
+	})
+}
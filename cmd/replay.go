@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/postgres/migrations"
+	"github.com/Layr-Labs/sidecar/pkg/replay"
+	pgStorage "github.com/Layr-Labs/sidecar/pkg/storage/postgres"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Rebuild eigen state from stored transaction logs",
+	Long: `Rebuild eigen state for a range of blocks from the transaction logs already stored in the database.
+
+The state of the selected models is deleted for the range and rebuilt by replaying the stored logs in order.
+The state root generated for each block is compared with the stored state root. No Ethereum node is required.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initReplayCmd(cmd)
+		cfg := config.NewConfig()
+		ctx := context.Background()
+
+		l, err := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		pgConfig := postgres.PostgresConfigFromDbConfig(&cfg.DatabaseConfig)
+
+		pg, err := postgres.NewPostgres(pgConfig)
+		if err != nil {
+			l.Fatal("Failed to setup postgres connection", zap.Error(err))
+		}
+
+		grm, err := postgres.NewGormFromPostgresConnection(pg.Db)
+		if err != nil {
+			l.Fatal("Failed to create gorm instance", zap.Error(err))
+		}
+
+		migrator := migrations.NewMigrator(pg.Db, grm, l, cfg)
+		if err = migrator.MigrateAll(); err != nil {
+			l.Fatal("Failed to migrate", zap.Error(err))
+		}
+
+		sm := stateManager.NewEigenStateManager(l, grm)
+		if err := eigenState.LoadEigenStateModels(sm, grm, l, cfg); err != nil {
+			l.Sugar().Fatalw("Failed to load eigen state models", zap.Error(err))
+		}
+
+		startBlock := cfg.ReplayConfig.StartBlock
+		if startBlock == 0 {
+			startBlock = cfg.GetGenesisBlockNumber()
+		}
+		endBlock := cfg.ReplayConfig.EndBlock
+		if endBlock == 0 {
+			mds := pgStorage.NewPostgresBlockStore(grm, l, cfg)
+			latestBlock, err := mds.GetLatestBlock()
+			if err != nil {
+				return fmt.Errorf("failed to get latest indexed block: %w", err)
+			}
+			endBlock = latestBlock.Number
+		}
+
+		r := replay.NewReplayer(sm, grm, l)
+		result, err := r.Replay(ctx, &replay.ReplayConfig{
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+			Models:     cfg.ReplayConfig.Models,
+		})
+		if err != nil {
+			l.Sugar().Errorw("Failed to replay state", zap.Error(err))
+			return err
+		}
+
+		l.Sugar().Infow("Replay complete",
+			zap.Uint64("startBlock", startBlock),
+			zap.Uint64("endBlock", endBlock),
+			zap.Uint64("blocksReplayed", result.BlocksReplayed),
+			zap.Uint64("logsReplayed", result.LogsReplayed),
+			zap.Int("mismatches", len(result.Mismatches)),
+		)
+		if len(result.Mismatches) > 0 {
+			return fmt.Errorf("replayed state roots do not match stored state roots for %d blocks; first mismatch at block %d",
+				len(result.Mismatches), result.Mismatches[0].BlockNumber)
+		}
+		return nil
+	},
+}
+
+func initReplayCmd(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err := viper.BindPFlag(config.KebabToSnakeCase(f.Name), f); err != nil {
+			fmt.Printf("Failed to bind flag '%s' - %+v\n", f.Name, err)
+		}
+		if err := viper.BindEnv(f.Name); err != nil {
+			fmt.Printf("Failed to bind env '%s' - %+v\n", f.Name, err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/metrics"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/postgres/migrations"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/rewards/stakerOperators"
+	pgStorage "github.com/Layr-Labs/sidecar/pkg/storage/postgres"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var retryRewardsSnapshotCmd = &cobra.Command{
+	Use:   "retry-rewards-snapshot",
+	Short: "Clean up and recalculate a failed rewards snapshot",
+	Long: `Drop the gold and staker-operator tables that a failed rewards calculation left behind for a snapshot date,
+reset its status and calculate it again.
+
+Failed snapshots are retried automatically up to --rewards.snapshot-retry-limit times. Use this command once
+the automatic retries are exhausted. The calculation runs in this process, so a running sidecar should retry the
+snapshot through its rewards snapshot retry RPC instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initRetryRewardsSnapshotCmd(cmd)
+		cfg := config.NewConfig()
+
+		l, err := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		snapshotDate := cfg.RetryRewardsSnapshotConfig.SnapshotDate
+		if _, err := time.Parse(time.DateOnly, snapshotDate); err != nil {
+			return fmt.Errorf("--%s must be a date formatted as YYYY-MM-DD", config.RetryRewardsSnapshotDate)
+		}
+
+		metricsClients, err := metrics.InitMetricsSinksFromConfig(cfg, l)
+		if err != nil {
+			l.Sugar().Fatal("Failed to setup metrics sink", zap.Error(err))
+		}
+
+		sdc, err := metrics.NewMetricsSink(&metrics.MetricsSinkConfig{}, metricsClients)
+		if err != nil {
+			l.Sugar().Fatal("Failed to setup metrics sink", zap.Error(err))
+		}
+
+		pgConfig := postgres.PostgresConfigFromDbConfig(&cfg.DatabaseConfig)
+
+		pg, err := postgres.NewPostgres(pgConfig)
+		if err != nil {
+			l.Fatal("Failed to setup postgres connection", zap.Error(err))
+		}
+
+		grm, err := postgres.NewGormFromPostgresConnection(pg.Db)
+		if err != nil {
+			l.Fatal("Failed to create gorm instance", zap.Error(err))
+		}
+
+		migrator := migrations.NewMigrator(pg.Db, grm, l, cfg)
+		if err = migrator.MigrateAll(); err != nil {
+			l.Fatal("Failed to migrate", zap.Error(err))
+		}
+
+		mds := pgStorage.NewPostgresBlockStore(grm, l, cfg)
+
+		sog := stakerOperators.NewStakerOperatorGenerator(grm, l, cfg)
+
+		rc, err := rewards.NewRewardsCalculator(cfg, grm, mds, sog, sdc, l)
+		if err != nil {
+			l.Sugar().Fatalw("Failed to create rewards calculator", zap.Error(err))
+		}
+
+		if err := rc.RetryFailedRewardSnapshot(context.Background(), snapshotDate); err != nil {
+			l.Sugar().Errorw("Failed to retry rewards snapshot", zap.String("snapshotDate", snapshotDate), zap.Error(err))
+			return err
+		}
+		l.Sugar().Infow("Recalculated rewards snapshot", zap.String("snapshotDate", snapshotDate))
+		return nil
+	},
+}
+
+func initRetryRewardsSnapshotCmd(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err := viper.BindPFlag(config.KebabToSnakeCase(f.Name), f); err != nil {
+			fmt.Printf("Failed to bind flag '%s' - %+v\n", f.Name, err)
+		}
+		if err := viper.BindEnv(f.Name); err != nil {
+			fmt.Printf("Failed to bind env '%s' - %+v\n", f.Name, err)
+		}
+	})
+}
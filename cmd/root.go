@@ -13,6 +13,9 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "sidecar",
 	Short: "The EigenLayer Sidecar makes it easy to interact with the EigenLayer protocol data",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return config.ValidateChain()
+	},
 }
 
 func Execute() {
@@ -26,13 +29,17 @@ func init() {
 	initConfig(rootCmd)
 
 	rootCmd.PersistentFlags().Bool("debug", false, `"true" or "false"`)
-	rootCmd.PersistentFlags().StringP("chain", "c", "mainnet", "The chain to use (mainnet, holesky, preprod")
+	rootCmd.PersistentFlags().StringP("chain", "c", "mainnet", "The chain to use (mainnet, holesky, preprod, custom)")
+	rootCmd.PersistentFlags().String("chain-profile", "", "Path to the YAML or JSON chain profile to use with --chain=custom")
 
 	rootCmd.PersistentFlags().String("ethereum.rpc-url", "", `e.g. "http://<hostname>:8545"`)
 	rootCmd.PersistentFlags().Int(config.EthereumRpcContractCallBatchSize, 25, `The number of contract calls to batch together when fetching data from the Ethereum node`)
 	rootCmd.PersistentFlags().Bool(config.EthereumRpcUseNativeBatchCall, true, `Use the native eth_call method for batch calls`)
 	rootCmd.PersistentFlags().Int(config.EthereumRpcNativeBatchCallSize, 500, `The number of calls to batch together when using the native eth_call method`)
 	rootCmd.PersistentFlags().Int(config.EthereumRpcChunkedBatchCallSize, 10, `The number of calls to make in parallel when using the chunked batch call method`)
+	rootCmd.PersistentFlags().StringSlice(config.EthereumRpcEndpoints, []string{}, `Additional Ethereum RPC endpoints to fail over to, in the form "<url>[|<weight>[|<requestsPerSecond>]]"`)
+	rootCmd.PersistentFlags().Float64(config.EthereumRpcRequestsPerSecond, 0, `The maximum number of requests per second to send to each Ethereum RPC endpoint. 0 means unlimited`)
+	rootCmd.PersistentFlags().String(config.EthereumRpcFetchStrategy, string(config.FetchStrategy_Receipts), `How to fetch transaction receipts; "receipts" fetches every receipt in a block, "logs" uses eth_getLogs filtered by the EigenLayer contract addresses`)
 
 	rootCmd.PersistentFlags().String(config.DatabaseHost, "localhost", `PostgreSQL host`)
 	rootCmd.PersistentFlags().Int(config.DatabasePort, 5432, `PostgreSQL port`)
@@ -43,6 +50,21 @@ func init() {
 
 	rootCmd.PersistentFlags().Bool(config.RewardsValidateRewardsRoot, true, `Validate rewards roots while indexing`)
 	rootCmd.PersistentFlags().Bool(config.RewardsGenerateStakerOperatorsTable, false, `Generate staker operators table while indexing`)
+	rootCmd.PersistentFlags().Bool(config.RewardsForceFullCalculation, false, `Regenerate the rewards snapshot tables over the entire history instead of only the days since the last completed snapshot`)
+	rootCmd.PersistentFlags().Bool(config.RewardsVerifyIncrementalCalculation, false, `Compare incrementally generated rewards tables against a full recompute and fail the calculation if they differ`)
+	rootCmd.PersistentFlags().Int(config.RewardsSnapshotRetryLimit, 3, `The number of times a failed rewards snapshot is automatically retried. 0 disables automatic retries`)
+	rootCmd.PersistentFlags().Bool(config.RewardsCheckInvariants, true, `Check the rewards output against invariants after every calculation`)
+	rootCmd.PersistentFlags().Bool(config.RewardsFailOnInvariantViolation, false, `Mark a rewards snapshot as failed when it violates an invariant, rather than only recording the violation. Snapshots that fail this way are not retried automatically`)
+	rootCmd.PersistentFlags().Int(config.RewardsPendingRewardsIntervalHours, 0, `How often, in hours, to estimate the rewards earners have accrued since the snapshot of the last submitted distribution root. Each estimate takes about as long as a full rewards calculation. 0 disables the estimate`)
+	rootCmd.PersistentFlags().Int(config.RewardsSimulationsPerHour, 10, `The maximum number of rewards simulations to run per hour over RPC. 0 disables rewards simulations over RPC`)
+	rootCmd.PersistentFlags().Int(config.RewardsProofCacheMaxMb, 1024, `Approximate memory, in MB, used to cache the merkle trees that claim proofs are generated from`)
+
+	rootCmd.PersistentFlags().Bool(config.IndexerFollowLatestBlock, false, `Follow the latest (unsafe) block rather than the latest safe block, rolling back state when a reorg is detected`)
+	rootCmd.PersistentFlags().Uint64(config.IndexerMaxReorgDepth, 64, `The maximum number of blocks to walk back when searching for the common ancestor of a reorg`)
+	rootCmd.PersistentFlags().Uint64(config.IndexerBackfillBatchSize, 100, `The number of blocks to fetch in a single batch during backfill`)
+	rootCmd.PersistentFlags().Int(config.IndexerBackfillFetchWorkers, 4, `The number of block batches to fetch concurrently ahead of state processing during backfill`)
+
+	rootCmd.PersistentFlags().String(config.BlockCacheDir, "", `Directory to cache fetched blocks and receipts in. Cached blocks are used instead of fetching them from the Ethereum node. Disabled when empty`)
 
 	rootCmd.PersistentFlags().Int("rpc.grpc-port", 7100, `gRPC port`)
 	rootCmd.PersistentFlags().Int("rpc.http-port", 7101, `http rpc port`)
@@ -62,6 +84,12 @@ func init() {
 	rootCmd.AddCommand(createSnapshotCmd)
 	rootCmd.AddCommand(restoreSnapshotCmd)
 	rootCmd.AddCommand(rpcCmd)
+	rootCmd.AddCommand(cacheBlocksCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(findStateRootDivergenceCmd)
+	rootCmd.AddCommand(retryRewardsSnapshotCmd)
+	rootCmd.AddCommand(simulateRewardsCmd)
+	rootCmd.AddCommand(exportRewardsCmd)
 
 	// bind any subcommand flags
 	createSnapshotCmd.PersistentFlags().String(config.SnapshotOutputFile, "", "(deprecated, use --output) Path to save the snapshot file")
@@ -76,6 +104,33 @@ func init() {
 	restoreSnapshotCmd.PersistentFlags().Bool(config.SnapshotVerifySignature, false, "Verify the signature of the snapshot file")
 	restoreSnapshotCmd.PersistentFlags().String(config.SnapshotKind, "full", "The kind of snapshot to restore (slim, full, or archive)")
 
+	cacheBlocksCmd.PersistentFlags().Uint64(config.CacheBlocksStartBlock, 0, "The first block to cache (default: the genesis block for the chain)")
+	cacheBlocksCmd.PersistentFlags().Uint64(config.CacheBlocksEndBlock, 0, "The last block to cache (default: the latest safe block)")
+
+	replayCmd.PersistentFlags().Uint64(config.ReplayFrom, 0, "The first block to replay (default: the genesis block for the chain)")
+	replayCmd.PersistentFlags().Uint64(config.ReplayTo, 0, "The last block to replay (default: the latest indexed block)")
+	replayCmd.PersistentFlags().StringSlice(config.ReplayModels, []string{}, "Names of the eigen state models to rebuild (default: all models)")
+
+	findStateRootDivergenceCmd.PersistentFlags().Uint64(config.StateRootDivergenceFrom, 0, "The first block to compare (default: the genesis block for the chain)")
+	findStateRootDivergenceCmd.PersistentFlags().Uint64(config.StateRootDivergenceTo, 0, "The last block to compare (default: the latest indexed block)")
+	findStateRootDivergenceCmd.PersistentFlags().String(config.StateRootDivergenceRemoteUrl, "", "gRPC url of the sidecar to compare against")
+	findStateRootDivergenceCmd.PersistentFlags().String(config.StateRootDivergenceRemoteHttpUrl, "", "HTTP url of the sidecar to compare against, used to fetch per-model state roots")
+	findStateRootDivergenceCmd.PersistentFlags().Bool(config.StateRootDivergenceRemoteInsecure, false, "Connect to the remote sidecar without TLS")
+	findStateRootDivergenceCmd.PersistentFlags().String(config.StateRootDivergenceOutput, "", "Path to write the divergence report to (default: stdout)")
+	findStateRootDivergenceCmd.PersistentFlags().Uint64(config.StateRootDivergenceWindowSize, 1000, "Number of blocks compared before checking for a divergence")
+	findStateRootDivergenceCmd.PersistentFlags().Int(config.StateRootDivergenceConcurrency, 16, "Number of blocks compared at the same time")
+
+	retryRewardsSnapshotCmd.PersistentFlags().String(config.RetryRewardsSnapshotDate, "", "The failed snapshot date to retry, formatted as YYYY-MM-DD")
+
+	simulateRewardsCmd.PersistentFlags().String(config.SimulateRewardsInput, "", "Path to the JSON file describing the reward submissions to simulate")
+	simulateRewardsCmd.PersistentFlags().String(config.SimulateRewardsOutput, "", "Path to write the simulated rewards to (default: stdout)")
+
+	exportRewardsCmd.PersistentFlags().String(config.ExportRewardsSnapshotDate, "", "The snapshot date to export rewards up to, formatted as YYYY-MM-DD")
+	exportRewardsCmd.PersistentFlags().String(config.ExportRewardsDataset, "gold", "The data to export (gold, staker_operator, or claim_status)")
+	exportRewardsCmd.PersistentFlags().String(config.ExportRewardsFormat, "csv", "The file format to export to (csv, jsonl, or parquet)")
+	exportRewardsCmd.PersistentFlags().String(config.ExportRewardsOutput, "", "Path to write the export to (default: stdout)")
+	exportRewardsCmd.PersistentFlags().Bool(config.ExportRewardsGzip, false, "Compress the export with gzip")
+
 	rpcCmd.PersistentFlags().String(config.SidecarPrimaryUrl, "", `RPC url of the "primary" Sidecar instance in an HA environment`)
 
 	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
@@ -95,9 +95,14 @@ var rpcCmd = &cobra.Command{
 			l.Sugar().Fatalw("Failed to create rewards calculator", zap.Error(err))
 		}
 
-		rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, l)
+		rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, grm, l)
 
-		rps := proofs.NewRewardsProofsStore(rc, l)
+		rps := proofs.NewRewardsProofsStore(rc, grm, l, cfg)
+		go func() {
+			if err := rps.WarmCache(ctx); err != nil {
+				l.Sugar().Warnw("Failed to warm proof cache", zap.Error(err))
+			}
+		}()
 
 		pds := protocolDataService.NewProtocolDataService(sm, grm, l, cfg)
 		rds := rewardsDataService.NewRewardsDataService(grm, l, cfg, rc)
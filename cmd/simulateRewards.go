@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/metrics"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/postgres/migrations"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	pgStorage "github.com/Layr-Labs/sidecar/pkg/storage/postgres"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var simulateRewardsCmd = &cobra.Command{
+	Use:   "simulate-rewards",
+	Short: "Calculate what each earner would receive from hypothetical reward submissions",
+	Long: `Run the rewards calculation for hypothetical reward submissions and print what each earner would receive
+from them up to the cutoff date.
+
+The input file is a JSON object with the cutoff date and the submissions to simulate:
+
+  {
+    "cutoffDate": "2025-03-01",
+    "rewardSubmissions": [{
+      "avs": "0x...", "token": "0x...", "amount": "1000000000000000000000", "rewardType": "avs",
+      "startTimestamp": 1738368000, "duration": 2419200,
+      "strategies": [{"strategy": "0x...", "multiplier": "1000000000000000000"}]
+    }],
+    "operatorDirectedRewardSubmissions": []
+  }
+
+The calculation runs in a scratch schema that is dropped afterwards, so no production tables are modified.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initSimulateRewardsCmd(cmd)
+		cfg := config.NewConfig()
+		simulateCfg := cfg.SimulateRewardsConfig
+
+		l, err := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		if simulateCfg.Input == "" {
+			return fmt.Errorf("--%s is required", config.SimulateRewardsInput)
+		}
+		input, err := os.ReadFile(simulateCfg.Input)
+		if err != nil {
+			return fmt.Errorf("failed to read rewards simulation: %w", err)
+		}
+		var simulation rewards.RewardsSimulation
+		if err := json.Unmarshal(input, &simulation); err != nil {
+			return fmt.Errorf("failed to decode rewards simulation: %w", err)
+		}
+		if err := simulation.Validate(); err != nil {
+			return err
+		}
+
+		metricsClients, err := metrics.InitMetricsSinksFromConfig(cfg, l)
+		if err != nil {
+			l.Sugar().Fatal("Failed to setup metrics sink", zap.Error(err))
+		}
+
+		sdc, err := metrics.NewMetricsSink(&metrics.MetricsSinkConfig{}, metricsClients)
+		if err != nil {
+			l.Sugar().Fatal("Failed to setup metrics sink", zap.Error(err))
+		}
+
+		pgConfig := postgres.PostgresConfigFromDbConfig(&cfg.DatabaseConfig)
+
+		pg, err := postgres.NewPostgres(pgConfig)
+		if err != nil {
+			l.Fatal("Failed to setup postgres connection", zap.Error(err))
+		}
+
+		grm, err := postgres.NewGormFromPostgresConnection(pg.Db)
+		if err != nil {
+			l.Fatal("Failed to create gorm instance", zap.Error(err))
+		}
+
+		migrator := migrations.NewMigrator(pg.Db, grm, l, cfg)
+		if err = migrator.MigrateAll(); err != nil {
+			l.Fatal("Failed to migrate", zap.Error(err))
+		}
+
+		mds := pgStorage.NewPostgresBlockStore(grm, l, cfg)
+
+		rc, err := rewards.NewRewardsCalculator(cfg, grm, mds, nil, sdc, l)
+		if err != nil {
+			l.Sugar().Fatalw("Failed to create rewards calculator", zap.Error(err))
+		}
+
+		result, err := rc.SimulateRewards(context.Background(), &simulation)
+		if err != nil {
+			return err
+		}
+
+		output, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode simulated rewards: %w", err)
+		}
+		if simulateCfg.Output == "" {
+			fmt.Println(string(output))
+			return nil
+		}
+		if err := os.WriteFile(simulateCfg.Output, output, 0644); err != nil {
+			return fmt.Errorf("failed to write simulated rewards: %w", err)
+		}
+		l.Sugar().Infow("Wrote simulated rewards", zap.String("output", simulateCfg.Output))
+		return nil
+	},
+}
+
+func initSimulateRewardsCmd(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err := viper.BindPFlag(config.KebabToSnakeCase(f.Name), f); err != nil {
+			fmt.Printf("Failed to bind flag '%s' - %+v\n", f.Name, err)
+		}
+		if err := viper.BindEnv(f.Name); err != nil {
+			fmt.Printf("Failed to bind env '%s' - %+v\n", f.Name, err)
+		}
+	})
+}
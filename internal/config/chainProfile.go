@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// BlockRange is an inclusive range of block numbers
+type BlockRange struct {
+	From uint64 `mapstructure:"from"`
+	To   uint64 `mapstructure:"to"`
+}
+
+func (r BlockRange) Contains(blockNumber uint64) bool {
+	return blockNumber >= r.From && blockNumber <= r.To
+}
+
+// ChainProfile describes an EigenLayer deployment that the sidecar has no built-in settings for, such as a devnet
+// on a local anvil chain. It is loaded from a YAML or JSON file when the chain is Chain_Custom.
+type ChainProfile struct {
+	Contracts ContractAddresses `mapstructure:"contracts"`
+
+	GenesisBlockNumber                   uint64 `mapstructure:"genesis_block_number"`
+	OperatorRestakedStrategiesStartBlock uint64 `mapstructure:"operator_restaked_strategies_start_block"`
+
+	// RewardsForkDates are formatted as YYYY-MM-DD. Forks that are left out are active from the start of the chain.
+	RewardsForkDates map[ForkName]string `mapstructure:"rewards_fork_dates"`
+
+	// ModelForks are block numbers. Forks that are left out are active from the start of the chain.
+	ModelForks map[ForkName]uint64 `mapstructure:"model_forks"`
+
+	// IgnorableRootRanges are the blocks at which a submitted rewards root is allowed to not match the calculated one
+	IgnorableRootRanges []BlockRange `mapstructure:"ignorable_root_ranges"`
+
+	// CoreContractsFile is the path to the ABIs and proxies of the core contracts, in the same format as the files in
+	// pkg/contractStore/coreContracts. A relative path is resolved from the directory of the profile.
+	CoreContractsFile string `mapstructure:"core_contracts_file"`
+}
+
+var rewardsForks = []ForkName{RewardsFork_Amazon, RewardsFork_Nile, RewardsFork_Panama, RewardsFork_Arno, RewardsFork_Trinity, RewardsFork_Mississippi}
+
+var modelForks = []ForkName{ModelFork_Austin, ModelFork_Boston}
+
+// LoadChainProfile reads and validates a chain profile. The format is picked from the file extension.
+func LoadChainProfile(path string) (*ChainProfile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("a chain profile is required for chain '%s'", Chain_Custom)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read chain profile '%s': %w", path, err)
+	}
+
+	profile := &ChainProfile{}
+	if err := v.Unmarshal(profile); err != nil {
+		return nil, fmt.Errorf("failed to decode chain profile '%s': %w", path, err)
+	}
+
+	if profile.RewardsForkDates == nil {
+		profile.RewardsForkDates = make(map[ForkName]string)
+	}
+	for _, fork := range rewardsForks {
+		if _, ok := profile.RewardsForkDates[fork]; !ok {
+			profile.RewardsForkDates[fork] = "1970-01-01"
+		}
+	}
+	if profile.ModelForks == nil {
+		profile.ModelForks = make(map[ForkName]uint64)
+	}
+	for _, fork := range modelForks {
+		if _, ok := profile.ModelForks[fork]; !ok {
+			profile.ModelForks[fork] = 0
+		}
+	}
+
+	if profile.CoreContractsFile != "" && !filepath.IsAbs(profile.CoreContractsFile) {
+		profile.CoreContractsFile = filepath.Join(filepath.Dir(path), profile.CoreContractsFile)
+	}
+
+	if err := profile.validate(); err != nil {
+		return nil, fmt.Errorf("invalid chain profile '%s': %w", path, err)
+	}
+	return profile, nil
+}
+
+func (p *ChainProfile) validate() error {
+	if p.Contracts.RewardsCoordinator == "" || p.Contracts.EigenpodManager == "" || p.Contracts.StrategyManager == "" ||
+		p.Contracts.DelegationManager == "" || p.Contracts.AvsDirectory == "" {
+		return errors.New("the address of every core contract other than the allocation manager is required")
+	}
+	for fork, date := range p.RewardsForkDates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("rewards fork '%s' has an invalid date '%s'", fork, date)
+		}
+	}
+	for _, r := range p.IgnorableRootRanges {
+		if r.From > r.To {
+			return fmt.Errorf("ignorable root range %d-%d is empty", r.From, r.To)
+		}
+	}
+	if p.CoreContractsFile == "" {
+		return errors.New("a core contracts file is required")
+	}
+	if _, err := os.Stat(p.CoreContractsFile); err != nil {
+		return fmt.Errorf("core contracts file: %w", err)
+	}
+	return nil
+}
+
+// ValidateChain checks that the chain flag names a supported chain, and that a custom chain has a valid profile
+func ValidateChain() error {
+	chain := Chain(viper.GetString(normalizeFlagName("chain")))
+	switch chain {
+	case "", Chain_Mainnet, Chain_Holesky, Chain_Preprod:
+		return nil
+	case Chain_Custom:
+		_, err := LoadChainProfile(viper.GetString(normalizeFlagName(ChainProfilePath)))
+		return err
+	}
+	return fmt.Errorf("unsupported chain '%s'", chain)
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testChainProfile = `
+contracts:
+  rewards_coordinator: "0x1111111111111111111111111111111111111111"
+  eigenpod_manager: "0x2222222222222222222222222222222222222222"
+  strategy_manager: "0x3333333333333333333333333333333333333333"
+  delegation_manager: "0x4444444444444444444444444444444444444444"
+  avs_directory: "0x5555555555555555555555555555555555555555"
+genesis_block_number: 10
+operator_restaked_strategies_start_block: 20
+rewards_fork_dates:
+  arno: "2025-01-02"
+  trinity: "2025-01-03"
+model_forks:
+  austin: 30
+ignorable_root_ranges:
+  - from: 100
+    to: 200
+core_contracts_file: coreContracts.json
+`
+
+func writeTestChainProfile(t *testing.T, profile string) string {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "coreContracts.json"), []byte(`{"core_contracts": [], "proxy_contracts": []}`), 0o644))
+
+	path := filepath.Join(dir, "profile.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(profile), 0o644))
+	return path
+}
+
+func Test_ChainProfile(t *testing.T) {
+	t.Run("Loads a YAML profile", func(t *testing.T) {
+		path := writeTestChainProfile(t, testChainProfile)
+
+		profile, err := LoadChainProfile(path)
+		assert.Nil(t, err)
+
+		cfg := &Config{Chain: Chain_Custom, ChainProfile: profile}
+		assert.Equal(t, "0x1111111111111111111111111111111111111111", cfg.GetContractsMapForChain().RewardsCoordinator)
+		assert.Equal(t, "0x5555555555555555555555555555555555555555", cfg.GetAVSDirectoryForChain())
+		assert.Equal(t, uint64(10), cfg.GetGenesisBlockNumber())
+		assert.Equal(t, uint64(20), cfg.GetOperatorRestakedStrategiesStartBlock())
+		assert.Equal(t, filepath.Join(filepath.Dir(path), "coreContracts.json"), profile.CoreContractsFile)
+
+		forks, err := cfg.GetRewardsSqlForkDates()
+		assert.Nil(t, err)
+		assert.Equal(t, "2025-01-02", forks[RewardsFork_Arno])
+		assert.Equal(t, "1970-01-01", forks[RewardsFork_Nile])
+
+		modelForks, err := cfg.GetModelForks()
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(30), modelForks[ModelFork_Austin])
+		assert.Equal(t, uint64(0), modelForks[ModelFork_Boston])
+
+		active, err := cfg.IsModelForkActive(ModelFork_Austin, 29)
+		assert.Nil(t, err)
+		assert.False(t, active)
+
+		mainnet := &Config{Chain: Chain_Mainnet}
+		active, err = mainnet.IsModelForkActive(ModelFork_Boston, 30_000_000)
+		assert.Nil(t, err)
+		assert.True(t, active)
+		assert.NotEmpty(t, mainnet.GetContractsMapForChain().AllocationManager)
+
+		enabled, err := cfg.IsRewardsV2EnabledForCutoffDate("2025-01-02")
+		assert.Nil(t, err)
+		assert.True(t, enabled)
+
+		enabled, err = cfg.IsRewardsV2_1EnabledForCutoffDate("2025-01-02")
+		assert.Nil(t, err)
+		assert.True(t, enabled)
+
+		enabled, err = mainnet.IsRewardsV2_1EnabledForCutoffDate("2025-03-01")
+		assert.Nil(t, err)
+		assert.False(t, enabled)
+
+		assert.True(t, cfg.CanIgnoreIncorrectRewardsRoot(150))
+		assert.False(t, cfg.CanIgnoreIncorrectRewardsRoot(201))
+	})
+	t.Run("Requires every core contract address", func(t *testing.T) {
+		path := writeTestChainProfile(t, `
+contracts:
+  rewards_coordinator: "0x1111111111111111111111111111111111111111"
+core_contracts_file: coreContracts.json
+`)
+		_, err := LoadChainProfile(path)
+		assert.NotNil(t, err)
+	})
+	t.Run("Rejects an invalid fork date", func(t *testing.T) {
+		path := writeTestChainProfile(t, strings.Replace(testChainProfile, `"2025-01-02"`, `"01/02/2025"`, 1))
+		_, err := LoadChainProfile(path)
+		assert.NotNil(t, err)
+	})
+	t.Run("Requires a profile path", func(t *testing.T) {
+		_, err := LoadChainProfile("")
+		assert.NotNil(t, err)
+	})
+}
@@ -29,9 +29,22 @@ const (
 	Chain_Holesky Chain = "holesky"
 	Chain_Preprod Chain = "preprod"
 
+	// Chain_Custom is a deployment described by a ChainProfile
+	Chain_Custom Chain = "custom"
+
 	ENV_PREFIX = "SIDECAR"
 )
 
+// FetchStrategy controls how the Fetcher collects transaction receipts for a block
+type FetchStrategy string
+
+const (
+	// FetchStrategy_Receipts fetches the receipt of every transaction in every block
+	FetchStrategy_Receipts FetchStrategy = "receipts"
+	// FetchStrategy_Logs uses eth_getLogs filtered by the interesting addresses and only fetches receipts for matching transactions
+	FetchStrategy_Logs FetchStrategy = "logs"
+)
+
 // Rewards forks named after rivers
 const (
 	RewardsFork_Nile    ForkName = "nile"
@@ -39,18 +52,26 @@ const (
 	RewardsFork_Panama  ForkName = "panama"
 	RewardsFork_Arno    ForkName = "arno"
 	RewardsFork_Trinity ForkName = "trinity"
+	// RewardsFork_Mississippi enables rewards v2.1: operator-directed rewards submitted to operator sets
+	RewardsFork_Mississippi ForkName = "mississippi"
 )
 
+// rewardsForkNotScheduled is the date of a rewards fork that has not been scheduled for a chain yet
+const rewardsForkNotScheduled = "9999-12-31"
+
 func normalizeFlagName(name string) string {
 	return strings.ReplaceAll(name, "-", "_")
 }
 
 type EthereumRpcConfig struct {
 	BaseUrl               string
-	ContractCallBatchSize int  // Number of contract calls to make in parallel
-	UseNativeBatchCall    bool // Use the native eth_call method for batch calls
-	NativeBatchCallSize   int  // Number of calls to put in a single eth_call request
-	ChunkedBatchCallSize  int  // Number of calls to make in parallel
+	Endpoints             []string // Additional endpoints in the form "<url>[|<weight>[|<requestsPerSecond>]]"
+	RequestsPerSecond     float64  // Default per-endpoint requests per second limit. 0 means unlimited
+	ContractCallBatchSize int      // Number of contract calls to make in parallel
+	UseNativeBatchCall    bool     // Use the native eth_call method for batch calls
+	NativeBatchCallSize   int      // Number of calls to put in a single eth_call request
+	ChunkedBatchCallSize  int      // Number of calls to make in parallel
+	FetchStrategy         FetchStrategy
 }
 
 type DatabaseConfig struct {
@@ -89,6 +110,14 @@ type RpcConfig struct {
 type RewardsConfig struct {
 	ValidateRewardsRoot          bool
 	GenerateStakerOperatorsTable bool
+	ForceFullCalculation         bool
+	VerifyIncrementalCalculation bool
+	SnapshotRetryLimit           int // Number of times a failed snapshot is automatically retried before manual intervention is needed
+	CheckInvariants              bool
+	FailOnInvariantViolation     bool // Mark the snapshot as failed when an invariant is violated rather than only recording it
+	ProofCacheMaxMb              int  // Approximate memory cap of the merkle trees cached for generating claim proofs
+	PendingRewardsIntervalHours  int  // How often pending rewards are estimated. 0 disables the estimate
+	SimulationsPerHour           int  // Maximum number of rewards simulations the RPC runs per hour. 0 disables simulations over RPC
 }
 
 type StatsdConfig struct {
@@ -120,20 +149,79 @@ type EtherscanConfig struct {
 	ApiKey string
 }
 
+type IndexerConfig struct {
+	FollowLatestBlock    bool   // Follow the latest (unsafe) head instead of the latest safe block
+	MaxReorgDepth        uint64 // Maximum number of blocks to walk back when searching for a common ancestor
+	BackfillBatchSize    uint64 // Number of blocks to fetch in a single batch during backfill
+	BackfillFetchWorkers int    // Number of batches to fetch concurrently ahead of state processing during backfill
+}
+
+type BlockCacheConfig struct {
+	Dir string // Directory to cache fetched blocks and receipts in. Empty disables the cache
+}
+
+type CacheBlocksConfig struct {
+	StartBlock uint64
+	EndBlock   uint64
+}
+
+type ReplayConfig struct {
+	StartBlock uint64
+	EndBlock   uint64
+	Models     []string
+}
+
+type StateRootDivergenceConfig struct {
+	StartBlock     uint64
+	EndBlock       uint64
+	RemoteUrl      string // gRPC url of the sidecar to compare against
+	RemoteHttpUrl  string // HTTP url of the sidecar to compare against, used to fetch per-model state roots
+	RemoteInsecure bool
+	Output         string
+	WindowSize     uint64 // Number of blocks compared before checking for a divergence
+	Concurrency    int    // Number of blocks of a window compared at the same time
+}
+
+type RetryRewardsSnapshotConfig struct {
+	SnapshotDate string
+}
+
+type SimulateRewardsConfig struct {
+	Input  string // Path to the JSON rewards simulation
+	Output string
+}
+
+type ExportRewardsConfig struct {
+	SnapshotDate string
+	Dataset      string // gold, staker_operator or claim_status
+	Format       string // csv, jsonl or parquet
+	Output       string
+	Gzip         bool
+}
+
 type Config struct {
-	Debug                 bool
-	EthereumRpcConfig     EthereumRpcConfig
-	DatabaseConfig        DatabaseConfig
-	CreateSnapshotConfig  CreateSnapshotConfig
-	RestoreSnapshotConfig RestoreSnapshotConfig
-	RpcConfig             RpcConfig
-	Chain                 Chain
-	Rewards               RewardsConfig
-	DataDogConfig         DataDogConfig
-	PrometheusConfig      PrometheusConfig
-	SidecarPrimaryConfig  SidecarPrimaryConfig
-	IpfsConfig            IpfsConfig
-	EtherscanConfig       EtherscanConfig
+	Debug                      bool
+	EthereumRpcConfig          EthereumRpcConfig
+	DatabaseConfig             DatabaseConfig
+	CreateSnapshotConfig       CreateSnapshotConfig
+	RestoreSnapshotConfig      RestoreSnapshotConfig
+	RpcConfig                  RpcConfig
+	Chain                      Chain
+	ChainProfile               *ChainProfile // Only set for Chain_Custom
+	Rewards                    RewardsConfig
+	DataDogConfig              DataDogConfig
+	PrometheusConfig           PrometheusConfig
+	SidecarPrimaryConfig       SidecarPrimaryConfig
+	IpfsConfig                 IpfsConfig
+	EtherscanConfig            EtherscanConfig
+	IndexerConfig              IndexerConfig
+	BlockCacheConfig           BlockCacheConfig
+	CacheBlocksConfig          CacheBlocksConfig
+	ReplayConfig               ReplayConfig
+	StateRootDivergenceConfig  StateRootDivergenceConfig
+	RetryRewardsSnapshotConfig RetryRewardsSnapshotConfig
+	SimulateRewardsConfig      SimulateRewardsConfig
+	ExportRewardsConfig        ExportRewardsConfig
 }
 
 func StringWithDefault(value, defaultValue string) string {
@@ -154,6 +242,7 @@ func StringWithDefaults(values ...string) string {
 
 var (
 	Debug              = "debug"
+	ChainProfilePath   = "chain_profile"
 	DatabaseHost       = "database.host"
 	DatabasePort       = "database.port"
 	DatabaseUser       = "database.user"
@@ -175,12 +264,23 @@ var (
 
 	RewardsValidateRewardsRoot          = "rewards.validate_rewards_root"
 	RewardsGenerateStakerOperatorsTable = "rewards.generate_staker_operators_table"
+	RewardsForceFullCalculation         = "rewards.force_full_calculation"
+	RewardsVerifyIncrementalCalculation = "rewards.verify_incremental_calculation"
+	RewardsSnapshotRetryLimit           = "rewards.snapshot_retry_limit"
+	RewardsCheckInvariants              = "rewards.check_invariants"
+	RewardsFailOnInvariantViolation     = "rewards.fail_on_invariant_violation"
+	RewardsProofCacheMaxMb              = "rewards.proof_cache_max_mb"
+	RewardsPendingRewardsIntervalHours  = "rewards.pending_rewards_interval_hours"
+	RewardsSimulationsPerHour           = "rewards.simulations_per_hour"
 
 	EthereumRpcBaseUrl               = "ethereum.rpc_url"
 	EthereumRpcContractCallBatchSize = "ethereum.contract_call_batch_size"
 	EthereumRpcUseNativeBatchCall    = "ethereum.use_native_batch_call"
 	EthereumRpcNativeBatchCallSize   = "ethereum.native_batch_call_size"
 	EthereumRpcChunkedBatchCallSize  = "ethereum.chunked_batch_call_size"
+	EthereumRpcFetchStrategy         = "ethereum.fetch_strategy"
+	EthereumRpcEndpoints             = "ethereum.rpc_endpoints"
+	EthereumRpcRequestsPerSecond     = "ethereum.requests_per_second"
 
 	DataDogStatsdEnabled    = "datadog.statsd.enabled"
 	DataDogStatsdUrl        = "datadog.statsd.url"
@@ -194,19 +294,65 @@ var (
 	IpfsUrl = "ipfs.url"
 
 	EtherscanApiKey = "etherscan.api-key"
+
+	IndexerFollowLatestBlock    = "indexer.follow_latest_block"
+	IndexerMaxReorgDepth        = "indexer.max_reorg_depth"
+	IndexerBackfillBatchSize    = "indexer.backfill_batch_size"
+	IndexerBackfillFetchWorkers = "indexer.backfill_fetch_workers"
+
+	BlockCacheDir = "block_cache.dir"
+
+	CacheBlocksStartBlock = "start-block"
+	CacheBlocksEndBlock   = "end-block"
+
+	ReplayFrom   = "from"
+	ReplayTo     = "to"
+	ReplayModels = "models"
+
+	StateRootDivergenceFrom           = "from"
+	StateRootDivergenceTo             = "to"
+	StateRootDivergenceRemoteUrl      = "remote-url"
+	StateRootDivergenceRemoteHttpUrl  = "remote-http-url"
+	StateRootDivergenceRemoteInsecure = "remote-insecure"
+	StateRootDivergenceOutput         = "output"
+	StateRootDivergenceWindowSize     = "window-size"
+	StateRootDivergenceConcurrency    = "concurrency"
+
+	RetryRewardsSnapshotDate = "snapshot-date"
+
+	SimulateRewardsInput  = "input"
+	SimulateRewardsOutput = "output"
+
+	ExportRewardsSnapshotDate = "snapshot-date"
+	ExportRewardsDataset      = "dataset"
+	ExportRewardsFormat       = "format"
+	ExportRewardsOutput       = "output"
+	ExportRewardsGzip         = "gzip"
 )
 
 func NewConfig() *Config {
+	chain := Chain(StringWithDefault(viper.GetString(normalizeFlagName("chain")), "holesky"))
+
+	// the profile is validated by ValidateChain before any command runs
+	var chainProfile *ChainProfile
+	if chain == Chain_Custom {
+		chainProfile, _ = LoadChainProfile(viper.GetString(normalizeFlagName(ChainProfilePath)))
+	}
+
 	return &Config{
-		Debug: viper.GetBool(normalizeFlagName("debug")),
-		Chain: Chain(StringWithDefault(viper.GetString(normalizeFlagName("chain")), "holesky")),
+		Debug:        viper.GetBool(normalizeFlagName("debug")),
+		Chain:        chain,
+		ChainProfile: chainProfile,
 
 		EthereumRpcConfig: EthereumRpcConfig{
 			BaseUrl:               viper.GetString(normalizeFlagName(EthereumRpcBaseUrl)),
+			Endpoints:             viper.GetStringSlice(normalizeFlagName(EthereumRpcEndpoints)),
+			RequestsPerSecond:     viper.GetFloat64(normalizeFlagName(EthereumRpcRequestsPerSecond)),
 			ContractCallBatchSize: viper.GetInt(normalizeFlagName(EthereumRpcContractCallBatchSize)),
 			UseNativeBatchCall:    viper.GetBool(normalizeFlagName(EthereumRpcUseNativeBatchCall)),
 			NativeBatchCallSize:   viper.GetInt(normalizeFlagName(EthereumRpcNativeBatchCallSize)),
 			ChunkedBatchCallSize:  viper.GetInt(normalizeFlagName(EthereumRpcChunkedBatchCallSize)),
+			FetchStrategy:         FetchStrategy(StringWithDefault(viper.GetString(normalizeFlagName(EthereumRpcFetchStrategy)), string(FetchStrategy_Receipts))),
 		},
 
 		DatabaseConfig: DatabaseConfig{
@@ -240,6 +386,14 @@ func NewConfig() *Config {
 		Rewards: RewardsConfig{
 			ValidateRewardsRoot:          viper.GetBool(normalizeFlagName(RewardsValidateRewardsRoot)),
 			GenerateStakerOperatorsTable: viper.GetBool(normalizeFlagName(RewardsGenerateStakerOperatorsTable)),
+			ForceFullCalculation:         viper.GetBool(normalizeFlagName(RewardsForceFullCalculation)),
+			VerifyIncrementalCalculation: viper.GetBool(normalizeFlagName(RewardsVerifyIncrementalCalculation)),
+			SnapshotRetryLimit:           viper.GetInt(normalizeFlagName(RewardsSnapshotRetryLimit)),
+			CheckInvariants:              viper.GetBool(normalizeFlagName(RewardsCheckInvariants)),
+			FailOnInvariantViolation:     viper.GetBool(normalizeFlagName(RewardsFailOnInvariantViolation)),
+			ProofCacheMaxMb:              viper.GetInt(normalizeFlagName(RewardsProofCacheMaxMb)),
+			PendingRewardsIntervalHours:  viper.GetInt(normalizeFlagName(RewardsPendingRewardsIntervalHours)),
+			SimulationsPerHour:           viper.GetInt(normalizeFlagName(RewardsSimulationsPerHour)),
 		},
 
 		DataDogConfig: DataDogConfig{
@@ -266,6 +420,56 @@ func NewConfig() *Config {
 		EtherscanConfig: EtherscanConfig{
 			ApiKey: viper.GetString(normalizeFlagName(EtherscanApiKey)),
 		},
+
+		IndexerConfig: IndexerConfig{
+			FollowLatestBlock:    viper.GetBool(normalizeFlagName(IndexerFollowLatestBlock)),
+			MaxReorgDepth:        viper.GetUint64(normalizeFlagName(IndexerMaxReorgDepth)),
+			BackfillBatchSize:    viper.GetUint64(normalizeFlagName(IndexerBackfillBatchSize)),
+			BackfillFetchWorkers: viper.GetInt(normalizeFlagName(IndexerBackfillFetchWorkers)),
+		},
+
+		BlockCacheConfig: BlockCacheConfig{
+			Dir: viper.GetString(normalizeFlagName(BlockCacheDir)),
+		},
+
+		CacheBlocksConfig: CacheBlocksConfig{
+			StartBlock: viper.GetUint64(normalizeFlagName(CacheBlocksStartBlock)),
+			EndBlock:   viper.GetUint64(normalizeFlagName(CacheBlocksEndBlock)),
+		},
+
+		ReplayConfig: ReplayConfig{
+			StartBlock: viper.GetUint64(normalizeFlagName(ReplayFrom)),
+			EndBlock:   viper.GetUint64(normalizeFlagName(ReplayTo)),
+			Models:     viper.GetStringSlice(normalizeFlagName(ReplayModels)),
+		},
+
+		StateRootDivergenceConfig: StateRootDivergenceConfig{
+			StartBlock:     viper.GetUint64(normalizeFlagName(StateRootDivergenceFrom)),
+			EndBlock:       viper.GetUint64(normalizeFlagName(StateRootDivergenceTo)),
+			RemoteUrl:      viper.GetString(normalizeFlagName(StateRootDivergenceRemoteUrl)),
+			RemoteHttpUrl:  viper.GetString(normalizeFlagName(StateRootDivergenceRemoteHttpUrl)),
+			RemoteInsecure: viper.GetBool(normalizeFlagName(StateRootDivergenceRemoteInsecure)),
+			Output:         viper.GetString(normalizeFlagName(StateRootDivergenceOutput)),
+			WindowSize:     viper.GetUint64(normalizeFlagName(StateRootDivergenceWindowSize)),
+			Concurrency:    viper.GetInt(normalizeFlagName(StateRootDivergenceConcurrency)),
+		},
+
+		RetryRewardsSnapshotConfig: RetryRewardsSnapshotConfig{
+			SnapshotDate: viper.GetString(normalizeFlagName(RetryRewardsSnapshotDate)),
+		},
+
+		SimulateRewardsConfig: SimulateRewardsConfig{
+			Input:  viper.GetString(normalizeFlagName(SimulateRewardsInput)),
+			Output: viper.GetString(normalizeFlagName(SimulateRewardsOutput)),
+		},
+
+		ExportRewardsConfig: ExportRewardsConfig{
+			SnapshotDate: viper.GetString(normalizeFlagName(ExportRewardsSnapshotDate)),
+			Dataset:      viper.GetString(normalizeFlagName(ExportRewardsDataset)),
+			Format:       viper.GetString(normalizeFlagName(ExportRewardsFormat)),
+			Output:       viper.GetString(normalizeFlagName(ExportRewardsOutput)),
+			Gzip:         viper.GetBool(normalizeFlagName(ExportRewardsGzip)),
+		},
 	}
 }
 
@@ -280,11 +484,12 @@ var AVSDirectoryAddresses = map[Chain]string{
 }
 
 type ContractAddresses struct {
-	RewardsCoordinator string
-	EigenpodManager    string
-	StrategyManager    string
-	DelegationManager  string
-	AvsDirectory       string
+	RewardsCoordinator string `mapstructure:"rewards_coordinator"`
+	EigenpodManager    string `mapstructure:"eigenpod_manager"`
+	StrategyManager    string `mapstructure:"strategy_manager"`
+	DelegationManager  string `mapstructure:"delegation_manager"`
+	AvsDirectory       string `mapstructure:"avs_directory"`
+	AllocationManager  string `mapstructure:"allocation_manager"` // Empty on custom chains without the slashing release
 }
 
 func (c *Config) ChainIsOneOf(chains ...Chain) bool {
@@ -299,6 +504,7 @@ func (c *Config) GetContractsMapForChain() *ContractAddresses {
 			StrategyManager:    "0xf9fbf2e35d8803273e214c99bf15174139f4e67a",
 			DelegationManager:  "0x75dfe5b44c2e530568001400d3f704bc8ae350cc",
 			AvsDirectory:       "0x141d6995556135d4997b2ff72eb443be300353bc",
+			AllocationManager:  "0xfdd5749e11977d60850e06bf5b13221ad95eb6b4",
 		}
 	} else if c.Chain == Chain_Holesky {
 		return &ContractAddresses{
@@ -307,6 +513,7 @@ func (c *Config) GetContractsMapForChain() *ContractAddresses {
 			StrategyManager:    "0xdfb5f6ce42aaa7830e94ecfccad411bef4d4d5b6",
 			DelegationManager:  "0xa44151489861fe9e3055d95adc98fbd462b948e7",
 			AvsDirectory:       "0x055733000064333caddbc92763c58bf0192ffebf",
+			AllocationManager:  "0x78469728304326cbc65f8f95fa756b0b73164462",
 		}
 	} else if c.Chain == Chain_Mainnet {
 		return &ContractAddresses{
@@ -315,7 +522,11 @@ func (c *Config) GetContractsMapForChain() *ContractAddresses {
 			StrategyManager:    "0x858646372cc42e1a627fce94aa7a7033e7cf075a",
 			DelegationManager:  "0x39053d51b77dc0d36036fc1fcc8cb819df8ef37a",
 			AvsDirectory:       "0x135dda560e946695d6f155dacafc6f1f25c1f5af",
+			AllocationManager:  "0x948a420b8cc1d6bfd0b6087c2e7c344a2cd0bc39",
 		}
+	} else if c.Chain == Chain_Custom && c.ChainProfile != nil {
+		contracts := c.ChainProfile.Contracts
+		return &contracts
 	} else {
 		return nil
 	}
@@ -328,13 +539,17 @@ func (c *Config) GetInterestingAddressForConfigEnv() []string {
 		return []string{}
 	}
 
-	return []string{
+	interesting := []string{
 		addresses.RewardsCoordinator,
 		addresses.EigenpodManager,
 		addresses.StrategyManager,
 		addresses.DelegationManager,
 		addresses.AvsDirectory,
 	}
+	if addresses.AllocationManager != "" {
+		interesting = append(interesting, addresses.AllocationManager)
+	}
+	return interesting
 }
 
 func (c *Config) GetGenesisBlockNumber() uint64 {
@@ -345,6 +560,11 @@ func (c *Config) GetGenesisBlockNumber() uint64 {
 		return 1167044
 	case Chain_Mainnet:
 		return 17445563
+	case Chain_Custom:
+		if c.ChainProfile != nil {
+			return c.ChainProfile.GenesisBlockNumber
+		}
+		return 0
 	default:
 		return 0
 	}
@@ -356,28 +576,35 @@ func (c *Config) GetRewardsSqlForkDates() (ForkMap, error) {
 	switch c.Chain {
 	case Chain_Preprod:
 		return ForkMap{
-			RewardsFork_Amazon:  "1970-01-01", // Amazon hard fork was never on preprod as we backfilled
-			RewardsFork_Nile:    "2024-08-14", // Last calculation end timestamp was 8-13: https://holesky.etherscan.io/tx/0xb5a6855e88c79312b7c0e1c9f59ae9890b97f157ea27e69e4f0fadada4712b64#eventlog
-			RewardsFork_Panama:  "2024-10-01",
-			RewardsFork_Arno:    "2024-12-11",
-			RewardsFork_Trinity: "2025-01-09",
+			RewardsFork_Amazon:      "1970-01-01", // Amazon hard fork was never on preprod as we backfilled
+			RewardsFork_Nile:        "2024-08-14", // Last calculation end timestamp was 8-13: https://holesky.etherscan.io/tx/0xb5a6855e88c79312b7c0e1c9f59ae9890b97f157ea27e69e4f0fadada4712b64#eventlog
+			RewardsFork_Panama:      "2024-10-01",
+			RewardsFork_Arno:        "2024-12-11",
+			RewardsFork_Trinity:     "2025-01-09",
+			RewardsFork_Mississippi: rewardsForkNotScheduled,
 		}, nil
 	case Chain_Holesky:
 		return ForkMap{
-			RewardsFork_Amazon:  "1970-01-01", // Amazon hard fork was never on testnet as we backfilled
-			RewardsFork_Nile:    "2024-08-13", // Last calculation end timestamp was 8-12: https://holesky.etherscan.io/tx/0x5fc81b5ed2a78b017ef313c181d8627737a97fef87eee85acedbe39fc8708c56#eventlog
-			RewardsFork_Panama:  "2024-10-01",
-			RewardsFork_Arno:    "2024-12-13",
-			RewardsFork_Trinity: "2025-01-09",
+			RewardsFork_Amazon:      "1970-01-01", // Amazon hard fork was never on testnet as we backfilled
+			RewardsFork_Nile:        "2024-08-13", // Last calculation end timestamp was 8-12: https://holesky.etherscan.io/tx/0x5fc81b5ed2a78b017ef313c181d8627737a97fef87eee85acedbe39fc8708c56#eventlog
+			RewardsFork_Panama:      "2024-10-01",
+			RewardsFork_Arno:        "2024-12-13",
+			RewardsFork_Trinity:     "2025-01-09",
+			RewardsFork_Mississippi: rewardsForkNotScheduled,
 		}, nil
 	case Chain_Mainnet:
 		return ForkMap{
-			RewardsFork_Amazon:  "2024-08-02", // Last calculation end timestamp was 8-01: https://etherscan.io/tx/0x2aff6f7b0132092c05c8f6f41a5e5eeeb208aa0d95ebcc9022d7823e343dd012#eventlog
-			RewardsFork_Nile:    "2024-08-12", // Last calculation end timestamp was 8-11: https://etherscan.io/tx/0x922d29d93c02d189fc2332041f01a80e0007cd7a625a5663ef9d30082f7ef66f#eventlog
-			RewardsFork_Panama:  "2024-10-01",
-			RewardsFork_Arno:    "2025-01-21",
-			RewardsFork_Trinity: "2025-01-21",
+			RewardsFork_Amazon:      "2024-08-02", // Last calculation end timestamp was 8-01: https://etherscan.io/tx/0x2aff6f7b0132092c05c8f6f41a5e5eeeb208aa0d95ebcc9022d7823e343dd012#eventlog
+			RewardsFork_Nile:        "2024-08-12", // Last calculation end timestamp was 8-11: https://etherscan.io/tx/0x922d29d93c02d189fc2332041f01a80e0007cd7a625a5663ef9d30082f7ef66f#eventlog
+			RewardsFork_Panama:      "2024-10-01",
+			RewardsFork_Arno:        "2025-01-21",
+			RewardsFork_Trinity:     "2025-01-21",
+			RewardsFork_Mississippi: rewardsForkNotScheduled,
 		}, nil
+	case Chain_Custom:
+		if c.ChainProfile != nil {
+			return ForkMap(c.ChainProfile.RewardsForkDates), nil
+		}
 	}
 	return nil, errors.New("unsupported chain")
 }
@@ -389,6 +616,13 @@ const (
 	// ModelFork_Austin changes the formatting for merkel leaves in: ODRewardSubmissions, OperatorAVSSplits, and
 	// OperatorPISplits based on feedback from the rewards-v2 audit
 	ModelFork_Austin ForkName = "austin"
+
+	// ModelFork_Boston adds the AllocationManager models (operator sets, allocations, max magnitudes and slashings)
+	// and the operator set rewards models to the state root, and applies slashings to operator and staker shares.
+	//
+	// Every change it makes is driven by events of the slashing release, so it applies from the EigenLayer genesis
+	// block without changing the state root of any block before the release was deployed.
+	ModelFork_Boston ForkName = "boston"
 )
 
 func (c *Config) GetModelForks() (ModelForkMap, error) {
@@ -396,23 +630,43 @@ func (c *Config) GetModelForks() (ModelForkMap, error) {
 	case Chain_Preprod:
 		return ModelForkMap{
 			ModelFork_Austin: 3113600,
+			ModelFork_Boston: 1140406,
 		}, nil
 	case Chain_Holesky:
 		return ModelForkMap{
 			ModelFork_Austin: 3113600,
+			ModelFork_Boston: 1167044,
 		}, nil
 	case Chain_Mainnet:
 		return ModelForkMap{
 			ModelFork_Austin: 0, // doesnt apply to mainnet
+			ModelFork_Boston: 17445563,
 		}, nil
+	case Chain_Custom:
+		if c.ChainProfile != nil {
+			return ModelForkMap(c.ChainProfile.ModelForks), nil
+		}
 	}
 	return nil, errors.New("unsupported chain")
 
 }
 
+// IsModelForkActive returns true if the model fork applies to the given block
+func (c *Config) IsModelForkActive(fork ForkName, blockNumber uint64) (bool, error) {
+	forks, err := c.GetModelForks()
+	if err != nil {
+		return false, err
+	}
+	forkBlock, ok := forks[fork]
+	if !ok {
+		return false, fmt.Errorf("unknown model fork '%s'", fork)
+	}
+	return blockNumber >= forkBlock, nil
+}
+
 func (c *Config) GetEigenLayerGenesisBlockHeight() (uint64, error) {
 	switch c.Chain {
-	case Chain_Preprod, Chain_Holesky:
+	case Chain_Preprod, Chain_Holesky, Chain_Custom:
 		return 1, nil
 	case Chain_Mainnet:
 		return 1, nil
@@ -428,6 +682,10 @@ func (c *Config) GetOperatorRestakedStrategiesStartBlock() uint64 {
 		return 1162800
 	case Chain_Mainnet:
 		return 19616400
+	case Chain_Custom:
+		if c.ChainProfile != nil {
+			return c.ChainProfile.OperatorRestakedStrategiesStartBlock
+		}
 	}
 	return 0
 }
@@ -449,6 +707,24 @@ func (c *Config) IsRewardsV2EnabledForCutoffDate(cutoffDate string) (bool, error
 	return cutoffDateTime.Compare(arnoForkDateTime) >= 0, nil
 }
 
+// IsRewardsV2_1EnabledForCutoffDate returns true if operator set rewards are calculated for the given cutoff date
+func (c *Config) IsRewardsV2_1EnabledForCutoffDate(cutoffDate string) (bool, error) {
+	forks, err := c.GetRewardsSqlForkDates()
+	if err != nil {
+		return false, err
+	}
+	cutoffDateTime, err := time.Parse(time.DateOnly, cutoffDate)
+	if err != nil {
+		return false, errors.Join(fmt.Errorf("failed to parse cutoff date %s", cutoffDate), err)
+	}
+	mississippiForkDateTime, err := time.Parse(time.DateOnly, forks[RewardsFork_Mississippi])
+	if err != nil {
+		return false, errors.Join(fmt.Errorf("failed to parse Mississippi fork date %s", forks[RewardsFork_Mississippi]), err)
+	}
+
+	return cutoffDateTime.Compare(mississippiForkDateTime) >= 0, nil
+}
+
 // CanIgnoreIncorrectRewardsRoot returns true if the rewards root can be ignored for the given block number
 //
 // Due to inconsistencies in the rewards root calculation on testnet, we know that some roots
@@ -475,6 +751,15 @@ func (c *Config) CanIgnoreIncorrectRewardsRoot(blockNumber uint64) bool {
 			return true
 		}
 	case Chain_Mainnet:
+	case Chain_Custom:
+		if c.ChainProfile == nil {
+			return false
+		}
+		for _, r := range c.ChainProfile.IgnorableRootRanges {
+			if r.Contains(blockNumber) {
+				return true
+			}
+		}
 	}
 	return false
 }
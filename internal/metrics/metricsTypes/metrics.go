@@ -31,6 +31,9 @@ var (
 	Metric_Incr_BlockProcessed = "blockProcessed"
 	Metric_Incr_GrpcRequest    = "rpc.grpc.request"
 	Metric_Incr_HttpRequest    = "rpc.http.request"
+	Metric_Incr_ChainReorg     = "chainReorg"
+
+	Metric_Incr_RewardsInvariantViolation = "rewards.invariants.violation"
 
 	Metric_Gauge_CurrentBlockHeight = "currentBlockHeight"
 	Metric_Gauge_SnapshotSize       = "snapshots.create.size"
@@ -58,6 +61,14 @@ var MetricTypes = map[MetricsType][]MetricsTypeConfig{
 			Name:   Metric_Incr_HttpRequest,
 			Labels: []string{},
 		},
+		MetricsTypeConfig{
+			Name:   Metric_Incr_ChainReorg,
+			Labels: []string{},
+		},
+		MetricsTypeConfig{
+			Name:   Metric_Incr_RewardsInvariantViolation,
+			Labels: []string{"invariant"},
+		},
 	},
 	MetricsType_Gauge: {
 		MetricsTypeConfig{
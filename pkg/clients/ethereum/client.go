@@ -40,6 +40,24 @@ type RPCError struct {
 	Message string `json:"message"`
 }
 
+// ErrRPCResponse is returned when an endpoint responds to a request with a JSON-RPC error
+type ErrRPCResponse struct {
+	RPCError *RPCError
+}
+
+func (e *ErrRPCResponse) Error() string {
+	return fmt.Sprintf("received error response: %+v", e.RPCError)
+}
+
+// ErrHTTPStatus is returned when an endpoint responds with a non-200 HTTP status code
+type ErrHTTPStatus struct {
+	StatusCode int
+}
+
+func (e *ErrHTTPStatus) Error() string {
+	return fmt.Sprintf("received http error code %+v", e.StatusCode)
+}
+
 type RPCResponse struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      *uint           `json:"id,omitempty"`
@@ -62,24 +80,49 @@ type Client struct {
 	Logger       *zap.Logger
 	httpClient   *http.Client
 	clientConfig *EthereumClientConfig
+	endpoints    []*Endpoint
 }
 
 type EthereumClientConfig struct {
 	BaseUrl              string
-	UseNativeBatchCall   bool // Use the native eth_call method for batch calls
-	NativeBatchCallSize  int  // Number of calls to put in a single eth_call request
-	ChunkedBatchCallSize int  // Number of calls to make in parallel
+	Endpoints            []string // Additional endpoints in the form "<url>[|<weight>[|<requestsPerSecond>]]"
+	RequestsPerSecond    float64  // Default per-endpoint requests per second limit. 0 means unlimited
+	UseNativeBatchCall   bool     // Use the native eth_call method for batch calls
+	NativeBatchCallSize  int      // Number of calls to put in a single eth_call request
+	ChunkedBatchCallSize int      // Number of calls to make in parallel
 }
 
 func ConvertGlobalConfigToEthereumConfig(cfg *config.EthereumRpcConfig) *EthereumClientConfig {
 	return &EthereumClientConfig{
 		BaseUrl:              cfg.BaseUrl,
+		Endpoints:            cfg.Endpoints,
+		RequestsPerSecond:    cfg.RequestsPerSecond,
 		UseNativeBatchCall:   cfg.UseNativeBatchCall,
 		NativeBatchCallSize:  cfg.NativeBatchCallSize,
 		ChunkedBatchCallSize: cfg.ChunkedBatchCallSize,
 	}
 }
 
+// GetEndpointConfigs returns the BaseUrl (if set) followed by each of the configured Endpoints.
+func (cfg *EthereumClientConfig) GetEndpointConfigs() ([]*EndpointConfig, error) {
+	endpoints := make([]*EndpointConfig, 0, len(cfg.Endpoints)+1)
+	if cfg.BaseUrl != "" {
+		endpoints = append(endpoints, &EndpointConfig{
+			Url:               cfg.BaseUrl,
+			Weight:            1,
+			RequestsPerSecond: cfg.RequestsPerSecond,
+		})
+	}
+	for _, e := range cfg.Endpoints {
+		ec, err := ParseEndpointConfig(e, cfg.RequestsPerSecond)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, ec)
+	}
+	return endpoints, nil
+}
+
 func DefaultNativeCallEthereumClientConfig() *EthereumClientConfig {
 	return &EthereumClientConfig{
 		UseNativeBatchCall:   true,
@@ -103,10 +146,20 @@ func NewClient(cfg *EthereumClientConfig, l *zap.Logger) *Client {
 
 	l.Sugar().Infow("Creating new Ethereum client", zap.Any("config", cfg))
 
+	endpointConfigs, err := cfg.GetEndpointConfigs()
+	if err != nil {
+		l.Sugar().Fatalw("Failed to parse Ethereum RPC endpoints", zap.Error(err))
+	}
+	endpoints := make([]*Endpoint, 0, len(endpointConfigs))
+	for _, ec := range endpointConfigs {
+		endpoints = append(endpoints, NewEndpoint(ec))
+	}
+
 	return &Client{
 		httpClient:   client,
 		Logger:       l,
 		clientConfig: cfg,
+		endpoints:    endpoints,
 	}
 }
 
@@ -115,7 +168,7 @@ func (c *Client) SetHttpClient(client *http.Client) {
 }
 
 func (c *Client) GetEthereumContractCaller() (*ethclient.Client, error) {
-	d, err := ethclient.Dial(c.clientConfig.BaseUrl)
+	d, err := ethclient.Dial(c.getPrimaryEndpointUrl())
 	if err != nil {
 		c.Logger.Sugar().Error("Failed to create new eth client", zap.Error(err))
 		return nil, err
@@ -123,6 +176,21 @@ func (c *Client) GetEthereumContractCaller() (*ethclient.Client, error) {
 	return d, nil
 }
 
+// getPrimaryEndpointUrl returns the url of the endpoint with the highest weight, for use with
+// clients that can only talk to a single endpoint.
+func (c *Client) getPrimaryEndpointUrl() string {
+	if len(c.endpoints) == 0 {
+		return c.clientConfig.BaseUrl
+	}
+	primary := c.endpoints[0]
+	for _, e := range c.endpoints[1:] {
+		if e.Weight > primary.Weight {
+			primary = e
+		}
+	}
+	return primary.Url
+}
+
 func (c *Client) ListenForNewBlocks(
 	ctx context.Context,
 	wsc *ethclient.Client,
@@ -248,6 +316,48 @@ func (c *Client) GetTransactionReceipt(ctx context.Context, txHash string) (*Eth
 	return txReceipt, nil
 }
 
+// GetLogs returns the logs emitted by the given addresses in the inclusive block range.
+//
+// Unlike most other methods, GetLogs does not retry with a backoff. Requests that fail because of the
+// endpoint, such as timeouts and 5xx responses, fail over to the next endpoint, but nodes commonly reject
+// eth_getLogs queries that span too many blocks or return too many results. Those errors are returned as is
+// so the caller can fall back to a different strategy rather than wait on the same request.
+func (c *Client) GetLogs(ctx context.Context, addresses []string, fromBlock uint64, toBlock uint64) ([]*EthereumEventLog, error) {
+	rpcRequest := GetLogsRequest(addresses, fromBlock, toBlock, 1)
+
+	res, err := c.call(ctx, rpcRequest)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := RPCMethod_getLogs.ResponseParser(res.Result)
+	if err != nil {
+		c.Logger.Sugar().Errorw("failed to parse logs",
+			zap.Error(err),
+			zap.Any("raw response", res.Result),
+		)
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (c *Client) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]*EthereumTransactionReceipt, error) {
+	rpcRequest := GetBlockReceiptsRequest(blockNumber, 1)
+
+	res, err := c.Call(ctx, rpcRequest)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := RPCMethod_getBlockReceipts.ResponseParser(res.Result)
+	if err != nil {
+		c.Logger.Sugar().Errorw("failed to parse block receipts",
+			zap.Error(err),
+			zap.Any("raw response", res.Result),
+		)
+		return nil, err
+	}
+	return receipts, nil
+}
+
 func (c *Client) GetStorageAt(ctx context.Context, address string, storagePosition string, block string) (string, error) {
 	rpcRequest := GetStorageAtRequest(address, storagePosition, block, 1)
 
@@ -289,10 +399,34 @@ type BatchRPCRequest[T any] struct {
 	Handler ResponseParserFunc[T]
 }
 
+// batchCall sends the requests as a single native batch, failing over to another endpoint if the batch fails
+// or if any of the individual responses indicate the endpoint is rate limiting or missing state.
 func (c *Client) batchCall(ctx context.Context, requests []*RPCRequest) ([]*RPCResponse, error) {
 	if len(requests) == 0 {
 		return make([]*RPCResponse, 0), nil
 	}
+	var results []*RPCResponse
+	err := c.withFailover(ctx, len(requests), func(e *Endpoint) error {
+		res, err := c.batchCallEndpoint(ctx, e, requests)
+		if err != nil {
+			return err
+		}
+		for _, r := range res {
+			if r.Error == nil {
+				continue
+			}
+			rpcErr := &ErrRPCResponse{RPCError: r.Error}
+			if classifyError(rpcErr) != FailoverReason_Error {
+				return rpcErr
+			}
+		}
+		results = res
+		return nil
+	})
+	return results, err
+}
+
+func (c *Client) batchCallEndpoint(ctx context.Context, e *Endpoint, requests []*RPCRequest) ([]*RPCResponse, error) {
 	requestBody, err := json.Marshal(requests)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to marshal requests: %s", err)
@@ -301,7 +435,7 @@ func (c *Client) batchCall(ctx context.Context, requests []*RPCRequest) ([]*RPCR
 	ctx, cancel := context.WithTimeout(ctx, time.Second*20)
 	defer cancel()
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.clientConfig.BaseUrl, bytes.NewReader(requestBody))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to make request: %s", err)
 	}
@@ -320,7 +454,7 @@ func (c *Client) batchCall(ctx context.Context, requests []*RPCRequest) ([]*RPCR
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received http error code %+v", response.StatusCode)
+		return nil, &ErrHTTPStatus{StatusCode: response.StatusCode}
 	}
 
 	destination := []*RPCResponse{}
@@ -519,7 +653,21 @@ func (c *Client) BatchCall(ctx context.Context, requests []*RPCRequest) ([]*RPCR
 	return c.chunkedBatchCall(ctx, requests)
 }
 
+// call sends a single request, failing over to another endpoint if the request fails because of the endpoint.
 func (c *Client) call(ctx context.Context, rpcRequest *RPCRequest) (*RPCResponse, error) {
+	var result *RPCResponse
+	err := c.withFailover(ctx, 1, func(e *Endpoint) error {
+		res, err := c.callEndpoint(ctx, e, rpcRequest)
+		if err != nil {
+			return err
+		}
+		result = res
+		return nil
+	})
+	return result, err
+}
+
+func (c *Client) callEndpoint(ctx context.Context, e *Endpoint, rpcRequest *RPCRequest) (*RPCResponse, error) {
 	requestBody, err := json.Marshal(rpcRequest)
 
 	c.Logger.Sugar().Debug("Request body", zap.String("requestBody", string(requestBody)))
@@ -530,7 +678,7 @@ func (c *Client) call(ctx context.Context, rpcRequest *RPCRequest) (*RPCResponse
 	ctx, cancel := context.WithTimeout(ctx, RPCMethod_GetBlock.RequestMethod.Timeout)
 	defer cancel()
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.clientConfig.BaseUrl, bytes.NewReader(requestBody))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to make request %s", err)
 	}
@@ -548,7 +696,7 @@ func (c *Client) call(ctx context.Context, rpcRequest *RPCRequest) (*RPCResponse
 		return nil, fmt.Errorf("Failed to read body %s", err)
 	}
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received http error code %+v", response.StatusCode)
+		return nil, &ErrHTTPStatus{StatusCode: response.StatusCode}
 	}
 
 	destination := &RPCResponse{}
@@ -557,7 +705,7 @@ func (c *Client) call(ctx context.Context, rpcRequest *RPCRequest) (*RPCResponse
 	}
 
 	if destination.Error != nil {
-		return nil, fmt.Errorf("received error response: %+v", destination.Error)
+		return nil, &ErrRPCResponse{RPCError: destination.Error}
 	}
 
 	response.Body.Close()
@@ -0,0 +1,344 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EndpointConfig describes a single Ethereum RPC endpoint that the client can send requests to.
+type EndpointConfig struct {
+	Url               string
+	Weight            float64 // Relative share of requests this endpoint should receive when healthy
+	RequestsPerSecond float64 // Maximum requests per second to send to this endpoint. 0 means unlimited
+}
+
+// ParseEndpointConfig parses an endpoint in the form "<url>[|<weight>[|<requestsPerSecond>]]".
+//
+// If the weight is omitted it defaults to 1. If the requests per second is omitted, defaultRequestsPerSecond is used.
+func ParseEndpointConfig(s string, defaultRequestsPerSecond float64) (*EndpointConfig, error) {
+	parts := strings.Split(strings.TrimSpace(s), "|")
+	if len(parts) > 3 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid endpoint '%s'; expected <url>[|<weight>[|<requestsPerSecond>]]", s)
+	}
+	ec := &EndpointConfig{
+		Url:               parts[0],
+		Weight:            1,
+		RequestsPerSecond: defaultRequestsPerSecond,
+	}
+	if len(parts) > 1 {
+		weight, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid weight '%s' for endpoint '%s'; must be a positive number", parts[1], parts[0])
+		}
+		ec.Weight = weight
+	}
+	if len(parts) > 2 {
+		rps, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || rps < 0 {
+			return nil, fmt.Errorf("invalid requests per second '%s' for endpoint '%s'; must be zero or a positive number", parts[2], parts[0])
+		}
+		ec.RequestsPerSecond = rps
+	}
+	return ec, nil
+}
+
+type FailoverReason string
+
+const (
+	FailoverReason_Error           FailoverReason = "error"
+	FailoverReason_RateLimited     FailoverReason = "rate_limited"
+	FailoverReason_MissingTrieNode FailoverReason = "missing_trie_node"
+)
+
+// classifyError determines why a request to an endpoint failed so that the endpoint can be penalized accordingly.
+func classifyError(err error) FailoverReason {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "missing trie node"):
+		return FailoverReason_MissingTrieNode
+	case strings.Contains(msg, "http error code 429"),
+		strings.Contains(msg, "rate limit"),
+		strings.Contains(msg, "too many requests"),
+		strings.Contains(msg, "-32005"):
+		return FailoverReason_RateLimited
+	default:
+		return FailoverReason_Error
+	}
+}
+
+// shouldFailover returns true if the request failed because of the endpoint rather than the request itself.
+//
+// Transport errors, timeouts, 5xx responses, rate limiting and missing state are specific to the endpoint and
+// are worth retrying elsewhere. Other JSON-RPC errors, such as reverts and invalid params, and other 4xx
+// responses would fail the same way on every endpoint.
+func shouldFailover(err error) bool {
+	if classifyError(err) != FailoverReason_Error {
+		return true
+	}
+	var statusErr *ErrHTTPStatus
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var rpcErr *ErrRPCResponse
+	return !errors.As(err, &rpcErr)
+}
+
+const (
+	// Smoothing factor for the moving averages of error rate and latency
+	healthEwmaAlpha = 0.2
+
+	// Floor for the health score so that an unhealthy endpoint is still occasionally retried and can recover
+	minHealthScore = 0.01
+
+	rateLimitCooldown    = 5 * time.Second
+	maxRateLimitCooldown = 60 * time.Second
+)
+
+// Endpoint is a single RPC endpoint along with its rate limiter and health statistics.
+type Endpoint struct {
+	Url     string
+	Weight  float64
+	limiter *rateLimiter
+
+	mu             sync.Mutex
+	totalRequests  uint64
+	totalErrors    uint64
+	errorRate      float64 // exponentially weighted moving average of failed requests, between 0 and 1
+	avgLatency     float64 // exponentially weighted moving average of request latency in milliseconds
+	cooldownUntil  time.Time
+	cooldownPeriod time.Duration
+}
+
+func NewEndpoint(cfg *EndpointConfig) *Endpoint {
+	return &Endpoint{
+		Url:     cfg.Url,
+		Weight:  cfg.Weight,
+		limiter: newRateLimiter(cfg.RequestsPerSecond),
+	}
+}
+
+// EndpointStats is a point-in-time snapshot of an endpoint's health.
+type EndpointStats struct {
+	Url           string
+	Weight        float64
+	TotalRequests uint64
+	TotalErrors   uint64
+	ErrorRate     float64
+	AvgLatencyMs  float64
+	HealthScore   float64
+	CoolingDown   bool
+}
+
+func (e *Endpoint) Stats() EndpointStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return EndpointStats{
+		Url:           e.Url,
+		Weight:        e.Weight,
+		TotalRequests: e.totalRequests,
+		TotalErrors:   e.totalErrors,
+		ErrorRate:     e.errorRate,
+		AvgLatencyMs:  e.avgLatency,
+		HealthScore:   e.healthScore(),
+		CoolingDown:   time.Now().Before(e.cooldownUntil),
+	}
+}
+
+// healthScore combines the configured weight with the observed error rate and latency.
+// An endpoint with no errors and low latency scores close to its weight. Caller must hold the lock.
+func (e *Endpoint) healthScore() float64 {
+	score := (1 - e.errorRate) / (1 + e.avgLatency/1000)
+	return e.Weight * math.Max(score, minHealthScore)
+}
+
+func (e *Endpoint) isCoolingDown(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return now.Before(e.cooldownUntil)
+}
+
+func (e *Endpoint) recordResult(latency time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.totalRequests++
+	e.avgLatency = healthEwmaAlpha*float64(latency.Milliseconds()) + (1-healthEwmaAlpha)*e.avgLatency
+
+	if err == nil {
+		e.errorRate = (1 - healthEwmaAlpha) * e.errorRate
+		e.cooldownPeriod = 0
+		return
+	}
+	e.totalErrors++
+	e.errorRate = healthEwmaAlpha + (1-healthEwmaAlpha)*e.errorRate
+
+	if classifyError(err) == FailoverReason_RateLimited {
+		// back off from endpoints that are rate limiting us, doubling the cooldown each consecutive time
+		if e.cooldownPeriod == 0 {
+			e.cooldownPeriod = rateLimitCooldown
+		} else {
+			e.cooldownPeriod = min(e.cooldownPeriod*2, maxRateLimitCooldown)
+		}
+		e.cooldownUntil = time.Now().Add(e.cooldownPeriod)
+	}
+}
+
+// selectEndpoint picks an endpoint that has not been tried yet using a weighted random choice based on
+// each endpoint's health score. Endpoints that are cooling down after being rate limited are skipped
+// unless there is nothing else left to try.
+func (c *Client) selectEndpoint(tried map[*Endpoint]bool) *Endpoint {
+	now := time.Now()
+	candidates := make([]*Endpoint, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		if !tried[e] && !e.isCoolingDown(now) {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, e := range c.endpoints {
+			if !tried[e] {
+				candidates = append(candidates, e)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	scores := make([]float64, len(candidates))
+	total := float64(0)
+	for i, e := range candidates {
+		e.mu.Lock()
+		scores[i] = e.healthScore()
+		e.mu.Unlock()
+		total += scores[i]
+	}
+	target := rand.Float64() * total
+	for i, e := range candidates {
+		target -= scores[i]
+		if target <= 0 {
+			return e
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// withFailover runs fn against endpoints until it succeeds or every endpoint has been tried once.
+//
+// Errors that are not caused by the endpoint are returned without failing over or penalizing the endpoint.
+func (c *Client) withFailover(ctx context.Context, weight int, fn func(e *Endpoint) error) error {
+	tried := make(map[*Endpoint]bool, len(c.endpoints))
+	var lastErr error
+
+	for {
+		e := c.selectEndpoint(tried)
+		if e == nil {
+			break
+		}
+		tried[e] = true
+
+		if err := e.limiter.Wait(ctx, weight); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		err := fn(e)
+		if err != nil && (ctx.Err() != nil || !shouldFailover(err)) {
+			// The endpoint responded (or the caller gave up), so it shouldn't count against its health
+			e.recordResult(time.Since(start), nil)
+			return err
+		}
+		e.recordResult(time.Since(start), err)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if len(tried) < len(c.endpoints) {
+			c.Logger.Sugar().Warnw("Request to endpoint failed, failing over",
+				zap.String("endpoint", e.Url),
+				zap.String("reason", string(classifyError(err))),
+				zap.Error(err),
+			)
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no ethereum endpoints configured")
+	}
+	return lastErr
+}
+
+// GetEndpointStats returns the current health statistics for each configured endpoint.
+func (c *Client) GetEndpointStats() []EndpointStats {
+	stats := make([]EndpointStats, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		stats = append(stats, e.Stats())
+	}
+	return stats
+}
+
+// rateLimiter is a token bucket that allows requests to borrow against future tokens, so that a
+// single batch larger than the bucket still goes through once its share of time has passed.
+type rateLimiter struct {
+	mu                sync.Mutex
+	requestsPerSecond float64
+	burst             float64
+	tokens            float64
+	lastRefill        time.Time
+}
+
+// newRateLimiter returns nil when requestsPerSecond is 0, which disables rate limiting.
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	burst := math.Max(requestsPerSecond, 1)
+	return &rateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		tokens:            burst,
+		lastRefill:        time.Now(),
+	}
+}
+
+// Wait blocks until n requests are allowed to be sent, or the context is cancelled.
+func (r *rateLimiter) Wait(ctx context.Context, n int) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	now := time.Now()
+	r.tokens = math.Min(r.burst, r.tokens+now.Sub(r.lastRefill).Seconds()*r.requestsPerSecond)
+	r.lastRefill = now
+	r.tokens -= float64(n)
+
+	var wait time.Duration
+	if r.tokens < 0 {
+		wait = time.Duration(-r.tokens / r.requestsPerSecond * float64(time.Second))
+	}
+	r.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
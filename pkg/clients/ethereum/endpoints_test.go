@@ -0,0 +1,137 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Endpoints(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+
+	t.Run("Should parse endpoint configs", func(t *testing.T) {
+		ec, err := ParseEndpointConfig("http://localhost:8545", 10)
+		assert.Nil(t, err)
+		assert.Equal(t, "http://localhost:8545", ec.Url)
+		assert.Equal(t, float64(1), ec.Weight)
+		assert.Equal(t, float64(10), ec.RequestsPerSecond)
+
+		ec, err = ParseEndpointConfig("http://localhost:8545|3|25", 10)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(3), ec.Weight)
+		assert.Equal(t, float64(25), ec.RequestsPerSecond)
+
+		_, err = ParseEndpointConfig("http://localhost:8545|0", 10)
+		assert.NotNil(t, err)
+
+		_, err = ParseEndpointConfig("http://localhost:8545|1|2|3", 10)
+		assert.NotNil(t, err)
+	})
+	t.Run("Should classify errors", func(t *testing.T) {
+		assert.Equal(t, FailoverReason_RateLimited, classifyError(errors.New("received http error code 429")))
+		assert.Equal(t, FailoverReason_RateLimited, classifyError(errors.New("received error response: &{Code:-32005 Message:limit exceeded}")))
+		assert.Equal(t, FailoverReason_MissingTrieNode, classifyError(errors.New("received error response: &{Code:-32000 Message:missing trie node abc}")))
+		assert.Equal(t, FailoverReason_Error, classifyError(errors.New("Request failed EOF")))
+	})
+	t.Run("Should only fail over on errors caused by the endpoint", func(t *testing.T) {
+		assert.True(t, shouldFailover(errors.New("Request failed EOF")))
+		assert.True(t, shouldFailover(&ErrHTTPStatus{StatusCode: http.StatusBadGateway}))
+		assert.True(t, shouldFailover(&ErrHTTPStatus{StatusCode: http.StatusTooManyRequests}))
+		assert.True(t, shouldFailover(&ErrRPCResponse{RPCError: &RPCError{Code: -32005, Message: "limit exceeded"}}))
+		assert.True(t, shouldFailover(&ErrRPCResponse{RPCError: &RPCError{Code: -32000, Message: "missing trie node abc"}}))
+
+		assert.False(t, shouldFailover(&ErrHTTPStatus{StatusCode: http.StatusBadRequest}))
+		assert.False(t, shouldFailover(&ErrRPCResponse{RPCError: &RPCError{Code: 3, Message: "execution reverted"}}))
+		assert.False(t, shouldFailover(&ErrRPCResponse{RPCError: &RPCError{Code: -32602, Message: "invalid params"}}))
+	})
+	t.Run("Should not fail over or penalize an endpoint for a reverted call", func(t *testing.T) {
+		hits := atomic.Int64{}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}}`))
+		})
+		first := httptest.NewServer(handler)
+		defer first.Close()
+		second := httptest.NewServer(handler)
+		defer second.Close()
+
+		cfg := DefaultNativeCallEthereumClientConfig()
+		cfg.BaseUrl = first.URL
+		cfg.Endpoints = []string{second.URL}
+		client := NewClient(cfg, l)
+
+		_, err := client.call(context.Background(), GetBlockRequest(1))
+		assert.NotNil(t, err)
+
+		var rpcErr *ErrRPCResponse
+		assert.True(t, errors.As(err, &rpcErr))
+		assert.Equal(t, int64(3), rpcErr.RPCError.Code)
+		assert.Equal(t, int64(1), hits.Load())
+
+		for _, stats := range client.GetEndpointStats() {
+			assert.Equal(t, uint64(0), stats.TotalErrors)
+		}
+	})
+	t.Run("Should fail over on a 5xx response", func(t *testing.T) {
+		unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer unavailable.Close()
+
+		healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		}))
+		defer healthy.Close()
+
+		cfg := DefaultNativeCallEthereumClientConfig()
+		cfg.BaseUrl = unavailable.URL
+		cfg.Endpoints = []string{healthy.URL}
+		client := NewClient(cfg, l)
+
+		for i := 0; i < 5; i++ {
+			res, err := client.call(context.Background(), GetBlockRequest(1))
+			assert.Nil(t, err)
+			assert.Equal(t, `"0x10"`, string(res.Result))
+		}
+		assert.Equal(t, uint64(5), client.GetEndpointStats()[1].TotalRequests)
+	})
+	t.Run("Should fail over to a healthy endpoint when rate limited", func(t *testing.T) {
+		rateLimited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer rateLimited.Close()
+
+		healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		}))
+		defer healthy.Close()
+
+		cfg := DefaultNativeCallEthereumClientConfig()
+		cfg.BaseUrl = rateLimited.URL
+		cfg.Endpoints = []string{healthy.URL}
+		client := NewClient(cfg, l)
+
+		for i := 0; i < 5; i++ {
+			blockNumber, err := client.GetBlockNumberUint64(context.Background())
+			assert.Nil(t, err)
+			assert.Equal(t, uint64(16), blockNumber)
+		}
+
+		stats := client.GetEndpointStats()
+		assert.Equal(t, 2, len(stats))
+		assert.Equal(t, rateLimited.URL, stats[0].Url)
+		assert.True(t, stats[0].TotalErrors > 0)
+		assert.True(t, stats[0].CoolingDown)
+		assert.Equal(t, uint64(5), stats[1].TotalRequests)
+		assert.Equal(t, uint64(0), stats[1].TotalErrors)
+	})
+}
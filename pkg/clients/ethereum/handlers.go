@@ -67,6 +67,34 @@ var (
 			return receipt, nil
 		},
 	}
+	RPCMethod_getLogs = &RequestResponseHandler[[]*EthereumEventLog]{
+		RequestMethod: &RequestMethod{
+			Name:    "eth_getLogs",
+			Timeout: time.Second * 5,
+		},
+		ResponseParser: func(res json.RawMessage) ([]*EthereumEventLog, error) {
+			logs := make([]*EthereumEventLog, 0)
+
+			if err := json.Unmarshal(res, &logs); err != nil {
+				return nil, err
+			}
+			return logs, nil
+		},
+	}
+	RPCMethod_getBlockReceipts = &RequestResponseHandler[[]*EthereumTransactionReceipt]{
+		RequestMethod: &RequestMethod{
+			Name:    "eth_getBlockReceipts",
+			Timeout: time.Second * 5,
+		},
+		ResponseParser: func(res json.RawMessage) ([]*EthereumTransactionReceipt, error) {
+			receipts := make([]*EthereumTransactionReceipt, 0)
+
+			if err := json.Unmarshal(res, &receipts); err != nil {
+				return nil, err
+			}
+			return receipts, nil
+		},
+	}
 	RPCMethod_getStorageAt = &RequestResponseHandler[string]{
 		RequestMethod: &RequestMethod{
 			Name:    "eth_getStorageAt",
@@ -134,6 +162,34 @@ func GetTransactionReceiptRequest(txHash string, id uint) *RPCRequest {
 	}
 }
 
+// https://docs.infura.io/api/networks/ethereum/json-rpc-methods/eth_getlogs
+// GetLogsRequest returns all logs emitted by the given addresses in the inclusive block range
+func GetLogsRequest(addresses []string, fromBlock uint64, toBlock uint64, id uint) *RPCRequest {
+	return &RPCRequest{
+		JSONRPC: jsonRPCVersion,
+		Method:  RPCMethod_getLogs.RequestMethod.Name,
+		Params: []interface{}{
+			map[string]interface{}{
+				"fromBlock": hexutil.EncodeUint64(fromBlock),
+				"toBlock":   hexutil.EncodeUint64(toBlock),
+				"address":   addresses,
+			},
+		},
+		ID: id,
+	}
+}
+
+// https://docs.infura.io/api/networks/ethereum/json-rpc-methods/eth_getblockreceipts
+// GetBlockReceiptsRequest returns the receipts of all transactions in the given block
+func GetBlockReceiptsRequest(blockNumber uint64, id uint) *RPCRequest {
+	return &RPCRequest{
+		JSONRPC: jsonRPCVersion,
+		Method:  RPCMethod_getBlockReceipts.RequestMethod.Name,
+		Params:  []interface{}{hexutil.EncodeUint64(blockNumber)},
+		ID:      id,
+	}
+}
+
 // https://docs.infura.io/api/networks/ethereum/json-rpc-methods/eth_getstorageat
 // GetStorageAt gets the value stored at the given position and block of an address
 //
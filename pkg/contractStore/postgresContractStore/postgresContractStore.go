@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/Layr-Labs/sidecar/pkg/contractStore"
 	"github.com/Layr-Labs/sidecar/pkg/postgres/helpers"
+	"os"
 	"strings"
 
 	"github.com/Layr-Labs/sidecar/internal/config"
@@ -236,18 +237,16 @@ func (s *PostgresContractStore) SetContractCheckedForProxy(address string) (*con
 }
 
 func (s *PostgresContractStore) loadContractData() (*contractStore.CoreContractsData, error) {
-	var filename string
-	switch s.globalConfig.Chain {
-	case config.Chain_Mainnet:
-		filename = "mainnet.json"
-	case config.Chain_Holesky:
-		filename = "testnet.json"
-	case config.Chain_Preprod:
-		filename = "preprod.json"
-	default:
-		return nil, fmt.Errorf("Unknown environment.")
+	var jsonData []byte
+	var err error
+	if s.globalConfig.Chain == config.Chain_Custom {
+		if s.globalConfig.ChainProfile == nil {
+			return nil, fmt.Errorf("No chain profile loaded for the custom chain.")
+		}
+		jsonData, err = os.ReadFile(s.globalConfig.ChainProfile.CoreContractsFile)
+	} else {
+		jsonData, err = readEmbeddedContractData(s.globalConfig.Chain)
 	}
-	jsonData, err := contractStore.CoreContracts.ReadFile(fmt.Sprintf("coreContracts/%s", filename))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to open core contracts file: %w", err)
 	}
@@ -261,6 +260,21 @@ func (s *PostgresContractStore) loadContractData() (*contractStore.CoreContracts
 	return data, nil
 }
 
+func readEmbeddedContractData(chain config.Chain) ([]byte, error) {
+	var filename string
+	switch chain {
+	case config.Chain_Mainnet:
+		filename = "mainnet.json"
+	case config.Chain_Holesky:
+		filename = "testnet.json"
+	case config.Chain_Preprod:
+		filename = "preprod.json"
+	default:
+		return nil, fmt.Errorf("Unknown environment.")
+	}
+	return contractStore.CoreContracts.ReadFile(fmt.Sprintf("coreContracts/%s", filename))
+}
+
 func (s *PostgresContractStore) InitializeCoreContracts() error {
 	coreContracts, err := s.loadContractData()
 	if err != nil {
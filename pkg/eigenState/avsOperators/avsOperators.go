@@ -192,7 +192,7 @@ func (a *AvsOperatorsModel) prepareState(blockNumber uint64) ([]*AvsOperatorStat
 	return accumulatedState, nil
 }
 
-func (a *AvsOperatorsModel) writeDeltaRecords(blockNumber uint64) error {
+func (a *AvsOperatorsModel) writeDeltaRecords(blockNumber uint64, tx *gorm.DB) error {
 	records, ok := a.stateAccumulator[blockNumber]
 	if !ok {
 		msg := "delta accumulator was not initialized"
@@ -201,7 +201,7 @@ func (a *AvsOperatorsModel) writeDeltaRecords(blockNumber uint64) error {
 	}
 
 	if len(records) > 0 {
-		res := a.DB.Model(&AvsOperatorStateChange{}).Clauses(clause.Returning{}).Create(&records)
+		res := tx.Model(&AvsOperatorStateChange{}).Clauses(clause.Returning{}).Create(&records)
 		if res.Error != nil {
 			a.logger.Sugar().Errorw("Failed to insert delta records", zap.Error(res.Error))
 			return res.Error
@@ -212,8 +212,8 @@ func (a *AvsOperatorsModel) writeDeltaRecords(blockNumber uint64) error {
 }
 
 // CommitFinalState commits the final state for the given block number.
-func (a *AvsOperatorsModel) CommitFinalState(blockNumber uint64) error {
-	if err := a.writeDeltaRecords(blockNumber); err != nil {
+func (a *AvsOperatorsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	if err := a.writeDeltaRecords(blockNumber, tx); err != nil {
 		return err
 	}
 
@@ -230,14 +230,21 @@ func (a *AvsOperatorsModel) GetCommittedState(blockNumber uint64) ([]interface{}
 	return base.CastCommittedStateToInterface(records), nil
 }
 
-// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
-func (a *AvsOperatorsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (a *AvsOperatorsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	deltas, err := a.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return a.sortValuesForMerkleTree(deltas), nil
+}
 
-	inputs := a.sortValuesForMerkleTree(deltas)
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (a *AvsOperatorsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := a.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(inputs) == 0 {
 		return nil, nil
@@ -269,8 +276,8 @@ func (a *AvsOperatorsModel) sortValuesForMerkleTree(deltas []*AvsOperatorStateCh
 	return inputs
 }
 
-func (a *AvsOperatorsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return a.BaseEigenState.DeleteState("avs_operator_state_changes", startBlockNumber, endBlockNumber, a.DB)
+func (a *AvsOperatorsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return a.BaseEigenState.DeleteState("avs_operator_state_changes", startBlockNumber, endBlockNumber, tx)
 }
 
 func (a *AvsOperatorsModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
@@ -121,7 +121,7 @@ func Test_AvsOperatorsIntegration(t *testing.T) {
 				}
 			}
 
-			if err := model.CommitFinalState(i); err != nil {
+			if err := model.CommitFinalState(i, grm); err != nil {
 				t.Logf("Failed to commit final state for block %d", i)
 				t.Fatal(err)
 			}
@@ -100,10 +100,7 @@ func (b *BaseEigenState) DeleteState(tableName string, startBlockNumber uint64,
 	return nil
 }
 
-type MerkleTreeInput struct {
-	SlotID types.SlotID
-	Value  []byte
-}
+type MerkleTreeInput = types.MerkleTreeInput
 
 // MerkleizeEigenState creates a merkle tree from the given inputs.
 //
@@ -128,7 +125,7 @@ func (b *BaseEigenState) MerkleizeEigenState(blockNumber uint64, inputs []*Merkl
 
 	leaves := b.InitializeMerkleTreeBaseStateWithBlock(blockNumber)
 	for rootIndex := om.Oldest(); rootIndex != nil; rootIndex = rootIndex.Next() {
-		leaves = append(leaves, encodeMerkleLeaf(rootIndex.Key, rootIndex.Value))
+		leaves = append(leaves, EncodeMerkleLeaf(rootIndex.Key, rootIndex.Value))
 	}
 	return merkletree.NewTree(
 		merkletree.WithData(leaves),
@@ -136,7 +133,8 @@ func (b *BaseEigenState) MerkleizeEigenState(blockNumber uint64, inputs []*Merkl
 	)
 }
 
-func encodeMerkleLeaf(slotID types.SlotID, value []byte) []byte {
+// EncodeMerkleLeaf encodes a state change as it is included in a model's state tree.
+func EncodeMerkleLeaf(slotID types.SlotID, value []byte) []byte {
 	return append(types.MerkleLeafPrefix_EigenStateChange, append([]byte(slotID), value...)...)
 }
 
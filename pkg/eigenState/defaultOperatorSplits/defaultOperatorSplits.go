@@ -198,7 +198,7 @@ func (dos *DefaultOperatorSplitModel) prepareState(blockNumber uint64) ([]*Defau
 }
 
 // CommitFinalState commits the final state for the given block number.
-func (dos *DefaultOperatorSplitModel) CommitFinalState(blockNumber uint64) error {
+func (dos *DefaultOperatorSplitModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	recordsToInsert, err := dos.prepareState(blockNumber)
 	if err != nil {
 		return err
@@ -206,7 +206,7 @@ func (dos *DefaultOperatorSplitModel) CommitFinalState(blockNumber uint64) error
 
 	if len(recordsToInsert) > 0 {
 		for _, record := range recordsToInsert {
-			res := dos.DB.Model(&DefaultOperatorSplit{}).Clauses(clause.Returning{}).Create(&record)
+			res := tx.Model(&DefaultOperatorSplit{}).Clauses(clause.Returning{}).Create(&record)
 			if res.Error != nil {
 				dos.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
 				return res.Error
@@ -217,14 +217,21 @@ func (dos *DefaultOperatorSplitModel) CommitFinalState(blockNumber uint64) error
 	return nil
 }
 
-// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
-func (dos *DefaultOperatorSplitModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (dos *DefaultOperatorSplitModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	inserts, err := dos.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return dos.sortValuesForMerkleTree(inserts), nil
+}
 
-	inputs := dos.sortValuesForMerkleTree(inserts)
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (dos *DefaultOperatorSplitModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := dos.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(inputs) == 0 {
 		return nil, nil
@@ -270,8 +277,8 @@ func (dos *DefaultOperatorSplitModel) sortValuesForMerkleTree(splits []*DefaultO
 	return inputs
 }
 
-func (dos *DefaultOperatorSplitModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return dos.BaseEigenState.DeleteState("default_operator_splits", startBlockNumber, endBlockNumber, dos.DB)
+func (dos *DefaultOperatorSplitModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return dos.BaseEigenState.DeleteState("default_operator_splits", startBlockNumber, endBlockNumber, tx)
 }
 
 func (dos *DefaultOperatorSplitModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
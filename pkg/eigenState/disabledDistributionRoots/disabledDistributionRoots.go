@@ -166,14 +166,14 @@ func (ddr *DisabledDistributionRootsModel) prepareState(blockNumber uint64) ([]*
 	return preparedState, nil
 }
 
-func (ddr *DisabledDistributionRootsModel) CommitFinalState(blockNumber uint64) error {
+func (ddr *DisabledDistributionRootsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	records, err := ddr.prepareState(blockNumber)
 	if err != nil {
 		return err
 	}
 
 	if len(records) > 0 {
-		res := ddr.DB.Model(&types.DisabledDistributionRoot{}).Clauses(clause.Returning{}).Create(&records)
+		res := tx.Model(&types.DisabledDistributionRoot{}).Clauses(clause.Returning{}).Create(&records)
 		if res.Error != nil {
 			ddr.logger.Sugar().Errorw("Failed to create new submitted_distribution_roots records", zap.Error(res.Error))
 			return res.Error
@@ -208,13 +208,20 @@ func (ddr *DisabledDistributionRootsModel) sortValuesForMerkleTree(inputs []*typ
 	return values
 }
 
-func (ddr *DisabledDistributionRootsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (ddr *DisabledDistributionRootsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	diffs, err := ddr.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return ddr.sortValuesForMerkleTree(diffs), nil
+}
 
-	sortedInputs := ddr.sortValuesForMerkleTree(diffs)
+func (ddr *DisabledDistributionRootsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	sortedInputs, err := ddr.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(sortedInputs) == 0 {
 		return nil, nil
@@ -232,8 +239,8 @@ func (ddr *DisabledDistributionRootsModel) GenerateStateRoot(blockNumber uint64)
 	return fullTree.Root(), nil
 }
 
-func (ddr *DisabledDistributionRootsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return ddr.BaseEigenState.DeleteState("disabled_distribution_roots", startBlockNumber, endBlockNumber, ddr.DB)
+func (ddr *DisabledDistributionRootsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return ddr.BaseEigenState.DeleteState("disabled_distribution_roots", startBlockNumber, endBlockNumber, tx)
 }
 
 func (ddr *DisabledDistributionRootsModel) GetAccumulatedState(blockNumber uint64) []*types.DisabledDistributionRoot {
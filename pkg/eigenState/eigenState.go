@@ -1,15 +1,26 @@
 package eigenState
 
 import (
+	"fmt"
+
 	"github.com/Layr-Labs/sidecar/internal/config"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/avsOperators"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/defaultOperatorSplits"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/disabledDistributionRoots"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorAVSSplits"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorAllocations"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorDirectedOperatorSetRewardSubmissions"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorDirectedRewardSubmissions"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorMaxMagnitudes"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorPISplits"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorSetOperatorRegistrations"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorSetSplits"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorSetStrategyRegistrations"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorSets"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorShares"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/plugins"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/rewardSubmissions"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/slashedOperators"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/stakerDelegations"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/stakerShares"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
@@ -68,5 +79,77 @@ func LoadEigenStateModels(
 		l.Sugar().Errorw("Failed to create DefaultOperatorSplitModel", zap.Error(err))
 		return err
 	}
+	if _, err := operatorSets.NewOperatorSetModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorSetModel", zap.Error(err))
+		return err
+	}
+	if _, err := operatorSetOperatorRegistrations.NewOperatorSetOperatorRegistrationModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorSetOperatorRegistrationModel", zap.Error(err))
+		return err
+	}
+	if _, err := operatorSetStrategyRegistrations.NewOperatorSetStrategyRegistrationModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorSetStrategyRegistrationModel", zap.Error(err))
+		return err
+	}
+	if _, err := operatorAllocations.NewOperatorAllocationModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorAllocationModel", zap.Error(err))
+		return err
+	}
+	if _, err := operatorMaxMagnitudes.NewOperatorMaxMagnitudeModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorMaxMagnitudeModel", zap.Error(err))
+		return err
+	}
+	if _, err := slashedOperators.NewSlashedOperatorModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create SlashedOperatorModel", zap.Error(err))
+		return err
+	}
+	if _, err := operatorDirectedOperatorSetRewardSubmissions.NewOperatorDirectedOperatorSetRewardSubmissionsModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorDirectedOperatorSetRewardSubmissionsModel", zap.Error(err))
+		return err
+	}
+	if _, err := operatorSetSplits.NewOperatorSetSplitModel(sm, grm, l, cfg); err != nil {
+		l.Sugar().Errorw("Failed to create OperatorSetSplitModel", zap.Error(err))
+		return err
+	}
+	return loadPluginModels(sm, grm, l, cfg)
+}
+
+// loadPluginModels registers the models of every registered plugin after the built-in models, in plugin order.
+func loadPluginModels(
+	sm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	l *zap.Logger,
+	cfg *config.Config,
+) error {
+	registered, err := plugins.GetEigenStateModels()
+	if err != nil {
+		l.Sugar().Errorw("Failed to order plugin models", zap.Error(err))
+		return err
+	}
+
+	for i, plugin := range registered {
+		model, err := plugin.NewModel(grm, l, cfg)
+		if err != nil {
+			l.Sugar().Errorw("Failed to create plugin model", zap.String("plugin", plugin.Name), zap.Error(err))
+			return err
+		}
+		if model.GetModelName() != plugin.Name {
+			return fmt.Errorf("plugin '%s' created a model named '%s'", plugin.Name, model.GetModelName())
+		}
+		sm.RegisterState(model, plugins.ModelIndexOffset+i)
+		if plugin.ExcludeFromStateRoot {
+			sm.ExcludeFromStateRoot(plugin.Name)
+		}
+	}
+
+	models := sm.GetModelsMappedByName()
+	for _, plugin := range registered {
+		for _, after := range plugin.After {
+			if _, ok := models[after]; !ok {
+				return fmt.Errorf("plugin '%s' is ordered after unknown model '%s'", plugin.Name, after)
+			}
+		}
+	}
+	l.Sugar().Infow("Loaded plugin models", zap.Int("count", len(registered)))
 	return nil
 }
@@ -213,7 +213,7 @@ func (oas *OperatorAVSSplitModel) prepareState(blockNumber uint64) ([]*OperatorA
 }
 
 // CommitFinalState commits the final state for the given block number.
-func (oas *OperatorAVSSplitModel) CommitFinalState(blockNumber uint64) error {
+func (oas *OperatorAVSSplitModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	recordsToInsert, err := oas.prepareState(blockNumber)
 	if err != nil {
 		return err
@@ -221,7 +221,7 @@ func (oas *OperatorAVSSplitModel) CommitFinalState(blockNumber uint64) error {
 
 	if len(recordsToInsert) > 0 {
 		for _, record := range recordsToInsert {
-			res := oas.DB.Model(&OperatorAVSSplit{}).Clauses(clause.Returning{}).Create(&record)
+			res := tx.Model(&OperatorAVSSplit{}).Clauses(clause.Returning{}).Create(&record)
 			if res.Error != nil {
 				oas.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
 				return res.Error
@@ -232,14 +232,18 @@ func (oas *OperatorAVSSplitModel) CommitFinalState(blockNumber uint64) error {
 	return nil
 }
 
-// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
-func (oas *OperatorAVSSplitModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (oas *OperatorAVSSplitModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	inserts, err := oas.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return oas.sortValuesForMerkleTree(inserts)
+}
 
-	inputs, err := oas.sortValuesForMerkleTree(inserts)
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (oas *OperatorAVSSplitModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := oas.GetMerkleTreeInputs(blockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -282,7 +286,7 @@ func (oas *OperatorAVSSplitModel) formatMerkleLeafValue(
 	if err != nil {
 		return "", err
 	}
-	if oas.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod) && blockNumber < modelForks[config.ModelFork_Austin] {
+	if oas.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod, config.Chain_Custom) && blockNumber < modelForks[config.ModelFork_Austin] {
 		// This format was used on preprod and testnet for rewards-v2 before launching to mainnet
 		return fmt.Sprintf("%s_%s_%d_%d_%d", operator, avs, activatedAt.Unix(), oldOperatorAVSSplitBips, newOperatorAVSSplitBips), nil
 	}
@@ -320,8 +324,8 @@ func (oas *OperatorAVSSplitModel) sortValuesForMerkleTree(splits []*OperatorAVSS
 	return inputs, nil
 }
 
-func (oas *OperatorAVSSplitModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return oas.BaseEigenState.DeleteState("operator_avs_splits", startBlockNumber, endBlockNumber, oas.DB)
+func (oas *OperatorAVSSplitModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return oas.BaseEigenState.DeleteState("operator_avs_splits", startBlockNumber, endBlockNumber, tx)
 }
 
 func (oar *OperatorAVSSplitModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
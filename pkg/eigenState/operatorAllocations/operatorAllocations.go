@@ -0,0 +1,315 @@
+package operatorAllocations
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OperatorAllocation is the magnitude of a strategy an operator allocated to an operator set. The allocation takes
+// effect at EffectBlock, which for deallocations is after the deallocation delay.
+type OperatorAllocation struct {
+	Operator        string
+	Avs             string
+	OperatorSetId   uint64
+	Strategy        string
+	Magnitude       uint64
+	EffectBlock     uint64
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
+
+type OperatorAllocationModel struct {
+	base.BaseEigenState
+	DB           *gorm.DB
+	logger       *zap.Logger
+	globalConfig *config.Config
+
+	// Accumulates state changes for SlotIds, grouped by block number
+	stateAccumulator map[uint64]map[types.SlotID]*OperatorAllocation
+	committedState   map[uint64][]*OperatorAllocation
+}
+
+func NewOperatorAllocationModel(
+	esm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	logger *zap.Logger,
+	globalConfig *config.Config,
+) (*OperatorAllocationModel, error) {
+	model := &OperatorAllocationModel{
+		BaseEigenState: base.BaseEigenState{
+			Logger: logger,
+		},
+		DB:               grm,
+		logger:           logger,
+		globalConfig:     globalConfig,
+		stateAccumulator: make(map[uint64]map[types.SlotID]*OperatorAllocation),
+		committedState:   make(map[uint64][]*OperatorAllocation),
+	}
+
+	esm.RegisterState(model, 14)
+	return model, nil
+}
+
+func (oa *OperatorAllocationModel) GetModelName() string {
+	return "OperatorAllocationModel"
+}
+
+type allocationUpdatedOutputData struct {
+	Operator    string `json:"operator"`
+	OperatorSet struct {
+		Avs string `json:"avs"`
+		Id  uint64 `json:"id"`
+	} `json:"operatorSet"`
+	Strategy    string `json:"strategy"`
+	Magnitude   uint64 `json:"magnitude"`
+	EffectBlock uint64 `json:"effectBlock"`
+}
+
+func parseAllocationUpdatedOutputData(outputDataStr string) (*allocationUpdatedOutputData, error) {
+	outputData := &allocationUpdatedOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(outputDataStr))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&outputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputData, err
+}
+
+func (oa *OperatorAllocationModel) handleAllocationUpdatedEvent(log *storage.TransactionLog) (*OperatorAllocation, error) {
+	outputData, err := parseAllocationUpdatedOutputData(log.OutputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperatorAllocation{
+		Operator:        strings.ToLower(outputData.Operator),
+		Avs:             strings.ToLower(outputData.OperatorSet.Avs),
+		OperatorSetId:   outputData.OperatorSet.Id,
+		Strategy:        strings.ToLower(outputData.Strategy),
+		Magnitude:       outputData.Magnitude,
+		EffectBlock:     outputData.EffectBlock,
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TransactionHash,
+		LogIndex:        log.LogIndex,
+	}, nil
+}
+
+func (oa *OperatorAllocationModel) GetStateTransitions() (types.StateTransitions[*OperatorAllocation], []uint64) {
+	stateChanges := make(types.StateTransitions[*OperatorAllocation])
+
+	stateChanges[0] = func(log *storage.TransactionLog) (*OperatorAllocation, error) {
+		allocation, err := oa.handleAllocationUpdatedEvent(log)
+		if err != nil {
+			return nil, err
+		}
+
+		slotId := base.NewSlotID(allocation.TransactionHash, allocation.LogIndex)
+
+		_, ok := oa.stateAccumulator[log.BlockNumber][slotId]
+		if ok {
+			err := fmt.Errorf("Duplicate operator allocation submitted for slot %s at block %d", slotId, log.BlockNumber)
+			oa.logger.Sugar().Errorw("Duplicate operator allocation submitted", zap.Error(err))
+			return nil, err
+		}
+
+		oa.stateAccumulator[log.BlockNumber][slotId] = allocation
+
+		return allocation, nil
+	}
+
+	// Create an ordered list of block numbers
+	blockNumbers := make([]uint64, 0)
+	for blockNumber := range stateChanges {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	slices.Reverse(blockNumbers)
+
+	return stateChanges, blockNumbers
+}
+
+func (oa *OperatorAllocationModel) getContractAddressesForEnvironment() map[string][]string {
+	contracts := oa.globalConfig.GetContractsMapForChain()
+	if contracts.AllocationManager == "" {
+		return map[string][]string{}
+	}
+	return map[string][]string{
+		contracts.AllocationManager: {
+			"AllocationUpdated",
+		},
+	}
+}
+
+func (oa *OperatorAllocationModel) IsInterestingLog(log *storage.TransactionLog) bool {
+	addresses := oa.getContractAddressesForEnvironment()
+	return oa.BaseEigenState.IsInterestingLog(addresses, log)
+}
+
+func (oa *OperatorAllocationModel) SetupStateForBlock(blockNumber uint64) error {
+	oa.stateAccumulator[blockNumber] = make(map[types.SlotID]*OperatorAllocation)
+	oa.committedState[blockNumber] = make([]*OperatorAllocation, 0)
+	return nil
+}
+
+func (oa *OperatorAllocationModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
+	delete(oa.stateAccumulator, blockNumber)
+	delete(oa.committedState, blockNumber)
+	return nil
+}
+
+func (oa *OperatorAllocationModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	stateChanges, sortedBlockNumbers := oa.GetStateTransitions()
+
+	for _, blockNumber := range sortedBlockNumbers {
+		if log.BlockNumber >= blockNumber {
+			oa.logger.Sugar().Debugw("Handling state change", zap.Uint64("blockNumber", log.BlockNumber))
+
+			change, err := stateChanges[blockNumber](log)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				return nil, nil
+			}
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+// prepareState prepares the state for commit by adding the new state to the existing state.
+func (oa *OperatorAllocationModel) prepareState(blockNumber uint64) ([]*OperatorAllocation, error) {
+	accumulatedState, ok := oa.stateAccumulator[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No accumulated state found for block %d", blockNumber)
+		oa.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+
+	recordsToInsert := make([]*OperatorAllocation, 0)
+	for _, allocation := range accumulatedState {
+		recordsToInsert = append(recordsToInsert, allocation)
+	}
+	return recordsToInsert, nil
+}
+
+// CommitFinalState commits the final state for the given block number.
+func (oa *OperatorAllocationModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	recordsToInsert, err := oa.prepareState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if len(recordsToInsert) > 0 {
+		res := tx.Model(&OperatorAllocation{}).Clauses(clause.Returning{}).Create(&recordsToInsert)
+		if res.Error != nil {
+			oa.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	oa.committedState[blockNumber] = recordsToInsert
+	return nil
+}
+
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+//
+// Allocations are only part of the state root from ModelFork_Boston.
+func (oa *OperatorAllocationModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	inserts, err := oa.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	active, err := oa.globalConfig.IsModelForkActive(config.ModelFork_Boston, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []*base.MerkleTreeInput{}, nil
+	}
+	return oa.sortValuesForMerkleTree(inserts), nil
+}
+
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (oa *OperatorAllocationModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := oa.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	fullTree, err := oa.MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		oa.logger.Sugar().Errorw("Failed to create merkle tree",
+			zap.Error(err),
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Any("inputs", inputs),
+		)
+		return nil, err
+	}
+	return fullTree.Root(), nil
+}
+
+func (oa *OperatorAllocationModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	records, ok := oa.committedState[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No committed state found for block %d", blockNumber)
+		oa.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+	return base.CastCommittedStateToInterface(records), nil
+}
+
+func (oa *OperatorAllocationModel) sortValuesForMerkleTree(allocations []*OperatorAllocation) []*base.MerkleTreeInput {
+	inputs := make([]*base.MerkleTreeInput, 0)
+	for _, allocation := range allocations {
+		slotID := base.NewSlotID(allocation.TransactionHash, allocation.LogIndex)
+		value := fmt.Sprintf("%s_%s_%016x_%s_%016x_%016x",
+			allocation.Operator, allocation.Avs, allocation.OperatorSetId, allocation.Strategy, allocation.Magnitude, allocation.EffectBlock,
+		)
+		inputs = append(inputs, &base.MerkleTreeInput{
+			SlotID: slotID,
+			Value:  []byte(value),
+		})
+	}
+
+	slices.SortFunc(inputs, func(i, j *base.MerkleTreeInput) int {
+		return strings.Compare(string(i.SlotID), string(j.SlotID))
+	})
+
+	return inputs
+}
+
+func (oa *OperatorAllocationModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return oa.BaseEigenState.DeleteState("operator_allocations", startBlockNumber, endBlockNumber, tx)
+}
+
+func (oa *OperatorAllocationModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	var allocations []*OperatorAllocation
+	res := oa.DB.Where("block_number >= ? AND block_number <= ?", startBlockNumber, endBlockNumber).Find(&allocations)
+	if res.Error != nil {
+		oa.logger.Sugar().Errorw("Failed to list records", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	return base.CastCommittedStateToInterface(allocations), nil
+}
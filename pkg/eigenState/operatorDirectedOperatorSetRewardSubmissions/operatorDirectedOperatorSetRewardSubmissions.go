@@ -0,0 +1,428 @@
+package operatorDirectedOperatorSetRewardSubmissions
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/Layr-Labs/sidecar/pkg/types/numbers"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OperatorDirectedOperatorSetRewardSubmission is one (strategy, operator) pair of an operator-directed rewards
+// submission made by an AVS to one of its operator sets
+type OperatorDirectedOperatorSetRewardSubmission struct {
+	Avs             string
+	OperatorSetId   uint64
+	RewardHash      string
+	Token           string
+	Operator        string
+	OperatorIndex   uint64
+	Amount          string
+	Strategy        string
+	StrategyIndex   uint64
+	Multiplier      string
+	StartTimestamp  *time.Time
+	EndTimestamp    *time.Time
+	Duration        uint64
+	Description     string
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
+
+type OperatorDirectedOperatorSetRewardSubmissionsModel struct {
+	base.BaseEigenState
+	DB           *gorm.DB
+	logger       *zap.Logger
+	globalConfig *config.Config
+
+	// Accumulates state changes for SlotIds, grouped by block number
+	stateAccumulator map[uint64]map[types.SlotID]*OperatorDirectedOperatorSetRewardSubmission
+	committedState   map[uint64][]*OperatorDirectedOperatorSetRewardSubmission
+}
+
+func NewOperatorDirectedOperatorSetRewardSubmissionsModel(
+	esm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	logger *zap.Logger,
+	globalConfig *config.Config,
+) (*OperatorDirectedOperatorSetRewardSubmissionsModel, error) {
+	model := &OperatorDirectedOperatorSetRewardSubmissionsModel{
+		BaseEigenState: base.BaseEigenState{
+			Logger: logger,
+		},
+		DB:               grm,
+		logger:           logger,
+		globalConfig:     globalConfig,
+		stateAccumulator: make(map[uint64]map[types.SlotID]*OperatorDirectedOperatorSetRewardSubmission),
+		committedState:   make(map[uint64][]*OperatorDirectedOperatorSetRewardSubmission),
+	}
+
+	esm.RegisterState(model, 17)
+	return model, nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) GetModelName() string {
+	return "OperatorDirectedOperatorSetRewardSubmissionsModel"
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) NewSlotID(
+	transactionHash string,
+	logIndex uint64,
+	rewardHash string,
+	strategyIndex uint64,
+	operatorIndex uint64,
+) types.SlotID {
+	return base.NewSlotIDWithSuffix(transactionHash, logIndex, fmt.Sprintf("%s_%016x_%016x", rewardHash, strategyIndex, operatorIndex))
+}
+
+type operatorDirectedRewardData struct {
+	StrategiesAndMultipliers []struct {
+		Strategy   string      `json:"strategy"`
+		Multiplier json.Number `json:"multiplier"`
+	} `json:"strategiesAndMultipliers"`
+	Token           string `json:"token"`
+	OperatorRewards []struct {
+		Operator string      `json:"operator"`
+		Amount   json.Number `json:"amount"`
+	} `json:"operatorRewards"`
+	StartTimestamp uint64 `json:"startTimestamp"`
+	Duration       uint64 `json:"duration"`
+	Description    string `json:"description"`
+}
+
+type operatorDirectedRewardSubmissionOutputData struct {
+	OperatorSet struct {
+		Avs string `json:"avs"`
+		Id  uint64 `json:"id"`
+	} `json:"operatorSet"`
+	SubmissionNonce                   json.Number                 `json:"submissionNonce"`
+	OperatorDirectedRewardsSubmission *operatorDirectedRewardData `json:"operatorDirectedRewardsSubmission"`
+}
+
+func parseRewardSubmissionOutputData(outputDataStr string) (*operatorDirectedRewardSubmissionOutputData, error) {
+	outputData := &operatorDirectedRewardSubmissionOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(outputDataStr))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&outputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputData, err
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) handleOperatorDirectedOperatorSetRewardSubmissionCreatedEvent(log *storage.TransactionLog) ([]*OperatorDirectedOperatorSetRewardSubmission, error) {
+	arguments, err := odrs.ParseLogArguments(log)
+	if err != nil {
+		return nil, err
+	}
+
+	outputData, err := parseRewardSubmissionOutputData(log.OutputData)
+	if err != nil {
+		return nil, err
+	}
+	outputRewardData := outputData.OperatorDirectedRewardsSubmission
+
+	if outputRewardData.Duration == 0 {
+		odrs.logger.Sugar().Infow("Skipping operator directed operator set reward submission with zero duration",
+			zap.Uint64("blockNumber", log.BlockNumber),
+			zap.String("transactionHash", log.TransactionHash),
+			zap.Uint64("logIndex", log.LogIndex),
+		)
+		return []*OperatorDirectedOperatorSetRewardSubmission{}, nil
+	}
+
+	rewardSubmissions := make([]*OperatorDirectedOperatorSetRewardSubmission, 0)
+
+	for i, strategyAndMultiplier := range outputRewardData.StrategiesAndMultipliers {
+		startTimestamp := time.Unix(int64(outputRewardData.StartTimestamp), 0)
+		endTimestamp := startTimestamp.Add(time.Duration(outputRewardData.Duration) * time.Second)
+
+		multiplierBig, success := numbers.NewBig257().SetString(strategyAndMultiplier.Multiplier.String(), 10)
+		if !success {
+			return nil, fmt.Errorf("Failed to parse multiplier to Big257: %s", strategyAndMultiplier.Multiplier.String())
+		}
+
+		for j, operatorReward := range outputRewardData.OperatorRewards {
+			amountBig, success := numbers.NewBig257().SetString(operatorReward.Amount.String(), 10)
+			if !success {
+				return nil, fmt.Errorf("Failed to parse amount to Big257: %s", operatorReward.Amount.String())
+			}
+
+			rewardSubmission := &OperatorDirectedOperatorSetRewardSubmission{
+				Avs:             strings.ToLower(outputData.OperatorSet.Avs),
+				OperatorSetId:   outputData.OperatorSet.Id,
+				RewardHash:      strings.ToLower(arguments[1].Value.(string)),
+				Token:           strings.ToLower(outputRewardData.Token),
+				Operator:        strings.ToLower(operatorReward.Operator),
+				OperatorIndex:   uint64(j),
+				Amount:          amountBig.String(),
+				Strategy:        strings.ToLower(strategyAndMultiplier.Strategy),
+				StrategyIndex:   uint64(i),
+				Multiplier:      multiplierBig.String(),
+				StartTimestamp:  &startTimestamp,
+				EndTimestamp:    &endTimestamp,
+				Duration:        outputRewardData.Duration,
+				Description:     outputRewardData.Description,
+				BlockNumber:     log.BlockNumber,
+				TransactionHash: log.TransactionHash,
+				LogIndex:        log.LogIndex,
+			}
+
+			rewardSubmissions = append(rewardSubmissions, rewardSubmission)
+		}
+	}
+
+	return rewardSubmissions, nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) GetStateTransitions() (types.StateTransitions[[]*OperatorDirectedOperatorSetRewardSubmission], []uint64) {
+	stateChanges := make(types.StateTransitions[[]*OperatorDirectedOperatorSetRewardSubmission])
+
+	stateChanges[0] = func(log *storage.TransactionLog) ([]*OperatorDirectedOperatorSetRewardSubmission, error) {
+		rewardSubmissions, err := odrs.handleOperatorDirectedOperatorSetRewardSubmissionCreatedEvent(log)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rewardSubmission := range rewardSubmissions {
+			slotId := odrs.NewSlotID(rewardSubmission.TransactionHash, rewardSubmission.LogIndex, rewardSubmission.RewardHash, rewardSubmission.StrategyIndex, rewardSubmission.OperatorIndex)
+
+			_, ok := odrs.stateAccumulator[log.BlockNumber][slotId]
+			if ok {
+				err := fmt.Errorf("Duplicate operator directed operator set reward submission submitted for slot %s at block %d", slotId, log.BlockNumber)
+				odrs.logger.Sugar().Errorw("Duplicate operator directed operator set reward submission submitted", zap.Error(err))
+				return nil, err
+			}
+
+			odrs.stateAccumulator[log.BlockNumber][slotId] = rewardSubmission
+		}
+
+		return rewardSubmissions, nil
+	}
+
+	// Create an ordered list of block numbers
+	blockNumbers := make([]uint64, 0)
+	for blockNumber := range stateChanges {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	slices.Reverse(blockNumbers)
+
+	return stateChanges, blockNumbers
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) getContractAddressesForEnvironment() map[string][]string {
+	contracts := odrs.globalConfig.GetContractsMapForChain()
+	return map[string][]string{
+		contracts.RewardsCoordinator: {
+			"OperatorDirectedOperatorSetRewardsSubmissionCreated",
+		},
+	}
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) IsInterestingLog(log *storage.TransactionLog) bool {
+	addresses := odrs.getContractAddressesForEnvironment()
+	return odrs.BaseEigenState.IsInterestingLog(addresses, log)
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) SetupStateForBlock(blockNumber uint64) error {
+	odrs.stateAccumulator[blockNumber] = make(map[types.SlotID]*OperatorDirectedOperatorSetRewardSubmission)
+	odrs.committedState[blockNumber] = make([]*OperatorDirectedOperatorSetRewardSubmission, 0)
+	return nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
+	delete(odrs.stateAccumulator, blockNumber)
+	delete(odrs.committedState, blockNumber)
+	return nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	stateChanges, sortedBlockNumbers := odrs.GetStateTransitions()
+
+	for _, blockNumber := range sortedBlockNumbers {
+		if log.BlockNumber >= blockNumber {
+			odrs.logger.Sugar().Debugw("Handling state change", zap.Uint64("blockNumber", log.BlockNumber))
+
+			change, err := stateChanges[blockNumber](log)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				return nil, nil
+			}
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+// prepareState prepares the state for commit by adding the new state to the existing state.
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) prepareState(blockNumber uint64) ([]*OperatorDirectedOperatorSetRewardSubmission, error) {
+	accumulatedState, ok := odrs.stateAccumulator[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No accumulated state found for block %d", blockNumber)
+		odrs.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+
+	recordsToInsert := make([]*OperatorDirectedOperatorSetRewardSubmission, 0)
+	for _, submission := range accumulatedState {
+		recordsToInsert = append(recordsToInsert, submission)
+	}
+	return recordsToInsert, nil
+}
+
+// CommitFinalState commits the final state for the given block number.
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	recordsToInsert, err := odrs.prepareState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if len(recordsToInsert) > 0 {
+		for _, record := range recordsToInsert {
+			res := tx.Model(&OperatorDirectedOperatorSetRewardSubmission{}).Clauses(clause.Returning{}).Create(&record)
+			if res.Error != nil {
+				odrs.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
+				return res.Error
+			}
+		}
+	}
+	odrs.committedState[blockNumber] = recordsToInsert
+	return nil
+}
+
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+//
+// Operator set reward submissions are only part of the state root from ModelFork_Boston.
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	inserts, err := odrs.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	active, err := odrs.globalConfig.IsModelForkActive(config.ModelFork_Boston, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []*base.MerkleTreeInput{}, nil
+	}
+	return odrs.sortValuesForMerkleTree(inserts)
+}
+
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := odrs.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	fullTree, err := odrs.MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		odrs.logger.Sugar().Errorw("Failed to create merkle tree",
+			zap.Error(err),
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Any("inputs", inputs),
+		)
+		return nil, err
+	}
+	return fullTree.Root(), nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	records, ok := odrs.committedState[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No committed state found for block %d", blockNumber)
+		odrs.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+	return base.CastCommittedStateToInterface(records), nil
+}
+
+// formatMerkleLeafValue formats the leaf the same way as an AVS operator-directed reward submission after
+// ModelFork_Austin, with the operator set added after the reward hash.
+//
+// Multiplier is a uint96 in the contracts, which translates to 24 hex characters
+// Amount is a uint256 in the contracts, which translates to 64 hex characters
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) formatMerkleLeafValue(submission *OperatorDirectedOperatorSetRewardSubmission) (string, error) {
+	multiplierBig, success := new(big.Int).SetString(submission.Multiplier, 10)
+	if !success {
+		return "", fmt.Errorf("failed to parse multiplier to BigInt: %s", submission.Multiplier)
+	}
+
+	amountBig, success := new(big.Int).SetString(submission.Amount, 10)
+	if !success {
+		return "", fmt.Errorf("failed to parse amount to BigInt: %s", submission.Amount)
+	}
+
+	return fmt.Sprintf("%s_%s_%016x_%s_%024x_%s_%064x",
+		submission.RewardHash, submission.Avs, submission.OperatorSetId, submission.Strategy, multiplierBig, submission.Operator, amountBig,
+	), nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) sortValuesForMerkleTree(submissions []*OperatorDirectedOperatorSetRewardSubmission) ([]*base.MerkleTreeInput, error) {
+	inputs := make([]*base.MerkleTreeInput, 0)
+	for _, submission := range submissions {
+		slotID := odrs.NewSlotID(submission.TransactionHash, submission.LogIndex, submission.RewardHash, submission.StrategyIndex, submission.OperatorIndex)
+
+		value, err := odrs.formatMerkleLeafValue(submission)
+		if err != nil {
+			odrs.logger.Sugar().Errorw("Failed to format merkle leaf value",
+				zap.Error(err),
+				zap.Uint64("blockNumber", submission.BlockNumber),
+				zap.String("rewardHash", submission.RewardHash),
+				zap.String("strategy", submission.Strategy),
+				zap.String("multiplier", submission.Multiplier),
+				zap.String("operator", submission.Operator),
+				zap.String("amount", submission.Amount),
+			)
+			return nil, err
+		}
+		inputs = append(inputs, &base.MerkleTreeInput{
+			SlotID: slotID,
+			Value:  []byte(value),
+		})
+	}
+
+	slices.SortFunc(inputs, func(i, j *base.MerkleTreeInput) int {
+		return strings.Compare(string(i.SlotID), string(j.SlotID))
+	})
+
+	return inputs, nil
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return odrs.BaseEigenState.DeleteState("operator_directed_operator_set_reward_submissions", startBlockNumber, endBlockNumber, tx)
+}
+
+func (odrs *OperatorDirectedOperatorSetRewardSubmissionsModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	var submissions []*OperatorDirectedOperatorSetRewardSubmission
+	res := odrs.DB.Where("block_number >= ? AND block_number <= ?", startBlockNumber, endBlockNumber).Find(&submissions)
+	if res.Error != nil {
+		odrs.logger.Sugar().Errorw("Failed to list records", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	return base.CastCommittedStateToInterface(submissions), nil
+}
@@ -0,0 +1,164 @@
+package operatorDirectedOperatorSetRewardSubmissions
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const rewardsCoordinator = "0xcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcd"
+
+func setup() (
+	string,
+	*gorm.DB,
+	*zap.Logger,
+	*config.Config,
+	error,
+) {
+	cfg := config.NewConfig()
+	cfg.Debug = os.Getenv(config.Debug) == "true"
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	// operator set reward submissions are only part of the state root from the Boston fork, which the custom chain
+	// activates from the first block
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		Contracts: config.ContractAddresses{
+			RewardsCoordinator: rewardsCoordinator,
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, grm, l, cfg, nil
+}
+
+func teardown(model *OperatorDirectedOperatorSetRewardSubmissionsModel) {
+	queries := []string{
+		`truncate table operator_directed_operator_set_reward_submissions`,
+		`truncate table blocks cascade`,
+	}
+	for _, query := range queries {
+		res := model.DB.Exec(query)
+		if res.Error != nil {
+			fmt.Printf("Failed to run query: %v\n", res.Error)
+		}
+	}
+}
+
+func createBlock(model *OperatorDirectedOperatorSetRewardSubmissionsModel, blockNumber uint64) error {
+	block := &storage.Block{
+		Number:    blockNumber,
+		Hash:      "some hash",
+		BlockTime: time.Now().Add(time.Hour * time.Duration(blockNumber)),
+	}
+	res := model.DB.Model(&storage.Block{}).Create(block)
+	if res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorDirectedOperatorSetRewardSubmissions(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test each event type", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+
+		model, err := NewOperatorDirectedOperatorSetRewardSubmissionsModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		t.Run("Handle an operator directed operator set reward submission", func(t *testing.T) {
+			blockNumber := uint64(102)
+
+			if err := createBlock(model, blockNumber); err != nil {
+				t.Fatal(err)
+			}
+
+			log := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          rewardsCoordinator,
+				Arguments:        `[{"Name": "caller", "Type": "address", "Value": "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", "Indexed": true}, {"Name": "operatorDirectedRewardsSubmissionHash", "Type": "bytes32", "Value": "0x7402669fb2c8a0cfe8108acb8a0070257c77ec6906ecb07d97c38e8a5ddc66a9", "Indexed": true}, {"Name": "operatorSet", "Type": "tuple", "Value": null, "Indexed": false}, {"Name": "submissionNonce", "Type": "uint256", "Value": null, "Indexed": false}, {"Name": "operatorDirectedRewardsSubmission", "Type": "((address,uint96)[],address,(address,uint256)[],uint32,uint32,string)", "Value": null, "Indexed": false}]`,
+				EventName:        "OperatorDirectedOperatorSetRewardsSubmissionCreated",
+				LogIndex:         12,
+				OutputData:       `{"operatorSet": {"avs": "0xD36B6E5EEE8311D7BFFB2F3BB33301A1AB7DE101", "id": 3}, "submissionNonce": 0, "operatorDirectedRewardsSubmission": {"token": "0x0ddd9dc88e638aef6a8e42d0c98aaa6a48a98d24", "operatorRewards": [{"operator": "0x9401E5E6564DB35C0f86573a9828DF69Fc778aF1", "amount": 30000000000000000000000}, {"operator": "0xF50Cba7a66b5E615587157e43286DaA7aF94009e", "amount": 40000000000000000000000}], "duration": 2419200, "startTimestamp": 1725494400, "strategiesAndMultipliers": [{"strategy": "0x5074dfd18e9498d9e006fb8d4f3fecdc9af90a2c", "multiplier": 1000000000000000000}, {"strategy": "0xD56e4eAb23cb81f43168F9F45211Eb027b9aC7cc", "multiplier": 2000000000000000000}], "description": "test reward submission"}}`,
+			}
+
+			err = model.SetupStateForBlock(blockNumber)
+			assert.Nil(t, err)
+
+			isInteresting := model.IsInterestingLog(log)
+			assert.True(t, isInteresting)
+
+			change, err := model.HandleStateChange(log)
+			assert.Nil(t, err)
+			assert.NotNil(t, change)
+
+			submissions := change.([]*OperatorDirectedOperatorSetRewardSubmission)
+			assert.Equal(t, 4, len(submissions))
+
+			submission := submissions[0]
+			assert.Equal(t, "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", submission.Avs)
+			assert.Equal(t, uint64(3), submission.OperatorSetId)
+			assert.Equal(t, "0x7402669fb2c8a0cfe8108acb8a0070257c77ec6906ecb07d97c38e8a5ddc66a9", submission.RewardHash)
+			assert.Equal(t, "0x0ddd9dc88e638aef6a8e42d0c98aaa6a48a98d24", submission.Token)
+			assert.Equal(t, "0x9401e5e6564db35c0f86573a9828df69fc778af1", submission.Operator)
+			assert.Equal(t, "30000000000000000000000", submission.Amount)
+			assert.Equal(t, "0x5074dfd18e9498d9e006fb8d4f3fecdc9af90a2c", submission.Strategy)
+			assert.Equal(t, "1000000000000000000", submission.Multiplier)
+			assert.Equal(t, int64(1725494400), submission.StartTimestamp.Unix())
+			assert.Equal(t, int64(1725494400+2419200), submission.EndTimestamp.Unix())
+
+			err = model.CommitFinalState(blockNumber, grm)
+			assert.Nil(t, err)
+
+			rows := make([]*OperatorDirectedOperatorSetRewardSubmission, 0)
+			query := `select * from operator_directed_operator_set_reward_submissions where block_number = ?`
+			res := model.DB.Raw(query, blockNumber).Scan(&rows)
+			assert.Nil(t, res.Error)
+			assert.Equal(t, 4, len(rows))
+
+			stateRoot, err := model.GenerateStateRoot(blockNumber)
+			assert.Nil(t, err)
+			assert.True(t, len(stateRoot) > 0)
+
+			t.Cleanup(func() {
+				teardown(model)
+			})
+		})
+
+		t.Cleanup(func() {
+			teardown(model)
+		})
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
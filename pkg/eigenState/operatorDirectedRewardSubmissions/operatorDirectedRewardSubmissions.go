@@ -90,7 +90,7 @@ func (odrs *OperatorDirectedRewardSubmissionsModel) NewSlotID(
 	if err != nil {
 		return "", err
 	}
-	if odrs.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod) && blockNumber < forks[config.ModelFork_Austin] {
+	if odrs.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod, config.Chain_Custom) && blockNumber < forks[config.ModelFork_Austin] {
 		// This format was used on preprod and testnet for rewards-v2 before launching to mainnet
 		return base.NewSlotIDWithSuffix(transactionHash, logIndex, fmt.Sprintf("%s_%d_%d", rewardHash, strategyIndex, operatorIndex)), nil
 	}
@@ -307,7 +307,7 @@ func (odrs *OperatorDirectedRewardSubmissionsModel) prepareState(blockNumber uin
 }
 
 // CommitFinalState commits the final state for the given block number.
-func (odrs *OperatorDirectedRewardSubmissionsModel) CommitFinalState(blockNumber uint64) error {
+func (odrs *OperatorDirectedRewardSubmissionsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	recordsToInsert, err := odrs.prepareState(blockNumber)
 	if err != nil {
 		return err
@@ -315,7 +315,7 @@ func (odrs *OperatorDirectedRewardSubmissionsModel) CommitFinalState(blockNumber
 
 	if len(recordsToInsert) > 0 {
 		for _, record := range recordsToInsert {
-			res := odrs.DB.Model(&OperatorDirectedRewardSubmission{}).Clauses(clause.Returning{}).Create(&record)
+			res := tx.Model(&OperatorDirectedRewardSubmission{}).Clauses(clause.Returning{}).Create(&record)
 			if res.Error != nil {
 				odrs.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
 				return res.Error
@@ -326,14 +326,18 @@ func (odrs *OperatorDirectedRewardSubmissionsModel) CommitFinalState(blockNumber
 	return nil
 }
 
-// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
-func (odrs *OperatorDirectedRewardSubmissionsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (odrs *OperatorDirectedRewardSubmissionsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	inserts, err := odrs.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return odrs.sortValuesForMerkleTree(inserts)
+}
 
-	inputs, err := odrs.sortValuesForMerkleTree(inserts)
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (odrs *OperatorDirectedRewardSubmissionsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := odrs.GetMerkleTreeInputs(blockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -377,7 +381,7 @@ func (odrs *OperatorDirectedRewardSubmissionsModel) formatMerkleLeafValue(
 		return "", err
 	}
 
-	if odrs.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod) && blockNumber < modelForks[config.ModelFork_Austin] {
+	if odrs.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod, config.Chain_Custom) && blockNumber < modelForks[config.ModelFork_Austin] {
 		// This format was used on preprod and testnet for rewards-v2 before launching to mainnet
 		return fmt.Sprintf("%s_%s_%s_%s_%s", rewardHash, strategy, multiplier, operator, amount), nil
 	}
@@ -441,8 +445,8 @@ func (odrs *OperatorDirectedRewardSubmissionsModel) sortValuesForMerkleTree(subm
 	return inputs, nil
 }
 
-func (odrs *OperatorDirectedRewardSubmissionsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return odrs.BaseEigenState.DeleteState("operator_directed_reward_submissions", startBlockNumber, endBlockNumber, odrs.DB)
+func (odrs *OperatorDirectedRewardSubmissionsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return odrs.BaseEigenState.DeleteState("operator_directed_reward_submissions", startBlockNumber, endBlockNumber, tx)
 }
 
 func (odrs *OperatorDirectedRewardSubmissionsModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
@@ -0,0 +1,302 @@
+package operatorMaxMagnitudes
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OperatorMaxMagnitude is the total magnitude of a strategy an operator can allocate. It starts at 1e18 (WAD) and
+// only decreases, as the operator is slashed.
+type OperatorMaxMagnitude struct {
+	Operator        string
+	Strategy        string
+	MaxMagnitude    uint64
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
+
+type OperatorMaxMagnitudeModel struct {
+	base.BaseEigenState
+	DB           *gorm.DB
+	logger       *zap.Logger
+	globalConfig *config.Config
+
+	// Accumulates state changes for SlotIds, grouped by block number
+	stateAccumulator map[uint64]map[types.SlotID]*OperatorMaxMagnitude
+	committedState   map[uint64][]*OperatorMaxMagnitude
+}
+
+func NewOperatorMaxMagnitudeModel(
+	esm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	logger *zap.Logger,
+	globalConfig *config.Config,
+) (*OperatorMaxMagnitudeModel, error) {
+	model := &OperatorMaxMagnitudeModel{
+		BaseEigenState: base.BaseEigenState{
+			Logger: logger,
+		},
+		DB:               grm,
+		logger:           logger,
+		globalConfig:     globalConfig,
+		stateAccumulator: make(map[uint64]map[types.SlotID]*OperatorMaxMagnitude),
+		committedState:   make(map[uint64][]*OperatorMaxMagnitude),
+	}
+
+	esm.RegisterState(model, 15)
+	return model, nil
+}
+
+func (omm *OperatorMaxMagnitudeModel) GetModelName() string {
+	return "OperatorMaxMagnitudeModel"
+}
+
+type maxMagnitudeUpdatedOutputData struct {
+	Operator     string `json:"operator"`
+	Strategy     string `json:"strategy"`
+	MaxMagnitude uint64 `json:"maxMagnitude"`
+}
+
+func parseMaxMagnitudeUpdatedOutputData(outputDataStr string) (*maxMagnitudeUpdatedOutputData, error) {
+	outputData := &maxMagnitudeUpdatedOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(outputDataStr))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&outputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputData, err
+}
+
+func (omm *OperatorMaxMagnitudeModel) handleMaxMagnitudeUpdatedEvent(log *storage.TransactionLog) (*OperatorMaxMagnitude, error) {
+	outputData, err := parseMaxMagnitudeUpdatedOutputData(log.OutputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperatorMaxMagnitude{
+		Operator:        strings.ToLower(outputData.Operator),
+		Strategy:        strings.ToLower(outputData.Strategy),
+		MaxMagnitude:    outputData.MaxMagnitude,
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TransactionHash,
+		LogIndex:        log.LogIndex,
+	}, nil
+}
+
+func (omm *OperatorMaxMagnitudeModel) GetStateTransitions() (types.StateTransitions[*OperatorMaxMagnitude], []uint64) {
+	stateChanges := make(types.StateTransitions[*OperatorMaxMagnitude])
+
+	stateChanges[0] = func(log *storage.TransactionLog) (*OperatorMaxMagnitude, error) {
+		magnitude, err := omm.handleMaxMagnitudeUpdatedEvent(log)
+		if err != nil {
+			return nil, err
+		}
+
+		slotId := base.NewSlotID(magnitude.TransactionHash, magnitude.LogIndex)
+
+		_, ok := omm.stateAccumulator[log.BlockNumber][slotId]
+		if ok {
+			err := fmt.Errorf("Duplicate operator max magnitude submitted for slot %s at block %d", slotId, log.BlockNumber)
+			omm.logger.Sugar().Errorw("Duplicate operator max magnitude submitted", zap.Error(err))
+			return nil, err
+		}
+
+		omm.stateAccumulator[log.BlockNumber][slotId] = magnitude
+
+		return magnitude, nil
+	}
+
+	// Create an ordered list of block numbers
+	blockNumbers := make([]uint64, 0)
+	for blockNumber := range stateChanges {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	slices.Reverse(blockNumbers)
+
+	return stateChanges, blockNumbers
+}
+
+func (omm *OperatorMaxMagnitudeModel) getContractAddressesForEnvironment() map[string][]string {
+	contracts := omm.globalConfig.GetContractsMapForChain()
+	if contracts.AllocationManager == "" {
+		return map[string][]string{}
+	}
+	return map[string][]string{
+		contracts.AllocationManager: {
+			"MaxMagnitudeUpdated",
+		},
+	}
+}
+
+func (omm *OperatorMaxMagnitudeModel) IsInterestingLog(log *storage.TransactionLog) bool {
+	addresses := omm.getContractAddressesForEnvironment()
+	return omm.BaseEigenState.IsInterestingLog(addresses, log)
+}
+
+func (omm *OperatorMaxMagnitudeModel) SetupStateForBlock(blockNumber uint64) error {
+	omm.stateAccumulator[blockNumber] = make(map[types.SlotID]*OperatorMaxMagnitude)
+	omm.committedState[blockNumber] = make([]*OperatorMaxMagnitude, 0)
+	return nil
+}
+
+func (omm *OperatorMaxMagnitudeModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
+	delete(omm.stateAccumulator, blockNumber)
+	delete(omm.committedState, blockNumber)
+	return nil
+}
+
+func (omm *OperatorMaxMagnitudeModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	stateChanges, sortedBlockNumbers := omm.GetStateTransitions()
+
+	for _, blockNumber := range sortedBlockNumbers {
+		if log.BlockNumber >= blockNumber {
+			omm.logger.Sugar().Debugw("Handling state change", zap.Uint64("blockNumber", log.BlockNumber))
+
+			change, err := stateChanges[blockNumber](log)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				return nil, nil
+			}
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+// prepareState prepares the state for commit by adding the new state to the existing state.
+func (omm *OperatorMaxMagnitudeModel) prepareState(blockNumber uint64) ([]*OperatorMaxMagnitude, error) {
+	accumulatedState, ok := omm.stateAccumulator[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No accumulated state found for block %d", blockNumber)
+		omm.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+
+	recordsToInsert := make([]*OperatorMaxMagnitude, 0)
+	for _, magnitude := range accumulatedState {
+		recordsToInsert = append(recordsToInsert, magnitude)
+	}
+	return recordsToInsert, nil
+}
+
+// CommitFinalState commits the final state for the given block number.
+func (omm *OperatorMaxMagnitudeModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	recordsToInsert, err := omm.prepareState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if len(recordsToInsert) > 0 {
+		res := tx.Model(&OperatorMaxMagnitude{}).Clauses(clause.Returning{}).Create(&recordsToInsert)
+		if res.Error != nil {
+			omm.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	omm.committedState[blockNumber] = recordsToInsert
+	return nil
+}
+
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+//
+// Max magnitudes are only part of the state root from ModelFork_Boston.
+func (omm *OperatorMaxMagnitudeModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	inserts, err := omm.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	active, err := omm.globalConfig.IsModelForkActive(config.ModelFork_Boston, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []*base.MerkleTreeInput{}, nil
+	}
+	return omm.sortValuesForMerkleTree(inserts), nil
+}
+
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (omm *OperatorMaxMagnitudeModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := omm.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	fullTree, err := omm.MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		omm.logger.Sugar().Errorw("Failed to create merkle tree",
+			zap.Error(err),
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Any("inputs", inputs),
+		)
+		return nil, err
+	}
+	return fullTree.Root(), nil
+}
+
+func (omm *OperatorMaxMagnitudeModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	records, ok := omm.committedState[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No committed state found for block %d", blockNumber)
+		omm.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+	return base.CastCommittedStateToInterface(records), nil
+}
+
+func (omm *OperatorMaxMagnitudeModel) sortValuesForMerkleTree(magnitudes []*OperatorMaxMagnitude) []*base.MerkleTreeInput {
+	inputs := make([]*base.MerkleTreeInput, 0)
+	for _, magnitude := range magnitudes {
+		slotID := base.NewSlotID(magnitude.TransactionHash, magnitude.LogIndex)
+		value := fmt.Sprintf("%s_%s_%016x", magnitude.Operator, magnitude.Strategy, magnitude.MaxMagnitude)
+		inputs = append(inputs, &base.MerkleTreeInput{
+			SlotID: slotID,
+			Value:  []byte(value),
+		})
+	}
+
+	slices.SortFunc(inputs, func(i, j *base.MerkleTreeInput) int {
+		return strings.Compare(string(i.SlotID), string(j.SlotID))
+	})
+
+	return inputs
+}
+
+func (omm *OperatorMaxMagnitudeModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return omm.BaseEigenState.DeleteState("operator_max_magnitudes", startBlockNumber, endBlockNumber, tx)
+}
+
+func (omm *OperatorMaxMagnitudeModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	var magnitudes []*OperatorMaxMagnitude
+	res := omm.DB.Where("block_number >= ? AND block_number <= ?", startBlockNumber, endBlockNumber).Find(&magnitudes)
+	if res.Error != nil {
+		omm.logger.Sugar().Errorw("Failed to list records", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	return base.CastCommittedStateToInterface(magnitudes), nil
+}
@@ -0,0 +1,153 @@
+package operatorMaxMagnitudes
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const allocationManager = "0xabababababababababababababababababababab"
+
+func setup() (
+	string,
+	*gorm.DB,
+	*zap.Logger,
+	*config.Config,
+	error,
+) {
+	cfg := config.NewConfig()
+	cfg.Debug = os.Getenv(config.Debug) == "true"
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	// the tests run against a custom chain so that they can use their own contract addresses and fork heights
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		Contracts: config.ContractAddresses{
+			AllocationManager: allocationManager,
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, grm, l, cfg, nil
+}
+
+func teardown(model *OperatorMaxMagnitudeModel) {
+	queries := []string{
+		`truncate table operator_max_magnitudes`,
+		`truncate table blocks cascade`,
+	}
+	for _, query := range queries {
+		res := model.DB.Exec(query)
+		if res.Error != nil {
+			fmt.Printf("Failed to run query: %v\n", res.Error)
+		}
+	}
+}
+
+func createBlock(model *OperatorMaxMagnitudeModel, blockNumber uint64) error {
+	block := &storage.Block{
+		Number:    blockNumber,
+		Hash:      "some hash",
+		BlockTime: time.Now().Add(time.Hour * time.Duration(blockNumber)),
+	}
+	res := model.DB.Model(&storage.Block{}).Create(block)
+	if res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorMaxMagnitude(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test each event type", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+
+		model, err := NewOperatorMaxMagnitudeModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		t.Run("Handle a max magnitude update", func(t *testing.T) {
+			blockNumber := uint64(102)
+
+			if err := createBlock(model, blockNumber); err != nil {
+				t.Fatal(err)
+			}
+
+			log := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          allocationManager,
+				Arguments:        `[{"Name": "operator", "Type": "address", "Value": null, "Indexed": false}, {"Name": "strategy", "Type": "address", "Value": null, "Indexed": false}, {"Name": "maxMagnitude", "Type": "uint64", "Value": null, "Indexed": false}]`,
+				EventName:        "MaxMagnitudeUpdated",
+				LogIndex:         12,
+				OutputData:       `{"operator": "0xD36B6E5EEE8311D7BFFB2F3BB33301A1AB7DE101", "strategy": "0x7D704507B76571A51D9CAE8AdDAbBFd0ba0e63d3", "maxMagnitude": 900000000000000000}`,
+			}
+
+			err = model.SetupStateForBlock(blockNumber)
+			assert.Nil(t, err)
+
+			isInteresting := model.IsInterestingLog(log)
+			assert.True(t, isInteresting)
+
+			change, err := model.HandleStateChange(log)
+			assert.Nil(t, err)
+			assert.NotNil(t, change)
+
+			maxMagnitude := change.(*OperatorMaxMagnitude)
+			assert.Equal(t, "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", maxMagnitude.Operator)
+			assert.Equal(t, "0x7d704507b76571a51d9cae8addabbfd0ba0e63d3", maxMagnitude.Strategy)
+			assert.Equal(t, uint64(900000000000000000), maxMagnitude.MaxMagnitude)
+
+			err = model.CommitFinalState(blockNumber, grm)
+			assert.Nil(t, err)
+
+			maxMagnitudes := make([]*OperatorMaxMagnitude, 0)
+			query := `select * from operator_max_magnitudes where block_number = ?`
+			res := model.DB.Raw(query, blockNumber).Scan(&maxMagnitudes)
+			assert.Nil(t, res.Error)
+			assert.Equal(t, 1, len(maxMagnitudes))
+
+			stateRoot, err := model.GenerateStateRoot(blockNumber)
+			assert.Nil(t, err)
+			assert.True(t, len(stateRoot) > 0)
+
+			t.Cleanup(func() {
+				teardown(model)
+			})
+		})
+
+		t.Cleanup(func() {
+			teardown(model)
+		})
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
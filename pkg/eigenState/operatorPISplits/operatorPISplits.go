@@ -211,7 +211,7 @@ func (ops *OperatorPISplitModel) prepareState(blockNumber uint64) ([]*OperatorPI
 }
 
 // CommitFinalState commits the final state for the given block number.
-func (ops *OperatorPISplitModel) CommitFinalState(blockNumber uint64) error {
+func (ops *OperatorPISplitModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	recordsToInsert, err := ops.prepareState(blockNumber)
 	if err != nil {
 		return err
@@ -219,7 +219,7 @@ func (ops *OperatorPISplitModel) CommitFinalState(blockNumber uint64) error {
 
 	if len(recordsToInsert) > 0 {
 		for _, record := range recordsToInsert {
-			res := ops.DB.Model(&OperatorPISplit{}).Clauses(clause.Returning{}).Create(&record)
+			res := tx.Model(&OperatorPISplit{}).Clauses(clause.Returning{}).Create(&record)
 			if res.Error != nil {
 				ops.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
 				return res.Error
@@ -230,14 +230,18 @@ func (ops *OperatorPISplitModel) CommitFinalState(blockNumber uint64) error {
 	return nil
 }
 
-// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
-func (ops *OperatorPISplitModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (ops *OperatorPISplitModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	inserts, err := ops.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return ops.sortValuesForMerkleTree(inserts)
+}
 
-	inputs, err := ops.sortValuesForMerkleTree(inserts)
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (ops *OperatorPISplitModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := ops.GetMerkleTreeInputs(blockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +283,7 @@ func (ops *OperatorPISplitModel) formatMerkleLeafValue(
 	if err != nil {
 		return "", err
 	}
-	if ops.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod) && blockNumber < modelForks[config.ModelFork_Austin] {
+	if ops.globalConfig.ChainIsOneOf(config.Chain_Holesky, config.Chain_Preprod, config.Chain_Custom) && blockNumber < modelForks[config.ModelFork_Austin] {
 		// This format was used on preprod and testnet for rewards-v2 before launching to mainnet
 		return fmt.Sprintf("%s_%d_%d_%d", operator, activatedAt.Unix(), oldOperatorPISplitBips, newOperatorPISplitBips), nil
 	}
@@ -316,8 +320,8 @@ func (ops *OperatorPISplitModel) sortValuesForMerkleTree(splits []*OperatorPISpl
 	return inputs, nil
 }
 
-func (ops *OperatorPISplitModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return ops.BaseEigenState.DeleteState("operator_pi_splits", startBlockNumber, endBlockNumber, ops.DB)
+func (ops *OperatorPISplitModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return ops.BaseEigenState.DeleteState("operator_pi_splits", startBlockNumber, endBlockNumber, tx)
 }
 
 func (ops *OperatorPISplitModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
@@ -0,0 +1,310 @@
+package operatorSetOperatorRegistrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OperatorSetOperatorRegistration is an operator registering for, or deregistering from, an operator set
+type OperatorSetOperatorRegistration struct {
+	Operator        string
+	Avs             string
+	OperatorSetId   uint64
+	IsActive        bool
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
+
+type OperatorSetOperatorRegistrationModel struct {
+	base.BaseEigenState
+	DB           *gorm.DB
+	logger       *zap.Logger
+	globalConfig *config.Config
+
+	// Accumulates state changes for SlotIds, grouped by block number
+	stateAccumulator map[uint64]map[types.SlotID]*OperatorSetOperatorRegistration
+	committedState   map[uint64][]*OperatorSetOperatorRegistration
+}
+
+func NewOperatorSetOperatorRegistrationModel(
+	esm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	logger *zap.Logger,
+	globalConfig *config.Config,
+) (*OperatorSetOperatorRegistrationModel, error) {
+	model := &OperatorSetOperatorRegistrationModel{
+		BaseEigenState: base.BaseEigenState{
+			Logger: logger,
+		},
+		DB:               grm,
+		logger:           logger,
+		globalConfig:     globalConfig,
+		stateAccumulator: make(map[uint64]map[types.SlotID]*OperatorSetOperatorRegistration),
+		committedState:   make(map[uint64][]*OperatorSetOperatorRegistration),
+	}
+
+	esm.RegisterState(model, 12)
+	return model, nil
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) GetModelName() string {
+	return "OperatorSetOperatorRegistrationModel"
+}
+
+type operatorSetMembershipOutputData struct {
+	OperatorSet struct {
+		Avs string `json:"avs"`
+		Id  uint64 `json:"id"`
+	} `json:"operatorSet"`
+}
+
+func parseOperatorSetMembershipOutputData(outputDataStr string) (*operatorSetMembershipOutputData, error) {
+	outputData := &operatorSetMembershipOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(outputDataStr))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&outputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputData, err
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) handleOperatorSetMembershipEvent(log *storage.TransactionLog) (*OperatorSetOperatorRegistration, error) {
+	arguments, err := osor.ParseLogArguments(log)
+	if err != nil {
+		return nil, err
+	}
+
+	outputData, err := parseOperatorSetMembershipOutputData(log.OutputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperatorSetOperatorRegistration{
+		Operator:        strings.ToLower(arguments[0].Value.(string)),
+		Avs:             strings.ToLower(outputData.OperatorSet.Avs),
+		OperatorSetId:   outputData.OperatorSet.Id,
+		IsActive:        log.EventName == "OperatorAddedToOperatorSet",
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TransactionHash,
+		LogIndex:        log.LogIndex,
+	}, nil
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) GetStateTransitions() (types.StateTransitions[*OperatorSetOperatorRegistration], []uint64) {
+	stateChanges := make(types.StateTransitions[*OperatorSetOperatorRegistration])
+
+	stateChanges[0] = func(log *storage.TransactionLog) (*OperatorSetOperatorRegistration, error) {
+		registration, err := osor.handleOperatorSetMembershipEvent(log)
+		if err != nil {
+			return nil, err
+		}
+
+		slotId := base.NewSlotID(registration.TransactionHash, registration.LogIndex)
+
+		_, ok := osor.stateAccumulator[log.BlockNumber][slotId]
+		if ok {
+			err := fmt.Errorf("Duplicate operator set registration submitted for slot %s at block %d", slotId, log.BlockNumber)
+			osor.logger.Sugar().Errorw("Duplicate operator set registration submitted", zap.Error(err))
+			return nil, err
+		}
+
+		osor.stateAccumulator[log.BlockNumber][slotId] = registration
+
+		return registration, nil
+	}
+
+	// Create an ordered list of block numbers
+	blockNumbers := make([]uint64, 0)
+	for blockNumber := range stateChanges {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	slices.Reverse(blockNumbers)
+
+	return stateChanges, blockNumbers
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) getContractAddressesForEnvironment() map[string][]string {
+	contracts := osor.globalConfig.GetContractsMapForChain()
+	if contracts.AllocationManager == "" {
+		return map[string][]string{}
+	}
+	return map[string][]string{
+		contracts.AllocationManager: {
+			"OperatorAddedToOperatorSet",
+			"OperatorRemovedFromOperatorSet",
+		},
+	}
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) IsInterestingLog(log *storage.TransactionLog) bool {
+	addresses := osor.getContractAddressesForEnvironment()
+	return osor.BaseEigenState.IsInterestingLog(addresses, log)
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) SetupStateForBlock(blockNumber uint64) error {
+	osor.stateAccumulator[blockNumber] = make(map[types.SlotID]*OperatorSetOperatorRegistration)
+	osor.committedState[blockNumber] = make([]*OperatorSetOperatorRegistration, 0)
+	return nil
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
+	delete(osor.stateAccumulator, blockNumber)
+	delete(osor.committedState, blockNumber)
+	return nil
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	stateChanges, sortedBlockNumbers := osor.GetStateTransitions()
+
+	for _, blockNumber := range sortedBlockNumbers {
+		if log.BlockNumber >= blockNumber {
+			osor.logger.Sugar().Debugw("Handling state change", zap.Uint64("blockNumber", log.BlockNumber))
+
+			change, err := stateChanges[blockNumber](log)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				return nil, nil
+			}
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+// prepareState prepares the state for commit by adding the new state to the existing state.
+func (osor *OperatorSetOperatorRegistrationModel) prepareState(blockNumber uint64) ([]*OperatorSetOperatorRegistration, error) {
+	accumulatedState, ok := osor.stateAccumulator[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No accumulated state found for block %d", blockNumber)
+		osor.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+
+	recordsToInsert := make([]*OperatorSetOperatorRegistration, 0)
+	for _, registration := range accumulatedState {
+		recordsToInsert = append(recordsToInsert, registration)
+	}
+	return recordsToInsert, nil
+}
+
+// CommitFinalState commits the final state for the given block number.
+func (osor *OperatorSetOperatorRegistrationModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	recordsToInsert, err := osor.prepareState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if len(recordsToInsert) > 0 {
+		res := tx.Model(&OperatorSetOperatorRegistration{}).Clauses(clause.Returning{}).Create(&recordsToInsert)
+		if res.Error != nil {
+			osor.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	osor.committedState[blockNumber] = recordsToInsert
+	return nil
+}
+
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+//
+// Operator set registrations are only part of the state root from ModelFork_Boston.
+func (osor *OperatorSetOperatorRegistrationModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	inserts, err := osor.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	active, err := osor.globalConfig.IsModelForkActive(config.ModelFork_Boston, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []*base.MerkleTreeInput{}, nil
+	}
+	return osor.sortValuesForMerkleTree(inserts), nil
+}
+
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (osor *OperatorSetOperatorRegistrationModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := osor.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	fullTree, err := osor.MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		osor.logger.Sugar().Errorw("Failed to create merkle tree",
+			zap.Error(err),
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Any("inputs", inputs),
+		)
+		return nil, err
+	}
+	return fullTree.Root(), nil
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	records, ok := osor.committedState[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No committed state found for block %d", blockNumber)
+		osor.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+	return base.CastCommittedStateToInterface(records), nil
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) sortValuesForMerkleTree(registrations []*OperatorSetOperatorRegistration) []*base.MerkleTreeInput {
+	inputs := make([]*base.MerkleTreeInput, 0)
+	for _, registration := range registrations {
+		slotID := base.NewSlotID(registration.TransactionHash, registration.LogIndex)
+		value := fmt.Sprintf("%s_%s_%016x_%t", registration.Operator, registration.Avs, registration.OperatorSetId, registration.IsActive)
+		inputs = append(inputs, &base.MerkleTreeInput{
+			SlotID: slotID,
+			Value:  []byte(value),
+		})
+	}
+
+	slices.SortFunc(inputs, func(i, j *base.MerkleTreeInput) int {
+		return strings.Compare(string(i.SlotID), string(j.SlotID))
+	})
+
+	return inputs
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return osor.BaseEigenState.DeleteState("operator_set_operator_registrations", startBlockNumber, endBlockNumber, tx)
+}
+
+func (osor *OperatorSetOperatorRegistrationModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	var registrations []*OperatorSetOperatorRegistration
+	res := osor.DB.Where("block_number >= ? AND block_number <= ?", startBlockNumber, endBlockNumber).Find(&registrations)
+	if res.Error != nil {
+		osor.logger.Sugar().Errorw("Failed to list records", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	return base.CastCommittedStateToInterface(registrations), nil
+}
@@ -0,0 +1,158 @@
+package operatorSetSplits
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const rewardsCoordinator = "0xcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcdcd"
+
+func setup() (
+	string,
+	*gorm.DB,
+	*zap.Logger,
+	*config.Config,
+	error,
+) {
+	cfg := config.NewConfig()
+	cfg.Debug = os.Getenv(config.Debug) == "true"
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	// operator set splits are only part of the state root from the Boston fork, which the custom chain activates from
+	// the first block
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		Contracts: config.ContractAddresses{
+			RewardsCoordinator: rewardsCoordinator,
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, grm, l, cfg, nil
+}
+
+func teardown(model *OperatorSetSplitModel) {
+	queries := []string{
+		`truncate table operator_set_splits`,
+		`truncate table blocks cascade`,
+	}
+	for _, query := range queries {
+		res := model.DB.Exec(query)
+		if res.Error != nil {
+			fmt.Printf("Failed to run query: %v\n", res.Error)
+		}
+	}
+}
+
+func createBlock(model *OperatorSetSplitModel, blockNumber uint64) error {
+	block := &storage.Block{
+		Number:    blockNumber,
+		Hash:      "some hash",
+		BlockTime: time.Now().Add(time.Hour * time.Duration(blockNumber)),
+	}
+	res := model.DB.Model(&storage.Block{}).Create(block)
+	if res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorSetSplit(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test each event type", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+
+		model, err := NewOperatorSetSplitModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		t.Run("Handle an operator set split", func(t *testing.T) {
+			blockNumber := uint64(102)
+
+			if err := createBlock(model, blockNumber); err != nil {
+				t.Fatal(err)
+			}
+
+			log := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          rewardsCoordinator,
+				Arguments:        `[{"Name": "caller", "Type": "address", "Value": "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", "Indexed": true}, {"Name": "operator", "Type": "address", "Value": "0xD36B6E5EEE8311D7BFFB2F3BB33301A1AB7DE101", "Indexed": true}, {"Name": "operatorSet", "Type": "tuple", "Value": null, "Indexed": false}, {"Name": "activatedAt", "Type": "uint32", "Value": null, "Indexed": false}, {"Name": "oldOperatorSetSplitBips", "Type": "uint16", "Value": null, "Indexed": false}, {"Name": "newOperatorSetSplitBips", "Type": "uint16", "Value": null, "Indexed": false}]`,
+				EventName:        "OperatorSetSplitBipsSet",
+				LogIndex:         12,
+				OutputData:       `{"operatorSet": {"avs": "0x9401E5E6564DB35C0f86573a9828DF69Fc778aF1", "id": 1}, "activatedAt": 1733341104, "oldOperatorSetSplitBips": 1000, "newOperatorSetSplitBips": 500}`,
+			}
+
+			err = model.SetupStateForBlock(blockNumber)
+			assert.Nil(t, err)
+
+			isInteresting := model.IsInterestingLog(log)
+			assert.True(t, isInteresting)
+
+			change, err := model.HandleStateChange(log)
+			assert.Nil(t, err)
+			assert.NotNil(t, change)
+
+			split := change.(*OperatorSetSplit)
+
+			assert.Equal(t, "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", split.Operator)
+			assert.Equal(t, "0x9401e5e6564db35c0f86573a9828df69fc778af1", split.Avs)
+			assert.Equal(t, uint64(1), split.OperatorSetId)
+			assert.Equal(t, int64(1733341104), split.ActivatedAt.Unix())
+			assert.Equal(t, uint64(1000), split.OldOperatorSetSplitBips)
+			assert.Equal(t, uint64(500), split.NewOperatorSetSplitBips)
+
+			err = model.CommitFinalState(blockNumber, grm)
+			assert.Nil(t, err)
+
+			splits := make([]*OperatorSetSplit, 0)
+			query := `select * from operator_set_splits where block_number = ?`
+			res := model.DB.Raw(query, blockNumber).Scan(&splits)
+			assert.Nil(t, res.Error)
+			assert.Equal(t, 1, len(splits))
+
+			stateRoot, err := model.GenerateStateRoot(blockNumber)
+			assert.Nil(t, err)
+			assert.True(t, len(stateRoot) > 0)
+
+			t.Cleanup(func() {
+				teardown(model)
+			})
+		})
+
+		t.Cleanup(func() {
+			teardown(model)
+		})
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
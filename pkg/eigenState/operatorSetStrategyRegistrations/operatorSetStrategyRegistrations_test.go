@@ -0,0 +1,167 @@
+package operatorSetStrategyRegistrations
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const allocationManager = "0xabababababababababababababababababababab"
+
+func setup() (
+	string,
+	*gorm.DB,
+	*zap.Logger,
+	*config.Config,
+	error,
+) {
+	cfg := config.NewConfig()
+	cfg.Debug = os.Getenv(config.Debug) == "true"
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	// the tests run against a custom chain so that they can use their own contract addresses and fork heights
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		Contracts: config.ContractAddresses{
+			AllocationManager: allocationManager,
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, grm, l, cfg, nil
+}
+
+func teardown(model *OperatorSetStrategyRegistrationModel) {
+	queries := []string{
+		`truncate table operator_set_strategy_registrations`,
+		`truncate table blocks cascade`,
+	}
+	for _, query := range queries {
+		res := model.DB.Exec(query)
+		if res.Error != nil {
+			fmt.Printf("Failed to run query: %v\n", res.Error)
+		}
+	}
+}
+
+func createBlock(model *OperatorSetStrategyRegistrationModel, blockNumber uint64) error {
+	block := &storage.Block{
+		Number:    blockNumber,
+		Hash:      "some hash",
+		BlockTime: time.Now().Add(time.Hour * time.Duration(blockNumber)),
+	}
+	res := model.DB.Model(&storage.Block{}).Create(block)
+	if res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorSetStrategyRegistration(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test each event type", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+
+		model, err := NewOperatorSetStrategyRegistrationModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		t.Run("Handle a strategy being added to and removed from an operator set", func(t *testing.T) {
+			blockNumber := uint64(102)
+
+			if err := createBlock(model, blockNumber); err != nil {
+				t.Fatal(err)
+			}
+
+			added := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          allocationManager,
+				Arguments:        `[{"Name": "operatorSet", "Type": "tuple", "Value": null, "Indexed": false}, {"Name": "strategy", "Type": "address", "Value": null, "Indexed": false}]`,
+				EventName:        "StrategyAddedToOperatorSet",
+				LogIndex:         12,
+				OutputData:       `{"operatorSet": {"avs": "0x9401e5e6564db35c0f86573a9828df69fc778af1", "id": 1}, "strategy": "0x7D704507B76571A51D9CAE8AdDAbBFd0ba0e63d3"}`,
+			}
+			removed := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          allocationManager,
+				Arguments:        `[{"Name": "operatorSet", "Type": "tuple", "Value": null, "Indexed": false}, {"Name": "strategy", "Type": "address", "Value": null, "Indexed": false}]`,
+				EventName:        "StrategyRemovedFromOperatorSet",
+				LogIndex:         13,
+				OutputData:       `{"operatorSet": {"avs": "0x9401e5e6564db35c0f86573a9828df69fc778af1", "id": 1}, "strategy": "0x7D704507B76571A51D9CAE8AdDAbBFd0ba0e63d3"}`,
+			}
+
+			err = model.SetupStateForBlock(blockNumber)
+			assert.Nil(t, err)
+
+			assert.True(t, model.IsInterestingLog(added))
+			assert.True(t, model.IsInterestingLog(removed))
+
+			change, err := model.HandleStateChange(added)
+			assert.Nil(t, err)
+			registration := change.(*OperatorSetStrategyRegistration)
+			assert.Equal(t, "0x7d704507b76571a51d9cae8addabbfd0ba0e63d3", registration.Strategy)
+			assert.Equal(t, "0x9401e5e6564db35c0f86573a9828df69fc778af1", registration.Avs)
+			assert.Equal(t, uint64(1), registration.OperatorSetId)
+			assert.True(t, registration.IsActive)
+
+			change, err = model.HandleStateChange(removed)
+			assert.Nil(t, err)
+			registration = change.(*OperatorSetStrategyRegistration)
+			assert.False(t, registration.IsActive)
+
+			err = model.CommitFinalState(blockNumber, grm)
+			assert.Nil(t, err)
+
+			registrations := make([]*OperatorSetStrategyRegistration, 0)
+			query := `select * from operator_set_strategy_registrations where block_number = ?`
+			res := model.DB.Raw(query, blockNumber).Scan(&registrations)
+			assert.Nil(t, res.Error)
+			assert.Equal(t, 2, len(registrations))
+
+			stateRoot, err := model.GenerateStateRoot(blockNumber)
+			assert.Nil(t, err)
+			assert.True(t, len(stateRoot) > 0)
+
+			t.Cleanup(func() {
+				teardown(model)
+			})
+		})
+
+		t.Cleanup(func() {
+			teardown(model)
+		})
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
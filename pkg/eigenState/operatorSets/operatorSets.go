@@ -0,0 +1,301 @@
+package operatorSets
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OperatorSet is an operator set created by an AVS. Operator sets are identified by the AVS and an id that is only
+// unique within the AVS.
+type OperatorSet struct {
+	Avs             string
+	OperatorSetId   uint64
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
+
+type OperatorSetModel struct {
+	base.BaseEigenState
+	DB           *gorm.DB
+	logger       *zap.Logger
+	globalConfig *config.Config
+
+	// Accumulates state changes for SlotIds, grouped by block number
+	stateAccumulator map[uint64]map[types.SlotID]*OperatorSet
+	committedState   map[uint64][]*OperatorSet
+}
+
+func NewOperatorSetModel(
+	esm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	logger *zap.Logger,
+	globalConfig *config.Config,
+) (*OperatorSetModel, error) {
+	model := &OperatorSetModel{
+		BaseEigenState: base.BaseEigenState{
+			Logger: logger,
+		},
+		DB:               grm,
+		logger:           logger,
+		globalConfig:     globalConfig,
+		stateAccumulator: make(map[uint64]map[types.SlotID]*OperatorSet),
+		committedState:   make(map[uint64][]*OperatorSet),
+	}
+
+	esm.RegisterState(model, 11)
+	return model, nil
+}
+
+func (osm *OperatorSetModel) GetModelName() string {
+	return "OperatorSetModel"
+}
+
+type operatorSetCreatedOutputData struct {
+	OperatorSet struct {
+		Avs string `json:"avs"`
+		Id  uint64 `json:"id"`
+	} `json:"operatorSet"`
+}
+
+func parseOperatorSetCreatedOutputData(outputDataStr string) (*operatorSetCreatedOutputData, error) {
+	outputData := &operatorSetCreatedOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(outputDataStr))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&outputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputData, err
+}
+
+func (osm *OperatorSetModel) handleOperatorSetCreatedEvent(log *storage.TransactionLog) (*OperatorSet, error) {
+	outputData, err := parseOperatorSetCreatedOutputData(log.OutputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperatorSet{
+		Avs:             strings.ToLower(outputData.OperatorSet.Avs),
+		OperatorSetId:   outputData.OperatorSet.Id,
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TransactionHash,
+		LogIndex:        log.LogIndex,
+	}, nil
+}
+
+func (osm *OperatorSetModel) GetStateTransitions() (types.StateTransitions[*OperatorSet], []uint64) {
+	stateChanges := make(types.StateTransitions[*OperatorSet])
+
+	stateChanges[0] = func(log *storage.TransactionLog) (*OperatorSet, error) {
+		operatorSet, err := osm.handleOperatorSetCreatedEvent(log)
+		if err != nil {
+			return nil, err
+		}
+
+		slotId := base.NewSlotID(operatorSet.TransactionHash, operatorSet.LogIndex)
+
+		_, ok := osm.stateAccumulator[log.BlockNumber][slotId]
+		if ok {
+			err := fmt.Errorf("Duplicate operator set submitted for slot %s at block %d", slotId, log.BlockNumber)
+			osm.logger.Sugar().Errorw("Duplicate operator set submitted", zap.Error(err))
+			return nil, err
+		}
+
+		osm.stateAccumulator[log.BlockNumber][slotId] = operatorSet
+
+		return operatorSet, nil
+	}
+
+	// Create an ordered list of block numbers
+	blockNumbers := make([]uint64, 0)
+	for blockNumber := range stateChanges {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	slices.Reverse(blockNumbers)
+
+	return stateChanges, blockNumbers
+}
+
+func (osm *OperatorSetModel) getContractAddressesForEnvironment() map[string][]string {
+	contracts := osm.globalConfig.GetContractsMapForChain()
+	if contracts.AllocationManager == "" {
+		return map[string][]string{}
+	}
+	return map[string][]string{
+		contracts.AllocationManager: {
+			"OperatorSetCreated",
+		},
+	}
+}
+
+func (osm *OperatorSetModel) IsInterestingLog(log *storage.TransactionLog) bool {
+	addresses := osm.getContractAddressesForEnvironment()
+	return osm.BaseEigenState.IsInterestingLog(addresses, log)
+}
+
+func (osm *OperatorSetModel) SetupStateForBlock(blockNumber uint64) error {
+	osm.stateAccumulator[blockNumber] = make(map[types.SlotID]*OperatorSet)
+	osm.committedState[blockNumber] = make([]*OperatorSet, 0)
+	return nil
+}
+
+func (osm *OperatorSetModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
+	delete(osm.stateAccumulator, blockNumber)
+	delete(osm.committedState, blockNumber)
+	return nil
+}
+
+func (osm *OperatorSetModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	stateChanges, sortedBlockNumbers := osm.GetStateTransitions()
+
+	for _, blockNumber := range sortedBlockNumbers {
+		if log.BlockNumber >= blockNumber {
+			osm.logger.Sugar().Debugw("Handling state change", zap.Uint64("blockNumber", log.BlockNumber))
+
+			change, err := stateChanges[blockNumber](log)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				return nil, nil
+			}
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+// prepareState prepares the state for commit by adding the new state to the existing state.
+func (osm *OperatorSetModel) prepareState(blockNumber uint64) ([]*OperatorSet, error) {
+	accumulatedState, ok := osm.stateAccumulator[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No accumulated state found for block %d", blockNumber)
+		osm.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+
+	recordsToInsert := make([]*OperatorSet, 0)
+	for _, operatorSet := range accumulatedState {
+		recordsToInsert = append(recordsToInsert, operatorSet)
+	}
+	return recordsToInsert, nil
+}
+
+// CommitFinalState commits the final state for the given block number.
+func (osm *OperatorSetModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	recordsToInsert, err := osm.prepareState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if len(recordsToInsert) > 0 {
+		res := tx.Model(&OperatorSet{}).Clauses(clause.Returning{}).Create(&recordsToInsert)
+		if res.Error != nil {
+			osm.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	osm.committedState[blockNumber] = recordsToInsert
+	return nil
+}
+
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+//
+// Operator sets are only part of the state root from ModelFork_Boston.
+func (osm *OperatorSetModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	inserts, err := osm.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	active, err := osm.globalConfig.IsModelForkActive(config.ModelFork_Boston, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []*base.MerkleTreeInput{}, nil
+	}
+	return osm.sortValuesForMerkleTree(inserts), nil
+}
+
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (osm *OperatorSetModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := osm.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	fullTree, err := osm.MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		osm.logger.Sugar().Errorw("Failed to create merkle tree",
+			zap.Error(err),
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Any("inputs", inputs),
+		)
+		return nil, err
+	}
+	return fullTree.Root(), nil
+}
+
+func (osm *OperatorSetModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	records, ok := osm.committedState[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No committed state found for block %d", blockNumber)
+		osm.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+	return base.CastCommittedStateToInterface(records), nil
+}
+
+func (osm *OperatorSetModel) sortValuesForMerkleTree(operatorSets []*OperatorSet) []*base.MerkleTreeInput {
+	inputs := make([]*base.MerkleTreeInput, 0)
+	for _, operatorSet := range operatorSets {
+		slotID := base.NewSlotID(operatorSet.TransactionHash, operatorSet.LogIndex)
+		value := fmt.Sprintf("%s_%016x", operatorSet.Avs, operatorSet.OperatorSetId)
+		inputs = append(inputs, &base.MerkleTreeInput{
+			SlotID: slotID,
+			Value:  []byte(value),
+		})
+	}
+
+	slices.SortFunc(inputs, func(i, j *base.MerkleTreeInput) int {
+		return strings.Compare(string(i.SlotID), string(j.SlotID))
+	})
+
+	return inputs
+}
+
+func (osm *OperatorSetModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return osm.BaseEigenState.DeleteState("operator_sets", startBlockNumber, endBlockNumber, tx)
+}
+
+func (osm *OperatorSetModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	var operatorSets []*OperatorSet
+	res := osm.DB.Where("block_number >= ? AND block_number <= ?", startBlockNumber, endBlockNumber).Find(&operatorSets)
+	if res.Error != nil {
+		osm.logger.Sugar().Errorw("Failed to list records", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	return base.CastCommittedStateToInterface(operatorSets), nil
+}
@@ -93,10 +93,64 @@ func parseLogOutputForOperatorShares(outputDataStr string) (*operatorSharesOutpu
 	return outputData, err
 }
 
+type operatorSharesSlashedOutput struct {
+	Strategy           string      `json:"strategy"`
+	TotalSlashedShares json.Number `json:"totalSlashedShares"`
+}
+
+// handleOperatorSharesSlashedEvent returns the shares an operator lost to a slashing as a negative delta that is not
+// attributed to any staker. Slashings are only applied from ModelFork_Boston.
+func (osm *OperatorSharesModel) handleOperatorSharesSlashedEvent(log *storage.TransactionLog) (*OperatorShareDeltas, error) {
+	active, err := osm.globalConfig.IsModelForkActive(config.ModelFork_Boston, log.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, nil
+	}
+
+	arguments, err := osm.ParseLogArguments(log)
+	if err != nil {
+		return nil, err
+	}
+	outputData := &operatorSharesSlashedOutput{}
+	decoder := json.NewDecoder(strings.NewReader(log.OutputData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&outputData); err != nil {
+		return nil, err
+	}
+
+	shares, err := decimal.NewFromString(outputData.TotalSlashedShares.String())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to convert slashed shares to big.Int: %s", outputData.TotalSlashedShares.String())
+	}
+
+	return &OperatorShareDeltas{
+		Operator:        strings.ToLower(arguments[0].Value.(string)),
+		Strategy:        strings.ToLower(outputData.Strategy),
+		Shares:          shares.Neg().String(),
+		TransactionHash: log.TransactionHash,
+		LogIndex:        log.LogIndex,
+		BlockNumber:     log.BlockNumber,
+	}, nil
+}
+
 func (osm *OperatorSharesModel) GetStateTransitions() (types.StateTransitions[*OperatorShareDeltas], []uint64) {
 	stateChanges := make(types.StateTransitions[*OperatorShareDeltas])
 
 	stateChanges[0] = func(log *storage.TransactionLog) (*OperatorShareDeltas, error) {
+		if log.EventName == "OperatorSharesSlashed" {
+			delta, err := osm.handleOperatorSharesSlashedEvent(log)
+			if err != nil || delta == nil {
+				return nil, err
+			}
+			if _, ok := osm.stateAccumulator[log.BlockNumber]; !ok {
+				return nil, fmt.Errorf("No state accumulator found for block %d", log.BlockNumber)
+			}
+			osm.stateAccumulator[log.BlockNumber] = append(osm.stateAccumulator[log.BlockNumber], delta)
+			return delta, nil
+		}
+
 		arguments, err := osm.ParseLogArguments(log)
 		if err != nil {
 			return nil, err
@@ -161,6 +215,7 @@ func (osm *OperatorSharesModel) getContractAddressesForEnvironment() map[string]
 		contracts.DelegationManager: {
 			"OperatorSharesIncreased",
 			"OperatorSharesDecreased",
+			"OperatorSharesSlashed",
 		},
 	}
 }
@@ -215,14 +270,14 @@ func (osm *OperatorSharesModel) prepareState(blockNumber uint64) ([]*OperatorSha
 	return records, nil
 }
 
-func (osm *OperatorSharesModel) writeDeltaRecords(blockNumber uint64) error {
+func (osm *OperatorSharesModel) writeDeltaRecords(blockNumber uint64, tx *gorm.DB) error {
 	deltas := osm.stateAccumulator[blockNumber]
 	if len(deltas) == 0 {
 		return nil
 	}
 
 	var block storage.Block
-	res := osm.DB.Model(&storage.Block{}).Where("number = ?", blockNumber).First(&block)
+	res := tx.Model(&storage.Block{}).Where("number = ?", blockNumber).First(&block)
 	if res.Error != nil {
 		osm.logger.Sugar().Errorw("Failed to fetch block", zap.Error(res.Error))
 		return res.Error
@@ -233,7 +288,7 @@ func (osm *OperatorSharesModel) writeDeltaRecords(blockNumber uint64) error {
 		d.BlockDate = block.BlockTime.Format(time.DateOnly)
 	}
 
-	res = osm.DB.Model(&OperatorShareDeltas{}).Clauses(clause.Returning{}).Create(&deltas)
+	res = tx.Model(&OperatorShareDeltas{}).Clauses(clause.Returning{}).Create(&deltas)
 	if res.Error != nil {
 		osm.logger.Sugar().Errorw("Failed to create new operator_share_deltas records", zap.Error(res.Error))
 		return res.Error
@@ -243,21 +298,28 @@ func (osm *OperatorSharesModel) writeDeltaRecords(blockNumber uint64) error {
 	return nil
 }
 
-func (osm *OperatorSharesModel) CommitFinalState(blockNumber uint64) error {
-	if err := osm.writeDeltaRecords(blockNumber); err != nil {
+func (osm *OperatorSharesModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	if err := osm.writeDeltaRecords(blockNumber, tx); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (osm *OperatorSharesModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (osm *OperatorSharesModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	deltas, err := osm.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return osm.sortValuesForMerkleTree(deltas), nil
+}
 
-	inputs := osm.sortValuesForMerkleTree(deltas)
+func (osm *OperatorSharesModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := osm.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(inputs) == 0 {
 		return nil, nil
@@ -299,8 +361,8 @@ func (osm *OperatorSharesModel) sortValuesForMerkleTree(diffs []*OperatorShareDe
 	return inputs
 }
 
-func (osm *OperatorSharesModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return osm.BaseEigenState.DeleteState("operator_share_deltas", startBlockNumber, endBlockNumber, osm.DB)
+func (osm *OperatorSharesModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return osm.BaseEigenState.DeleteState("operator_share_deltas", startBlockNumber, endBlockNumber, tx)
 }
 
 func (osm *OperatorSharesModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
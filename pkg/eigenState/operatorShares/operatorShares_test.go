@@ -107,7 +107,7 @@ func Test_OperatorSharesState(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, change)
 
-		err = model.CommitFinalState(block.Number)
+		err = model.CommitFinalState(block.Number, grm)
 		assert.Nil(t, err)
 
 		states := []OperatorShareDeltas{}
@@ -173,7 +173,7 @@ func Test_OperatorSharesState(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, change)
 
-		err = model.CommitFinalState(block.Number)
+		err = model.CommitFinalState(block.Number, grm)
 		assert.Nil(t, err)
 
 		states = []OperatorShareDeltas{}
@@ -217,6 +217,32 @@ func Test_OperatorSharesState(t *testing.T) {
 		}
 		assert.Equal(t, "0", rows[1].Shares)
 	})
+	t.Run("Should ignore OperatorSharesSlashed before the slashing fork", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+		model, err := NewOperatorSharesModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		blockNumber := uint64(200)
+		slashedLog := storage.TransactionLog{
+			TransactionHash:  "some hash",
+			TransactionIndex: 1,
+			BlockNumber:      blockNumber,
+			Address:          cfg.GetContractsMapForChain().DelegationManager,
+			Arguments:        `[{"Name": "operator", "Type": "address", "Value": "0xd172a86a0f250aec23ee19c759a8e73621fe3c10", "Indexed": true}, {"Name": "strategy", "Type": "address", "Value": null, "Indexed": false}, {"Name": "totalSlashedShares", "Type": "uint256", "Value": null, "Indexed": false}]`,
+			EventName:        "OperatorSharesSlashed",
+			LogIndex:         1,
+			OutputData:       `{"strategy": "0x13760f50a9d7377e4f20cb8cf9e4c26586c658ff", "totalSlashedShares": 1000}`,
+		}
+
+		err = model.SetupStateForBlock(blockNumber)
+		assert.Nil(t, err)
+
+		assert.True(t, model.IsInterestingLog(&slashedLog))
+
+		change, err := model.HandleStateChange(&slashedLog)
+		assert.Nil(t, err)
+		assert.Nil(t, change)
+	})
 	t.Cleanup(func() {
 		// postgres.TeardownTestDatabase(dbName, cfg, grm, l)
 	})
@@ -0,0 +1,181 @@
+// Package plugins allows Go modules that embed the sidecar to register their own eigen state models.
+//
+// Registered models are loaded by eigenState.LoadEigenStateModels after the built-in models and take part in the
+// same ordered, per-block pipeline: they see every log, commit their state in the block's transaction, are rolled
+// back on reorgs and, unless they opt out, contribute a leaf to the block's state root.
+//
+// Plugins are typically registered from an init function before the sidecar commands are executed:
+//
+//	func init() {
+//		plugins.MustRegisterEigenStateModel(&plugins.EigenStateModelPlugin{
+//			Name:       MyRegistryModelName,
+//			After:      []string{"OperatorSharesModel"},
+//			Migrations: []plugins.Migration{&createMyRegistryTable{}},
+//			NewModel:   NewMyRegistryModel,
+//		})
+//	}
+package plugins
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ModelIndexOffset is the state manager index of the first plugin model. Built-in models use the indexes below it,
+// so plugin models are always processed, and included in the state root, after every built-in model.
+const ModelIndexOffset = 1000
+
+// Migration is a database migration run by the sidecar's migrator after its own migrations.
+//
+// Migrations are recorded by name and only run once, so names must be unique across the sidecar and every plugin.
+// Prefixing names with the plugin name is recommended.
+type Migration interface {
+	Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error
+	GetName() string
+}
+
+// ModelConstructor creates a new instance of a plugin model.
+//
+// A new instance is created for every EigenStateManager, so the constructor must not rely on global state.
+type ModelConstructor func(grm *gorm.DB, l *zap.Logger, cfg *config.Config) (types.IEigenStateModel, error)
+
+type EigenStateModelPlugin struct {
+	// Name is the name of the model and must match the GetModelName of the models it creates
+	Name string
+
+	// After lists the names of models, built-in or plugin, that this model must be processed after.
+	// Plugin models are always processed after the built-in models.
+	After []string
+
+	// Migrations are run, in order, after the sidecar's own migrations
+	Migrations []Migration
+
+	// ExcludeFromStateRoot keeps the model's state out of the block's state root, which allows a sidecar
+	// with the plugin loaded to keep producing the same state roots as sidecars without it.
+	ExcludeFromStateRoot bool
+
+	NewModel ModelConstructor
+}
+
+type registry struct {
+	mu      sync.Mutex
+	plugins map[string]*EigenStateModelPlugin
+}
+
+var defaultRegistry = &registry{
+	plugins: make(map[string]*EigenStateModelPlugin),
+}
+
+// RegisterEigenStateModel registers a plugin model to be loaded alongside the built-in models
+func RegisterEigenStateModel(plugin *EigenStateModelPlugin) error {
+	return defaultRegistry.register(plugin)
+}
+
+// MustRegisterEigenStateModel is like RegisterEigenStateModel but panics if the plugin cannot be registered
+func MustRegisterEigenStateModel(plugin *EigenStateModelPlugin) {
+	if err := RegisterEigenStateModel(plugin); err != nil {
+		panic(err)
+	}
+}
+
+// GetEigenStateModels returns the registered plugins in the order their models must be processed
+func GetEigenStateModels() ([]*EigenStateModelPlugin, error) {
+	return defaultRegistry.ordered()
+}
+
+// GetMigrations returns the migrations of every registered plugin, in plugin order
+func GetMigrations() ([]Migration, error) {
+	ordered, err := defaultRegistry.ordered()
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0)
+	for _, plugin := range ordered {
+		migrations = append(migrations, plugin.Migrations...)
+	}
+	return migrations, nil
+}
+
+func (r *registry) register(plugin *EigenStateModelPlugin) error {
+	if plugin == nil {
+		return errors.New("plugin must not be nil")
+	}
+	if plugin.Name == "" {
+		return errors.New("plugin name must not be empty")
+	}
+	if plugin.NewModel == nil {
+		return fmt.Errorf("plugin '%s' must provide a model constructor", plugin.Name)
+	}
+	for _, migration := range plugin.Migrations {
+		if migration == nil || migration.GetName() == "" {
+			return fmt.Errorf("plugin '%s' has a migration without a name", plugin.Name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.plugins[plugin.Name]; ok {
+		return fmt.Errorf("plugin '%s' is already registered", plugin.Name)
+	}
+	r.plugins[plugin.Name] = plugin
+	return nil
+}
+
+// ordered sorts the plugins so that every plugin comes after the plugins named in its After list.
+// Plugins that do not depend on each other are sorted by name so the order is stable across builds.
+// Names in After that are not plugins refer to built-in models, which always come first.
+func (r *registry) ordered() ([]*EigenStateModelPlugin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := make(map[string]int, len(r.plugins))
+	dependents := make(map[string][]string, len(r.plugins))
+	for name, plugin := range r.plugins {
+		remaining[name] = 0
+		for _, after := range plugin.After {
+			if _, ok := r.plugins[after]; !ok {
+				continue
+			}
+			if after == name {
+				return nil, fmt.Errorf("plugin '%s' cannot be ordered after itself", name)
+			}
+			remaining[name]++
+			dependents[after] = append(dependents[after], name)
+		}
+	}
+
+	ready := make([]string, 0)
+	for name, count := range remaining {
+		if count == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	ordered := make([]*EigenStateModelPlugin, 0, len(r.plugins))
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		name := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, r.plugins[name])
+
+		for _, dependent := range dependents[name] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(ordered) != len(r.plugins) {
+		return nil, errors.New("plugins have a circular ordering dependency")
+	}
+	return ordered, nil
+}
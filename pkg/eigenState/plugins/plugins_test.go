@@ -0,0 +1,73 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestRegistry() *registry {
+	return &registry{plugins: make(map[string]*EigenStateModelPlugin)}
+}
+
+func newTestPlugin(name string, after ...string) *EigenStateModelPlugin {
+	return &EigenStateModelPlugin{
+		Name:  name,
+		After: after,
+		NewModel: func(grm *gorm.DB, l *zap.Logger, cfg *config.Config) (types.IEigenStateModel, error) {
+			return nil, nil
+		},
+	}
+}
+
+func pluginNames(plugins []*EigenStateModelPlugin) []string {
+	names := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func Test_Plugins(t *testing.T) {
+	t.Run("Should order plugins by name when they do not depend on each other", func(t *testing.T) {
+		r := newTestRegistry()
+		assert.Nil(t, r.register(newTestPlugin("C")))
+		assert.Nil(t, r.register(newTestPlugin("A", "OperatorSharesModel")))
+		assert.Nil(t, r.register(newTestPlugin("B")))
+
+		ordered, err := r.ordered()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"A", "B", "C"}, pluginNames(ordered))
+	})
+	t.Run("Should order plugins after the plugins they depend on", func(t *testing.T) {
+		r := newTestRegistry()
+		assert.Nil(t, r.register(newTestPlugin("A", "C")))
+		assert.Nil(t, r.register(newTestPlugin("B")))
+		assert.Nil(t, r.register(newTestPlugin("C", "B")))
+
+		ordered, err := r.ordered()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"B", "C", "A"}, pluginNames(ordered))
+	})
+	t.Run("Should fail for a circular dependency", func(t *testing.T) {
+		r := newTestRegistry()
+		assert.Nil(t, r.register(newTestPlugin("A", "B")))
+		assert.Nil(t, r.register(newTestPlugin("B", "A")))
+
+		_, err := r.ordered()
+		assert.NotNil(t, err)
+	})
+	t.Run("Should not register a plugin twice", func(t *testing.T) {
+		r := newTestRegistry()
+		assert.Nil(t, r.register(newTestPlugin("A")))
+		assert.NotNil(t, r.register(newTestPlugin("A")))
+	})
+	t.Run("Should not register a plugin without a constructor", func(t *testing.T) {
+		r := newTestRegistry()
+		assert.NotNil(t, r.register(&EigenStateModelPlugin{Name: "A"}))
+	})
+}
@@ -291,7 +291,7 @@ func (rs *RewardSubmissionsModel) prepareState(blockNumber uint64) ([]*RewardSub
 }
 
 // CommitFinalState commits the final state for the given block number.
-func (rs *RewardSubmissionsModel) CommitFinalState(blockNumber uint64) error {
+func (rs *RewardSubmissionsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	recordsToInsert, err := rs.prepareState(blockNumber)
 	if err != nil {
 		return err
@@ -299,7 +299,7 @@ func (rs *RewardSubmissionsModel) CommitFinalState(blockNumber uint64) error {
 
 	if len(recordsToInsert) > 0 {
 		for _, record := range recordsToInsert {
-			res := rs.DB.Model(&RewardSubmission{}).Clauses(clause.Returning{}).Create(&record)
+			res := tx.Model(&RewardSubmission{}).Clauses(clause.Returning{}).Create(&record)
 			if res.Error != nil {
 				rs.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
 				return res.Error
@@ -319,14 +319,21 @@ func (rs *RewardSubmissionsModel) GetCommittedState(blockNumber uint64) ([]inter
 	return base.CastCommittedStateToInterface(records), nil
 }
 
-// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
-func (rs *RewardSubmissionsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (rs *RewardSubmissionsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	inserts, err := rs.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return rs.sortValuesForMerkleTree(inserts), nil
+}
 
-	inputs := rs.sortValuesForMerkleTree(inserts)
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (rs *RewardSubmissionsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := rs.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(inputs) == 0 {
 		return nil, nil
@@ -362,8 +369,8 @@ func (rs *RewardSubmissionsModel) sortValuesForMerkleTree(submissions []*RewardS
 	return inputs
 }
 
-func (rs *RewardSubmissionsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return rs.BaseEigenState.DeleteState("reward_submissions", startBlockNumber, endBlockNumber, rs.DB)
+func (rs *RewardSubmissionsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return rs.BaseEigenState.DeleteState("reward_submissions", startBlockNumber, endBlockNumber, tx)
 }
 
 func (rs *RewardSubmissionsModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
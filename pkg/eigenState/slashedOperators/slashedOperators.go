@@ -0,0 +1,327 @@
+package slashedOperators
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SlashedOperator is the share of one strategy an AVS slashed from an operator through an operator set, as a
+// fraction of 1e18 (WAD) of the magnitude the operator allocated to it
+type SlashedOperator struct {
+	Operator        string
+	Avs             string
+	OperatorSetId   uint64
+	Strategy        string
+	WadSlashed      string
+	Description     string
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
+
+type SlashedOperatorModel struct {
+	base.BaseEigenState
+	DB           *gorm.DB
+	logger       *zap.Logger
+	globalConfig *config.Config
+
+	// Accumulates state changes for SlotIds, grouped by block number
+	stateAccumulator map[uint64]map[types.SlotID]*SlashedOperator
+	committedState   map[uint64][]*SlashedOperator
+}
+
+func NewSlashedOperatorModel(
+	esm *stateManager.EigenStateManager,
+	grm *gorm.DB,
+	logger *zap.Logger,
+	globalConfig *config.Config,
+) (*SlashedOperatorModel, error) {
+	model := &SlashedOperatorModel{
+		BaseEigenState: base.BaseEigenState{
+			Logger: logger,
+		},
+		DB:               grm,
+		logger:           logger,
+		globalConfig:     globalConfig,
+		stateAccumulator: make(map[uint64]map[types.SlotID]*SlashedOperator),
+		committedState:   make(map[uint64][]*SlashedOperator),
+	}
+
+	esm.RegisterState(model, 16)
+	return model, nil
+}
+
+func (so *SlashedOperatorModel) GetModelName() string {
+	return "SlashedOperatorModel"
+}
+
+type operatorSlashedOutputData struct {
+	Operator    string `json:"operator"`
+	OperatorSet struct {
+		Avs string `json:"avs"`
+		Id  uint64 `json:"id"`
+	} `json:"operatorSet"`
+	Strategies  []string      `json:"strategies"`
+	WadSlashed  []json.Number `json:"wadSlashed"`
+	Description string        `json:"description"`
+}
+
+func parseOperatorSlashedOutputData(outputDataStr string) (*operatorSlashedOutputData, error) {
+	outputData := &operatorSlashedOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(outputDataStr))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&outputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return outputData, err
+}
+
+// handleOperatorSlashedEvent returns a record for every strategy slashed by the event
+func (so *SlashedOperatorModel) handleOperatorSlashedEvent(log *storage.TransactionLog) ([]*SlashedOperator, error) {
+	outputData, err := parseOperatorSlashedOutputData(log.OutputData)
+	if err != nil {
+		return nil, err
+	}
+	if len(outputData.Strategies) != len(outputData.WadSlashed) {
+		return nil, fmt.Errorf("OperatorSlashed has %d strategies and %d slashed amounts", len(outputData.Strategies), len(outputData.WadSlashed))
+	}
+
+	slashings := make([]*SlashedOperator, 0, len(outputData.Strategies))
+	for i, strategy := range outputData.Strategies {
+		slashings = append(slashings, &SlashedOperator{
+			Operator:        strings.ToLower(outputData.Operator),
+			Avs:             strings.ToLower(outputData.OperatorSet.Avs),
+			OperatorSetId:   outputData.OperatorSet.Id,
+			Strategy:        strings.ToLower(strategy),
+			WadSlashed:      outputData.WadSlashed[i].String(),
+			Description:     outputData.Description,
+			BlockNumber:     log.BlockNumber,
+			TransactionHash: log.TransactionHash,
+			LogIndex:        log.LogIndex,
+		})
+	}
+	return slashings, nil
+}
+
+func NewSlotID(transactionHash string, logIndex uint64, strategy string) types.SlotID {
+	return base.NewSlotIDWithSuffix(transactionHash, logIndex, strategy)
+}
+
+func (so *SlashedOperatorModel) GetStateTransitions() (types.StateTransitions[[]*SlashedOperator], []uint64) {
+	stateChanges := make(types.StateTransitions[[]*SlashedOperator])
+
+	stateChanges[0] = func(log *storage.TransactionLog) ([]*SlashedOperator, error) {
+		slashings, err := so.handleOperatorSlashedEvent(log)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, slashing := range slashings {
+			slotId := NewSlotID(slashing.TransactionHash, slashing.LogIndex, slashing.Strategy)
+
+			_, ok := so.stateAccumulator[log.BlockNumber][slotId]
+			if ok {
+				err := fmt.Errorf("Duplicate operator slashing submitted for slot %s at block %d", slotId, log.BlockNumber)
+				so.logger.Sugar().Errorw("Duplicate operator slashing submitted", zap.Error(err))
+				return nil, err
+			}
+
+			so.stateAccumulator[log.BlockNumber][slotId] = slashing
+		}
+
+		return slashings, nil
+	}
+
+	// Create an ordered list of block numbers
+	blockNumbers := make([]uint64, 0)
+	for blockNumber := range stateChanges {
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] < blockNumbers[j]
+	})
+	slices.Reverse(blockNumbers)
+
+	return stateChanges, blockNumbers
+}
+
+func (so *SlashedOperatorModel) getContractAddressesForEnvironment() map[string][]string {
+	contracts := so.globalConfig.GetContractsMapForChain()
+	if contracts.AllocationManager == "" {
+		return map[string][]string{}
+	}
+	return map[string][]string{
+		contracts.AllocationManager: {
+			"OperatorSlashed",
+		},
+	}
+}
+
+func (so *SlashedOperatorModel) IsInterestingLog(log *storage.TransactionLog) bool {
+	addresses := so.getContractAddressesForEnvironment()
+	return so.BaseEigenState.IsInterestingLog(addresses, log)
+}
+
+func (so *SlashedOperatorModel) SetupStateForBlock(blockNumber uint64) error {
+	so.stateAccumulator[blockNumber] = make(map[types.SlotID]*SlashedOperator)
+	so.committedState[blockNumber] = make([]*SlashedOperator, 0)
+	return nil
+}
+
+func (so *SlashedOperatorModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
+	delete(so.stateAccumulator, blockNumber)
+	delete(so.committedState, blockNumber)
+	return nil
+}
+
+func (so *SlashedOperatorModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	stateChanges, sortedBlockNumbers := so.GetStateTransitions()
+
+	for _, blockNumber := range sortedBlockNumbers {
+		if log.BlockNumber >= blockNumber {
+			so.logger.Sugar().Debugw("Handling state change", zap.Uint64("blockNumber", log.BlockNumber))
+
+			change, err := stateChanges[blockNumber](log)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				return nil, nil
+			}
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+// prepareState prepares the state for commit by adding the new state to the existing state.
+func (so *SlashedOperatorModel) prepareState(blockNumber uint64) ([]*SlashedOperator, error) {
+	accumulatedState, ok := so.stateAccumulator[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No accumulated state found for block %d", blockNumber)
+		so.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+
+	recordsToInsert := make([]*SlashedOperator, 0)
+	for _, slashing := range accumulatedState {
+		recordsToInsert = append(recordsToInsert, slashing)
+	}
+	return recordsToInsert, nil
+}
+
+// CommitFinalState commits the final state for the given block number.
+func (so *SlashedOperatorModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	recordsToInsert, err := so.prepareState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if len(recordsToInsert) > 0 {
+		res := tx.Model(&SlashedOperator{}).Clauses(clause.Returning{}).Create(&recordsToInsert)
+		if res.Error != nil {
+			so.logger.Sugar().Errorw("Failed to insert records", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	so.committedState[blockNumber] = recordsToInsert
+	return nil
+}
+
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+//
+// Slashings are only part of the state root from ModelFork_Boston.
+func (so *SlashedOperatorModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	inserts, err := so.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	active, err := so.globalConfig.IsModelForkActive(config.ModelFork_Boston, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []*base.MerkleTreeInput{}, nil
+	}
+	return so.sortValuesForMerkleTree(inserts), nil
+}
+
+// GenerateStateRoot generates the state root for the given block number using the results of the state changes.
+func (so *SlashedOperatorModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := so.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	fullTree, err := so.MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		so.logger.Sugar().Errorw("Failed to create merkle tree",
+			zap.Error(err),
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Any("inputs", inputs),
+		)
+		return nil, err
+	}
+	return fullTree.Root(), nil
+}
+
+func (so *SlashedOperatorModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	records, ok := so.committedState[blockNumber]
+	if !ok {
+		err := fmt.Errorf("No committed state found for block %d", blockNumber)
+		so.logger.Sugar().Errorw(err.Error(), zap.Error(err), zap.Uint64("blockNumber", blockNumber))
+		return nil, err
+	}
+	return base.CastCommittedStateToInterface(records), nil
+}
+
+func (so *SlashedOperatorModel) sortValuesForMerkleTree(slashings []*SlashedOperator) []*base.MerkleTreeInput {
+	inputs := make([]*base.MerkleTreeInput, 0)
+	for _, slashing := range slashings {
+		slotID := NewSlotID(slashing.TransactionHash, slashing.LogIndex, slashing.Strategy)
+		value := fmt.Sprintf("%s_%s_%016x_%s_%s", slashing.Operator, slashing.Avs, slashing.OperatorSetId, slashing.Strategy, slashing.WadSlashed)
+		inputs = append(inputs, &base.MerkleTreeInput{
+			SlotID: slotID,
+			Value:  []byte(value),
+		})
+	}
+
+	slices.SortFunc(inputs, func(i, j *base.MerkleTreeInput) int {
+		return strings.Compare(string(i.SlotID), string(j.SlotID))
+	})
+
+	return inputs
+}
+
+func (so *SlashedOperatorModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return so.BaseEigenState.DeleteState("slashed_operators", startBlockNumber, endBlockNumber, tx)
+}
+
+func (so *SlashedOperatorModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	var slashings []*SlashedOperator
+	res := so.DB.Where("block_number >= ? AND block_number <= ?", startBlockNumber, endBlockNumber).Find(&slashings)
+	if res.Error != nil {
+		so.logger.Sugar().Errorw("Failed to list records", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	return base.CastCommittedStateToInterface(slashings), nil
+}
@@ -0,0 +1,184 @@
+package slashedOperators
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const allocationManager = "0xabababababababababababababababababababab"
+
+func setup() (
+	string,
+	*gorm.DB,
+	*zap.Logger,
+	*config.Config,
+	error,
+) {
+	cfg := config.NewConfig()
+	cfg.Debug = os.Getenv(config.Debug) == "true"
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	// the tests run against a custom chain so that they can use their own contract addresses and fork heights
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		Contracts: config.ContractAddresses{
+			AllocationManager: allocationManager,
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, grm, l, cfg, nil
+}
+
+func teardown(model *SlashedOperatorModel) {
+	queries := []string{
+		`truncate table slashed_operators`,
+		`truncate table blocks cascade`,
+	}
+	for _, query := range queries {
+		res := model.DB.Exec(query)
+		if res.Error != nil {
+			fmt.Printf("Failed to run query: %v\n", res.Error)
+		}
+	}
+}
+
+func createBlock(model *SlashedOperatorModel, blockNumber uint64) error {
+	block := &storage.Block{
+		Number:    blockNumber,
+		Hash:      "some hash",
+		BlockTime: time.Now().Add(time.Hour * time.Duration(blockNumber)),
+	}
+	res := model.DB.Model(&storage.Block{}).Create(block)
+	if res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func Test_SlashedOperator(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test each event type", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+
+		model, err := NewSlashedOperatorModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		t.Run("Handle an operator slashed for two strategies", func(t *testing.T) {
+			blockNumber := uint64(102)
+
+			if err := createBlock(model, blockNumber); err != nil {
+				t.Fatal(err)
+			}
+
+			log := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          allocationManager,
+				Arguments:        `[{"Name": "operator", "Type": "address", "Value": null, "Indexed": false}, {"Name": "operatorSet", "Type": "tuple", "Value": null, "Indexed": false}, {"Name": "strategies", "Type": "address[]", "Value": null, "Indexed": false}, {"Name": "wadSlashed", "Type": "uint256[]", "Value": null, "Indexed": false}, {"Name": "description", "Type": "string", "Value": null, "Indexed": false}]`,
+				EventName:        "OperatorSlashed",
+				LogIndex:         12,
+				OutputData:       `{"operator": "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", "operatorSet": {"avs": "0x9401e5e6564db35c0f86573a9828df69fc778af1", "id": 1}, "strategies": ["0x7d704507b76571a51d9cae8addabbfd0ba0e63d3", "0xbeac0eeeeeeeeeeeeeeeeeeeeeeeeeeeeeebeac0"], "wadSlashed": [100000000000000000, 200000000000000000], "description": "missed a task"}`,
+			}
+
+			err = model.SetupStateForBlock(blockNumber)
+			assert.Nil(t, err)
+
+			isInteresting := model.IsInterestingLog(log)
+			assert.True(t, isInteresting)
+
+			change, err := model.HandleStateChange(log)
+			assert.Nil(t, err)
+			assert.NotNil(t, change)
+
+			slashes := change.([]*SlashedOperator)
+			assert.Equal(t, 2, len(slashes))
+			assert.Equal(t, "0x7d704507b76571a51d9cae8addabbfd0ba0e63d3", slashes[0].Strategy)
+			assert.Equal(t, "100000000000000000", slashes[0].WadSlashed)
+			assert.Equal(t, "0xbeac0eeeeeeeeeeeeeeeeeeeeeeeeeeeeeebeac0", slashes[1].Strategy)
+			assert.Equal(t, "200000000000000000", slashes[1].WadSlashed)
+			assert.Equal(t, "missed a task", slashes[1].Description)
+
+			err = model.CommitFinalState(blockNumber, grm)
+			assert.Nil(t, err)
+
+			records := make([]*SlashedOperator, 0)
+			query := `select * from slashed_operators where block_number = ?`
+			res := model.DB.Raw(query, blockNumber).Scan(&records)
+			assert.Nil(t, res.Error)
+			assert.Equal(t, 2, len(records))
+
+			stateRoot, err := model.GenerateStateRoot(blockNumber)
+			assert.Nil(t, err)
+			assert.True(t, len(stateRoot) > 0)
+
+			t.Cleanup(func() {
+				teardown(model)
+			})
+		})
+		t.Run("Reject a slash with mismatched strategies and amounts", func(t *testing.T) {
+			blockNumber := uint64(103)
+
+			if err := createBlock(model, blockNumber); err != nil {
+				t.Fatal(err)
+			}
+
+			log := &storage.TransactionLog{
+				TransactionHash:  "some hash",
+				TransactionIndex: 100,
+				BlockNumber:      blockNumber,
+				Address:          allocationManager,
+				Arguments:        `[]`,
+				EventName:        "OperatorSlashed",
+				LogIndex:         13,
+				OutputData:       `{"operator": "0xd36b6e5eee8311d7bffb2f3bb33301a1ab7de101", "operatorSet": {"avs": "0x9401e5e6564db35c0f86573a9828df69fc778af1", "id": 1}, "strategies": ["0x7d704507b76571a51d9cae8addabbfd0ba0e63d3"], "wadSlashed": [], "description": ""}`,
+			}
+
+			err = model.SetupStateForBlock(blockNumber)
+			assert.Nil(t, err)
+
+			_, err = model.HandleStateChange(log)
+			assert.NotNil(t, err)
+
+			t.Cleanup(func() {
+				teardown(model)
+			})
+		})
+
+		t.Cleanup(func() {
+			teardown(model)
+		})
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
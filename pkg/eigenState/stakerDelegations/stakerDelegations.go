@@ -173,7 +173,7 @@ func (s *StakerDelegationsModel) prepareState(blockNumber uint64) ([]*StakerDele
 	return deltas, nil
 }
 
-func (s *StakerDelegationsModel) writeDeltaRecords(blockNumber uint64) error {
+func (s *StakerDelegationsModel) writeDeltaRecords(blockNumber uint64, tx *gorm.DB) error {
 	records, ok := s.stateAccumulator[blockNumber]
 	if !ok {
 		msg := "delta accumulator was not initialized"
@@ -181,7 +181,7 @@ func (s *StakerDelegationsModel) writeDeltaRecords(blockNumber uint64) error {
 		return errors.New(msg)
 	}
 	if len(records) > 0 {
-		res := s.DB.Model(&StakerDelegationChange{}).Clauses(clause.Returning{}).Create(&records)
+		res := tx.Model(&StakerDelegationChange{}).Clauses(clause.Returning{}).Create(&records)
 		if res.Error != nil {
 			s.logger.Sugar().Errorw("Failed to insert delta records", zap.Error(res.Error))
 			return res.Error
@@ -190,8 +190,8 @@ func (s *StakerDelegationsModel) writeDeltaRecords(blockNumber uint64) error {
 	return nil
 }
 
-func (s *StakerDelegationsModel) CommitFinalState(blockNumber uint64) error {
-	if err := s.writeDeltaRecords(blockNumber); err != nil {
+func (s *StakerDelegationsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	if err := s.writeDeltaRecords(blockNumber, tx); err != nil {
 		return err
 	}
 	return nil
@@ -207,16 +207,23 @@ func (s *StakerDelegationsModel) GetCommittedState(blockNumber uint64) ([]interf
 	return base.CastCommittedStateToInterface(records), nil
 }
 
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (s *StakerDelegationsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
+	deltas, err := s.prepareState(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.sortValuesForMerkleTree(deltas), nil
+}
+
 // GenerateStateRoot generates the state root for the given block number by storing
 // the state changes in a merkle tree.
 func (s *StakerDelegationsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
-	deltas, err := s.prepareState(blockNumber)
+	inputs, err := s.GetMerkleTreeInputs(blockNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	inputs := s.sortValuesForMerkleTree(deltas)
-
 	if len(inputs) == 0 {
 		return nil, nil
 	}
@@ -247,8 +254,8 @@ func (s *StakerDelegationsModel) sortValuesForMerkleTree(deltas []*StakerDelegat
 	return inputs
 }
 
-func (s *StakerDelegationsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return s.BaseEigenState.DeleteState("staker_delegation_changes", startBlockNumber, endBlockNumber, s.DB)
+func (s *StakerDelegationsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return s.BaseEigenState.DeleteState("staker_delegation_changes", startBlockNumber, endBlockNumber, tx)
 }
 
 func (s *StakerDelegationsModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
@@ -37,6 +37,12 @@ type StakerShareDeltas struct {
 	WithdrawalRootString string `gorm:"-"`
 }
 
+// beaconChainStrategy is the placeholder strategy that native ETH restaked through EigenPods is accounted under
+const beaconChainStrategy = "0xbeac0eeeeeeeeeeeeeeeeeeeeeeeeeeeeeebeac0"
+
+// wad is the 1e18 fixed point base that slashed proportions are expressed in
+var wad = big.NewInt(1_000_000_000_000_000_000)
+
 func NewSlotID(transactionHash string, logIndex uint64, staker string, strategy string, strategyIndex uint64) types.SlotID {
 	return base.NewSlotIDWithSuffix(transactionHash, logIndex, fmt.Sprintf("%s_%s_%016x", staker, strategy, strategyIndex))
 }
@@ -50,6 +56,11 @@ type StakerSharesModel struct {
 	// Accumulates deltas for each block
 	stateAccumulator map[uint64][]*StakerShareDeltas
 	committedState   map[uint64][]*StakerShareDeltas
+
+	// The operator each staker whose delegation changed earlier in the block is delegated to, or an empty string if
+	// they undelegated. The delegations are only committed with the block, so slashings need these to find the
+	// stakers of an operator that changed within the block.
+	delegationAccumulator map[uint64]map[string]string
 }
 
 func NewStakerSharesModel(
@@ -59,12 +70,13 @@ func NewStakerSharesModel(
 	globalConfig *config.Config,
 ) (*StakerSharesModel, error) {
 	model := &StakerSharesModel{
-		BaseEigenState:   base.BaseEigenState{},
-		DB:               grm,
-		logger:           logger,
-		globalConfig:     globalConfig,
-		stateAccumulator: make(map[uint64][]*StakerShareDeltas),
-		committedState:   make(map[uint64][]*StakerShareDeltas),
+		BaseEigenState:        base.BaseEigenState{},
+		DB:                    grm,
+		logger:                logger,
+		globalConfig:          globalConfig,
+		stateAccumulator:      make(map[uint64][]*StakerShareDeltas),
+		committedState:        make(map[uint64][]*StakerShareDeltas),
+		delegationAccumulator: make(map[uint64]map[string]string),
 	}
 
 	esm.RegisterState(model, 3)
@@ -174,7 +186,7 @@ func (ss *StakerSharesModel) handlePodSharesUpdatedEvent(log *storage.Transactio
 
 	return &StakerShareDeltas{
 		Staker:          staker,
-		Strategy:        "0xbeac0eeeeeeeeeeeeeeeeeeeeeeeeeeeeeebeac0",
+		Strategy:        beaconChainStrategy,
 		Shares:          sharesDelta.String(),
 		StrategyIndex:   uint64(0),
 		LogIndex:        log.LogIndex,
@@ -351,6 +363,309 @@ func (ss *StakerSharesModel) handleM2QueuedWithdrawal(log *storage.TransactionLo
 	return records, nil
 }
 
+type slashingWithdrawalOutputData struct {
+	Withdrawal struct {
+		Staker     string   `json:"staker"`
+		Strategies []string `json:"strategies"`
+	} `json:"withdrawal"`
+	SharesToWithdraw []json.Number `json:"sharesToWithdraw"`
+}
+
+// handleSlashingWithdrawalQueued handles the SlashingWithdrawalQueued event from the DelegationManager contract of the
+// slashing release, which replaces WithdrawalQueued. The shares to withdraw are what the staker's deposit shares are
+// worth after slashing, so they are removed rather than the deposit shares.
+func (ss *StakerSharesModel) handleSlashingWithdrawalQueued(log *storage.TransactionLog) ([]*StakerShareDeltas, error) {
+	active, err := ss.globalConfig.IsModelForkActive(config.ModelFork_Boston, log.BlockNumber)
+	if err != nil || !active {
+		return nil, err
+	}
+
+	outputData := &slashingWithdrawalOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(log.OutputData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&outputData); err != nil {
+		return nil, err
+	}
+	if len(outputData.Withdrawal.Strategies) != len(outputData.SharesToWithdraw) {
+		return nil, fmt.Errorf("SlashingWithdrawalQueued has %d strategies and %d shares to withdraw",
+			len(outputData.Withdrawal.Strategies), len(outputData.SharesToWithdraw),
+		)
+	}
+
+	records := make([]*StakerShareDeltas, 0, len(outputData.Withdrawal.Strategies))
+	for i, strategy := range outputData.Withdrawal.Strategies {
+		shares, success := numbers.NewBig257().SetString(outputData.SharesToWithdraw[i].String(), 10)
+		if !success {
+			return nil, fmt.Errorf("Failed to convert shares to big.Int: %s", outputData.SharesToWithdraw[i])
+		}
+		records = append(records, &StakerShareDeltas{
+			Staker:          strings.ToLower(outputData.Withdrawal.Staker),
+			Strategy:        strings.ToLower(strategy),
+			Shares:          shares.Neg(shares).String(),
+			StrategyIndex:   uint64(i),
+			LogIndex:        log.LogIndex,
+			TransactionHash: log.TransactionHash,
+			BlockNumber:     log.BlockNumber,
+		})
+	}
+	return records, nil
+}
+
+// trackDelegationChange records which operator a staker is delegated to after a StakerDelegated or StakerUndelegated
+// event, for slashings later in the same block
+func (ss *StakerSharesModel) trackDelegationChange(log *storage.TransactionLog) error {
+	arguments, err := ss.ParseLogArguments(log)
+	if err != nil {
+		return err
+	}
+	delegations, ok := ss.delegationAccumulator[log.BlockNumber]
+	if !ok {
+		return fmt.Errorf("No delegation accumulator found for block %d", log.BlockNumber)
+	}
+
+	staker := strings.ToLower(arguments[0].Value.(string))
+	if log.EventName == "StakerDelegated" {
+		delegations[staker] = strings.ToLower(arguments[1].Value.(string))
+	} else {
+		delegations[staker] = ""
+	}
+	return nil
+}
+
+// getDelegatedStakers returns the stakers that are delegated to the operator at the log. Only delegations committed
+// before the block of the log are read, so that replaying a block sees the same delegations, and the changes earlier
+// in the block are applied on top of them.
+func (ss *StakerSharesModel) getDelegatedStakers(operator string, blockNumber uint64) ([]string, error) {
+	query := `
+		select
+			staker
+		from (
+			select distinct on (staker)
+				staker,
+				operator,
+				delegated
+			from staker_delegation_changes
+			where
+				staker in (
+					select staker
+					from staker_delegation_changes
+					where
+						operator = @operator
+						and block_number < @blockNumber
+				)
+				and block_number < @blockNumber
+			order by staker, block_number desc, log_index desc
+		) as latest_delegations
+		where
+			operator = @operator
+			and delegated = true
+	`
+	committedStakers := make([]string, 0)
+	res := ss.DB.Raw(query,
+		sql.Named("operator", operator),
+		sql.Named("blockNumber", blockNumber),
+	).Scan(&committedStakers)
+	if res.Error != nil {
+		ss.logger.Sugar().Errorw("Failed to fetch delegated stakers", zap.Error(res.Error))
+		return nil, res.Error
+	}
+
+	delegations := ss.delegationAccumulator[blockNumber]
+	stakers := make([]string, 0, len(committedStakers))
+	for _, staker := range committedStakers {
+		if delegatedTo, ok := delegations[staker]; ok && delegatedTo != operator {
+			continue
+		}
+		stakers = append(stakers, staker)
+	}
+	for staker, delegatedTo := range delegations {
+		if delegatedTo == operator && !slices.Contains(committedStakers, staker) {
+			stakers = append(stakers, staker)
+		}
+	}
+	slices.Sort(stakers)
+	return stakers, nil
+}
+
+// getStakerShares returns the shares the stakers hold in the strategy at the log, from the deltas committed before
+// the block of the log and the deltas accumulated earlier in the block
+func (ss *StakerSharesModel) getStakerShares(stakers []string, strategy string, blockNumber uint64) (map[string]*big.Int, error) {
+	shares := make(map[string]*big.Int, len(stakers))
+	if len(stakers) == 0 {
+		return shares, nil
+	}
+
+	query := `
+		select
+			staker,
+			cast(sum(shares) as varchar) as shares
+		from staker_share_deltas
+		where
+			staker in @stakers
+			and strategy = @strategy
+			and block_number < @blockNumber
+		group by staker
+	`
+	var committedShares []struct {
+		Staker string
+		Shares string
+	}
+	res := ss.DB.Raw(query,
+		sql.Named("stakers", stakers),
+		sql.Named("strategy", strategy),
+		sql.Named("blockNumber", blockNumber),
+	).Scan(&committedShares)
+	if res.Error != nil {
+		ss.logger.Sugar().Errorw("Failed to fetch staker shares", zap.Error(res.Error))
+		return nil, res.Error
+	}
+	for _, s := range committedShares {
+		amount, success := numbers.NewBig257().SetString(s.Shares, 10)
+		if !success {
+			return nil, fmt.Errorf("Failed to convert shares to big.Int: %s", s.Shares)
+		}
+		shares[s.Staker] = amount
+	}
+
+	for _, delta := range ss.stateAccumulator[blockNumber] {
+		if delta.Strategy != strategy || !slices.Contains(stakers, delta.Staker) {
+			continue
+		}
+		amount, success := numbers.NewBig257().SetString(delta.Shares, 10)
+		if !success {
+			return nil, fmt.Errorf("Failed to convert shares to big.Int: %s", delta.Shares)
+		}
+		if _, ok := shares[delta.Staker]; !ok {
+			shares[delta.Staker] = big.NewInt(0)
+		}
+		shares[delta.Staker].Add(shares[delta.Staker], amount)
+	}
+	return shares, nil
+}
+
+type operatorSlashedOutputData struct {
+	Operator   string        `json:"operator"`
+	Strategies []string      `json:"strategies"`
+	WadSlashed []json.Number `json:"wadSlashed"`
+}
+
+// handleOperatorSlashedEvent handles the OperatorSlashed event from the AllocationManager contract. The stakers
+// delegated to the operator lose the same proportion of their shares in each slashed strategy as the operator.
+func (ss *StakerSharesModel) handleOperatorSlashedEvent(log *storage.TransactionLog) ([]*StakerShareDeltas, error) {
+	active, err := ss.globalConfig.IsModelForkActive(config.ModelFork_Boston, log.BlockNumber)
+	if err != nil || !active {
+		return nil, err
+	}
+
+	outputData := &operatorSlashedOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(log.OutputData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&outputData); err != nil {
+		return nil, err
+	}
+	if len(outputData.Strategies) != len(outputData.WadSlashed) {
+		return nil, fmt.Errorf("OperatorSlashed has %d strategies and %d slashed amounts", len(outputData.Strategies), len(outputData.WadSlashed))
+	}
+
+	stakers, err := ss.getDelegatedStakers(strings.ToLower(outputData.Operator), log.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*StakerShareDeltas, 0)
+	for i, strategy := range outputData.Strategies {
+		strategy = strings.ToLower(strategy)
+		wadSlashed, success := numbers.NewBig257().SetString(outputData.WadSlashed[i].String(), 10)
+		if !success {
+			return nil, fmt.Errorf("Failed to convert wadSlashed to big.Int: %s", outputData.WadSlashed[i])
+		}
+
+		shares, err := ss.getStakerShares(stakers, strategy, log.BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		for _, staker := range stakers {
+			stakerShares, ok := shares[staker]
+			if !ok || stakerShares.Sign() <= 0 {
+				continue
+			}
+			slashedShares := new(big.Int).Mul(stakerShares, wadSlashed)
+			slashedShares.Div(slashedShares, wad)
+			if slashedShares.Sign() == 0 {
+				continue
+			}
+			records = append(records, &StakerShareDeltas{
+				Staker:          staker,
+				Strategy:        strategy,
+				Shares:          slashedShares.Neg(slashedShares).String(),
+				StrategyIndex:   uint64(i),
+				LogIndex:        log.LogIndex,
+				TransactionHash: log.TransactionHash,
+				BlockNumber:     log.BlockNumber,
+			})
+		}
+	}
+	return records, nil
+}
+
+type beaconChainSlashingFactorDecreasedOutputData struct {
+	Staker                        string      `json:"staker"`
+	PrevBeaconChainSlashingFactor json.Number `json:"prevBeaconChainSlashingFactor"`
+	NewBeaconChainSlashingFactor  json.Number `json:"newBeaconChainSlashingFactor"`
+}
+
+// handleBeaconChainSlashingFactorDecreasedEvent handles the BeaconChainSlashingFactorDecreased event from the
+// EigenPodManager contract, which replaces negative PodSharesUpdated events in the slashing release. The staker's
+// beacon chain shares shrink by the same proportion as their slashing factor.
+func (ss *StakerSharesModel) handleBeaconChainSlashingFactorDecreasedEvent(log *storage.TransactionLog) (*StakerShareDeltas, error) {
+	active, err := ss.globalConfig.IsModelForkActive(config.ModelFork_Boston, log.BlockNumber)
+	if err != nil || !active {
+		return nil, err
+	}
+
+	outputData := &beaconChainSlashingFactorDecreasedOutputData{}
+	decoder := json.NewDecoder(strings.NewReader(log.OutputData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&outputData); err != nil {
+		return nil, err
+	}
+	prevFactor, success := numbers.NewBig257().SetString(outputData.PrevBeaconChainSlashingFactor.String(), 10)
+	if !success || prevFactor.Sign() <= 0 {
+		return nil, fmt.Errorf("Invalid previous beacon chain slashing factor: %s", outputData.PrevBeaconChainSlashingFactor)
+	}
+	newFactor, success := numbers.NewBig257().SetString(outputData.NewBeaconChainSlashingFactor.String(), 10)
+	if !success {
+		return nil, fmt.Errorf("Invalid new beacon chain slashing factor: %s", outputData.NewBeaconChainSlashingFactor)
+	}
+
+	staker := strings.ToLower(outputData.Staker)
+	shares, err := ss.getStakerShares([]string{staker}, beaconChainStrategy, log.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	stakerShares, ok := shares[staker]
+	if !ok || stakerShares.Sign() <= 0 {
+		return nil, nil
+	}
+
+	remainingShares := new(big.Int).Mul(stakerShares, newFactor)
+	remainingShares.Div(remainingShares, prevFactor)
+	slashedShares := new(big.Int).Sub(stakerShares, remainingShares)
+	if slashedShares.Sign() == 0 {
+		return nil, nil
+	}
+
+	return &StakerShareDeltas{
+		Staker:          staker,
+		Strategy:        beaconChainStrategy,
+		Shares:          slashedShares.Neg(slashedShares).String(),
+		StrategyIndex:   uint64(0),
+		LogIndex:        log.LogIndex,
+		TransactionHash: log.TransactionHash,
+		BlockNumber:     log.BlockNumber,
+	}, nil
+}
+
 type AccumulatedStateChanges struct {
 	Changes []*StakerShareDeltas
 }
@@ -367,6 +682,9 @@ func (ss *StakerSharesModel) GetStateTransitions() (types.StateTransitions[*Accu
 	- M2 WithdrawalQueued (delegation manager)
 	- M2 WithdrawalMigrated (delegation manager)
 	- PodSharesUpdated (eigenpod manager)
+	- SlashingWithdrawalQueued (delegation manager, from ModelFork_Boston)
+	- OperatorSlashed (allocation manager, from ModelFork_Boston)
+	- BeaconChainSlashingFactorDecreased (eigenpod manager, from ModelFork_Boston)
 
 	In the case of M2, M2 WithdrawalQueued handles BOTH standard M2 withdrawals and was paired with M2 WithdrawalMigrated
 	for the cases where M1 withdrawals were migrated to M2.
@@ -430,6 +748,22 @@ func (ss *StakerSharesModel) GetStateTransitions() (types.StateTransitions[*Accu
 					ss.stateAccumulator[log.BlockNumber] = filteredDeltas
 				}
 			}
+		} else if log.Address == contractAddresses.DelegationManager && log.EventName == "SlashingWithdrawalQueued" {
+			var records []*StakerShareDeltas
+			records, err = ss.handleSlashingWithdrawalQueued(log)
+			deltaRecords = append(deltaRecords, records...)
+		} else if log.Address == contractAddresses.DelegationManager && (log.EventName == "StakerDelegated" || log.EventName == "StakerUndelegated") {
+			err = ss.trackDelegationChange(log)
+		} else if log.Address == contractAddresses.AllocationManager && log.EventName == "OperatorSlashed" {
+			var records []*StakerShareDeltas
+			records, err = ss.handleOperatorSlashedEvent(log)
+			deltaRecords = append(deltaRecords, records...)
+		} else if log.Address == contractAddresses.EigenpodManager && log.EventName == "BeaconChainSlashingFactorDecreased" {
+			var record *StakerShareDeltas
+			record, err = ss.handleBeaconChainSlashingFactorDecreasedEvent(log)
+			if record != nil {
+				deltaRecords = append(deltaRecords, record)
+			}
 		} else {
 			ss.logger.Sugar().Debugw("Got stakerShares event that we don't handle",
 				zap.String("eventName", log.EventName),
@@ -463,10 +797,13 @@ func (ss *StakerSharesModel) GetStateTransitions() (types.StateTransitions[*Accu
 
 func (ss *StakerSharesModel) getContractAddressesForEnvironment() map[string][]string {
 	contracts := ss.globalConfig.GetContractsMapForChain()
-	return map[string][]string{
+	addresses := map[string][]string{
 		contracts.DelegationManager: {
 			"WithdrawalMigrated",
 			"WithdrawalQueued",
+			"SlashingWithdrawalQueued",
+			"StakerDelegated",
+			"StakerUndelegated",
 		},
 		contracts.StrategyManager: {
 			"Deposit",
@@ -474,8 +811,15 @@ func (ss *StakerSharesModel) getContractAddressesForEnvironment() map[string][]s
 		},
 		contracts.EigenpodManager: {
 			"PodSharesUpdated",
+			"BeaconChainSlashingFactorDecreased",
 		},
 	}
+	if contracts.AllocationManager != "" {
+		addresses[contracts.AllocationManager] = []string{
+			"OperatorSlashed",
+		}
+	}
+	return addresses
 }
 
 func (ss *StakerSharesModel) IsInterestingLog(log *storage.TransactionLog) bool {
@@ -486,12 +830,14 @@ func (ss *StakerSharesModel) IsInterestingLog(log *storage.TransactionLog) bool
 func (ss *StakerSharesModel) SetupStateForBlock(blockNumber uint64) error {
 	ss.stateAccumulator[blockNumber] = make([]*StakerShareDeltas, 0)
 	ss.committedState[blockNumber] = make([]*StakerShareDeltas, 0)
+	ss.delegationAccumulator[blockNumber] = make(map[string]string)
 	return nil
 }
 
 func (ss *StakerSharesModel) CleanupProcessedStateForBlock(blockNumber uint64) error {
 	delete(ss.stateAccumulator, blockNumber)
 	delete(ss.committedState, blockNumber)
+	delete(ss.delegationAccumulator, blockNumber)
 	return nil
 }
 
@@ -530,7 +876,7 @@ func (ss *StakerSharesModel) prepareState(blockNumber uint64) ([]*StakerShareDel
 	return records, nil
 }
 
-func (ss *StakerSharesModel) writeDeltaRecords(blockNumber uint64) error {
+func (ss *StakerSharesModel) writeDeltaRecords(blockNumber uint64, tx *gorm.DB) error {
 	records, ok := ss.stateAccumulator[blockNumber]
 	if !ok {
 		msg := "accumulator was not initialized"
@@ -542,7 +888,7 @@ func (ss *StakerSharesModel) writeDeltaRecords(blockNumber uint64) error {
 		return nil
 	}
 	var block storage.Block
-	res := ss.DB.Model(&storage.Block{}).Where("number = ?", blockNumber).First(&block)
+	res := tx.Model(&storage.Block{}).Where("number = ?", blockNumber).First(&block)
 	if res.Error != nil {
 		ss.logger.Sugar().Errorw("Failed to fetch block", zap.Error(res.Error))
 		return res.Error
@@ -553,7 +899,7 @@ func (ss *StakerSharesModel) writeDeltaRecords(blockNumber uint64) error {
 		r.BlockDate = block.BlockTime.Format(time.DateOnly)
 	}
 
-	res = ss.DB.Model(&StakerShareDeltas{}).Clauses(clause.Returning{}).Create(&records)
+	res = tx.Model(&StakerShareDeltas{}).Clauses(clause.Returning{}).Create(&records)
 	if res.Error != nil {
 		ss.logger.Sugar().Errorw("Failed to insert delta records", zap.Error(res.Error))
 		return res.Error
@@ -561,8 +907,8 @@ func (ss *StakerSharesModel) writeDeltaRecords(blockNumber uint64) error {
 	return nil
 }
 
-func (ss *StakerSharesModel) CommitFinalState(blockNumber uint64) error {
-	if err := ss.writeDeltaRecords(blockNumber); err != nil {
+func (ss *StakerSharesModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
+	if err := ss.writeDeltaRecords(blockNumber, tx); err != nil {
 		return err
 	}
 
@@ -579,13 +925,20 @@ func (ss *StakerSharesModel) GetCommittedState(blockNumber uint64) ([]interface{
 	return base.CastCommittedStateToInterface(records), nil
 }
 
-func (ss *StakerSharesModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (ss *StakerSharesModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	deltas, err := ss.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return ss.sortValuesForMerkleTree(deltas), nil
+}
 
-	inputs := ss.sortValuesForMerkleTree(deltas)
+func (ss *StakerSharesModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	inputs, err := ss.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(inputs) == 0 {
 		return nil, nil
@@ -618,8 +971,8 @@ func (ss *StakerSharesModel) sortValuesForMerkleTree(diffs []*StakerShareDeltas)
 	return inputs
 }
 
-func (ss *StakerSharesModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return ss.BaseEigenState.DeleteState("staker_share_deltas", startBlockNumber, endBlockNumber, ss.DB)
+func (ss *StakerSharesModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return ss.BaseEigenState.DeleteState("staker_share_deltas", startBlockNumber, endBlockNumber, tx)
 }
 
 func (ss *StakerSharesModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
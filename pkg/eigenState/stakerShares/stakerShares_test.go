@@ -1,6 +1,7 @@
 package stakerShares
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
 	"testing"
@@ -120,7 +121,7 @@ func Test_StakerSharesState(t *testing.T) {
 		assert.Equal(t, "0x0fe4f44bee93503346a3ac9ee5a26b130a5796d6", accumulatedState[0].Strategy)
 		assert.Equal(t, "502179505706314959", accumulatedState[0].Shares)
 
-		err = model.CommitFinalState(transaction.BlockNumber)
+		err = model.CommitFinalState(transaction.BlockNumber, grm)
 		assert.Nil(t, err)
 
 		// --------------------------------------------------------------------
@@ -190,7 +191,7 @@ func Test_StakerSharesState(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, change)
 
-		err = model.CommitFinalState(transaction.BlockNumber)
+		err = model.CommitFinalState(transaction.BlockNumber, grm)
 		assert.Nil(t, err)
 
 		// --------------------------------------------------------------------
@@ -261,7 +262,7 @@ func Test_StakerSharesState(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, change)
 
-		err = model.CommitFinalState(transaction.BlockNumber)
+		err = model.CommitFinalState(transaction.BlockNumber, grm)
 		assert.Nil(t, err)
 
 		// --------------------------------------------------------------------
@@ -310,7 +311,7 @@ func Test_StakerSharesState(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, change)
 
-		err = model.CommitFinalState(transaction.BlockNumber)
+		err = model.CommitFinalState(transaction.BlockNumber, grm)
 		assert.Nil(t, err)
 
 		query := `select * from staker_share_deltas order by block_number asc`
@@ -429,7 +430,7 @@ func Test_StakerSharesState(t *testing.T) {
 		assert.Equal(t, strings.ToLower("0x049ea11d337f185b1aa910d98e8fbd991f0fba7b"), typedChange.Changes[0].Staker)
 		assert.Equal(t, "0xbeac0eeeeeeeeeeeeeeeeeeeeeeeeeeeeeebeac0", typedChange.Changes[0].Strategy)
 
-		err = model.CommitFinalState(transaction.BlockNumber)
+		err = model.CommitFinalState(transaction.BlockNumber, grm)
 		assert.Nil(t, err)
 
 		var count int
@@ -443,3 +444,218 @@ func Test_StakerSharesState(t *testing.T) {
 		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
 	})
 }
+
+func Test_StakerSharesSlashing(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		operator  = "0x00000000000000000000000000000000000000aa"
+		stakerA   = "0x0000000000000000000000000000000000000001"
+		stakerB   = "0x0000000000000000000000000000000000000002"
+		stakerC   = "0x0000000000000000000000000000000000000003"
+		stakerD   = "0x0000000000000000000000000000000000000004"
+		strategy  = "0x93c4b944d05dfe6df7645a86cd2206016c51564d"
+		forkBlock = uint64(17445563)
+	)
+	contracts := cfg.GetContractsMapForChain()
+
+	// stakerA and stakerB are delegated to the operator before the slashing block, stakerC undelegated from it
+	for _, blockNumber := range []uint64{forkBlock + 1, forkBlock + 2, forkBlock + 3} {
+		res := grm.Create(&storage.Block{Number: blockNumber, Hash: fmt.Sprintf("hash_%d", blockNumber)})
+		assert.Nil(t, res.Error)
+	}
+	delegations := []struct {
+		staker    string
+		delegated bool
+		logIndex  uint64
+	}{
+		{stakerA, true, 0},
+		{stakerB, true, 1},
+		{stakerC, true, 2},
+		{stakerC, false, 3},
+	}
+	for _, d := range delegations {
+		res := grm.Exec(`insert into staker_delegation_changes (staker, operator, delegated, block_number, log_index, transaction_hash) values (?, ?, ?, ?, ?, '0xdelegation')`,
+			d.staker, operator, d.delegated, forkBlock+1, d.logIndex,
+		)
+		assert.Nil(t, res.Error)
+	}
+	deposits := []struct {
+		staker   string
+		strategy string
+		shares   string
+	}{
+		{stakerA, strategy, "1000"},
+		{stakerB, strategy, "3000"},
+		{stakerC, strategy, "5000"},
+		{stakerA, beaconChainStrategy, "32000000000000000000"},
+	}
+	for i, d := range deposits {
+		res := grm.Exec(`insert into staker_share_deltas (staker, strategy, shares, strategy_index, transaction_hash, log_index, block_time, block_date, block_number) values (?, ?, ?, 0, '0xdeposit', ?, now(), '2025-01-01', ?)`,
+			d.staker, d.strategy, d.shares, i, forkBlock+1,
+		)
+		assert.Nil(t, res.Error)
+	}
+	// shares deposited after the slashing must not be slashed when its block is replayed
+	res := grm.Exec(`insert into staker_share_deltas (staker, strategy, shares, strategy_index, transaction_hash, log_index, block_time, block_date, block_number) values (?, ?, '7000', 0, '0xlater', 0, now(), '2025-01-01', ?)`,
+		stakerA, strategy, forkBlock+3,
+	)
+	assert.Nil(t, res.Error)
+
+	t.Run("Should slash the shares of the stakers delegated to the operator", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+		model, err := NewStakerSharesModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		blockNumber := forkBlock + 2
+		err = model.SetupStateForBlock(blockNumber)
+		assert.Nil(t, err)
+
+		// stakerD delegates to the operator and deposits earlier in the block, stakerB undelegates
+		logs := []*storage.TransactionLog{
+			{
+				TransactionHash: "0xdelegate",
+				BlockNumber:     blockNumber,
+				Address:         contracts.DelegationManager,
+				Arguments:       fmt.Sprintf(`[{"Name": "staker", "Type": "address", "Value": "%s", "Indexed": true}, {"Name": "operator", "Type": "address", "Value": "%s", "Indexed": true}]`, stakerD, operator),
+				EventName:       "StakerDelegated",
+				LogIndex:        0,
+				OutputData:      `{}`,
+			},
+			{
+				TransactionHash: "0xdeposit",
+				BlockNumber:     blockNumber,
+				Address:         contracts.StrategyManager,
+				Arguments:       `[{"Name": "staker", "Type": "address", "Value": null, "Indexed": false}, {"Name": "token", "Type": "address", "Value": null, "Indexed": false}, {"Name": "strategy", "Type": "address", "Value": null, "Indexed": false}, {"Name": "shares", "Type": "uint256", "Value": null, "Indexed": false}]`,
+				EventName:       "Deposit",
+				LogIndex:        1,
+				OutputData:      fmt.Sprintf(`{"token": "0xae7ab96520de3a18e5e111b5eaab095312d7fe84", "shares": 2000, "staker": "%s", "strategy": "%s"}`, stakerD, strategy),
+			},
+			{
+				TransactionHash: "0xundelegate",
+				BlockNumber:     blockNumber,
+				Address:         contracts.DelegationManager,
+				Arguments:       fmt.Sprintf(`[{"Name": "staker", "Type": "address", "Value": "%s", "Indexed": true}, {"Name": "operator", "Type": "address", "Value": "%s", "Indexed": true}]`, stakerB, operator),
+				EventName:       "StakerUndelegated",
+				LogIndex:        2,
+				OutputData:      `{}`,
+			},
+		}
+		for _, log := range logs {
+			assert.True(t, model.IsInterestingLog(log))
+			_, err := model.HandleStateChange(log)
+			assert.Nil(t, err)
+		}
+
+		slashedLog := &storage.TransactionLog{
+			TransactionHash: "0xslash",
+			BlockNumber:     blockNumber,
+			Address:         contracts.AllocationManager,
+			Arguments:       `[{"Name": "operator", "Type": "address", "Value": null, "Indexed": false}, {"Name": "operatorSet", "Type": "(address,uint32)", "Value": null, "Indexed": false}, {"Name": "strategies", "Type": "address[]", "Value": null, "Indexed": false}, {"Name": "wadSlashed", "Type": "uint256[]", "Value": null, "Indexed": false}, {"Name": "description", "Type": "string", "Value": null, "Indexed": false}]`,
+			EventName:       "OperatorSlashed",
+			LogIndex:        3,
+			OutputData:      fmt.Sprintf(`{"operator": "%s", "operatorSet": {"avs": "0x00000000000000000000000000000000000000bb", "id": 1}, "strategies": ["%s"], "wadSlashed": [250000000000000000], "description": "slashed"}`, operator, strategy),
+		}
+		assert.True(t, model.IsInterestingLog(slashedLog))
+
+		change, err := model.HandleStateChange(slashedLog)
+		assert.Nil(t, err)
+		typedChange := change.(*AccumulatedStateChanges)
+
+		slashed := make(map[string]string)
+		for _, c := range typedChange.Changes {
+			if c.TransactionHash == slashedLog.TransactionHash {
+				assert.Equal(t, strategy, c.Strategy)
+				slashed[c.Staker] = c.Shares
+			}
+		}
+		assert.Equal(t, map[string]string{
+			stakerA: "-250",
+			stakerD: "-500",
+		}, slashed)
+	})
+	t.Run("Should slash beacon chain shares by the decrease of the slashing factor", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+		model, err := NewStakerSharesModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		blockNumber := forkBlock + 2
+		err = model.SetupStateForBlock(blockNumber)
+		assert.Nil(t, err)
+
+		log := &storage.TransactionLog{
+			TransactionHash: "0xbeacon",
+			BlockNumber:     blockNumber,
+			Address:         contracts.EigenpodManager,
+			Arguments:       `[{"Name": "staker", "Type": "address", "Value": null, "Indexed": false}, {"Name": "prevBeaconChainSlashingFactor", "Type": "uint64", "Value": null, "Indexed": false}, {"Name": "newBeaconChainSlashingFactor", "Type": "uint64", "Value": null, "Indexed": false}]`,
+			EventName:       "BeaconChainSlashingFactorDecreased",
+			LogIndex:        0,
+			OutputData:      fmt.Sprintf(`{"staker": "%s", "prevBeaconChainSlashingFactor": 1000000000000000000, "newBeaconChainSlashingFactor": 750000000000000000}`, stakerA),
+		}
+		assert.True(t, model.IsInterestingLog(log))
+
+		change, err := model.HandleStateChange(log)
+		assert.Nil(t, err)
+		typedChange := change.(*AccumulatedStateChanges)
+		assert.Equal(t, 1, len(typedChange.Changes))
+		assert.Equal(t, stakerA, typedChange.Changes[0].Staker)
+		assert.Equal(t, beaconChainStrategy, typedChange.Changes[0].Strategy)
+		assert.Equal(t, "-8000000000000000000", typedChange.Changes[0].Shares)
+	})
+	t.Run("Should remove the withdrawable shares of a slashing withdrawal", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+		model, err := NewStakerSharesModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		blockNumber := forkBlock + 2
+		err = model.SetupStateForBlock(blockNumber)
+		assert.Nil(t, err)
+
+		log := &storage.TransactionLog{
+			TransactionHash: "0xwithdrawal",
+			BlockNumber:     blockNumber,
+			Address:         contracts.DelegationManager,
+			Arguments:       `[{"Name": "withdrawalRoot", "Type": "bytes32", "Value": null, "Indexed": false}, {"Name": "withdrawal", "Type": "(address,address,address,uint256,uint32,address[],uint256[])", "Value": null, "Indexed": false}, {"Name": "sharesToWithdraw", "Type": "uint256[]", "Value": null, "Indexed": false}]`,
+			EventName:       "SlashingWithdrawalQueued",
+			LogIndex:        0,
+			OutputData:      fmt.Sprintf(`{"withdrawalRoot": [1, 2, 3], "withdrawal": {"staker": "%s", "delegatedTo": "%s", "withdrawer": "%s", "nonce": 0, "startBlock": 1, "strategies": ["%s", "%s"], "scaledShares": [1000, 32000000000000000000]}, "sharesToWithdraw": [750, 24000000000000000000]}`, stakerA, operator, stakerA, strategy, beaconChainStrategy),
+		}
+		assert.True(t, model.IsInterestingLog(log))
+
+		change, err := model.HandleStateChange(log)
+		assert.Nil(t, err)
+		typedChange := change.(*AccumulatedStateChanges)
+		assert.Equal(t, 2, len(typedChange.Changes))
+		assert.Equal(t, strategy, typedChange.Changes[0].Strategy)
+		assert.Equal(t, "-750", typedChange.Changes[0].Shares)
+		assert.Equal(t, beaconChainStrategy, typedChange.Changes[1].Strategy)
+		assert.Equal(t, "-24000000000000000000", typedChange.Changes[1].Shares)
+	})
+	t.Run("Should ignore slashings before the slashing fork", func(t *testing.T) {
+		esm := stateManager.NewEigenStateManager(l, grm)
+		model, err := NewStakerSharesModel(esm, grm, l, cfg)
+		assert.Nil(t, err)
+
+		blockNumber := forkBlock - 1
+		err = model.SetupStateForBlock(blockNumber)
+		assert.Nil(t, err)
+
+		log := &storage.TransactionLog{
+			TransactionHash: "0xslash",
+			BlockNumber:     blockNumber,
+			Address:         contracts.AllocationManager,
+			EventName:       "OperatorSlashed",
+			LogIndex:        0,
+			OutputData:      fmt.Sprintf(`{"operator": "%s", "strategies": ["%s"], "wadSlashed": [250000000000000000]}`, operator, strategy),
+		}
+		change, err := model.HandleStateChange(log)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(change.(*AccumulatedStateChanges).Changes))
+	})
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,81 @@
+package stateManager
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ModelRoot is the persisted root of a single model's state tree for a block.
+//
+// Only models that had state changes in the block, and are therefore included in the block's state root, are stored.
+type ModelRoot struct {
+	EthBlockNumber uint64
+	EthBlockHash   string
+	ModelName      string
+	Root           string
+	CreatedAt      time.Time
+}
+
+func (*ModelRoot) TableName() string {
+	return "model_state_roots"
+}
+
+// WriteModelRoots persists the model roots generated by GenerateStateRootWithModelRoots.
+func (e *EigenStateManager) WriteModelRoots(
+	blockNumber uint64,
+	blockHash string,
+	modelRoots []*ModelStateRoot,
+	tx *gorm.DB,
+) ([]*ModelRoot, error) {
+	records := make([]*ModelRoot, 0, len(modelRoots))
+	for _, modelRoot := range modelRoots {
+		if modelRoot.Root == nil {
+			continue
+		}
+		records = append(records, &ModelRoot{
+			EthBlockNumber: blockNumber,
+			EthBlockHash:   blockHash,
+			ModelName:      modelRoot.ModelName,
+			Root:           utils.ConvertBytesToString(modelRoot.Root),
+		})
+	}
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	res := tx.Model(&ModelRoot{}).Create(&records)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return records, nil
+}
+
+// GetModelRootsForBlock returns the model roots persisted for the block
+func (e *EigenStateManager) GetModelRootsForBlock(blockNumber uint64) ([]*ModelRoot, error) {
+	roots := make([]*ModelRoot, 0)
+	res := e.DB.Model(&ModelRoot{}).Where("eth_block_number = ?", blockNumber).Order("model_name asc").Find(&roots)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return roots, nil
+}
+
+func (e *EigenStateManager) deleteModelRoots(startBlock uint64, endBlock uint64, tx *gorm.DB) error {
+	if endBlock != 0 && endBlock < startBlock {
+		return errors.New("Invalid block range; endBlock must be greater than or equal to startBlock")
+	}
+	query := tx.Where("eth_block_number >= ?", startBlock)
+	if endBlock > 0 {
+		query = query.Where("eth_block_number <= ?", endBlock)
+	}
+	res := query.Delete(&ModelRoot{})
+	if res.Error != nil {
+		e.logger.Sugar().Errorw("Failed to delete model state roots", zap.Error(res.Error))
+		return res.Error
+	}
+	return nil
+}
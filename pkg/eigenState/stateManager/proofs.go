@@ -0,0 +1,122 @@
+package stateManager
+
+import (
+	"fmt"
+
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-merkletree/v2"
+	"github.com/wealdtech/go-merkletree/v2/keccak256"
+)
+
+// StateChangeProof proves that a state change, identified by its SlotID and value, is part of a block's state root.
+//
+// The proof has two levels: SlotProof proves the state change is a leaf of the model's state tree with root ModelRoot,
+// and ModelProof proves the model's leaf is part of the block's state tree with root StateRoot.
+type StateChangeProof struct {
+	BlockNumber uint64
+	BlockHash   string
+	StateRoot   []byte
+	ModelName   string
+	ModelRoot   []byte
+	SlotID      types.SlotID
+	Value       []byte
+	SlotProof   *merkletree.Proof
+	ModelProof  *merkletree.Proof
+}
+
+// GenerateStateChangeProof generates a proof that the state change for the given slot in the given model
+// is part of the block's state root.
+//
+// Like GenerateStateRoot, this must be called while the block is being processed, after all logs have been handled.
+func (e *EigenStateManager) GenerateStateChangeProof(
+	blockNumber uint64,
+	blockHash string,
+	modelName string,
+	slotID types.SlotID,
+) (*StateChangeProof, error) {
+	model, ok := e.GetModelsMappedByName()[modelName]
+	if !ok {
+		return nil, fmt.Errorf("unknown model '%s'", modelName)
+	}
+	if e.IsExcludedFromStateRoot(modelName) {
+		return nil, fmt.Errorf("model '%s' is not included in the state root", modelName)
+	}
+
+	inputs, err := model.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var slot *types.MerkleTreeInput
+	for _, input := range inputs {
+		if input.SlotID == slotID {
+			slot = input
+			break
+		}
+	}
+	if slot == nil {
+		return nil, fmt.Errorf("slot '%s' not found in model '%s' for block %d", slotID, modelName, blockNumber)
+	}
+
+	modelTree, err := (&base.BaseEigenState{Logger: e.logger}).MerkleizeEigenState(blockNumber, inputs)
+	if err != nil {
+		return nil, err
+	}
+	slotProof, err := modelTree.GenerateProof(base.EncodeMerkleLeaf(slot.SlotID, slot.Value), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	_, modelRoots, err := e.GenerateStateRootWithModelRoots(blockNumber, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	stateTree, err := newStateRootTree(blockNumber, blockHash, modelRoots)
+	if err != nil {
+		return nil, err
+	}
+	modelProof, err := stateTree.GenerateProof(encodeModelLeafForRoot(modelName, modelTree.Root()), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateChangeProof{
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash,
+		StateRoot:   stateTree.Root(),
+		ModelName:   modelName,
+		ModelRoot:   modelTree.Root(),
+		SlotID:      slot.SlotID,
+		Value:       slot.Value,
+		SlotProof:   slotProof,
+		ModelProof:  modelProof,
+	}, nil
+}
+
+// VerifyStateChangeProof checks that the proof's state change is part of the given state root.
+func VerifyStateChangeProof(proof *StateChangeProof, stateRoot types.StateRoot) (bool, error) {
+	if proof.SlotProof == nil || proof.ModelProof == nil {
+		return false, fmt.Errorf("proof is incomplete")
+	}
+	root := common.FromHex(string(stateRoot))
+
+	ok, err := merkletree.VerifyProofUsing(
+		base.EncodeMerkleLeaf(proof.SlotID, proof.Value),
+		false,
+		proof.SlotProof,
+		[][]byte{proof.ModelRoot},
+		keccak256.New(),
+	)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return merkletree.VerifyProofUsing(
+		encodeModelLeafForRoot(proof.ModelName, proof.ModelRoot),
+		false,
+		proof.ModelProof,
+		[][]byte{root},
+		keccak256.New(),
+	)
+}
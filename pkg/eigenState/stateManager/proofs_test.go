@@ -0,0 +1,132 @@
+package stateManager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/base"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeModel is a model with a fixed set of slots
+type fakeModel struct {
+	base.BaseEigenState
+	name   string
+	inputs []*types.MerkleTreeInput
+}
+
+func (f *fakeModel) GetModelName() string                                   { return f.name }
+func (f *fakeModel) IsInterestingLog(log *storage.TransactionLog) bool      { return false }
+func (f *fakeModel) SetupStateForBlock(blockNumber uint64) error            { return nil }
+func (f *fakeModel) CleanupProcessedStateForBlock(blockNumber uint64) error { return nil }
+func (f *fakeModel) HandleStateChange(log *storage.TransactionLog) (interface{}, error) {
+	return nil, nil
+}
+func (f *fakeModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error { return nil }
+func (f *fakeModel) GetCommittedState(blockNumber uint64) ([]interface{}, error) {
+	return nil, nil
+}
+func (f *fakeModel) GetMerkleTreeInputs(blockNumber uint64) ([]*types.MerkleTreeInput, error) {
+	return f.inputs, nil
+}
+func (f *fakeModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	if len(f.inputs) == 0 {
+		return nil, nil
+	}
+	tree, err := f.MerkleizeEigenState(blockNumber, f.inputs)
+	if err != nil {
+		return nil, err
+	}
+	return tree.Root(), nil
+}
+func (f *fakeModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return nil
+}
+func (f *fakeModel) ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error) {
+	return nil, nil
+}
+
+func newFakeModel(name string, slots int) *fakeModel {
+	inputs := make([]*types.MerkleTreeInput, 0, slots)
+	for i := 0; i < slots; i++ {
+		inputs = append(inputs, &types.MerkleTreeInput{
+			SlotID: base.NewSlotID(fmt.Sprintf("0x%064x", i), uint64(i)),
+			Value:  []byte(fmt.Sprintf("%s-value-%d", name, i)),
+		})
+	}
+	return &fakeModel{name: name, inputs: inputs}
+}
+
+func Test_StateChangeProofs(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+
+	blockNumber := uint64(100)
+	blockHash := "0x1234"
+
+	esm := NewEigenStateManager(l, nil)
+	esm.RegisterState(newFakeModel("ModelA", 5), 0)
+	esm.RegisterState(newFakeModel("ModelB", 0), 1)
+	esm.RegisterState(newFakeModel("ModelC", 3), 2)
+
+	stateRoot, err := esm.GenerateStateRoot(blockNumber, blockHash)
+	assert.Nil(t, err)
+
+	t.Run("Should generate a proof that verifies against the state root", func(t *testing.T) {
+		slotID := base.NewSlotID(fmt.Sprintf("0x%064x", 3), 3)
+		proof, err := esm.GenerateStateChangeProof(blockNumber, blockHash, "ModelA", slotID)
+		assert.Nil(t, err)
+		assert.Equal(t, slotID, proof.SlotID)
+		assert.Equal(t, []byte("ModelA-value-3"), proof.Value)
+
+		ok, err := VerifyStateChangeProof(proof, stateRoot)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	})
+	t.Run("Should not verify a tampered value", func(t *testing.T) {
+		proof, err := esm.GenerateStateChangeProof(blockNumber, blockHash, "ModelC", base.NewSlotID(fmt.Sprintf("0x%064x", 1), 1))
+		assert.Nil(t, err)
+
+		proof.Value = []byte("not the value")
+		ok, err := VerifyStateChangeProof(proof, stateRoot)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+	t.Run("Should not verify against a different state root", func(t *testing.T) {
+		proof, err := esm.GenerateStateChangeProof(blockNumber, blockHash, "ModelC", base.NewSlotID(fmt.Sprintf("0x%064x", 0), 0))
+		assert.Nil(t, err)
+
+		ok, err := VerifyStateChangeProof(proof, types.StateRoot("0x1234"))
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+	t.Run("Should fail for a slot that does not exist", func(t *testing.T) {
+		_, err := esm.GenerateStateChangeProof(blockNumber, blockHash, "ModelB", base.NewSlotID("0x1", 0))
+		assert.NotNil(t, err)
+	})
+	t.Run("Should only include models with state changes in the model roots", func(t *testing.T) {
+		_, modelRoots, err := esm.GenerateStateRootWithModelRoots(blockNumber, blockHash)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(modelRoots))
+		assert.Nil(t, modelRoots[1].Root)
+		assert.Nil(t, modelRoots[1].Leaf)
+	})
+	t.Run("Should not include an excluded model in the state root", func(t *testing.T) {
+		excluding := NewEigenStateManager(l, nil)
+		excluding.RegisterState(newFakeModel("ModelA", 5), 0)
+		excluding.RegisterState(newFakeModel("ModelB", 0), 1)
+		excluding.RegisterState(newFakeModel("ModelC", 3), 2)
+		excluding.RegisterState(newFakeModel("PluginModel", 2), 1000)
+		excluding.ExcludeFromStateRoot("PluginModel")
+
+		root, err := excluding.GenerateStateRoot(blockNumber, blockHash)
+		assert.Nil(t, err)
+		assert.Equal(t, stateRoot, root)
+
+		_, err = excluding.GenerateStateChangeProof(blockNumber, blockHash, "PluginModel", base.NewSlotID(fmt.Sprintf("0x%064x", 0), 0))
+		assert.NotNil(t, err)
+	})
+}
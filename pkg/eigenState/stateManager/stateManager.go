@@ -29,13 +29,20 @@ type EigenStateManager struct {
 	StateModels map[int]types.IEigenStateModel
 	logger      *zap.Logger
 	DB          *gorm.DB
+
+	// names of models that are processed but not included in the state root
+	excludedFromStateRoot map[string]bool
+
+	// guards the in-memory state of the models, which is shared between the pipeline and on-demand rebuilds of past blocks
+	processingLock sync.Mutex
 }
 
 func NewEigenStateManager(logger *zap.Logger, grm *gorm.DB) *EigenStateManager {
 	return &EigenStateManager{
-		StateModels: make(map[int]types.IEigenStateModel),
-		logger:      logger,
-		DB:          grm,
+		StateModels:           make(map[int]types.IEigenStateModel),
+		logger:                logger,
+		DB:                    grm,
+		excludedFromStateRoot: make(map[string]bool),
 	}
 }
 
@@ -47,6 +54,17 @@ func (e *EigenStateManager) RegisterState(model types.IEigenStateModel, index in
 	e.StateModels[index] = model
 }
 
+// ExcludeFromStateRoot keeps the state of a registered model out of the state root.
+// The model still processes logs and commits its state like any other model.
+func (e *EigenStateManager) ExcludeFromStateRoot(modelName string) {
+	e.excludedFromStateRoot[modelName] = true
+}
+
+// IsExcludedFromStateRoot returns true if the model's state is not included in the state root
+func (e *EigenStateManager) IsExcludedFromStateRoot(modelName string) bool {
+	return e.excludedFromStateRoot[modelName]
+}
+
 // Given a log, allow each state model to determine if/how to process it.
 func (e *EigenStateManager) HandleLogStateChange(log *storage.TransactionLog) error {
 	e.logger.Sugar().Debugw("Handling log state change", zap.String("transactionHash", log.TransactionHash), zap.Uint64("logIndex", log.LogIndex))
@@ -68,6 +86,18 @@ func (e *EigenStateManager) HandleLogStateChange(log *storage.TransactionLog) er
 	return nil
 }
 
+// LockProcessing gives the caller exclusive use of the models' in-memory state until UnlockProcessing is called.
+//
+// The pipeline holds the lock while it processes a block and anything that rebuilds the state of a block with the
+// same EigenStateManager must hold it for the whole rebuild.
+func (e *EigenStateManager) LockProcessing() {
+	e.processingLock.Lock()
+}
+
+func (e *EigenStateManager) UnlockProcessing() {
+	e.processingLock.Unlock()
+}
+
 func (e *EigenStateManager) InitProcessingForBlock(blockNumber uint64) error {
 	for _, index := range e.GetSortedModelIndexes() {
 		state := e.StateModels[index]
@@ -80,11 +110,13 @@ func (e *EigenStateManager) InitProcessingForBlock(blockNumber uint64) error {
 }
 
 // With all transactions/logs processed for a block, commit the final state to the table.
-func (e *EigenStateManager) CommitFinalState(blockNumber uint64) (map[string][]interface{}, error) {
+//
+// All writes go through the provided transaction so that the block's state is applied atomically.
+func (e *EigenStateManager) CommitFinalState(blockNumber uint64, tx *gorm.DB) (map[string][]interface{}, error) {
 	committedState := make(map[string][]interface{})
 	for _, index := range e.GetSortedModelIndexes() {
 		state := e.StateModels[index]
-		err := state.CommitFinalState(blockNumber)
+		err := state.CommitFinalState(blockNumber, tx)
 		if err != nil {
 			return committedState, err
 		}
@@ -109,41 +141,62 @@ func (e *EigenStateManager) CleanupProcessedStateForBlock(blockNumber uint64) er
 }
 
 func (e *EigenStateManager) GenerateStateRoot(blockNumber uint64, blockHash string) (types.StateRoot, error) {
-	sortedIndexes := e.GetSortedModelIndexes()
-	common.FromHex(blockHash)
+	root, _, err := e.GenerateStateRootWithModelRoots(blockNumber, blockHash)
+	return root, err
+}
+
+// GenerateStateRootWithModelRoots generates the state root for the block along with the root of each model
+// that went into it. Inputs are not populated on the returned model roots.
+func (e *EigenStateManager) GenerateStateRootWithModelRoots(blockNumber uint64, blockHash string) (types.StateRoot, []*ModelStateRoot, error) {
+	modelRoots := make([]*ModelStateRoot, 0, len(e.StateModels))
+	for _, index := range e.GetSortedModelIndexes() {
+		state := e.StateModels[index]
+		if e.IsExcludedFromStateRoot(state.GetModelName()) {
+			continue
+		}
+		root, err := state.GenerateStateRoot(blockNumber)
+		if err != nil {
+			return "", nil, err
+		}
+		modelRoots = append(modelRoots, &ModelStateRoot{
+			ModelName: state.GetModelName(),
+			Root:      root,
+			Leaf:      encodeModelLeafForRoot(state.GetModelName(), root),
+		})
+	}
+
+	tree, err := newStateRootTree(blockNumber, blockHash, modelRoots)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return types.StateRoot(utils.ConvertBytesToString(tree.Root())), modelRoots, nil
+}
+
+func newStateRootTree(blockNumber uint64, blockHash string, modelRoots []*ModelStateRoot) (*merkletree.MerkleTree, error) {
 	roots := [][]byte{
 		append(types.MerkleLeafPrefix_Block, binary.BigEndian.AppendUint64([]byte{}, blockNumber)...),
 		append(types.MerkleLeafPrefix_BlockHash, common.FromHex(blockHash)...),
 	}
 
-	for _, state := range sortedIndexes {
-		state := e.StateModels[state]
-		leaf, err := e.encodeModelLeaf(state, blockNumber)
-		if err != nil {
-			return "", err
-		}
-
+	for _, modelRoot := range modelRoots {
 		// a nil value indicates the model did not have any state changes for this block
-		if leaf != nil {
-			roots = append(roots, leaf)
+		if modelRoot.Leaf != nil {
+			roots = append(roots, modelRoot.Leaf)
 		}
 	}
 
-	tree, err := merkletree.NewTree(
+	return merkletree.NewTree(
 		merkletree.WithData(roots),
 		merkletree.WithHashType(keccak256.New()),
 	)
-	if err != nil {
-		return "", err
-	}
-
-	return types.StateRoot(utils.ConvertBytesToString(tree.Root())), nil
 }
 
 func (e *EigenStateManager) WriteStateRoot(
 	blockNumber uint64,
 	blockHash string,
 	stateroot types.StateRoot,
+	tx *gorm.DB,
 ) (*StateRoot, error) {
 	root := &StateRoot{
 		EthBlockNumber: blockNumber,
@@ -151,7 +204,7 @@ func (e *EigenStateManager) WriteStateRoot(
 		StateRoot:      string(stateroot),
 	}
 
-	result := e.DB.Model(&StateRoot{}).Clauses(clause.Returning{}).Create(&root)
+	result := tx.Model(&StateRoot{}).Clauses(clause.Returning{}).Create(&root)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -167,17 +220,53 @@ func (e *EigenStateManager) GetStateRootForBlock(blockNumber uint64) (*StateRoot
 	return root, nil
 }
 
-func (e *EigenStateManager) encodeModelLeaf(model types.IEigenStateModel, blockNumber uint64) ([]byte, error) {
-	root, err := model.GenerateStateRoot(blockNumber)
-	if err != nil {
-		return nil, err
-	}
+func encodeModelLeafForRoot(modelName string, root []byte) []byte {
 	// If there is no root string returned, it means nothing meaningful happened to the model
 	// during this block and should not be included in the state root.
 	if root == nil {
-		return nil, nil
+		return nil
 	}
-	return append(types.MerkleLeafPrefix_EigenStateRoot, append([]byte(model.GetModelName()), root...)...), nil
+	return append(types.MerkleLeafPrefix_EigenStateRoot, append([]byte(modelName), root...)...)
+}
+
+// ModelStateRoot is the root of a single model's state tree for a block, along with the slots it was built from.
+type ModelStateRoot struct {
+	ModelName string
+	// Root is nil when the model had no state changes in the block
+	Root []byte
+	// Leaf is the leaf included in the block's state root, or nil if the model is not included
+	Leaf   []byte
+	Inputs []*types.MerkleTreeInput
+}
+
+// GetModelStateRoots returns the root of each model's state tree that goes into the state root for the block,
+// in the same order as they are used by GenerateStateRoot.
+//
+// Like GenerateStateRoot, this must be called while the block is being processed, after all logs have been handled.
+func (e *EigenStateManager) GetModelStateRoots(blockNumber uint64) ([]*ModelStateRoot, error) {
+	modelRoots := make([]*ModelStateRoot, 0, len(e.StateModels))
+	for _, index := range e.GetSortedModelIndexes() {
+		model := e.StateModels[index]
+		if e.IsExcludedFromStateRoot(model.GetModelName()) {
+			continue
+		}
+
+		inputs, err := model.GetMerkleTreeInputs(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		root, err := model.GenerateStateRoot(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		modelRoots = append(modelRoots, &ModelStateRoot{
+			ModelName: model.GetModelName(),
+			Root:      root,
+			Leaf:      encodeModelLeafForRoot(model.GetModelName(), root),
+			Inputs:    inputs,
+		})
+	}
+	return modelRoots, nil
 }
 
 func (e *EigenStateManager) GetModelsMappedByName() map[string]types.IEigenStateModel {
@@ -213,10 +302,11 @@ func (e *EigenStateManager) GetLatestStateRoot() (*StateRoot, error) {
 //
 // @param startBlock the block number to start deleting state from (inclusive)
 // @param endBlock the block number to end deleting state from (inclusive). If 0, delete all state from startBlock.
-func (e *EigenStateManager) DeleteCorruptedState(startBlock uint64, endBlock uint64) error {
+// @param tx the database transaction the deletes are made in
+func (e *EigenStateManager) DeleteCorruptedState(startBlock uint64, endBlock uint64, tx *gorm.DB) error {
 	for _, index := range e.GetSortedModelIndexes() {
 		state := e.StateModels[index]
-		err := state.DeleteState(startBlock, endBlock)
+		err := state.DeleteState(startBlock, endBlock, tx)
 		if err != nil {
 			return err
 		}
@@ -224,10 +314,33 @@ func (e *EigenStateManager) DeleteCorruptedState(startBlock uint64, endBlock uin
 	return nil
 }
 
-func (e *EigenStateManager) GetSubmittedDistributionRoots(blockNumber uint64) ([]*types.SubmittedDistributionRoot, error) {
+// DeleteStateRoots deletes the state roots stored for the given block range
+//
+// @param startBlock the block number to start deleting state roots from (inclusive)
+// @param endBlock the block number to end deleting state roots from (inclusive). If 0, delete all state roots from startBlock.
+// @param tx the database transaction the deletes are made in
+func (e *EigenStateManager) DeleteStateRoots(startBlock uint64, endBlock uint64, tx *gorm.DB) error {
+	if endBlock != 0 && endBlock < startBlock {
+		return errors.New("Invalid block range; endBlock must be greater than or equal to startBlock")
+	}
+	query := tx.Where("eth_block_number >= ?", startBlock)
+	if endBlock > 0 {
+		query = query.Where("eth_block_number <= ?", endBlock)
+	}
+	res := query.Delete(&StateRoot{})
+	if res.Error != nil {
+		e.logger.Sugar().Errorw("Failed to delete state roots", zap.Error(res.Error))
+		return res.Error
+	}
+	return e.deleteModelRoots(startBlock, endBlock, tx)
+}
+
+// GetSubmittedDistributionRoots returns the distribution roots submitted in the given block. The query is made
+// using the provided transaction so that roots committed as part of an in-progress block are visible.
+func (e *EigenStateManager) GetSubmittedDistributionRoots(blockNumber uint64, tx *gorm.DB) ([]*types.SubmittedDistributionRoot, error) {
 	roots := make([]*types.SubmittedDistributionRoot, 0)
 
-	res := e.DB.Model(&types.SubmittedDistributionRoot{}).Where("block_number = ?", blockNumber).Find(&roots)
+	res := tx.Model(&types.SubmittedDistributionRoot{}).Where("block_number = ?", blockNumber).Find(&roots)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -67,7 +67,7 @@ func Test_StateManager(t *testing.T) {
 
 		stateRoot := types.StateRoot("0x456")
 
-		root, err := esm.WriteStateRoot(blockNumber, blockHash, stateRoot)
+		root, err := esm.WriteStateRoot(blockNumber, blockHash, stateRoot, grm)
 		assert.Nil(t, err)
 		assert.Equal(t, blockNumber, root.EthBlockNumber)
 		assert.Equal(t, blockHash, root.EthBlockHash)
@@ -86,6 +86,33 @@ func Test_StateManager(t *testing.T) {
 		assert.Equal(t, insertedStateRoots[0].EthBlockHash, root.EthBlockHash)
 		assert.Equal(t, insertedStateRoots[0].StateRoot, root.StateRoot)
 	})
+	t.Run("Should write model roots to the db", func(t *testing.T) {
+		esm := NewEigenStateManager(l, grm)
+
+		blockNumber := insertedStateRoots[0].EthBlockNumber
+		blockHash := insertedStateRoots[0].EthBlockHash
+
+		modelRoots := []*ModelStateRoot{
+			{ModelName: "ModelA", Root: []byte{0x01, 0x02}},
+			{ModelName: "ModelB", Root: nil},
+		}
+		written, err := esm.WriteModelRoots(blockNumber, blockHash, modelRoots, grm)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(written))
+
+		roots, err := esm.GetModelRootsForBlock(blockNumber)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(roots))
+		assert.Equal(t, "ModelA", roots[0].ModelName)
+		assert.Equal(t, "0x0102", roots[0].Root)
+
+		err = esm.DeleteStateRoots(blockNumber, 0, grm)
+		assert.Nil(t, err)
+
+		roots, err = esm.GetModelRootsForBlock(blockNumber)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(roots))
+	})
 
 	t.Cleanup(func() {
 		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
@@ -234,14 +234,14 @@ func (sdr *SubmittedDistributionRootsModel) prepareState(blockNumber uint64) ([]
 	return preparedState, nil
 }
 
-func (sdr *SubmittedDistributionRootsModel) CommitFinalState(blockNumber uint64) error {
+func (sdr *SubmittedDistributionRootsModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) error {
 	records, err := sdr.prepareState(blockNumber)
 	if err != nil {
 		return err
 	}
 
 	if len(records) > 0 {
-		res := sdr.DB.Model(&types.SubmittedDistributionRoot{}).Clauses(clause.Returning{}).Create(&records)
+		res := tx.Model(&types.SubmittedDistributionRoot{}).Clauses(clause.Returning{}).Create(&records)
 		if res.Error != nil {
 			sdr.logger.Sugar().Errorw("Failed to create new submitted_distribution_roots records", zap.Error(res.Error))
 			return res.Error
@@ -276,13 +276,20 @@ func (sdr *SubmittedDistributionRootsModel) sortValuesForMerkleTree(inputs []*ty
 	return values
 }
 
-func (sdr *SubmittedDistributionRootsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+// GetMerkleTreeInputs returns the sorted slots that make up the model's state root for the given block.
+func (sdr *SubmittedDistributionRootsModel) GetMerkleTreeInputs(blockNumber uint64) ([]*base.MerkleTreeInput, error) {
 	diffs, err := sdr.prepareState(blockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return sdr.sortValuesForMerkleTree(diffs), nil
+}
 
-	sortedInputs := sdr.sortValuesForMerkleTree(diffs)
+func (sdr *SubmittedDistributionRootsModel) GenerateStateRoot(blockNumber uint64) ([]byte, error) {
+	sortedInputs, err := sdr.GetMerkleTreeInputs(blockNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(sortedInputs) == 0 {
 		return nil, nil
@@ -300,8 +307,8 @@ func (sdr *SubmittedDistributionRootsModel) GenerateStateRoot(blockNumber uint64
 	return fullTree.Root(), nil
 }
 
-func (sdr *SubmittedDistributionRootsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return sdr.BaseEigenState.DeleteState("submitted_distribution_roots", startBlockNumber, endBlockNumber, sdr.DB)
+func (sdr *SubmittedDistributionRootsModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return sdr.BaseEigenState.DeleteState("submitted_distribution_roots", startBlockNumber, endBlockNumber, tx)
 }
 
 func (sdr *SubmittedDistributionRootsModel) GetAccumulatedState(blockNumber uint64) []*types.SubmittedDistributionRoot {
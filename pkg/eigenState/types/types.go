@@ -2,6 +2,7 @@ package types
 
 import (
 	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"gorm.io/gorm"
 )
 
 type StateRoot string
@@ -30,13 +31,19 @@ type IEigenStateModel interface {
 	HandleStateChange(log *storage.TransactionLog) (interface{}, error)
 
 	// CommitFinalState
-	// Once all state changes are processed, commit the final state to the database
-	CommitFinalState(blockNumber uint64) error
+	// Once all state changes are processed, commit the final state to the database.
+	//
+	// All writes must be made using the provided transaction so that the block is applied atomically.
+	CommitFinalState(blockNumber uint64, tx *gorm.DB) error
 
 	// GetCommittedState
 	// Get the committed state for the model at the given block height.
 	GetCommittedState(blockNumber uint64) ([]interface{}, error)
 
+	// GetMerkleTreeInputs
+	// Get the sorted slots that make up the model's state root for the given block
+	GetMerkleTreeInputs(blockNumber uint64) ([]*MerkleTreeInput, error)
+
 	// GenerateStateRoot
 	// Generate the state root for the model
 	GenerateStateRoot(blockNumber uint64) ([]byte, error)
@@ -46,7 +53,8 @@ type IEigenStateModel interface {
 	//
 	// @param startBlockNumber the block number to start deleting state from (inclusive)
 	// @param endBlockNumber the block number to end deleting state from (inclusive). If 0, delete all state from startBlockNumber
-	DeleteState(startBlockNumber uint64, endBlockNumber uint64) error
+	// @param tx the database transaction the delete is made in
+	DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error
 
 	// ListForBlockRange lists all records for the block range, inclusive of start and end block numbers
 	ListForBlockRange(startBlockNumber uint64, endBlockNumber uint64) ([]interface{}, error)
@@ -58,6 +66,12 @@ type StateTransitions[T any] map[uint64]func(log *storage.TransactionLog) (T, er
 
 type SlotID string
 
+// MerkleTreeInput is a single slot in a model's state tree
+type MerkleTreeInput struct {
+	SlotID SlotID
+	Value  []byte
+}
+
 type MerkleLeafPrefix []byte
 
 var (
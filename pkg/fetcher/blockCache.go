@@ -0,0 +1,183 @@
+package fetcher
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"go.uber.org/zap"
+)
+
+// Number of blocks stored in a single cache sub-directory to keep directory listings small
+const blockCacheBucketSize = 10000
+
+// cachedBlock is the on-disk representation of a FetchedBlock.
+//
+// FetchStrategy records how the block was fetched. Blocks fetched using the logs strategy only contain the
+// interesting transactions, so they can only be served back to a Fetcher that is also using the logs strategy.
+type cachedBlock struct {
+	FetchStrategy config.FetchStrategy `json:"fetchStrategy"`
+	Block         *FetchedBlock        `json:"block"`
+}
+
+// BlockCache is a content-addressed, on-disk cache of fetched blocks and their receipts.
+//
+// Each block is stored as a gzipped JSON file named after its number and hash:
+//
+//	<dir>/<blockNumber / 10000>/<blockNumber>_<blockHash>.json.gz
+//
+// Only one entry is kept per block number; storing a block with a different hash replaces the previous entry.
+type BlockCache struct {
+	dir    string
+	logger *zap.Logger
+}
+
+func NewBlockCache(dir string, l *zap.Logger) (*BlockCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create block cache directory '%s': %w", dir, err)
+	}
+	return &BlockCache{
+		dir:    dir,
+		logger: l,
+	}, nil
+}
+
+func (c *BlockCache) bucketDir(blockNumber uint64) string {
+	return filepath.Join(c.dir, strconv.FormatUint(blockNumber/blockCacheBucketSize, 10))
+}
+
+func (c *BlockCache) blockPath(blockNumber uint64, blockHash string) string {
+	return filepath.Join(c.bucketDir(blockNumber), fmt.Sprintf("%d_%s.json.gz", blockNumber, strings.ToLower(blockHash)))
+}
+
+// findEntries returns the paths of all cached entries for the given block number.
+func (c *BlockCache) findEntries(blockNumber uint64) ([]string, error) {
+	return filepath.Glob(filepath.Join(c.bucketDir(blockNumber), fmt.Sprintf("%d_*.json.gz", blockNumber)))
+}
+
+// Get returns the cached block for the given block number if one exists and was fetched with a strategy that is
+// compatible with fetchStrategy. A nil block and nil error are returned on a cache miss.
+func (c *BlockCache) Get(blockNumber uint64, fetchStrategy config.FetchStrategy) (*FetchedBlock, error) {
+	entries, err := c.findEntries(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		return nil, nil
+	}
+	return c.read(entries[0], fetchStrategy)
+}
+
+// GetByHash returns the cached block for the given block number and hash, or nil if it is not cached.
+func (c *BlockCache) GetByHash(blockNumber uint64, blockHash string, fetchStrategy config.FetchStrategy) (*FetchedBlock, error) {
+	path := c.blockPath(blockNumber, blockHash)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c.read(path, fetchStrategy)
+}
+
+func (c *BlockCache) read(path string, fetchStrategy config.FetchStrategy) (*FetchedBlock, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached block '%s': %w", path, err)
+	}
+	defer gz.Close()
+
+	var cb cachedBlock
+	if err := json.NewDecoder(gz).Decode(&cb); err != nil {
+		return nil, fmt.Errorf("failed to decode cached block '%s': %w", path, err)
+	}
+	if cb.Block == nil || cb.Block.Block == nil {
+		return nil, fmt.Errorf("cached block '%s' is empty", path)
+	}
+
+	// A block fetched with every receipt can be used by either strategy, but a block fetched using logs can't
+	// be used to index every transaction.
+	if cb.FetchStrategy == config.FetchStrategy_Logs && fetchStrategy != config.FetchStrategy_Logs {
+		return nil, nil
+	}
+	return cb.Block, nil
+}
+
+// Put writes the block to the cache, replacing any existing entry for the same block number.
+//
+// The file is written to a temporary file first and renamed into place so that a partially written
+// entry is never read back.
+func (c *BlockCache) Put(block *FetchedBlock, fetchStrategy config.FetchStrategy) error {
+	blockNumber := block.Block.Number.Value()
+	path := c.blockPath(blockNumber, block.Block.Hash.Value())
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), fmt.Sprintf(".%d_*.tmp", blockNumber))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(gz).Encode(&cachedBlock{FetchStrategy: fetchStrategy, Block: block}); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to encode block %d: %w", blockNumber, err)
+	}
+	if err := gz.Close(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	entries, err := c.findEntries(blockNumber)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry != path {
+			if err := os.Remove(entry); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Invalidate removes all cached entries for blocks in the inclusive range.
+func (c *BlockCache) Invalidate(startBlockInclusive uint64, endBlockInclusive uint64) error {
+	for blockNumber := startBlockInclusive; blockNumber <= endBlockInclusive; blockNumber++ {
+		entries, err := c.findEntries(blockNumber)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := os.Remove(entry); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	c.logger.Sugar().Debugw("Invalidated cached blocks",
+		zap.Uint64("startBlock", startBlockInclusive),
+		zap.Uint64("endBlock", endBlockInclusive),
+	)
+	return nil
+}
@@ -0,0 +1,111 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFetchedBlock(number uint64, hash string) *FetchedBlock {
+	status := ethereum.EthereumQuantity(1)
+	return &FetchedBlock{
+		Block: &ethereum.EthereumBlock{
+			Hash:       ethereum.EthereumHexString(hash),
+			ParentHash: "0xparent",
+			Number:     ethereum.EthereumQuantity(number),
+			Timestamp:  ethereum.EthereumQuantity(1700000000),
+			Transactions: []*ethereum.EthereumTransaction{
+				{Hash: "0xtx", BlockNumber: ethereum.EthereumQuantity(number), From: "0xfrom", To: "0xto"},
+			},
+		},
+		TxReceipts: map[string]*ethereum.EthereumTransactionReceipt{
+			"0xtx": {
+				TransactionHash: "0xtx",
+				BlockNumber:     ethereum.EthereumQuantity(number),
+				Status:          &status,
+				Logs: []*ethereum.EthereumEventLog{
+					{Address: "0xto", Data: "0x", Topics: []ethereum.EthereumHexString{"0xtopic"}},
+				},
+			},
+		},
+	}
+}
+
+func Test_BlockCache(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+
+	t.Run("Should round trip a block through the cache", func(t *testing.T) {
+		bc, err := NewBlockCache(t.TempDir(), l)
+		assert.Nil(t, err)
+
+		block, err := bc.Get(100, config.FetchStrategy_Receipts)
+		assert.Nil(t, err)
+		assert.Nil(t, block)
+
+		assert.Nil(t, bc.Put(newTestFetchedBlock(100, "0xabc"), config.FetchStrategy_Receipts))
+
+		block, err = bc.Get(100, config.FetchStrategy_Receipts)
+		assert.Nil(t, err)
+		assert.NotNil(t, block)
+		assert.Equal(t, "0xabc", block.Block.Hash.Value())
+		assert.Equal(t, uint64(100), block.Block.Number.Value())
+		assert.Equal(t, 1, len(block.Block.Transactions))
+		assert.Equal(t, uint64(1), block.TxReceipts["0xtx"].Status.Value())
+		assert.Equal(t, "0xtopic", block.TxReceipts["0xtx"].Logs[0].Topics[0].Value())
+
+		block, err = bc.GetByHash(100, "0xabc", config.FetchStrategy_Receipts)
+		assert.Nil(t, err)
+		assert.NotNil(t, block)
+
+		block, err = bc.GetByHash(100, "0xdef", config.FetchStrategy_Receipts)
+		assert.Nil(t, err)
+		assert.Nil(t, block)
+	})
+	t.Run("Should replace a block with a different hash at the same height", func(t *testing.T) {
+		bc, err := NewBlockCache(t.TempDir(), l)
+		assert.Nil(t, err)
+
+		assert.Nil(t, bc.Put(newTestFetchedBlock(100, "0xabc"), config.FetchStrategy_Receipts))
+		assert.Nil(t, bc.Put(newTestFetchedBlock(100, "0xdef"), config.FetchStrategy_Receipts))
+
+		block, err := bc.Get(100, config.FetchStrategy_Receipts)
+		assert.Nil(t, err)
+		assert.Equal(t, "0xdef", block.Block.Hash.Value())
+	})
+	t.Run("Should not serve blocks fetched using logs to the receipts strategy", func(t *testing.T) {
+		bc, err := NewBlockCache(t.TempDir(), l)
+		assert.Nil(t, err)
+
+		assert.Nil(t, bc.Put(newTestFetchedBlock(100, "0xabc"), config.FetchStrategy_Logs))
+
+		block, err := bc.Get(100, config.FetchStrategy_Receipts)
+		assert.Nil(t, err)
+		assert.Nil(t, block)
+
+		block, err = bc.Get(100, config.FetchStrategy_Logs)
+		assert.Nil(t, err)
+		assert.NotNil(t, block)
+	})
+	t.Run("Should invalidate a range of blocks", func(t *testing.T) {
+		bc, err := NewBlockCache(t.TempDir(), l)
+		assert.Nil(t, err)
+
+		for i := uint64(9998); i <= 10002; i++ {
+			assert.Nil(t, bc.Put(newTestFetchedBlock(i, "0xabc"), config.FetchStrategy_Receipts))
+		}
+		assert.Nil(t, bc.Invalidate(9999, 10001))
+
+		for i := uint64(9998); i <= 10002; i++ {
+			block, err := bc.Get(i, config.FetchStrategy_Receipts)
+			assert.Nil(t, err)
+			if i >= 9999 && i <= 10001 {
+				assert.Nil(t, block)
+			} else {
+				assert.NotNil(t, block)
+			}
+		}
+	})
+}
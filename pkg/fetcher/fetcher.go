@@ -1,6 +1,7 @@
 package fetcher
 
 import (
+	"cmp"
 	"context"
 	"github.com/Layr-Labs/sidecar/internal/config"
 	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
@@ -12,17 +13,26 @@ import (
 )
 
 type Fetcher struct {
-	EthClient *ethereum.Client
-	Logger    *zap.Logger
-	Config    *config.Config
+	EthClient  *ethereum.Client
+	Logger     *zap.Logger
+	Config     *config.Config
+	BlockCache *BlockCache // optional; nil when the block cache is disabled
 }
 
 func NewFetcher(ethClient *ethereum.Client, cfg *config.Config, l *zap.Logger) *Fetcher {
-	return &Fetcher{
+	f := &Fetcher{
 		EthClient: ethClient,
 		Logger:    l,
 		Config:    cfg,
 	}
+	if cfg.BlockCacheConfig.Dir != "" {
+		bc, err := NewBlockCache(cfg.BlockCacheConfig.Dir, l)
+		if err != nil {
+			l.Sugar().Fatalw("Failed to initialize block cache", zap.String("dir", cfg.BlockCacheConfig.Dir), zap.Error(err))
+		}
+		f.BlockCache = bc
+	}
+	return f
 }
 
 type FetchedBlock struct {
@@ -32,12 +42,34 @@ type FetchedBlock struct {
 }
 
 func (f *Fetcher) FetchBlock(ctx context.Context, blockNumber uint64) (*FetchedBlock, error) {
+	if cached := f.getCachedBlock(blockNumber); cached != nil {
+		return cached, nil
+	}
+
+	fetchedBlock, err := f.fetchBlockFromNode(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	f.putCachedBlocks([]*FetchedBlock{fetchedBlock})
+	return fetchedBlock, nil
+}
+
+func (f *Fetcher) fetchBlockFromNode(ctx context.Context, blockNumber uint64) (*FetchedBlock, error) {
 	block, err := f.EthClient.GetBlockByNumber(ctx, blockNumber)
 	if err != nil {
 		f.Logger.Sugar().Errorw("failed to get block by number", zap.Error(err))
 		return nil, err
 	}
 
+	if f.Config.EthereumRpcConfig.FetchStrategy == config.FetchStrategy_Logs {
+		fetchedBlocks, err := f.FetchReceiptsForBlocksUsingLogs(ctx, []*ethereum.EthereumBlock{block})
+		if err != nil {
+			f.Logger.Sugar().Errorw("failed to fetch receipts for block using logs", zap.Error(err))
+			return nil, err
+		}
+		return fetchedBlocks[0], nil
+	}
+
 	receipts, err := f.FetchReceiptsForBlock(ctx, block)
 	if err != nil {
 		f.Logger.Sugar().Errorw("failed to fetch receipts for block", zap.Error(err))
@@ -53,11 +85,19 @@ func (f *Fetcher) FetchBlock(ctx context.Context, blockNumber uint64) (*FetchedB
 func (f *Fetcher) FetchReceiptsForBlock(ctx context.Context, block *ethereum.EthereumBlock) (map[string]*ethereum.EthereumTransactionReceipt, error) {
 	blockNumber := block.Number.Value()
 
-	txReceiptRequests := make([]*ethereum.RPCRequest, 0)
 	f.Logger.Sugar().Debugf("Fetching '%d' transactions from block '%d'", len(block.Transactions), blockNumber)
 
-	for i, tx := range block.Transactions {
-		txReceiptRequests = append(txReceiptRequests, ethereum.GetTransactionReceiptRequest(tx.Hash.Value(), uint(i)))
+	txHashes := make([]string, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		txHashes = append(txHashes, tx.Hash.Value())
+	}
+	return f.fetchReceiptsForTransactions(ctx, blockNumber, txHashes)
+}
+
+func (f *Fetcher) fetchReceiptsForTransactions(ctx context.Context, blockNumber uint64, txHashes []string) (map[string]*ethereum.EthereumTransactionReceipt, error) {
+	txReceiptRequests := make([]*ethereum.RPCRequest, 0)
+	for i, txHash := range txHashes {
+		txReceiptRequests = append(txReceiptRequests, ethereum.GetTransactionReceiptRequest(txHash, uint(i)))
 	}
 
 	f.Logger.Sugar().Debugw("Fetching transaction receipts",
@@ -130,21 +170,53 @@ func (f *Fetcher) FetchBlocksWithRetries(ctx context.Context, startBlockInclusiv
 	f.Logger.Sugar().Errorw("failed to fetch blocks for range, exhausted all retries",
 		zap.Uint64("startBlock", startBlockInclusive),
 		zap.Uint64("endBlock", endBlockInclusive),
+		zap.Any("endpoints", f.EthClient.GetEndpointStats()),
 		zap.Error(e),
 	)
 	return nil, e
 }
 
 func (f *Fetcher) FetchBlocks(ctx context.Context, startBlockInclusive uint64, endBlockInclusive uint64) ([]*FetchedBlock, error) {
+	cachedBlocks := make([]*FetchedBlock, 0)
 	blockNumbers := make([]uint64, 0)
 	for i := startBlockInclusive; i <= endBlockInclusive; i++ {
+		if cached := f.getCachedBlock(i); cached != nil {
+			cachedBlocks = append(cachedBlocks, cached)
+			continue
+		}
 		blockNumbers = append(blockNumbers, i)
 	}
 
 	if len(blockNumbers) == 0 {
-		return []*FetchedBlock{}, nil
+		return cachedBlocks, nil
 	}
 
+	fetchedBlocks, err := f.fetchBlocksFromNode(ctx, blockNumbers)
+	if err != nil {
+		return nil, err
+	}
+	f.putCachedBlocks(fetchedBlocks)
+
+	if len(cachedBlocks) == 0 {
+		return fetchedBlocks, nil
+	}
+	f.Logger.Sugar().Debugw("Using cached blocks",
+		zap.Int("cached", len(cachedBlocks)),
+		zap.Int("fetched", len(fetchedBlocks)),
+		zap.Uint64("startBlock", startBlockInclusive),
+		zap.Uint64("endBlock", endBlockInclusive),
+	)
+
+	fetchedBlocks = append(fetchedBlocks, cachedBlocks...)
+	slices.SortFunc(fetchedBlocks, func(i, j *FetchedBlock) int {
+		return cmp.Compare(i.Block.Number.Value(), j.Block.Number.Value())
+	})
+	return fetchedBlocks, nil
+}
+
+// fetchBlocksFromNode fetches the given blocks and their receipts from the Ethereum node.
+func (f *Fetcher) fetchBlocksFromNode(ctx context.Context, blockNumbers []uint64) ([]*FetchedBlock, error) {
+
 	blockRequests := make([]*ethereum.RPCRequest, 0)
 	for i, n := range blockNumbers {
 		blockRequests = append(blockRequests, ethereum.GetBlockByNumberRequest(n, uint(i)))
@@ -184,6 +256,10 @@ func (f *Fetcher) FetchBlocks(ctx context.Context, startBlockInclusive uint64, e
 		return nil, err
 	}
 
+	if f.Config.EthereumRpcConfig.FetchStrategy == config.FetchStrategy_Logs {
+		return f.FetchReceiptsForBlocksUsingLogs(ctx, blocks)
+	}
+
 	fetchedBlockResponses := make(chan *FetchedBlock, len(blocks))
 	foundErrorsChan := make(chan bool, 1)
 
@@ -232,14 +308,53 @@ func (f *Fetcher) FetchBlocks(ctx context.Context, startBlockInclusive uint64, e
 
 	// ensure blocks are sorted ascending
 	slices.SortFunc(fetchedBlocks, func(i, j *FetchedBlock) int {
-		return int(i.Block.Number.Value() - j.Block.Number.Value())
+		return cmp.Compare(i.Block.Number.Value(), j.Block.Number.Value())
 	})
 
 	f.Logger.Sugar().Debugw("Fetched blocks",
 		zap.Int("count", len(fetchedBlocks)),
-		zap.Uint64("startBlock", startBlockInclusive),
-		zap.Uint64("endBlock", endBlockInclusive),
+		zap.Uint64("startBlock", blockNumbers[0]),
+		zap.Uint64("endBlock", blockNumbers[len(blockNumbers)-1]),
 	)
 
 	return fetchedBlocks, nil
 }
+
+// getCachedBlock returns the block from the block cache, or nil if the cache is disabled or doesn't contain the block.
+func (f *Fetcher) getCachedBlock(blockNumber uint64) *FetchedBlock {
+	if f.BlockCache == nil {
+		return nil
+	}
+	block, err := f.BlockCache.Get(blockNumber, f.Config.EthereumRpcConfig.FetchStrategy)
+	if err != nil {
+		f.Logger.Sugar().Warnw("failed to read block from cache, fetching from node",
+			zap.Uint64("blockNumber", blockNumber),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return block
+}
+
+// putCachedBlocks writes the blocks to the block cache if it is enabled. Failing to write to the cache is not fatal.
+func (f *Fetcher) putCachedBlocks(blocks []*FetchedBlock) {
+	if f.BlockCache == nil {
+		return
+	}
+	for _, b := range blocks {
+		if err := f.BlockCache.Put(b, f.Config.EthereumRpcConfig.FetchStrategy); err != nil {
+			f.Logger.Sugar().Warnw("failed to write block to cache",
+				zap.Uint64("blockNumber", b.Block.Number.Value()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// InvalidateCachedBlocks removes the given range of blocks from the block cache, e.g. after they were reorged out.
+func (f *Fetcher) InvalidateCachedBlocks(startBlockInclusive uint64, endBlockInclusive uint64) error {
+	if f.BlockCache == nil {
+		return nil
+	}
+	return f.BlockCache.Invalidate(startBlockInclusive, endBlockInclusive)
+}
@@ -0,0 +1,202 @@
+package fetcher
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// FetchReceiptsForBlocksUsingLogs uses eth_getLogs, filtered by the interesting contract addresses, to find
+// the transactions in the given blocks that we care about and only fetches receipts for those transactions.
+//
+// Transactions that are sent to, or deploy, an interesting contract are also included since they may not emit logs.
+// Blocks without any matching transactions are returned with an empty transaction list.
+//
+// If eth_getLogs fails (e.g. the node does not support it or rejects the range), this falls back to fetching
+// every receipt for each block using eth_getBlockReceipts.
+func (f *Fetcher) FetchReceiptsForBlocksUsingLogs(ctx context.Context, blocks []*ethereum.EthereumBlock) ([]*FetchedBlock, error) {
+	if len(blocks) == 0 {
+		return []*FetchedBlock{}, nil
+	}
+
+	slices.SortFunc(blocks, func(i, j *ethereum.EthereumBlock) int {
+		return cmp.Compare(i.Number.Value(), j.Number.Value())
+	})
+	startBlock := blocks[0].Number.Value()
+	endBlock := blocks[len(blocks)-1].Number.Value()
+
+	addresses := f.Config.GetInterestingAddressForConfigEnv()
+
+	logs, err := f.EthClient.GetLogs(ctx, addresses, startBlock, endBlock)
+	if err != nil {
+		f.Logger.Sugar().Warnw("failed to get logs for block range, falling back to eth_getBlockReceipts",
+			zap.Uint64("startBlock", startBlock),
+			zap.Uint64("endBlock", endBlock),
+			zap.Error(err),
+		)
+		return f.FetchBlockReceiptsForBlocks(ctx, blocks)
+	}
+
+	interestingTxs, err := f.findInterestingTransactions(blocks, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedBlocks := make([]*FetchedBlock, 0, len(blocks))
+	for _, b := range blocks {
+		blockNumber := b.Number.Value()
+		txHashes := interestingTxs[blockNumber]
+
+		transactions := make([]*ethereum.EthereumTransaction, 0, len(txHashes))
+		for _, tx := range b.Transactions {
+			if txHashes[strings.ToLower(tx.Hash.Value())] {
+				transactions = append(transactions, tx)
+			}
+		}
+		b.Transactions = transactions
+
+		receipts, err := f.FetchReceiptsForBlock(ctx, b)
+		if err != nil {
+			f.Logger.Sugar().Errorw("failed to fetch receipts for block",
+				zap.Uint64("blockNumber", blockNumber),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		fetchedBlocks = append(fetchedBlocks, &FetchedBlock{
+			Block:      b,
+			TxReceipts: receipts,
+		})
+	}
+
+	f.Logger.Sugar().Debugw("Fetched blocks using logs",
+		zap.Int("count", len(fetchedBlocks)),
+		zap.Int("logCount", len(logs)),
+		zap.Uint64("startBlock", startBlock),
+		zap.Uint64("endBlock", endBlock),
+	)
+
+	return fetchedBlocks, nil
+}
+
+// findInterestingTransactions returns the hashes of the interesting transactions in each block, keyed by block number.
+//
+// A transaction is interesting if it emitted one of the given logs or if it is sent to, or deploys, an interesting
+// contract. Removed logs and logs for blocks that are not in the list are ignored.
+func (f *Fetcher) findInterestingTransactions(
+	blocks []*ethereum.EthereumBlock,
+	logs []*ethereum.EthereumEventLog,
+) (map[uint64]map[string]bool, error) {
+	blocksByNumber := make(map[uint64]*ethereum.EthereumBlock, len(blocks))
+	for _, b := range blocks {
+		blocksByNumber[b.Number.Value()] = b
+	}
+
+	// map[blockNumber] => set of interesting transaction hashes
+	interestingTxs := make(map[uint64]map[string]bool, len(blocks))
+	for _, b := range blocks {
+		interestingTxs[b.Number.Value()] = make(map[string]bool)
+	}
+
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		blockNumber := log.BlockNumber.Value()
+		b, ok := blocksByNumber[blockNumber]
+		if !ok {
+			continue
+		}
+		// The logs and the blocks are fetched in separate calls; make sure they belong to the same chain
+		if !strings.EqualFold(log.BlockHash.Value(), b.Hash.Value()) {
+			f.Logger.Sugar().Errorw("log block hash does not match fetched block hash",
+				zap.Uint64("blockNumber", blockNumber),
+				zap.String("logBlockHash", log.BlockHash.Value()),
+				zap.String("blockHash", b.Hash.Value()),
+			)
+			return nil, fmt.Errorf("log block hash '%s' does not match block hash '%s' for block %d", log.BlockHash.Value(), b.Hash.Value(), blockNumber)
+		}
+		interestingTxs[blockNumber][strings.ToLower(log.TransactionHash.Value())] = true
+	}
+
+	for _, b := range blocks {
+		for _, tx := range b.Transactions {
+			if f.isInterestingTransaction(tx) {
+				interestingTxs[b.Number.Value()][strings.ToLower(tx.Hash.Value())] = true
+			}
+		}
+	}
+	return interestingTxs, nil
+}
+
+// FetchBlockReceiptsForBlocks fetches all receipts for each of the given blocks using a single
+// eth_getBlockReceipts call per block rather than one eth_getTransactionReceipt call per transaction.
+func (f *Fetcher) FetchBlockReceiptsForBlocks(ctx context.Context, blocks []*ethereum.EthereumBlock) ([]*FetchedBlock, error) {
+	requests := make([]*ethereum.RPCRequest, 0, len(blocks))
+	for i, b := range blocks {
+		requests = append(requests, ethereum.GetBlockReceiptsRequest(b.Number.Value(), uint(i)))
+	}
+
+	responses, err := f.EthClient.BatchCall(ctx, requests)
+	if err != nil {
+		f.Logger.Sugar().Errorw("failed to batch call for block receipts", zap.Error(err))
+		return nil, err
+	}
+	if len(responses) != len(requests) {
+		f.Logger.Sugar().Errorw("failed to fetch all block receipts",
+			zap.Int("fetched", len(responses)),
+			zap.Int("expected", len(requests)),
+		)
+		return nil, errors.New("failed to fetch all block receipts")
+	}
+
+	fetchedBlocks := make([]*FetchedBlock, 0, len(blocks))
+	for _, response := range responses {
+		b := blocks[*response.ID]
+		blockReceipts, err := ethereum.RPCMethod_getBlockReceipts.ResponseParser(response.Result)
+		if err != nil {
+			f.Logger.Sugar().Errorw("failed to parse block receipts",
+				zap.Error(err),
+				zap.Uint("response ID", *response.ID),
+			)
+			return nil, err
+		}
+		if len(blockReceipts) != len(b.Transactions) {
+			f.Logger.Sugar().Errorw("block receipt count does not match transaction count",
+				zap.Uint64("blockNumber", b.Number.Value()),
+				zap.Int("receipts", len(blockReceipts)),
+				zap.Int("transactions", len(b.Transactions)),
+			)
+			return nil, fmt.Errorf("expected %d receipts for block %d, got %d", len(b.Transactions), b.Number.Value(), len(blockReceipts))
+		}
+
+		receipts := make(map[string]*ethereum.EthereumTransactionReceipt, len(blockReceipts))
+		for _, r := range blockReceipts {
+			receipts[r.TransactionHash.Value()] = r
+		}
+		fetchedBlocks = append(fetchedBlocks, &FetchedBlock{
+			Block:      b,
+			TxReceipts: receipts,
+		})
+	}
+	return fetchedBlocks, nil
+}
+
+// isInterestingTransaction returns true if the transaction is sent to an interesting address, or if it
+// deploys a contract directly to an interesting address.
+func (f *Fetcher) isInterestingTransaction(tx *ethereum.EthereumTransaction) bool {
+	to := strings.ToLower(tx.To.Value())
+	if to != "" {
+		return f.IsInterestingAddress(to)
+	}
+	contractAddress := crypto.CreateAddress(common.HexToAddress(tx.From.Value()), tx.Nonce.Value())
+	return f.IsInterestingAddress(strings.ToLower(contractAddress.Hex()))
+}
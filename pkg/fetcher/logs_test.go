@@ -0,0 +1,65 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindInterestingTransactions(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+
+	cfg := config.NewConfig()
+	cfg.Chain = config.Chain_Mainnet
+	f := &Fetcher{Logger: l, Config: cfg}
+
+	rewardsCoordinator := cfg.GetContractsMapForChain().RewardsCoordinator
+
+	newBlocks := func() []*ethereum.EthereumBlock {
+		return []*ethereum.EthereumBlock{
+			{
+				Number: ethereum.EthereumQuantity(100),
+				Hash:   "0xblock100",
+				Transactions: []*ethereum.EthereumTransaction{
+					{Hash: "0xLOGGED", From: "0xfrom", To: "0xsomeproxy"},
+					{Hash: "0xcall", From: "0xfrom", To: ethereum.EthereumHexString(rewardsCoordinator)},
+					{Hash: "0xuninteresting", From: "0xfrom", To: "0xsomeoneelse"},
+				},
+			},
+			{
+				Number: ethereum.EthereumQuantity(101),
+				Hash:   "0xblock101",
+				Transactions: []*ethereum.EthereumTransaction{
+					{Hash: "0xremoved", From: "0xfrom", To: "0xsomeproxy"},
+				},
+			},
+		}
+	}
+
+	t.Run("Should include transactions with logs or sent to interesting contracts", func(t *testing.T) {
+		logs := []*ethereum.EthereumEventLog{
+			{BlockNumber: 100, BlockHash: "0xBLOCK100", TransactionHash: "0xlogged", Address: ethereum.EthereumHexString(rewardsCoordinator)},
+			{BlockNumber: 101, BlockHash: "0xblock101", TransactionHash: "0xremoved", Removed: true},
+			{BlockNumber: 102, BlockHash: "0xblock102", TransactionHash: "0xotherblock"},
+		}
+
+		interestingTxs, err := f.findInterestingTransactions(newBlocks(), logs)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(interestingTxs))
+
+		assert.Equal(t, map[string]bool{"0xlogged": true, "0xcall": true}, interestingTxs[100])
+		assert.Equal(t, 0, len(interestingTxs[101]))
+	})
+	t.Run("Should fail when a log belongs to a different block hash", func(t *testing.T) {
+		logs := []*ethereum.EthereumEventLog{
+			{BlockNumber: 100, BlockHash: "0xreorged", TransactionHash: "0xlogged"},
+		}
+
+		interestingTxs, err := f.findInterestingTransactions(newBlocks(), logs)
+		assert.NotNil(t, err)
+		assert.Nil(t, interestingTxs)
+	})
+}
@@ -112,6 +112,15 @@ func NewIndexer(
 	}
 }
 
+// WithTransaction returns a copy of the Indexer whose block, transaction and log writes are made using the
+// given database transaction.
+func (idx *Indexer) WithTransaction(tx *gorm.DB) *Indexer {
+	txIdx := *idx
+	txIdx.MetadataStore = idx.MetadataStore.WithTransaction(tx)
+	txIdx.db = tx
+	return &txIdx
+}
+
 func (idx *Indexer) ParseInterestingTransactionsAndLogs(ctx context.Context, fetchedBlock *fetcher.FetchedBlock) ([]*parser.ParsedTransaction, *IndexError) {
 	parsedTransactions := make([]*parser.ParsedTransaction, 0)
 	for i, tx := range fetchedBlock.Block.Transactions {
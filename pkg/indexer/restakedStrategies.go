@@ -28,6 +28,8 @@ func (idx *Indexer) ProcessRestakedStrategiesForBlock(ctx context.Context, block
 	if idx.Config.Chain == config.Chain_Preprod || idx.Config.Chain == config.Chain_Holesky {
 		addresses = append(addresses, config.AVSDirectoryAddresses[config.Chain_Preprod])
 		addresses = append(addresses, config.AVSDirectoryAddresses[config.Chain_Holesky])
+	} else if idx.Config.Chain == config.Chain_Custom {
+		addresses = append(addresses, idx.Config.GetAVSDirectoryForChain())
 	} else {
 		addresses = append(addresses, config.AVSDirectoryAddresses[config.Chain_Mainnet])
 	}
@@ -72,10 +72,10 @@ func (msm *MetaStateManager) HandleTransactionLog(log *storage.TransactionLog) e
 	return nil
 }
 
-func (msm *MetaStateManager) CommitFinalState(blockNumber uint64) (map[string][]interface{}, error) {
+func (msm *MetaStateManager) CommitFinalState(blockNumber uint64, tx *gorm.DB) (map[string][]interface{}, error) {
 	committedState := make(map[string][]interface{})
 	for _, model := range msm.metaStateModels {
-		state, err := model.CommitFinalState(blockNumber)
+		state, err := model.CommitFinalState(blockNumber, tx)
 		if err != nil {
 			msm.logger.Sugar().Errorw("Failed to commit final state",
 				"blockNumber", blockNumber,
@@ -88,3 +88,23 @@ func (msm *MetaStateManager) CommitFinalState(blockNumber uint64) (map[string][]
 	}
 	return committedState, nil
 }
+
+// DeleteCorruptedState deletes meta state stored that may be incomplete or corrupted
+//
+// @param startBlockNumber the block number to start deleting state from (inclusive)
+// @param endBlockNumber the block number to end deleting state from (inclusive). If 0, delete all state from startBlockNumber.
+// @param tx the database transaction the deletes are made in
+func (msm *MetaStateManager) DeleteCorruptedState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	for _, model := range msm.metaStateModels {
+		if err := model.DeleteState(startBlockNumber, endBlockNumber, tx); err != nil {
+			msm.logger.Sugar().Errorw("Failed to delete state",
+				"startBlockNumber", startBlockNumber,
+				"endBlockNumber", endBlockNumber,
+				"model", model.ModelName(),
+				"error", err,
+			)
+			return err
+		}
+	}
+	return nil
+}
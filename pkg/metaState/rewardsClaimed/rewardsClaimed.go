@@ -110,7 +110,7 @@ func (rcm *RewardsClaimedModel) HandleTransactionLog(log *storage.TransactionLog
 	return claimed, nil
 }
 
-func (rcm *RewardsClaimedModel) CommitFinalState(blockNumber uint64) ([]interface{}, error) {
+func (rcm *RewardsClaimedModel) CommitFinalState(blockNumber uint64, tx *gorm.DB) ([]interface{}, error) {
 	rowsToInsert, ok := rcm.accumulatedState[blockNumber]
 	if !ok {
 		return nil, fmt.Errorf("block number not initialized in accumulatedState %d", blockNumber)
@@ -121,7 +121,7 @@ func (rcm *RewardsClaimedModel) CommitFinalState(blockNumber uint64) ([]interfac
 		return nil, nil
 	}
 
-	res := rcm.db.Model(&types.RewardsClaimed{}).Clauses(clause.Returning{}).Create(&rowsToInsert)
+	res := tx.Model(&types.RewardsClaimed{}).Clauses(clause.Returning{}).Create(&rowsToInsert)
 	if res.Error != nil {
 		rcm.logger.Sugar().Errorw("Failed to insert rewards claimed records", zap.Error(res.Error))
 		return nil, res.Error
@@ -130,6 +130,6 @@ func (rcm *RewardsClaimedModel) CommitFinalState(blockNumber uint64) ([]interfac
 	return baseModel.CastCommittedStateToInterface(rowsToInsert), nil
 }
 
-func (rcm *RewardsClaimedModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64) error {
-	return baseModel.DeleteState(rcm.ModelName(), startBlockNumber, endBlockNumber, rcm.db, rcm.logger)
+func (rcm *RewardsClaimedModel) DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error {
+	return baseModel.DeleteState(rcm.ModelName(), startBlockNumber, endBlockNumber, tx, rcm.logger)
 }
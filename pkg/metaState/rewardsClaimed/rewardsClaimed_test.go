@@ -94,7 +94,7 @@ func Test_RewardsClaimed(t *testing.T) {
 		assert.Equal(t, log.TransactionHash, typedState.TransactionHash)
 		assert.Equal(t, log.LogIndex, typedState.LogIndex)
 
-		_, err = rewardsClaimedModel.CommitFinalState(block.Number)
+		_, err = rewardsClaimedModel.CommitFinalState(block.Number, grm)
 		assert.Nil(t, err)
 
 		// Check if the rewardsClaimed event was inserted
@@ -151,7 +151,7 @@ func Test_RewardsClaimed(t *testing.T) {
 		assert.Equal(t, log.TransactionHash, typedState.TransactionHash)
 		assert.Equal(t, log.LogIndex, typedState.LogIndex)
 
-		_, err = rewardsClaimedModel.CommitFinalState(block.Number)
+		_, err = rewardsClaimedModel.CommitFinalState(block.Number, grm)
 		assert.Nil(t, err)
 
 		// Check if the rewardsClaimed event was inserted
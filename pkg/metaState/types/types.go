@@ -1,6 +1,9 @@
 package types
 
-import "github.com/Layr-Labs/sidecar/pkg/storage"
+import (
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"gorm.io/gorm"
+)
 
 type IMetaStateModel interface {
 	ModelName() string
@@ -13,7 +16,9 @@ type IMetaStateModel interface {
 
 	HandleTransactionLog(log *storage.TransactionLog) (interface{}, error)
 
-	CommitFinalState(blockNumber uint64) ([]interface{}, error)
+	// CommitFinalState writes the accumulated state for the block using the provided transaction
+	CommitFinalState(blockNumber uint64, tx *gorm.DB) ([]interface{}, error)
 
-	DeleteState(startBlockNumber uint64, endBlockNumber uint64) error
+	// DeleteState deletes the state for the block range using the provided transaction
+	DeleteState(startBlockNumber uint64, endBlockNumber uint64, tx *gorm.DB) error
 }
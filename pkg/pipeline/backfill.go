@@ -0,0 +1,176 @@
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/fetcher"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBackfillBatchSize    = uint64(100)
+	defaultBackfillFetchWorkers = 4
+)
+
+type fetchedBatch struct {
+	startBlock uint64
+	endBlock   uint64
+	blocks     []*fetcher.FetchedBlock
+	err        error
+}
+
+// RunBackfill indexes blocks from startBlock up to the current tip using a producer/consumer model.
+//
+// A dispatcher splits the range into batches and hands each batch to one of a bounded number of fetch workers.
+// Batches are queued in block order into a bounded buffer, which a single consumer drains in order, running
+// each fetched block through RunForFetchedBlock. Fetching therefore happens ahead of, and concurrently with,
+// state processing while blocks are still applied strictly in ascending order.
+//
+// The tip is read on every batch so that the range can grow while the backfill is running. onBatchProcessed is
+// called after each batch is fully processed; returning false stops the backfill after that batch.
+//
+// Returns the next block that needs to be processed.
+func (p *Pipeline) RunBackfill(
+	ctx context.Context,
+	startBlock uint64,
+	currentTip *atomic.Uint64,
+	onBatchProcessed func(endBlock uint64) bool,
+) (uint64, error) {
+	batchSize := p.globalConfig.IndexerConfig.BackfillBatchSize
+	if batchSize == 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+	fetchWorkers := p.globalConfig.IndexerConfig.BackfillFetchWorkers
+	if fetchWorkers < 1 {
+		fetchWorkers = defaultBackfillFetchWorkers
+	}
+
+	p.Logger.Sugar().Infow("Starting backfill",
+		zap.Uint64("startBlock", startBlock),
+		zap.Uint64("currentTip", currentTip.Load()),
+		zap.Uint64("batchSize", batchSize),
+		zap.Int("fetchWorkers", fetchWorkers),
+	)
+
+	return runBackfill(ctx, startBlock, currentTip, batchSize, fetchWorkers,
+		p.Fetcher.FetchBlocksWithRetries,
+		func(ctx context.Context, block *fetcher.FetchedBlock) error {
+			return p.RunForFetchedBlock(ctx, block, true)
+		},
+		onBatchProcessed,
+		p.Logger,
+	)
+}
+
+// runBackfill runs the producer/consumer loop of RunBackfill using the given functions to fetch a batch of blocks
+// and to process a single block.
+func runBackfill(
+	ctx context.Context,
+	startBlock uint64,
+	currentTip *atomic.Uint64,
+	batchSize uint64,
+	fetchWorkers int,
+	fetchBatch func(ctx context.Context, startBlock uint64, endBlock uint64) ([]*fetcher.FetchedBlock, error),
+	processBlock func(ctx context.Context, block *fetcher.FetchedBlock) error,
+	onBatchProcessed func(endBlock uint64) bool,
+	l *zap.Logger,
+) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Limits the number of batches being fetched at once
+	workers := make(chan struct{}, fetchWorkers)
+
+	// Ordered, bounded queue of pending batches. Each entry receives exactly one result.
+	pending := make(chan chan *fetchedBatch, fetchWorkers)
+
+	go func() {
+		defer close(pending)
+
+		nextBlock := startBlock
+		for {
+			tip := currentTip.Load()
+			if nextBlock > tip {
+				return
+			}
+			endBlock := nextBlock + batchSize - 1
+			if endBlock > tip {
+				endBlock = tip
+			}
+
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			result := make(chan *fetchedBatch, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				<-workers
+				return
+			}
+
+			go func(start uint64, end uint64) {
+				defer func() { <-workers }()
+
+				fetchStart := time.Now()
+				blocks, err := fetchBatch(ctx, start, end)
+				l.Sugar().Debugw("Fetched block batch",
+					zap.Uint64("startBlock", start),
+					zap.Uint64("endBlock", end),
+					zap.Int64("fetchTime", time.Since(fetchStart).Milliseconds()),
+				)
+				result <- &fetchedBatch{
+					startBlock: start,
+					endBlock:   end,
+					blocks:     blocks,
+					err:        err,
+				}
+			}(nextBlock, endBlock)
+
+			nextBlock = endBlock + 1
+		}
+	}()
+
+	nextBlock := startBlock
+	for result := range pending {
+		batch := <-result
+		if batch.err != nil {
+			l.Sugar().Errorw("Failed to fetch block batch",
+				zap.Uint64("startBlock", batch.startBlock),
+				zap.Uint64("endBlock", batch.endBlock),
+				zap.Error(batch.err),
+			)
+			return nextBlock, batch.err
+		}
+
+		for _, block := range batch.blocks {
+			if err := ctx.Err(); err != nil {
+				return nextBlock, err
+			}
+			if err := processBlock(ctx, block); err != nil {
+				l.Sugar().Errorw("Failed to run pipeline for fetched block",
+					zap.Uint64("blockNumber", block.Block.Number.Value()),
+					zap.Error(err),
+				)
+				return nextBlock, err
+			}
+			nextBlock = block.Block.Number.Value() + 1
+		}
+		nextBlock = batch.endBlock + 1
+
+		if !onBatchProcessed(batch.endBlock) {
+			l.Sugar().Infow("Stopping backfill", zap.Uint64("lastBlockProcessed", batch.endBlock))
+			return nextBlock, nil
+		}
+	}
+	// The dispatcher also stops when the context is cancelled, which must not look like reaching the tip
+	if err := ctx.Err(); err != nil {
+		return nextBlock, err
+	}
+	return nextBlock, nil
+}
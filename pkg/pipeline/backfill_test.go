@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
+	"github.com/Layr-Labs/sidecar/pkg/fetcher"
+	"github.com/stretchr/testify/assert"
+)
+
+// fetchTestBatch returns a fetch function that builds empty blocks for the range. Later batches are fetched
+// faster than earlier ones so that they complete out of order.
+func fetchTestBatch(failAt uint64) func(ctx context.Context, startBlock uint64, endBlock uint64) ([]*fetcher.FetchedBlock, error) {
+	return func(ctx context.Context, startBlock uint64, endBlock uint64) ([]*fetcher.FetchedBlock, error) {
+		select {
+		case <-time.After(time.Duration(100-startBlock%100) * time.Millisecond / 10):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if failAt != 0 && startBlock <= failAt && failAt <= endBlock {
+			return nil, errors.New("failed to fetch batch")
+		}
+		blocks := make([]*fetcher.FetchedBlock, 0)
+		for i := startBlock; i <= endBlock; i++ {
+			blocks = append(blocks, &fetcher.FetchedBlock{
+				Block: &ethereum.EthereumBlock{Number: ethereum.EthereumQuantity(i)},
+			})
+		}
+		return blocks, nil
+	}
+}
+
+func Test_RunBackfill(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+
+	newTip := func(tip uint64) *atomic.Uint64 {
+		currentTip := &atomic.Uint64{}
+		currentTip.Store(tip)
+		return currentTip
+	}
+
+	type processedBlocks struct {
+		mu     sync.Mutex
+		blocks []uint64
+	}
+	record := func(processed *processedBlocks, failAt uint64) func(ctx context.Context, block *fetcher.FetchedBlock) error {
+		return func(ctx context.Context, block *fetcher.FetchedBlock) error {
+			if block.Block.Number.Value() == failAt {
+				return errors.New("failed to process block")
+			}
+			processed.mu.Lock()
+			defer processed.mu.Unlock()
+			processed.blocks = append(processed.blocks, block.Block.Number.Value())
+			return nil
+		}
+	}
+	keepGoing := func(endBlock uint64) bool { return true }
+
+	t.Run("Should process every block in order", func(t *testing.T) {
+		processed := &processedBlocks{}
+
+		nextBlock, err := runBackfill(context.Background(), 10, newTip(99), 7, 4, fetchTestBatch(0), record(processed, 0), keepGoing, l)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(100), nextBlock)
+
+		assert.Equal(t, 90, len(processed.blocks))
+		for i, blockNumber := range processed.blocks {
+			assert.Equal(t, uint64(10+i), blockNumber)
+		}
+	})
+	t.Run("Should return a fetch error and the first unprocessed block", func(t *testing.T) {
+		processed := &processedBlocks{}
+
+		nextBlock, err := runBackfill(context.Background(), 10, newTip(99), 10, 4, fetchTestBatch(45), record(processed, 0), keepGoing, l)
+		assert.NotNil(t, err)
+		assert.Equal(t, uint64(40), nextBlock)
+		assert.Equal(t, 30, len(processed.blocks))
+	})
+	t.Run("Should return a processing error and the block that failed", func(t *testing.T) {
+		processed := &processedBlocks{}
+
+		nextBlock, err := runBackfill(context.Background(), 10, newTip(99), 10, 4, fetchTestBatch(0), record(processed, 25), keepGoing, l)
+		assert.NotNil(t, err)
+		assert.Equal(t, uint64(25), nextBlock)
+		assert.Equal(t, uint64(24), processed.blocks[len(processed.blocks)-1])
+	})
+	t.Run("Should stop when the context is cancelled", func(t *testing.T) {
+		processed := &processedBlocks{}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		process := func(ctx context.Context, block *fetcher.FetchedBlock) error {
+			if block.Block.Number.Value() == 30 {
+				cancel()
+			}
+			return record(processed, 0)(ctx, block)
+		}
+
+		nextBlock, err := runBackfill(ctx, 10, newTip(99), 10, 4, fetchTestBatch(0), process, keepGoing, l)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, uint64(31), nextBlock)
+		assert.Equal(t, uint64(30), processed.blocks[len(processed.blocks)-1])
+	})
+	t.Run("Should stop after the batch when asked to", func(t *testing.T) {
+		processed := &processedBlocks{}
+
+		nextBlock, err := runBackfill(context.Background(), 10, newTip(99), 10, 4, fetchTestBatch(0), record(processed, 0), func(endBlock uint64) bool {
+			return endBlock < 29
+		}, l)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(30), nextBlock)
+		assert.Equal(t, 20, len(processed.blocks))
+	})
+}
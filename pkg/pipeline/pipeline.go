@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"github.com/Layr-Labs/sidecar/internal/config"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type Pipeline struct {
@@ -35,6 +37,7 @@ type Pipeline struct {
 	globalConfig      *config.Config
 	metricsSink       *metrics.MetricsSink
 	eventBus          eventBusTypes.IEventBus
+	db                *gorm.DB
 }
 
 func NewPipeline(
@@ -48,6 +51,7 @@ func NewPipeline(
 	gc *config.Config,
 	ms *metrics.MetricsSink,
 	eb eventBusTypes.IEventBus,
+	grm *gorm.DB,
 	l *zap.Logger,
 ) *Pipeline {
 	return &Pipeline{
@@ -62,6 +66,7 @@ func NewPipeline(
 		globalConfig:      gc,
 		metricsSink:       ms,
 		eventBus:          eb,
+		db:                grm,
 	}
 }
 
@@ -80,164 +85,247 @@ func (p *Pipeline) RunForFetchedBlock(ctx context.Context, block *fetcher.Fetche
 		})
 	}()
 
-	indexedBlock, found, err := p.Indexer.IndexFetchedBlock(block)
-	if err != nil {
-		p.Logger.Sugar().Errorw("Failed to index block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
-		hasError = true
-		return err
-	}
-	if found {
-		p.Logger.Sugar().Infow("Block already indexed", zap.Uint64("blockNumber", blockNumber))
+	// When following the unsafe head, make sure the block builds on top of what we have stored
+	// before writing anything for it.
+	if p.globalConfig.IndexerConfig.FollowLatestBlock {
+		if err := p.CheckParentHash(block); err != nil {
+			p.Logger.Sugar().Warnw("Parent hash check failed", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			hasError = true
+			return err
+		}
 	}
-	p.Logger.Sugar().Debugw("Indexed block",
-		zap.Uint64("blockNumber", blockNumber),
-		zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
-	)
-
-	blockFetchTime = time.Now()
 
-	// Parse all transactions and logs for the block.
-	// - If a transaction is not calling to a contract, it is ignored
-	// - If a transaction has 0 interesting logs and itself is not interesting, it is ignored
-	parsedTransactions, ierr := p.Indexer.ParseInterestingTransactionsAndLogs(ctx, block)
-	if ierr != nil {
-		p.Logger.Sugar().Errorw("Failed to parse transactions and logs",
+	// Everything written for a block (the block itself, its transactions and logs, the committed state of each
+	// model and the state root) is written in a single database transaction so that a block is either fully
+	// applied or not applied at all. Rewards roots are validated once the transaction is committed.
+	var (
+		indexedBlock           *storage.Block
+		indexedTransactions    = make([]*storage.Transaction, 0)
+		indexedTransactionLogs = make([]*storage.TransactionLog, 0)
+		committedState         map[string][]interface{}
+		sr                     *stateManager.StateRoot
+	)
+	//
+	// The in-memory state of the eigen state models is shared with on-demand rebuilds of past blocks, so it is held
+	// exclusively until the block's state has been committed and cleaned up.
+	p.stateManager.LockProcessing()
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		idx := p.Indexer.WithTransaction(tx)
+
+		var err error
+		var found bool
+		indexedBlock, found, err = idx.IndexFetchedBlock(block)
+		if err != nil {
+			p.Logger.Sugar().Errorw("Failed to index block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		if found {
+			p.Logger.Sugar().Infow("Block already indexed", zap.Uint64("blockNumber", blockNumber))
+		}
+		p.Logger.Sugar().Debugw("Indexed block",
 			zap.Uint64("blockNumber", blockNumber),
-			zap.String("transactionHash", ierr.TransactionHash),
-			zap.Error(ierr.Err),
+			zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
 		)
-		hasError = true
-		return ierr
-	}
-	p.Logger.Sugar().Debugw("Parsed transactions",
-		zap.Uint64("blockNumber", blockNumber),
-		zap.Int("count", len(parsedTransactions)),
-		zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
-	)
-
-	if err := p.stateManager.InitProcessingForBlock(blockNumber); err != nil {
-		p.Logger.Sugar().Errorw("Failed to init processing for block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
-		hasError = true
-		return err
-	}
-	if err := p.metaStateManager.InitProcessingForBlock(blockNumber); err != nil {
-		p.Logger.Sugar().Errorw("MetaStateManager: Failed to init processing for block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
-		hasError = true
-		return err
-	}
-	p.Logger.Sugar().Debugw("Initialized processing for block", zap.Uint64("blockNumber", blockNumber))
-
-	p.Logger.Sugar().Debugw("Handling parsed transactions", zap.Int("count", len(parsedTransactions)), zap.Uint64("blockNumber", blockNumber))
 
-	// With only interesting transactions/logs parsed, insert them into the database
-	indexedTransactions := make([]*storage.Transaction, 0)
-	indexedTransactionLogs := make([]*storage.TransactionLog, 0)
-	blockFetchTime = time.Now()
-	for _, pt := range parsedTransactions {
-		transactionTime := time.Now()
+		blockFetchTime = time.Now()
 
-		indexedTransaction, err := p.Indexer.IndexTransaction(indexedBlock, pt.Transaction, pt.Receipt)
-		if err != nil {
-			p.Logger.Sugar().Errorw("Failed to index transaction",
+		// Parse all transactions and logs for the block.
+		// - If a transaction is not calling to a contract, it is ignored
+		// - If a transaction has 0 interesting logs and itself is not interesting, it is ignored
+		parsedTransactions, ierr := idx.ParseInterestingTransactionsAndLogs(ctx, block)
+		if ierr != nil {
+			p.Logger.Sugar().Errorw("Failed to parse transactions and logs",
 				zap.Uint64("blockNumber", blockNumber),
-				zap.String("transactionHash", pt.Transaction.Hash.Value()),
-				zap.Error(err),
+				zap.String("transactionHash", ierr.TransactionHash),
+				zap.Error(ierr.Err),
 			)
-			hasError = true
-			return err
+			return ierr
 		}
-		indexedTransactions = append(indexedTransactions, indexedTransaction)
-
-		p.Logger.Sugar().Debugw("Indexed transaction",
+		p.Logger.Sugar().Debugw("Parsed transactions",
 			zap.Uint64("blockNumber", blockNumber),
-			zap.String("transactionHash", indexedTransaction.TransactionHash),
+			zap.Int("count", len(parsedTransactions)),
+			zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
 		)
 
-		for _, log := range pt.Logs {
-			indexedLog, err := p.Indexer.IndexLog(
-				ctx,
-				indexedBlock.Number,
-				indexedTransaction.TransactionHash,
-				indexedTransaction.TransactionIndex,
-				log,
-			)
+		if err := p.stateManager.InitProcessingForBlock(blockNumber); err != nil {
+			p.Logger.Sugar().Errorw("Failed to init processing for block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		if err := p.metaStateManager.InitProcessingForBlock(blockNumber); err != nil {
+			p.Logger.Sugar().Errorw("MetaStateManager: Failed to init processing for block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		p.Logger.Sugar().Debugw("Initialized processing for block", zap.Uint64("blockNumber", blockNumber))
+
+		p.Logger.Sugar().Debugw("Handling parsed transactions", zap.Int("count", len(parsedTransactions)), zap.Uint64("blockNumber", blockNumber))
+
+		// With only interesting transactions/logs parsed, insert them into the database
+		blockFetchTime = time.Now()
+		for _, pt := range parsedTransactions {
+			transactionTime := time.Now()
+
+			indexedTransaction, err := idx.IndexTransaction(indexedBlock, pt.Transaction, pt.Receipt)
 			if err != nil {
-				p.Logger.Sugar().Errorw("Failed to index log",
+				p.Logger.Sugar().Errorw("Failed to index transaction",
 					zap.Uint64("blockNumber", blockNumber),
 					zap.String("transactionHash", pt.Transaction.Hash.Value()),
-					zap.Uint64("logIndex", log.LogIndex),
 					zap.Error(err),
 				)
-				hasError = true
 				return err
 			}
-			indexedTransactionLogs = append(indexedTransactionLogs, indexedLog)
-			p.Logger.Sugar().Debugw("Indexed log",
+			indexedTransactions = append(indexedTransactions, indexedTransaction)
+
+			p.Logger.Sugar().Debugw("Indexed transaction",
 				zap.Uint64("blockNumber", blockNumber),
 				zap.String("transactionHash", indexedTransaction.TransactionHash),
-				zap.Uint64("logIndex", log.LogIndex),
 			)
 
-			if err := p.stateManager.HandleLogStateChange(indexedLog); err != nil {
-				p.Logger.Sugar().Errorw("Failed to handle log state change",
-					zap.Uint64("blockNumber", blockNumber),
-					zap.String("transactionHash", pt.Transaction.Hash.Value()),
-					zap.Uint64("logIndex", log.LogIndex),
-					zap.Error(err),
+			for _, log := range pt.Logs {
+				indexedLog, err := idx.IndexLog(
+					ctx,
+					indexedBlock.Number,
+					indexedTransaction.TransactionHash,
+					indexedTransaction.TransactionIndex,
+					log,
 				)
-				hasError = true
-				return err
-			}
-
-			if err := p.metaStateManager.HandleTransactionLog(indexedLog); err != nil {
-				p.Logger.Sugar().Errorw("MetaStateManager: Failed to handle log state change",
+				if err != nil {
+					p.Logger.Sugar().Errorw("Failed to index log",
+						zap.Uint64("blockNumber", blockNumber),
+						zap.String("transactionHash", pt.Transaction.Hash.Value()),
+						zap.Uint64("logIndex", log.LogIndex),
+						zap.Error(err),
+					)
+					return err
+				}
+				indexedTransactionLogs = append(indexedTransactionLogs, indexedLog)
+				p.Logger.Sugar().Debugw("Indexed log",
 					zap.Uint64("blockNumber", blockNumber),
-					zap.String("transactionHash", pt.Transaction.Hash.Value()),
+					zap.String("transactionHash", indexedTransaction.TransactionHash),
 					zap.Uint64("logIndex", log.LogIndex),
-					zap.Error(err),
 				)
-				hasError = true
-				return err
+
+				if err := p.stateManager.HandleLogStateChange(indexedLog); err != nil {
+					p.Logger.Sugar().Errorw("Failed to handle log state change",
+						zap.Uint64("blockNumber", blockNumber),
+						zap.String("transactionHash", pt.Transaction.Hash.Value()),
+						zap.Uint64("logIndex", log.LogIndex),
+						zap.Error(err),
+					)
+					return err
+				}
+
+				if err := p.metaStateManager.HandleTransactionLog(indexedLog); err != nil {
+					p.Logger.Sugar().Errorw("MetaStateManager: Failed to handle log state change",
+						zap.Uint64("blockNumber", blockNumber),
+						zap.String("transactionHash", pt.Transaction.Hash.Value()),
+						zap.Uint64("logIndex", log.LogIndex),
+						zap.Error(err),
+					)
+					return err
+				}
 			}
+			p.Logger.Sugar().Debugw("Handled log state changes",
+				zap.Uint64("blockNumber", blockNumber),
+				zap.String("transactionHash", indexedTransaction.TransactionHash),
+				zap.Duration("indexTime", time.Since(transactionTime)),
+			)
 		}
-		p.Logger.Sugar().Debugw("Handled log state changes",
+		p.Logger.Sugar().Debugw("Handled all log state changes",
 			zap.Uint64("blockNumber", blockNumber),
-			zap.String("transactionHash", indexedTransaction.TransactionHash),
-			zap.Duration("indexTime", time.Since(transactionTime)),
+			zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
 		)
-	}
-	p.Logger.Sugar().Debugw("Handled all log state changes",
-		zap.Uint64("blockNumber", blockNumber),
-		zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
-	)
 
-	if block.Block.Number.Value()%3600 == 0 {
-		p.Logger.Sugar().Infow("Indexing OperatorRestakedStrategies", zap.Uint64("blockNumber", block.Block.Number.Value()))
-		if err := p.Indexer.ProcessRestakedStrategiesForBlock(ctx, block.Block.Number.Value()); err != nil {
-			p.Logger.Sugar().Errorw("Failed to process restaked strategies", zap.Uint64("blockNumber", block.Block.Number.Value()), zap.Error(err))
-			hasError = true
+		if block.Block.Number.Value()%3600 == 0 {
+			p.Logger.Sugar().Infow("Indexing OperatorRestakedStrategies", zap.Uint64("blockNumber", block.Block.Number.Value()))
+			if err := idx.ProcessRestakedStrategiesForBlock(ctx, block.Block.Number.Value()); err != nil {
+				p.Logger.Sugar().Errorw("Failed to process restaked strategies", zap.Uint64("blockNumber", block.Block.Number.Value()), zap.Error(err))
+				return err
+			}
+		}
+
+		blockFetchTime = time.Now()
+		committedState, err = p.stateManager.CommitFinalState(blockNumber, tx)
+		if err != nil {
+			p.Logger.Sugar().Errorw("Failed to commit final state", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
 			return err
 		}
-	}
+		_, err = p.metaStateManager.CommitFinalState(blockNumber, tx)
+		if err != nil {
+			p.Logger.Sugar().Errorw("MetaStateManager: Failed to commit final state", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		p.Logger.Sugar().Debugw("Committed final state", zap.Uint64("blockNumber", blockNumber), zap.Duration("indexTime", time.Since(blockFetchTime)))
+
+		blockFetchTime = time.Now()
+		stateRoot, modelRoots, err := p.stateManager.GenerateStateRootWithModelRoots(blockNumber, block.Block.Hash.Value())
+		if err != nil {
+			p.Logger.Sugar().Errorw("Failed to generate state root", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		p.Logger.Sugar().Debugw("Generated state root", zap.Duration("indexTime", time.Since(blockFetchTime)))
+
+		blockFetchTime = time.Now()
+		sr, err = p.stateManager.WriteStateRoot(blockNumber, block.Block.Hash.Value(), stateRoot, tx)
+		if err != nil {
+			p.Logger.Sugar().Errorw("Failed to write state root", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		p.Logger.Sugar().Debugw("Wrote state root", zap.Uint64("blockNumber", blockNumber), zap.Any("stateRoot", sr))
 
-	blockFetchTime = time.Now()
-	committedState, err := p.stateManager.CommitFinalState(blockNumber)
+		if _, err = p.stateManager.WriteModelRoots(blockNumber, block.Block.Hash.Value(), modelRoots, tx); err != nil {
+			p.Logger.Sugar().Errorw("Failed to write model state roots", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	_ = p.stateManager.CleanupProcessedStateForBlock(blockNumber)
+	p.stateManager.UnlockProcessing()
 	if err != nil {
-		p.Logger.Sugar().Errorw("Failed to commit final state", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
 		hasError = true
 		return err
 	}
-	_, err = p.metaStateManager.CommitFinalState(blockNumber)
+
+	// Validating rewards roots can mean waiting on a full rewards calculation, so it happens after the block
+	// is committed rather than holding its transaction open.
+	calculatedRewards, err = p.validateRewardsRootsForBlock(ctx, blockNumber)
 	if err != nil {
-		p.Logger.Sugar().Errorw("MetaStateManager: Failed to commit final state", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
 		hasError = true
+		// Roll the block back so that its rewards roots are validated again when it is re-processed
+		if rerr := p.RollbackToBlock(blockNumber - 1); rerr != nil {
+			p.Logger.Sugar().Errorw("Failed to roll back block after rewards validation failed",
+				zap.Uint64("blockNumber", blockNumber),
+				zap.Error(rerr),
+			)
+		}
 		return err
 	}
-	p.Logger.Sugar().Debugw("Committed final state", zap.Uint64("blockNumber", blockNumber), zap.Duration("indexTime", time.Since(blockFetchTime)))
+
+	p.Logger.Sugar().Debugw("Finished processing block",
+		zap.Uint64("blockNumber", blockNumber),
+		zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
+		zap.Int64("totalTime", time.Since(totalRunTime).Milliseconds()),
+	)
+	_ = p.metricsSink.Incr(metricsTypes.Metric_Incr_BlockProcessed, nil, 1)
+	_ = p.metricsSink.Gauge(metricsTypes.Metric_Gauge_CurrentBlockHeight, float64(blockNumber), nil)
+	go p.HandleBlockProcessedHook(indexedBlock, indexedTransactions, indexedTransactionLogs, sr, committedState)
+
+	// Push cleanup to the background since it doesnt need to be blocking
+	go func() {
+		_ = p.metaStateManager.CleanupProcessedStateForBlock(blockNumber)
+	}()
+	return nil
+}
+
+// validateRewardsRootsForBlock recalculates the rewards for every distribution root submitted in the block and
+// compares the merkle root of the calculated rewards with the submitted root.
+//
+// Returns true if rewards were calculated.
+func (p *Pipeline) validateRewardsRootsForBlock(ctx context.Context, blockNumber uint64) (bool, error) {
+	calculatedRewards := false
 
 	p.Logger.Sugar().Debugw("Checking for rewards to validate", zap.Uint64("blockNumber", blockNumber))
 
-	distributionRoots, err := p.stateManager.GetSubmittedDistributionRoots(blockNumber)
+	distributionRoots, err := p.stateManager.GetSubmittedDistributionRoots(blockNumber, p.db)
 	if err == nil && distributionRoots != nil {
 		for _, rs := range distributionRoots {
 
@@ -252,8 +340,7 @@ func (p *Pipeline) RunForFetchedBlock(ctx context.Context, block *fetcher.Fetche
 					zap.Uint64("rootIndex", rs.RootIndex),
 					zap.Error(err),
 				)
-				hasError = true
-				return err
+				return calculatedRewards, err
 			}
 			if rewardsRoot.Disabled {
 				p.Logger.Sugar().Warnw("Root is disabled, skipping rewards validation",
@@ -300,8 +387,7 @@ func (p *Pipeline) RunForFetchedBlock(ctx context.Context, block *fetcher.Fetche
 					zap.Uint64("blockNumber", blockNumber),
 					zap.Any("distributionRoot", rs),
 				)
-				hasError = true
-				return err
+				return calculatedRewards, err
 			}
 
 			p.Logger.Sugar().Infow("Merkelizing rewards for snapshot date",
@@ -314,8 +400,7 @@ func (p *Pipeline) RunForFetchedBlock(ctx context.Context, block *fetcher.Fetche
 					zap.String("cutoffDate", cutoffDate), zap.Error(err),
 					zap.Uint64("blockNumber", blockNumber),
 				)
-				hasError = true
-				return err
+				return calculatedRewards, err
 			}
 			root := utils.ConvertBytesToString(accountTree.Root())
 
@@ -333,8 +418,7 @@ func (p *Pipeline) RunForFetchedBlock(ctx context.Context, block *fetcher.Fetche
 						zap.String("computedRoot", root),
 						zap.Int64("rewardsTotalTimeMs", rewardsTotalTimeMs),
 					)
-					hasError = true
-					return errors.New("roots do not match")
+					return calculatedRewards, errors.New("roots do not match")
 				}
 				p.Logger.Sugar().Warnw("Roots do not match, but allowed to ignore",
 					zap.String("cutoffDate", cutoffDate),
@@ -348,40 +432,7 @@ func (p *Pipeline) RunForFetchedBlock(ctx context.Context, block *fetcher.Fetche
 			}
 		}
 	}
-
-	blockFetchTime = time.Now()
-	stateRoot, err := p.stateManager.GenerateStateRoot(blockNumber, block.Block.Hash.Value())
-	if err != nil {
-		p.Logger.Sugar().Errorw("Failed to generate state root", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
-		hasError = true
-		return err
-	}
-	p.Logger.Sugar().Debugw("Generated state root", zap.Duration("indexTime", time.Since(blockFetchTime)))
-
-	blockFetchTime = time.Now()
-	sr, err := p.stateManager.WriteStateRoot(blockNumber, block.Block.Hash.Value(), stateRoot)
-	if err != nil {
-		p.Logger.Sugar().Errorw("Failed to write state root", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
-		hasError = true
-		return err
-	} else {
-		p.Logger.Sugar().Debugw("Wrote state root", zap.Uint64("blockNumber", blockNumber), zap.Any("stateRoot", sr))
-	}
-	p.Logger.Sugar().Debugw("Finished processing block",
-		zap.Uint64("blockNumber", blockNumber),
-		zap.Int64("indexTime", time.Since(blockFetchTime).Milliseconds()),
-		zap.Int64("totalTime", time.Since(totalRunTime).Milliseconds()),
-	)
-	_ = p.metricsSink.Incr(metricsTypes.Metric_Incr_BlockProcessed, nil, 1)
-	_ = p.metricsSink.Gauge(metricsTypes.Metric_Gauge_CurrentBlockHeight, float64(blockNumber), nil)
-	go p.HandleBlockProcessedHook(indexedBlock, indexedTransactions, indexedTransactionLogs, sr, committedState)
-
-	// Push cleanup to the background since it doesnt need to be blocking
-	go func() {
-		_ = p.stateManager.CleanupProcessedStateForBlock(blockNumber)
-		_ = p.metaStateManager.CleanupProcessedStateForBlock(blockNumber)
-	}()
-	return nil
+	return calculatedRewards, nil
 }
 
 func (p *Pipeline) RunForBlock(ctx context.Context, blockNumber uint64, isBackfill bool) error {
@@ -415,7 +466,7 @@ func (p *Pipeline) RunForBlockBatch(ctx context.Context, startBlock uint64, endB
 
 	// sort blocks ascending
 	slices.SortFunc(fetchedBlocks, func(b1, b2 *fetcher.FetchedBlock) int {
-		return int(b1.Block.Number.Value() - b2.Block.Number.Value())
+		return cmp.Compare(b1.Block.Number.Value(), b2.Block.Number.Value())
 	})
 
 	for _, block := range fetchedBlocks {
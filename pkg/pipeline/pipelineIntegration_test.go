@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/Layr-Labs/sidecar/pkg/metaState"
 	"log"
@@ -110,7 +111,7 @@ func setup(ethConfig *ethereum.EthereumClientConfig) (
 	sog := stakerOperators.NewStakerOperatorGenerator(grm, l, cfg)
 	rc, _ := rewards.NewRewardsCalculator(cfg, grm, mds, sog, sdc, l)
 
-	rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, l)
+	rcq := rewardsCalculatorQueue.NewRewardsCalculatorQueue(rc, grm, l)
 
 	fetchr := fetcher.NewFetcher(client, cfg, l)
 
@@ -131,7 +132,7 @@ func Test_PipelineIntegration(t *testing.T) {
 		fetchr, idxr, mds, sm, msm, rc, rcq, cfg, l, sdc, grm, eb, dbName := setup(ethConfig)
 		blockNumber := uint64(20386320)
 
-		p := NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, l)
+		p := NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, grm, l)
 
 		err := p.RunForBlockBatch(context.Background(), blockNumber, blockNumber+1, true)
 		assert.Nil(t, err)
@@ -160,7 +161,7 @@ func Test_PipelineIntegration(t *testing.T) {
 		fetchr, idxr, mds, sm, msm, rc, rcq, cfg, l, sdc, grm, eb, dbName := setup(ethConfig)
 		blockNumber := uint64(20386320)
 
-		p := NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, l)
+		p := NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, grm, l)
 
 		err := p.RunForBlockBatch(context.Background(), blockNumber, blockNumber+1, true)
 		assert.Nil(t, err)
@@ -180,6 +181,82 @@ func Test_PipelineIntegration(t *testing.T) {
 		assert.Equal(t, uint64(128), avsOperatorChanges[0].LogIndex)
 		assert.Equal(t, blockNumber, avsOperatorChanges[0].BlockNumber)
 
+		t.Cleanup(func() {
+			postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+		})
+	})
+	t.Run("Should index a block, transaction with logs using the logs fetch strategy", func(t *testing.T) {
+		ethConfig := ethereum.DefaultNativeCallEthereumClientConfig()
+		fetchr, idxr, mds, sm, msm, rc, rcq, cfg, l, sdc, grm, eb, dbName := setup(ethConfig)
+		cfg.EthereumRpcConfig.FetchStrategy = config.FetchStrategy_Logs
+		blockNumber := uint64(20386320)
+
+		p := NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, grm, l)
+
+		err := p.RunForBlockBatch(context.Background(), blockNumber, blockNumber+1, true)
+		assert.Nil(t, err)
+
+		query := `select * from avs_operator_state_changes where block_number = @blockNumber`
+		avsOperatorChanges := make([]avsOperators.AvsOperatorStateChange, 0)
+		res := grm.Raw(query, sql.Named("blockNumber", blockNumber)).Scan(&avsOperatorChanges)
+		assert.Nil(t, res.Error)
+
+		assert.Equal(t, 1, len(avsOperatorChanges))
+		assert.Equal(t, "0xf6ad76de4c80c056a51fcb457942df40a6d99f76", avsOperatorChanges[0].Operator)
+		assert.Equal(t, "0xe7d0894ac9266f5cbe8f8e750ac6cbe128fbbeb7", avsOperatorChanges[0].Avs)
+		assert.Equal(t, uint64(128), avsOperatorChanges[0].LogIndex)
+		assert.Equal(t, blockNumber, avsOperatorChanges[0].BlockNumber)
+
+		t.Cleanup(func() {
+			postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+		})
+	})
+	t.Run("Should detect a reorg and roll back blocks above the common ancestor", func(t *testing.T) {
+		ethConfig := ethereum.DefaultNativeCallEthereumClientConfig()
+		fetchr, idxr, mds, sm, msm, rc, rcq, cfg, l, sdc, grm, eb, dbName := setup(ethConfig)
+		blockNumber := uint64(20386320)
+
+		p := NewPipeline(fetchr, idxr, mds, sm, msm, rc, rcq, cfg, sdc, eb, grm, l)
+
+		_, err := mds.InsertBlockAtHeight(blockNumber-1, "0xstale", "0xstaleparent", 1721912351)
+		assert.Nil(t, err)
+		_, err = mds.InsertBlockAtHeight(blockNumber, "0xstalechild", "0xstale", 1721912363)
+		assert.Nil(t, err)
+		_, err = sm.WriteStateRoot(blockNumber, "0xstalechild", "0xstaleroot", grm)
+		assert.Nil(t, err)
+
+		block := &fetcher.FetchedBlock{
+			Block: &ethereum.EthereumBlock{
+				Number:     ethereum.EthereumQuantity(blockNumber + 1),
+				ParentHash: ethereum.EthereumHexString("0xcanonical"),
+			},
+		}
+		err = p.CheckParentHash(block)
+		assert.NotNil(t, err)
+
+		reorgErr, ok := err.(*ErrReorgDetected)
+		assert.True(t, ok)
+		assert.Equal(t, blockNumber, reorgErr.StoredParentNumber)
+		assert.Equal(t, "0xstalechild", reorgErr.StoredParentHash)
+
+		err = p.RollbackToBlock(blockNumber - 1)
+		assert.Nil(t, err)
+
+		storedBlock, err := mds.GetBlockByNumber(blockNumber)
+		assert.Nil(t, err)
+		assert.Nil(t, storedBlock)
+
+		_, err = sm.GetStateRootForBlock(blockNumber)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		storedBlock, err = mds.GetBlockByNumber(blockNumber - 1)
+		assert.Nil(t, err)
+		assert.NotNil(t, storedBlock)
+
+		block.Block.ParentHash = ethereum.EthereumHexString("0xstale")
+		block.Block.Number = ethereum.EthereumQuantity(blockNumber)
+		assert.Nil(t, p.CheckParentHash(block))
+
 		t.Cleanup(func() {
 			postgres.TeardownTestDatabase(dbName, cfg, grm, l)
 		})
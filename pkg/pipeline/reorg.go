@@ -0,0 +1,169 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/internal/metrics/metricsTypes"
+	"github.com/Layr-Labs/sidecar/pkg/fetcher"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrReorgDetected is returned when the parent hash of a fetched block does not match the hash
+// of the block stored at the previous height.
+type ErrReorgDetected struct {
+	BlockNumber        uint64
+	ParentHash         string
+	StoredParentHash   string
+	StoredParentNumber uint64
+}
+
+func (e *ErrReorgDetected) Error() string {
+	return fmt.Sprintf("reorg detected at block %d: parent hash '%s' does not match stored hash '%s' for block %d",
+		e.BlockNumber, e.ParentHash, e.StoredParentHash, e.StoredParentNumber)
+}
+
+// CheckParentHash compares the parent hash of the fetched block with the hash of the block stored
+// at height N-1. If there is no stored block at N-1 (e.g. the first block after genesis), the check is skipped.
+func (p *Pipeline) CheckParentHash(block *fetcher.FetchedBlock) error {
+	blockNumber := block.Block.Number.Value()
+	if blockNumber == 0 {
+		return nil
+	}
+
+	parentBlock, err := p.BlockStore.GetBlockByNumber(blockNumber - 1)
+	if err != nil {
+		p.Logger.Sugar().Errorw("Failed to get parent block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+		return err
+	}
+	if parentBlock == nil {
+		return nil
+	}
+
+	parentHash := block.Block.ParentHash.Value()
+	if !strings.EqualFold(parentHash, parentBlock.Hash) {
+		return &ErrReorgDetected{
+			BlockNumber:        blockNumber,
+			ParentHash:         parentHash,
+			StoredParentHash:   parentBlock.Hash,
+			StoredParentNumber: parentBlock.Number,
+		}
+	}
+	return nil
+}
+
+// FindCommonAncestor walks back from the given block number until it finds a block whose stored hash
+// matches the canonical hash reported by the Ethereum node.
+//
+// @param fromBlock the block number to start walking back from (inclusive)
+func (p *Pipeline) FindCommonAncestor(ctx context.Context, fromBlock uint64) (uint64, error) {
+	maxDepth := p.globalConfig.IndexerConfig.MaxReorgDepth
+
+	for depth := uint64(0); maxDepth == 0 || depth < maxDepth; depth++ {
+		if depth > fromBlock {
+			break
+		}
+		blockNumber := fromBlock - depth
+
+		storedBlock, err := p.BlockStore.GetBlockByNumber(blockNumber)
+		if err != nil {
+			return 0, err
+		}
+		if storedBlock == nil {
+			// nothing stored at or below this height, so there is nothing left to compare against
+			return blockNumber, nil
+		}
+
+		canonicalBlock, err := p.Fetcher.EthClient.GetBlockByNumber(ctx, blockNumber)
+		if err != nil {
+			p.Logger.Sugar().Errorw("Failed to fetch canonical block", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+			return 0, err
+		}
+
+		if strings.EqualFold(canonicalBlock.Hash.Value(), storedBlock.Hash) {
+			p.Logger.Sugar().Infow("Found common ancestor",
+				zap.Uint64("blockNumber", blockNumber),
+				zap.String("blockHash", storedBlock.Hash),
+				zap.Uint64("depth", depth),
+			)
+			return blockNumber, nil
+		}
+		p.Logger.Sugar().Infow("Block is not on the canonical chain",
+			zap.Uint64("blockNumber", blockNumber),
+			zap.String("storedHash", storedBlock.Hash),
+			zap.String("canonicalHash", canonicalBlock.Hash.Value()),
+		)
+	}
+	return 0, fmt.Errorf("failed to find common ancestor within %d blocks of block %d", maxDepth, fromBlock)
+}
+
+// RollbackToBlock removes everything that was indexed after the given block number so that the
+// canonical branch can be re-indexed on top of it.
+//
+// All deletes are made in a single database transaction so that a failure part way through leaves the
+// stored state untouched.
+//
+// @param blockNumber the last block to keep (exclusive of the rollback)
+func (p *Pipeline) RollbackToBlock(blockNumber uint64) error {
+	startBlock := blockNumber + 1
+
+	p.Logger.Sugar().Warnw("Rolling back state", zap.Uint64("startBlock", startBlock))
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := p.stateManager.DeleteCorruptedState(startBlock, 0, tx); err != nil {
+			p.Logger.Sugar().Errorw("Failed to delete eigen state", zap.Uint64("startBlock", startBlock), zap.Error(err))
+			return err
+		}
+		if err := p.metaStateManager.DeleteCorruptedState(startBlock, 0, tx); err != nil {
+			p.Logger.Sugar().Errorw("Failed to delete meta state", zap.Uint64("startBlock", startBlock), zap.Error(err))
+			return err
+		}
+		// Rewards are found using the blocks and logs being rolled back, so they need to be deleted first
+		if err := p.rewardsCalculator.DeleteCorruptedRewardsFromBlockHeight(startBlock, tx); err != nil {
+			p.Logger.Sugar().Errorw("Failed to delete rewards", zap.Uint64("startBlock", startBlock), zap.Error(err))
+			return err
+		}
+		if err := p.stateManager.DeleteStateRoots(startBlock, 0, tx); err != nil {
+			p.Logger.Sugar().Errorw("Failed to delete state roots", zap.Uint64("startBlock", startBlock), zap.Error(err))
+			return err
+		}
+		if err := p.BlockStore.WithTransaction(tx).DeleteCorruptedState(startBlock, 0); err != nil {
+			p.Logger.Sugar().Errorw("Failed to delete blocks", zap.Uint64("startBlock", startBlock), zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.Logger.Sugar().Infow("Rolled back state", zap.Uint64("lastBlock", blockNumber))
+	return nil
+}
+
+// HandleReorg finds the common ancestor for a detected reorg and rolls back all state above it.
+// It returns the block number of the common ancestor.
+func (p *Pipeline) HandleReorg(ctx context.Context, reorg *ErrReorgDetected) (uint64, error) {
+	p.Logger.Sugar().Warnw("Handling chain reorg",
+		zap.Uint64("blockNumber", reorg.BlockNumber),
+		zap.String("parentHash", reorg.ParentHash),
+		zap.String("storedParentHash", reorg.StoredParentHash),
+	)
+	ancestor, err := p.FindCommonAncestor(ctx, reorg.StoredParentNumber)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.RollbackToBlock(ancestor); err != nil {
+		return 0, err
+	}
+	_ = p.metricsSink.Incr(metricsTypes.Metric_Incr_ChainReorg, nil, 1)
+
+	// Make sure the orphaned blocks aren't served from the block cache when re-indexing
+	if err := p.Fetcher.InvalidateCachedBlocks(ancestor+1, reorg.BlockNumber); err != nil {
+		p.Logger.Sugar().Errorw("Failed to invalidate cached blocks", zap.Uint64("startBlock", ancestor+1), zap.Error(err))
+		return 0, err
+	}
+	return ancestor, nil
+}
@@ -0,0 +1,35 @@
+package _202503031000_modelStateRoots
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists model_state_roots (
+			eth_block_number bigint not null,
+			eth_block_hash varchar not null,
+			model_name varchar not null,
+			root varchar not null,
+			created_at timestamp with time zone default current_timestamp,
+			unique(eth_block_number, model_name),
+			CONSTRAINT model_state_roots_block_number_fkey FOREIGN KEY (eth_block_number) REFERENCES blocks(number) ON DELETE CASCADE
+		)`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503031000_modelStateRoots"
+}
@@ -0,0 +1,43 @@
+package _202503041000_rewardsCalculationJobs
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists rewards_calculation_jobs (
+			id bigserial primary key,
+			calculation_type varchar not null,
+			cutoff_date varchar not null default '',
+			status varchar not null,
+			stage varchar not null default '',
+			completed_steps integer not null default 0,
+			total_steps integer not null default 0,
+			result_cutoff_date varchar not null default '',
+			error text not null default '',
+			cancel_requested boolean not null default false,
+			created_at timestamp with time zone default current_timestamp,
+			updated_at timestamp with time zone default current_timestamp,
+			started_at timestamp with time zone default null,
+			finished_at timestamp with time zone default null
+		)`,
+		`create index if not exists idx_rewards_calculation_jobs_status on rewards_calculation_jobs (status)`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503041000_rewardsCalculationJobs"
+}
@@ -0,0 +1,32 @@
+package _202503051000_rewardsSnapshotTableCutoffs
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists rewards_snapshot_table_cutoffs (
+			id bigserial primary key,
+			cutoff_date varchar not null,
+			start_date varchar not null default '',
+			created_at timestamp with time zone default current_timestamp
+		)`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503051000_rewardsSnapshotTableCutoffs"
+}
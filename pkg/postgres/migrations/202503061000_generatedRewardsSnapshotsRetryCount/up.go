@@ -0,0 +1,23 @@
+package _202503061000_generatedRewardsSnapshotsRetryCount
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	query := `alter table generated_rewards_snapshots add column if not exists retry_count integer not null default 0`
+	if res := grm.Exec(query); res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503061000_generatedRewardsSnapshotsRetryCount"
+}
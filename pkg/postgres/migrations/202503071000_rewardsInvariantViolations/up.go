@@ -0,0 +1,35 @@
+package _202503071000_rewardsInvariantViolations
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists rewards_invariant_violations (
+			id bigserial primary key,
+			generated_rewards_snapshot_id integer not null references generated_rewards_snapshots(id) on delete cascade,
+			invariant varchar not null,
+			violation_count bigint not null,
+			examples text not null default '',
+			created_at timestamp with time zone default current_timestamp
+		)`,
+		`create index if not exists idx_rewards_invariant_violations_snapshot on rewards_invariant_violations (generated_rewards_snapshot_id)`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503071000_rewardsInvariantViolations"
+}
@@ -0,0 +1,35 @@
+package _202503081000_rewardsProofDistributions
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists rewards_proof_distributions (
+			id bigserial primary key,
+			snapshot_date varchar not null,
+			generated_rewards_snapshot_id integer not null references generated_rewards_snapshots(id) on delete cascade,
+			account_root varchar not null,
+			distribution bytea not null,
+			created_at timestamp with time zone default current_timestamp,
+			unique (snapshot_date, generated_rewards_snapshot_id)
+		)`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503081000_rewardsProofDistributions"
+}
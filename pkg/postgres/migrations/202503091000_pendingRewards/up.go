@@ -0,0 +1,35 @@
+package _202503091000_pendingRewards
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists pending_rewards (
+			earner varchar not null,
+			token varchar not null,
+			amount numeric not null,
+			snapshot_date varchar not null,
+			generated_rewards_snapshot_id bigint not null,
+			calculated_at timestamp with time zone not null
+		)`,
+		`create index if not exists idx_pending_rewards_earner_token on pending_rewards (earner, token)`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503091000_pendingRewards"
+}
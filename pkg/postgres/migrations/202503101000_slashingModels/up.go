@@ -0,0 +1,99 @@
+package _202503101000_slashingModels
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists operator_sets (
+			avs varchar not null,
+			operator_set_id bigint not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number),
+			constraint operator_sets_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create index if not exists idx_operator_sets_avs on operator_sets (avs, operator_set_id)`,
+		`create table if not exists operator_set_operator_registrations (
+			operator varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			is_active boolean not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number),
+			constraint operator_set_operator_registrations_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create index if not exists idx_operator_set_operator_registrations_operator on operator_set_operator_registrations (operator, block_number)`,
+		`create table if not exists operator_set_strategy_registrations (
+			strategy varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			is_active boolean not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number),
+			constraint operator_set_strategy_registrations_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create index if not exists idx_operator_set_strategy_registrations_avs on operator_set_strategy_registrations (avs, operator_set_id, block_number)`,
+		`create table if not exists operator_allocations (
+			operator varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			strategy varchar not null,
+			magnitude bigint not null,
+			effect_block bigint not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number),
+			constraint operator_allocations_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create index if not exists idx_operator_allocations_operator on operator_allocations (operator, block_number)`,
+		`create table if not exists operator_max_magnitudes (
+			operator varchar not null,
+			strategy varchar not null,
+			max_magnitude bigint not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number),
+			constraint operator_max_magnitudes_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create index if not exists idx_operator_max_magnitudes_operator on operator_max_magnitudes (operator, strategy, block_number)`,
+		`create table if not exists slashed_operators (
+			operator varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			strategy varchar not null,
+			wad_slashed numeric not null,
+			description varchar not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number, strategy),
+			constraint slashed_operators_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create index if not exists idx_slashed_operators_operator on slashed_operators (operator, block_number)`,
+	}
+	for _, query := range queries {
+		if err := grm.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503101000_slashingModels"
+}
@@ -0,0 +1,81 @@
+package _202503111000_operatorSetRewards
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		`create table if not exists operator_directed_operator_set_reward_submissions (
+			avs varchar not null,
+			operator_set_id bigint not null,
+			reward_hash varchar not null,
+			token varchar not null,
+			operator varchar not null,
+			operator_index integer not null,
+			amount numeric not null,
+			strategy varchar not null,
+			strategy_index integer not null,
+			multiplier numeric(78) not null,
+			start_timestamp timestamp(6) not null,
+			end_timestamp timestamp(6) not null,
+			duration bigint not null,
+			description varchar,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number, reward_hash, strategy_index, operator_index),
+			constraint operator_directed_operator_set_reward_submissions_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create table if not exists operator_set_splits (
+			operator varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			activated_at timestamp(6) not null,
+			old_operator_set_split_bips integer not null,
+			new_operator_set_split_bips integer not null,
+			block_number bigint not null,
+			transaction_hash varchar not null,
+			log_index bigint not null,
+			unique(transaction_hash, log_index, block_number),
+			constraint operator_set_splits_block_number_fkey foreign key (block_number) references blocks(number) on delete cascade
+		)`,
+		`create table if not exists operator_set_operator_registration_snapshots (
+			operator varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			snapshot date not null
+		)`,
+		`create index if not exists idx_operator_set_operator_registration_snapshots_avs_snapshot on operator_set_operator_registration_snapshots (avs, operator_set_id, snapshot)`,
+		`create table if not exists operator_set_strategy_registration_snapshots (
+			strategy varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			snapshot date not null
+		)`,
+		`create index if not exists idx_operator_set_strategy_registration_snapshots_avs_snapshot on operator_set_strategy_registration_snapshots (avs, operator_set_id, snapshot)`,
+		`create table if not exists operator_set_split_snapshots (
+			operator varchar not null,
+			avs varchar not null,
+			operator_set_id bigint not null,
+			split integer not null,
+			snapshot date not null
+		)`,
+	}
+	for _, query := range queries {
+		if err := grm.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503111000_operatorSetRewards"
+}
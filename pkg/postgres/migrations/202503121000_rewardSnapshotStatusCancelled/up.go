@@ -0,0 +1,23 @@
+package _202503121000_rewardSnapshotStatusCancelled
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	query := `alter type reward_snapshot_status add value if not exists 'cancelled'`
+	if res := grm.Exec(query); res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503121000_rewardSnapshotStatusCancelled"
+}
@@ -0,0 +1,23 @@
+package _202503131000_generatedRewardsSnapshotsRetryable
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	query := `alter table generated_rewards_snapshots add column if not exists retryable boolean not null default true`
+	if res := grm.Exec(query); res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503131000_generatedRewardsSnapshotsRetryable"
+}
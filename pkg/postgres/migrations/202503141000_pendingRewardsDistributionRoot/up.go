@@ -0,0 +1,29 @@
+package _202503141000_pendingRewardsDistributionRoot
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+}
+
+func (m *Migration) Up(db *sql.DB, grm *gorm.DB, cfg *config.Config) error {
+	queries := []string{
+		// estimates made before this column existed were not based on a distribution root
+		`delete from pending_rewards`,
+		`alter table pending_rewards add column if not exists distribution_root_index bigint`,
+	}
+	for _, query := range queries {
+		if res := grm.Exec(query); res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (m *Migration) GetName() string {
+	return "202503141000_pendingRewardsDistributionRoot"
+}
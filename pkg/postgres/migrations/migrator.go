@@ -6,9 +6,22 @@ import (
 	_202501241111_addIndexesForRpcFunctions "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202501241111_addIndexesForRpcFunctions"
 	_202502100846_goldTableRewardHashIndex "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202502100846_goldTableRewardHashIndex"
 	_202502211539_hydrateClaimedRewards "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202502211539_hydrateClaimedRewards"
+	_202503031000_modelStateRoots "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503031000_modelStateRoots"
+	_202503041000_rewardsCalculationJobs "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503041000_rewardsCalculationJobs"
+	_202503051000_rewardsSnapshotTableCutoffs "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503051000_rewardsSnapshotTableCutoffs"
+	_202503061000_generatedRewardsSnapshotsRetryCount "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503061000_generatedRewardsSnapshotsRetryCount"
+	_202503071000_rewardsInvariantViolations "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503071000_rewardsInvariantViolations"
+	_202503081000_rewardsProofDistributions "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503081000_rewardsProofDistributions"
+	_202503091000_pendingRewards "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503091000_pendingRewards"
+	_202503101000_slashingModels "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503101000_slashingModels"
+	_202503111000_operatorSetRewards "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503111000_operatorSetRewards"
+	_202503121000_rewardSnapshotStatusCancelled "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503121000_rewardSnapshotStatusCancelled"
+	_202503131000_generatedRewardsSnapshotsRetryable "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503131000_generatedRewardsSnapshotsRetryable"
+	_202503141000_pendingRewardsDistributionRoot "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202503141000_pendingRewardsDistributionRoot"
 	"time"
 
 	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/plugins"
 	_202409061249_bootstrapDb "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202409061249_bootstrapDb"
 	_202409061250_eigenlayerStateTables "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202409061250_eigenlayerStateTables"
 	_202409061720_operatorShareChanges "github.com/Layr-Labs/sidecar/pkg/postgres/migrations/202409061720_operatorShareChanges"
@@ -140,6 +153,18 @@ func (m *Migrator) MigrateAll() error {
 		&_202501241111_addIndexesForRpcFunctions.Migration{},
 		&_202502100846_goldTableRewardHashIndex.Migration{},
 		&_202502211539_hydrateClaimedRewards.Migration{},
+		&_202503031000_modelStateRoots.Migration{},
+		&_202503041000_rewardsCalculationJobs.Migration{},
+		&_202503051000_rewardsSnapshotTableCutoffs.Migration{},
+		&_202503061000_generatedRewardsSnapshotsRetryCount.Migration{},
+		&_202503071000_rewardsInvariantViolations.Migration{},
+		&_202503081000_rewardsProofDistributions.Migration{},
+		&_202503091000_pendingRewards.Migration{},
+		&_202503101000_slashingModels.Migration{},
+		&_202503111000_operatorSetRewards.Migration{},
+		&_202503121000_rewardSnapshotStatusCancelled.Migration{},
+		&_202503131000_generatedRewardsSnapshotsRetryable.Migration{},
+		&_202503141000_pendingRewardsDistributionRoot.Migration{},
 	}
 
 	for _, migration := range migrations {
@@ -148,6 +173,18 @@ func (m *Migrator) MigrateAll() error {
 			panic(err)
 		}
 	}
+
+	// plugin migrations always run after the sidecar's own migrations
+	pluginMigrations, err := plugins.GetMigrations()
+	if err != nil {
+		return err
+	}
+	for _, migration := range pluginMigrations {
+		err := m.Migrate(migration)
+		if err != nil {
+			panic(err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,78 @@
+package proofs
+
+import (
+	"strings"
+	"sync"
+
+	rewardsCoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// The bindings the project depends on predate processClaims, so the ABI of both claim methods is declared here.
+// Both take the same RewardsMerkleClaim struct as the bindings.
+const rewardsMerkleClaimAbiComponents = `[
+	{"name": "rootIndex", "type": "uint32"},
+	{"name": "earnerIndex", "type": "uint32"},
+	{"name": "earnerTreeProof", "type": "bytes"},
+	{"name": "earnerLeaf", "type": "tuple", "components": [
+		{"name": "earner", "type": "address"},
+		{"name": "earnerTokenRoot", "type": "bytes32"}
+	]},
+	{"name": "tokenIndices", "type": "uint32[]"},
+	{"name": "tokenTreeProofs", "type": "bytes[]"},
+	{"name": "tokenLeaves", "type": "tuple[]", "components": [
+		{"name": "token", "type": "address"},
+		{"name": "cumulativeEarnings", "type": "uint256"}
+	]}
+]`
+
+const processClaimsAbi = `[
+	{
+		"type": "function",
+		"name": "processClaim",
+		"inputs": [
+			{"name": "claim", "type": "tuple", "components": ` + rewardsMerkleClaimAbiComponents + `},
+			{"name": "recipient", "type": "address"}
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	},
+	{
+		"type": "function",
+		"name": "processClaims",
+		"inputs": [
+			{"name": "claims", "type": "tuple[]", "components": ` + rewardsMerkleClaimAbiComponents + `},
+			{"name": "recipient", "type": "address"}
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	}
+]`
+
+var getProcessClaimsAbi = sync.OnceValues(func() (abi.ABI, error) {
+	return abi.JSON(strings.NewReader(processClaimsAbi))
+})
+
+// EncodeProcessClaimCalldata returns the calldata of RewardsCoordinator.processClaim for the claim
+func EncodeProcessClaimCalldata(claim *rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim, recipient gethcommon.Address) ([]byte, error) {
+	a, err := getProcessClaimsAbi()
+	if err != nil {
+		return nil, err
+	}
+	return a.Pack("processClaim", *claim, recipient)
+}
+
+// EncodeProcessClaimsCalldata returns the calldata of RewardsCoordinator.processClaims, which pays out every claim
+// to the same recipient
+func EncodeProcessClaimsCalldata(claims []*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim, recipient gethcommon.Address) ([]byte, error) {
+	a, err := getProcessClaimsAbi()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim, 0, len(claims))
+	for _, claim := range claims {
+		values = append(values, *claim)
+	}
+	return a.Pack("processClaims", values, recipient)
+}
@@ -0,0 +1,97 @@
+package proofs
+
+import (
+	"container/list"
+	"sync"
+)
+
+// proofDataLeafOverheadBytes approximates the memory used by the distribution for each leaf, on top of the
+// leaf data and nodes held by the merkle trees
+const proofDataLeafOverheadBytes = 256
+
+// estimateProofDataSize approximates the number of bytes held in memory by the proof data
+func estimateProofDataSize(data *ProofData) int64 {
+	var size int64
+	if data.AccountTree != nil {
+		for _, d := range data.AccountTree.Data {
+			size += int64(len(d)) + proofDataLeafOverheadBytes
+		}
+		for _, n := range data.AccountTree.Nodes {
+			size += int64(len(n))
+		}
+	}
+	for _, tree := range data.TokenTree {
+		for _, d := range tree.Data {
+			size += int64(len(d)) + proofDataLeafOverheadBytes
+		}
+		for _, n := range tree.Nodes {
+			size += int64(len(n))
+		}
+	}
+	return size
+}
+
+type proofDataCacheEntry struct {
+	key  string
+	data *ProofData
+	size int64
+}
+
+// proofDataCache is a least recently used cache of proof data capped by its estimated memory use.
+//
+// The most recently added entry is always kept, even if it alone exceeds the cap, since it is about to be used.
+type proofDataCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	entries  *list.List
+	keys     map[string]*list.Element
+}
+
+func newProofDataCache(maxBytes int64) *proofDataCache {
+	return &proofDataCache{
+		maxBytes: maxBytes,
+		entries:  list.New(),
+		keys:     make(map[string]*list.Element),
+	}
+}
+
+func (c *proofDataCache) get(key string) (*ProofData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.keys[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	return elem.Value.(*proofDataCacheEntry).data, true
+}
+
+func (c *proofDataCache) add(key string, data *ProofData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.keys[key]; ok {
+		c.remove(elem)
+	}
+	entry := &proofDataCacheEntry{key: key, data: data, size: estimateProofDataSize(data)}
+	c.keys[key] = c.entries.PushFront(entry)
+	c.size += entry.size
+
+	for c.size > c.maxBytes && c.entries.Len() > 1 {
+		c.remove(c.entries.Back())
+	}
+}
+
+func (c *proofDataCache) remove(elem *list.Element) {
+	entry := c.entries.Remove(elem).(*proofDataCacheEntry)
+	delete(c.keys, entry.key)
+	c.size -= entry.size
+}
+
+func (c *proofDataCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
@@ -0,0 +1,62 @@
+package proofs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wealdtech/go-merkletree/v2"
+)
+
+func newTestProofData(snapshot string, leafSize int) *ProofData {
+	return &ProofData{
+		SnapshotDate: snapshot,
+		AccountTree: &merkletree.MerkleTree{
+			Data:  [][]byte{make([]byte, leafSize)},
+			Nodes: [][]byte{make([]byte, 32), make([]byte, 32)},
+		},
+	}
+}
+
+func Test_ProofDataCache(t *testing.T) {
+	entrySize := estimateProofDataSize(newTestProofData("", 100))
+
+	t.Run("Evicts least recently used entries over the cap", func(t *testing.T) {
+		cache := newProofDataCache(entrySize * 2)
+
+		cache.add("2025-01-01", newTestProofData("2025-01-01", 100))
+		cache.add("2025-01-02", newTestProofData("2025-01-02", 100))
+
+		// using the first entry makes the second the least recently used
+		_, ok := cache.get("2025-01-01")
+		assert.True(t, ok)
+
+		cache.add("2025-01-03", newTestProofData("2025-01-03", 100))
+		assert.Equal(t, 2, cache.len())
+
+		_, ok = cache.get("2025-01-02")
+		assert.False(t, ok)
+		data, ok := cache.get("2025-01-01")
+		assert.True(t, ok)
+		assert.Equal(t, "2025-01-01", data.SnapshotDate)
+		_, ok = cache.get("2025-01-03")
+		assert.True(t, ok)
+	})
+	t.Run("Keeps the newest entry even if it exceeds the cap", func(t *testing.T) {
+		cache := newProofDataCache(entrySize)
+
+		cache.add("2025-01-01", newTestProofData("2025-01-01", 100))
+		cache.add("2025-01-02", newTestProofData("2025-01-02", 1000))
+		assert.Equal(t, 1, cache.len())
+
+		_, ok := cache.get("2025-01-02")
+		assert.True(t, ok)
+	})
+	t.Run("Replaces an existing entry", func(t *testing.T) {
+		cache := newProofDataCache(entrySize * 2)
+
+		cache.add("2025-01-01", newTestProofData("2025-01-01", 100))
+		cache.add("2025-01-01", newTestProofData("2025-01-01", 100))
+		assert.Equal(t, 1, cache.len())
+		assert.Equal(t, entrySize, cache.size)
+	})
+}
@@ -1,21 +1,39 @@
 package proofs
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"time"
+
 	rewardsCoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
 	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
 	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
 	"github.com/Layr-Labs/sidecar/pkg/rewards"
 	"github.com/Layr-Labs/sidecar/pkg/utils"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/wealdtech/go-merkletree/v2"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 )
 
 type RewardsProofsStore struct {
 	rewardsCalculator *rewards.RewardsCalculator
+	grm               *gorm.DB
 	logger            *zap.Logger
-	rewardsData       map[string]*ProofData
+	globalConfig      *config.Config
+
+	rewardsData *proofDataCache
+
+	// builds makes concurrent requests for the same snapshot wait on a single build of its trees
+	builds singleflight.Group
 }
 
 type ProofData struct {
@@ -25,45 +43,200 @@ type ProofData struct {
 	Distribution *distribution.Distribution
 }
 
+// RewardsProofDistribution is the distribution of a snapshot persisted after its trees were first built, so
+// that they can be rebuilt without querying the gold table again.
+//
+// Distributions are tied to the generated rewards snapshot they were built from and are deleted along with it
+// when rewards are regenerated.
+type RewardsProofDistribution struct {
+	Id                         uint64 `gorm:"primaryKey"`
+	SnapshotDate               string
+	GeneratedRewardsSnapshotId uint64
+	AccountRoot                string
+
+	// Distribution is the gzipped JSON of the distribution
+	Distribution []byte
+	CreatedAt    time.Time
+}
+
+func (*RewardsProofDistribution) TableName() string {
+	return "rewards_proof_distributions"
+}
+
 func NewRewardsProofsStore(
 	rc *rewards.RewardsCalculator,
+	grm *gorm.DB,
 	l *zap.Logger,
+	cfg *config.Config,
 ) *RewardsProofsStore {
 	return &RewardsProofsStore{
 		rewardsCalculator: rc,
+		grm:               grm,
 		logger:            l,
-		rewardsData:       make(map[string]*ProofData),
+		globalConfig:      cfg,
+		rewardsData:       newProofDataCache(int64(cfg.Rewards.ProofCacheMaxMb) * 1024 * 1024),
 	}
 }
 
-func (rps *RewardsProofsStore) getRewardsDataForSnapshot(snapshot string) (*ProofData, error) {
-	data, ok := rps.rewardsData[snapshot]
-	if !ok {
-		accountTree, tokenTree, distro, err := rps.rewardsCalculator.MerkelizeRewardsForSnapshot(snapshot)
+// getRewardsDataForSnapshot returns the trees for the snapshot, in order of preference from the in-memory cache,
+// from the distribution persisted for the snapshot or by merkelizing the gold table.
+func (rps *RewardsProofsStore) getRewardsDataForSnapshot(snapshot string, generatedSnapshotId uint64) (*ProofData, error) {
+	key := fmt.Sprintf("%s_%d", snapshot, generatedSnapshotId)
+	if data, ok := rps.rewardsData.get(key); ok {
+		return data, nil
+	}
+
+	data, err, _ := rps.builds.Do(key, func() (interface{}, error) {
+		if data, ok := rps.rewardsData.get(key); ok {
+			return data, nil
+		}
+
+		data, err := rps.loadPersistedRewardsData(snapshot, generatedSnapshotId)
 		if err != nil {
-			rps.logger.Sugar().Errorw("Failed to fetch rewards for snapshot",
+			// the persisted distribution only saves time, so fall back to building the trees from scratch
+			rps.logger.Sugar().Warnw("Failed to load persisted distribution for snapshot",
 				zap.String("snapshot", snapshot),
 				zap.Error(err),
 			)
-			return nil, err
 		}
+		if data == nil {
+			accountTree, tokenTree, distro, err := rps.rewardsCalculator.MerkelizeRewardsForSnapshot(snapshot)
+			if err != nil {
+				rps.logger.Sugar().Errorw("Failed to fetch rewards for snapshot",
+					zap.String("snapshot", snapshot),
+					zap.Error(err),
+				)
+				return nil, err
+			}
+			data = &ProofData{
+				SnapshotDate: snapshot,
+				AccountTree:  accountTree,
+				TokenTree:    tokenTree,
+				Distribution: distro,
+			}
+			if err := rps.persistRewardsData(data, generatedSnapshotId); err != nil {
+				rps.logger.Sugar().Warnw("Failed to persist distribution for snapshot",
+					zap.String("snapshot", snapshot),
+					zap.Error(err),
+				)
+			}
+		}
+		rps.rewardsData.add(key, data)
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.(*ProofData), nil
+}
 
-		data = &ProofData{
-			SnapshotDate: snapshot,
-			AccountTree:  accountTree,
-			TokenTree:    tokenTree,
-			Distribution: distro,
+// loadPersistedRewardsData rebuilds the trees from the persisted distribution, returning nil if there is none
+func (rps *RewardsProofsStore) loadPersistedRewardsData(snapshot string, generatedSnapshotId uint64) (*ProofData, error) {
+	var persisted *RewardsProofDistribution
+	res := rps.grm.Model(&RewardsProofDistribution{}).
+		Where("snapshot_date = ? and generated_rewards_snapshot_id = ?", snapshot, generatedSnapshotId).
+		First(&persisted)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
-		rps.rewardsData[snapshot] = data
+		return nil, res.Error
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(persisted.Distribution))
+	if err != nil {
+		return nil, err
+	}
+	distroJson, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	distro, err := distribution.NewDistributionWithData(distroJson)
+	if err != nil {
+		return nil, err
+	}
+	accountTree, tokenTree, err := distro.Merklize()
+	if err != nil {
+		return nil, err
 	}
-	return data, nil
+	if root := hex.EncodeToString(accountTree.Root()); root != persisted.AccountRoot {
+		return nil, fmt.Errorf("persisted distribution has root '%s', expected '%s'", root, persisted.AccountRoot)
+	}
+
+	rps.logger.Sugar().Infow("Loaded persisted distribution for snapshot",
+		zap.String("snapshot", snapshot),
+		zap.Uint64("generatedSnapshotId", generatedSnapshotId),
+	)
+	return &ProofData{
+		SnapshotDate: snapshot,
+		AccountTree:  accountTree,
+		TokenTree:    tokenTree,
+		Distribution: distro,
+	}, nil
 }
 
-func (rps *RewardsProofsStore) GenerateRewardsClaimProof(earnerAddress string, tokenAddresses []string, rootIndex int64) (
-	[]byte,
-	*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim,
-	error,
-) {
+func (rps *RewardsProofsStore) persistRewardsData(data *ProofData, generatedSnapshotId uint64) error {
+	distroJson, err := data.Distribution.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(distroJson); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	res := rps.grm.Exec(`
+		insert into rewards_proof_distributions (snapshot_date, generated_rewards_snapshot_id, account_root, distribution)
+		values (?, ?, ?, ?)
+		on conflict (snapshot_date, generated_rewards_snapshot_id) do nothing
+	`, data.SnapshotDate, generatedSnapshotId, hex.EncodeToString(data.AccountTree.Root()), compressed.Bytes())
+	return res.Error
+}
+
+// WarmCache builds the trees for the newest claimable distribution root so that the first claim proof requested
+// after startup does not have to wait for them.
+func (rps *RewardsProofsStore) WarmCache(ctx context.Context) error {
+	distributionRoot, err := rps.rewardsCalculator.FindClaimableDistributionRoot(-1)
+	if err != nil {
+		return err
+	}
+	if distributionRoot == nil {
+		rps.logger.Sugar().Infow("No claimable distribution root found, skipping proof cache warm up")
+		return nil
+	}
+	snapshotDate := distributionRoot.GetSnapshotDate()
+
+	generatedSnapshot, err := rps.rewardsCalculator.GetGeneratedRewardsForSnapshotDate(snapshotDate)
+	if err != nil {
+		return err
+	}
+	if generatedSnapshot == nil {
+		rps.logger.Sugar().Infow("Rewards not yet generated for newest claimable root, skipping proof cache warm up",
+			zap.String("snapshot", snapshotDate),
+		)
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if _, err := rps.getRewardsDataForSnapshot(snapshotDate, generatedSnapshot.Id); err != nil {
+		return err
+	}
+	rps.logger.Sugar().Infow("Warmed proof cache for newest claimable root",
+		zap.Uint64("rootIndex", distributionRoot.RootIndex),
+		zap.String("snapshot", snapshotDate),
+	)
+	return nil
+}
+
+// getProofDataForRootIndex returns the claimable distribution root with the given index, or the newest one if
+// rootIndex is -1, along with the trees of its snapshot
+func (rps *RewardsProofsStore) getProofDataForRootIndex(rootIndex int64) (*types.SubmittedDistributionRoot, *ProofData, error) {
 	distributionRoot, err := rps.rewardsCalculator.FindClaimableDistributionRoot(rootIndex)
 	if err != nil {
 		rps.logger.Sugar().Errorf("Failed to find claimable distribution root for root_index",
@@ -85,12 +258,15 @@ func (rps *RewardsProofsStore) GenerateRewardsClaimProof(earnerAddress string, t
 		rps.logger.Sugar().Errorf("Failed to get generated rewards for snapshot date", zap.Error(err))
 		return nil, nil, err
 	}
+	if generatedSnapshot == nil {
+		return nil, nil, fmt.Errorf("Rewards have not been generated for snapshot %s", snapshotDate)
+	}
 	rps.logger.Sugar().Infow("Using snapshot for rewards proof",
 		zap.String("requestedSnapshot", snapshotDate),
 		zap.String("snapshot", generatedSnapshot.SnapshotDate),
 	)
 
-	proofData, err := rps.getRewardsDataForSnapshot(snapshotDate)
+	proofData, err := rps.getRewardsDataForSnapshot(snapshotDate, generatedSnapshot.Id)
 	if err != nil {
 		rps.logger.Sugar().Error("Failed to get rewards data for snapshot",
 			zap.String("snapshot", snapshotDate),
@@ -98,20 +274,55 @@ func (rps *RewardsProofsStore) GenerateRewardsClaimProof(earnerAddress string, t
 		)
 		return nil, nil, err
 	}
+	return distributionRoot, proofData, nil
+}
 
-	tokens := utils.Map(tokenAddresses, func(addr string, i uint64) gethcommon.Address {
-		return gethcommon.HexToAddress(addr)
-	})
-	earner := gethcommon.HexToAddress(earnerAddress)
-
+// generateVerifiedClaimProof generates the claim proof of the earner and checks it against the account tree root
+// before returning it
+func (rps *RewardsProofsStore) generateVerifiedClaimProof(
+	proofData *ProofData,
+	rootIndex uint64,
+	earner gethcommon.Address,
+	tokens []gethcommon.Address,
+) (*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim, error) {
 	claim, err := claimgen.GetProofForEarner(
 		proofData.Distribution,
-		uint32(distributionRoot.RootIndex),
+		uint32(rootIndex),
 		proofData.AccountTree,
 		proofData.TokenTree,
 		earner,
 		tokens,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyClaimProof(proofData.AccountTree.Root(), claim); err != nil {
+		rps.logger.Sugar().Errorw("Generated claim proof does not verify against the distribution root",
+			zap.String("earner", earner.String()),
+			zap.String("snapshot", proofData.SnapshotDate),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return claim, nil
+}
+
+func (rps *RewardsProofsStore) GenerateRewardsClaimProof(earnerAddress string, tokenAddresses []string, rootIndex int64) (
+	[]byte,
+	*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+	error,
+) {
+	distributionRoot, proofData, err := rps.getProofDataForRootIndex(rootIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens := utils.Map(tokenAddresses, func(addr string, i uint64) gethcommon.Address {
+		return gethcommon.HexToAddress(addr)
+	})
+	earner := gethcommon.HexToAddress(earnerAddress)
+
+	claim, err := rps.generateVerifiedClaimProof(proofData, distributionRoot.RootIndex, earner, tokens)
 	if err != nil {
 		rps.logger.Sugar().Error("Failed to generate claim proof for earner", zap.Error(err))
 		return nil, nil, err
@@ -119,3 +330,65 @@ func (rps *RewardsProofsStore) GenerateRewardsClaimProof(earnerAddress string, t
 
 	return proofData.AccountTree.Root(), claim, nil
 }
+
+// ClaimProofRequest is an earner to generate a claim proof for. If Tokens is empty, the proof covers every token
+// the earner has rewards in.
+type ClaimProofRequest struct {
+	Earner string
+	Tokens []string
+}
+
+// ClaimProofResult is the claim proof of a single earner of a batch. Err is set instead of Claim if the proof
+// could not be generated, e.g. because the earner has no rewards in the root.
+type ClaimProofResult struct {
+	Earner string
+	Claim  *rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim
+	Err    error
+}
+
+type ClaimProofBatch struct {
+	Root         []byte
+	RootIndex    uint64
+	SnapshotDate string
+	Results      []*ClaimProofResult
+}
+
+// GenerateRewardsClaimProofs generates claim proofs for many earners against the same distribution root.
+//
+// Every proof is verified against the root before it is returned. A proof that cannot be generated for one earner
+// is reported in its result rather than failing the batch.
+func (rps *RewardsProofsStore) GenerateRewardsClaimProofs(requests []*ClaimProofRequest, rootIndex int64) (*ClaimProofBatch, error) {
+	distributionRoot, proofData, err := rps.getProofDataForRootIndex(rootIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ClaimProofResult, 0, len(requests))
+	for _, req := range requests {
+		earner := gethcommon.HexToAddress(req.Earner)
+		tokens := utils.Map(req.Tokens, func(addr string, i uint64) gethcommon.Address {
+			return gethcommon.HexToAddress(addr)
+		})
+		if len(tokens) == 0 {
+			if earnerTokens, ok := proofData.Distribution.GetTokensForEarner(earner); ok {
+				for pair := earnerTokens.Oldest(); pair != nil; pair = pair.Next() {
+					tokens = append(tokens, pair.Key)
+				}
+			}
+		}
+
+		claim, err := rps.generateVerifiedClaimProof(proofData, distributionRoot.RootIndex, earner, tokens)
+		results = append(results, &ClaimProofResult{
+			Earner: req.Earner,
+			Claim:  claim,
+			Err:    err,
+		})
+	}
+
+	return &ClaimProofBatch{
+		Root:         proofData.AccountTree.Root(),
+		RootIndex:    distributionRoot.RootIndex,
+		SnapshotDate: distributionRoot.GetSnapshotDate(),
+		Results:      results,
+	}, nil
+}
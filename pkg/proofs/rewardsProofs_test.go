@@ -0,0 +1,266 @@
+package proofs
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	rewardsCoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/metrics"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setupProofs() (
+	string,
+	*config.Config,
+	*gorm.DB,
+	*zap.Logger,
+	*metrics.MetricsSink,
+	error,
+) {
+	cfg := tests.GetConfig()
+	cfg.Chain = config.Chain_Mainnet
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	sink, _ := metrics.NewMetricsSink(&metrics.MetricsSinkConfig{}, nil)
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, nil, err
+	}
+
+	return dbname, cfg, grm, l, sink, nil
+}
+
+// insertTestRewards inserts gold rows for the snapshot along with its completed generated snapshot and an activated
+// distribution root, returning the id of the generated snapshot
+func insertTestRewards(t *testing.T, grm *gorm.DB, snapshotDate string) uint64 {
+	goldRows := []struct {
+		earner string
+		token  string
+		amount string
+	}{
+		{"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", testTokenA.String(), "100"},
+		{"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", testTokenB.String(), "200"},
+		{"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", testTokenA.String(), "300"},
+		{"0xcccccccccccccccccccccccccccccccccccccccc", testTokenA.String(), "400"},
+	}
+	for _, row := range goldRows {
+		res := grm.Exec(`insert into gold_table (earner, snapshot, reward_hash, token, amount) values (?, ?, '0xreward', ?, ?)`,
+			row.earner, snapshotDate, row.token, row.amount,
+		)
+		assert.Nil(t, res.Error)
+	}
+
+	generatedSnapshot := &storage.GeneratedRewardsSnapshots{
+		SnapshotDate: snapshotDate,
+		Status:       storage.RewardSnapshotStatusCompleted.String(),
+	}
+	res := grm.Create(generatedSnapshot)
+	assert.Nil(t, res.Error)
+
+	calculationEnd, err := time.Parse(time.DateOnly, snapshotDate)
+	assert.Nil(t, err)
+	res = grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: calculationEnd.Add(24 * time.Hour)})
+	assert.Nil(t, res.Error)
+	res = grm.Exec(`
+		insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+		values ('0xroot', 1, 0, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', 0)
+	`, calculationEnd, calculationEnd.Add(24*time.Hour))
+	assert.Nil(t, res.Error)
+
+	return generatedSnapshot.Id
+}
+
+func Test_RewardsProofsStore(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupProofs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := rewards.NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2024-08-02"
+	generatedSnapshotId := insertTestRewards(t, grm, snapshotDate)
+	cacheKey := fmt.Sprintf("%s_%d", snapshotDate, generatedSnapshotId)
+
+	earner := "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	tokens := []string{testTokenA.String(), testTokenB.String()}
+
+	builtRoot, builtClaim, err := NewRewardsProofsStore(rc, grm, l, cfg).GenerateRewardsClaimProof(earner, tokens, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should persist the distribution when the trees are first built", func(t *testing.T) {
+		var persisted []*RewardsProofDistribution
+		res := grm.Model(&RewardsProofDistribution{}).Find(&persisted)
+		assert.Nil(t, res.Error)
+		assert.Len(t, persisted, 1)
+		assert.Equal(t, snapshotDate, persisted[0].SnapshotDate)
+		assert.Equal(t, generatedSnapshotId, persisted[0].GeneratedRewardsSnapshotId)
+		assert.Equal(t, hex.EncodeToString(builtRoot), persisted[0].AccountRoot)
+	})
+	t.Run("Should rebuild the same trees from the persisted distribution", func(t *testing.T) {
+		rps := NewRewardsProofsStore(rc, grm, l, cfg)
+
+		data, err := rps.loadPersistedRewardsData(snapshotDate, generatedSnapshotId)
+		assert.Nil(t, err)
+		assert.NotNil(t, data)
+		assert.Equal(t, builtRoot, data.AccountTree.Root())
+		// one token tree per earner
+		assert.Equal(t, 3, len(data.TokenTree))
+	})
+	t.Run("Should return nothing when no distribution was persisted for the generated snapshot", func(t *testing.T) {
+		rps := NewRewardsProofsStore(rc, grm, l, cfg)
+
+		data, err := rps.loadPersistedRewardsData(snapshotDate, generatedSnapshotId+1)
+		assert.Nil(t, err)
+		assert.Nil(t, data)
+	})
+	t.Run("Should warm the cache from the persisted distribution and generate identical proofs", func(t *testing.T) {
+		// without the gold rows the trees can only come from the persisted distribution
+		res := grm.Exec(`delete from gold_table`)
+		assert.Nil(t, res.Error)
+
+		rps := NewRewardsProofsStore(rc, grm, l, cfg)
+		err := rps.WarmCache(context.Background())
+		assert.Nil(t, err)
+
+		data, ok := rps.rewardsData.get(cacheKey)
+		assert.True(t, ok)
+		assert.Equal(t, builtRoot, data.AccountTree.Root())
+
+		root, claim, err := rps.GenerateRewardsClaimProof(earner, tokens, -1)
+		assert.Nil(t, err)
+		assert.Equal(t, builtRoot, root)
+		assert.Equal(t, builtClaim, claim)
+	})
+	t.Run("Should reject a persisted distribution that does not match its root", func(t *testing.T) {
+		res := grm.Exec(`update rewards_proof_distributions set account_root = ?`, hex.EncodeToString(make([]byte, 32)))
+		assert.Nil(t, res.Error)
+
+		rps := NewRewardsProofsStore(rc, grm, l, cfg)
+		data, err := rps.loadPersistedRewardsData(snapshotDate, generatedSnapshotId)
+		assert.NotNil(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
+
+func Test_VerifyClaimProof(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupProofs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := rewards.NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rps := NewRewardsProofsStore(rc, grm, l, cfg)
+
+	distro, data := newTestDistribution(t)
+	root := utils.ConvertBytesToString(data.AccountTree.Root())
+
+	res := grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Now().Add(-48 * time.Hour)})
+	assert.Nil(t, res.Error)
+	submitDistributionRoot := func(t *testing.T, rootIndex uint32, submittedRoot string, activatedAt time.Time) {
+		res := grm.Exec(`
+			insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+			values (?, 1, ?, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', ?)
+		`, submittedRoot, rootIndex, time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC), activatedAt, rootIndex)
+		assert.Nil(t, res.Error)
+	}
+	claimForRootIndex := func(t *testing.T, rootIndex uint32) *rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim {
+		claim, err := claimgen.GetProofForEarner(distro, rootIndex, data.AccountTree, data.TokenTree,
+			gethcommon.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), []gethcommon.Address{testTokenA, testTokenB},
+		)
+		assert.Nil(t, err)
+		return claim
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	submitDistributionRoot(t, 0, root, yesterday)
+	submitDistributionRoot(t, 1, root, yesterday)
+	res = grm.Exec(`insert into disabled_distribution_roots (root_index, log_index, transaction_hash, block_number) values (1, 10, '0xtx', 1)`)
+	assert.Nil(t, res.Error)
+	submitDistributionRoot(t, 2, root, time.Now().Add(24*time.Hour))
+	submitDistributionRoot(t, 3, utils.ConvertBytesToString(make([]byte, 32)), yesterday)
+
+	t.Run("Should verify a claim against an active root", func(t *testing.T) {
+		verification, err := rps.VerifyClaimProof(claimForRootIndex(t, 0))
+		assert.Nil(t, err)
+		assert.True(t, verification.Valid)
+		assert.Empty(t, verification.Reason)
+		assert.Equal(t, DistributionRootStatus_Active, verification.RootStatus)
+		assert.Equal(t, root, verification.ComputedRoot)
+		assert.Equal(t, root, verification.SubmittedRoot)
+		assert.NotNil(t, verification.ActivatedAt)
+	})
+	t.Run("Should report a disabled root", func(t *testing.T) {
+		verification, err := rps.VerifyClaimProof(claimForRootIndex(t, 1))
+		assert.Nil(t, err)
+		assert.True(t, verification.Valid)
+		assert.Equal(t, DistributionRootStatus_Disabled, verification.RootStatus)
+	})
+	t.Run("Should report a root that is not activated yet", func(t *testing.T) {
+		verification, err := rps.VerifyClaimProof(claimForRootIndex(t, 2))
+		assert.Nil(t, err)
+		assert.True(t, verification.Valid)
+		assert.Equal(t, DistributionRootStatus_PendingActivation, verification.RootStatus)
+	})
+	t.Run("Should reject a claim for a different root", func(t *testing.T) {
+		verification, err := rps.VerifyClaimProof(claimForRootIndex(t, 3))
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.NotEmpty(t, verification.Reason)
+		assert.Equal(t, DistributionRootStatus_Active, verification.RootStatus)
+		assert.Equal(t, root, verification.ComputedRoot)
+	})
+	t.Run("Should reject a claim with an altered amount", func(t *testing.T) {
+		claim := claimForRootIndex(t, 0)
+		claim.TokenLeaves[0].CumulativeEarnings = big.NewInt(101)
+
+		verification, err := rps.VerifyClaimProof(claim)
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.NotEmpty(t, verification.Reason)
+		assert.Empty(t, verification.ComputedRoot)
+		assert.Equal(t, DistributionRootStatus_Active, verification.RootStatus)
+	})
+	t.Run("Should report a root index that was never submitted", func(t *testing.T) {
+		verification, err := rps.VerifyClaimProof(claimForRootIndex(t, 9))
+		assert.Nil(t, err)
+		assert.False(t, verification.Valid)
+		assert.NotEmpty(t, verification.Reason)
+		assert.Equal(t, DistributionRootStatus_NotFound, verification.RootStatus)
+		assert.Empty(t, verification.SubmittedRoot)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,226 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	rewardsCoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+type ErrInvalidClaimProof struct {
+	Message string
+}
+
+func (e *ErrInvalidClaimProof) Error() string {
+	return fmt.Sprintf("invalid claim proof: %s", e.Message)
+}
+
+// computeMerkleRoot walks a proof of sibling hashes from the leaf at index up to the root, the same way the
+// RewardsCoordinator verifies inclusion with Merkle.verifyInclusionKeccak.
+//
+// Like the contract, it rejects empty proofs and indexes that do not fit in a tree as deep as the proof, since
+// either would let a proof for one leaf pass as a proof for another.
+func computeMerkleRoot(leaf []byte, index uint32, proof []byte) ([]byte, error) {
+	if len(proof) == 0 {
+		return nil, fmt.Errorf("proof is empty")
+	}
+	if len(proof)%32 != 0 {
+		return nil, fmt.Errorf("proof length %d is not a multiple of 32", len(proof))
+	}
+	if depth := len(proof) / 32; depth < 32 && uint64(index) >= uint64(1)<<depth {
+		return nil, fmt.Errorf("index %d is out of bounds for a proof of %d hashes", index, depth)
+	}
+	computed := crypto.Keccak256(leaf)
+	for i := 0; i < len(proof); i += 32 {
+		if index%2 == 0 {
+			computed = crypto.Keccak256(computed, proof[i:i+32])
+		} else {
+			computed = crypto.Keccak256(proof[i:i+32], computed)
+		}
+		index /= 2
+	}
+	return computed, nil
+}
+
+// ComputeClaimRoot recomputes the distribution root that a claim proves inclusion in.
+//
+// Every token leaf is checked against the earner's token root before the earner leaf is walked up to the root,
+// so a claim that does not match its own earner leaf is rejected with an ErrInvalidClaimProof.
+func ComputeClaimRoot(claim *rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim) ([]byte, error) {
+	if len(claim.TokenIndices) != len(claim.TokenLeaves) || len(claim.TokenTreeProofs) != len(claim.TokenLeaves) {
+		return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("claim has %d token leaves, %d token indices and %d token proofs",
+			len(claim.TokenLeaves), len(claim.TokenIndices), len(claim.TokenTreeProofs),
+		)}
+	}
+
+	for i, leaf := range claim.TokenLeaves {
+		if leaf.CumulativeEarnings == nil || leaf.CumulativeEarnings.Sign() < 0 {
+			return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("token %s has an invalid cumulative amount", leaf.Token.String())}
+		}
+		tokenRoot, err := computeMerkleRoot(distribution.EncodeTokenLeaf(leaf.Token, leaf.CumulativeEarnings), claim.TokenIndices[i], claim.TokenTreeProofs[i])
+		if err != nil {
+			return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("token %s: %s", leaf.Token.String(), err.Error())}
+		}
+		if !bytes.Equal(tokenRoot, claim.EarnerLeaf.EarnerTokenRoot[:]) {
+			return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("token %s does not match the earner token root 0x%s",
+				leaf.Token.String(), hex.EncodeToString(claim.EarnerLeaf.EarnerTokenRoot[:]),
+			)}
+		}
+	}
+
+	earnerLeaf := distribution.EncodeAccountLeaf(claim.EarnerLeaf.Earner, claim.EarnerLeaf.EarnerTokenRoot[:])
+	root, err := computeMerkleRoot(earnerLeaf, claim.EarnerIndex, claim.EarnerTreeProof)
+	if err != nil {
+		return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("earner %s: %s", claim.EarnerLeaf.Earner.String(), err.Error())}
+	}
+	return root, nil
+}
+
+// verifyClaimProof checks that the claim proves inclusion in the given root
+func verifyClaimProof(root []byte, claim *rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim) error {
+	computed, err := ComputeClaimRoot(claim)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(computed, root) {
+		return &ErrInvalidClaimProof{Message: fmt.Sprintf("claim proves root 0x%s, expected 0x%s", hex.EncodeToString(computed), hex.EncodeToString(root))}
+	}
+	return nil
+}
+
+// Statuses of the distribution root that a claim proof is verified against
+const (
+	DistributionRootStatus_Active            = "active"
+	DistributionRootStatus_Disabled          = "disabled"
+	DistributionRootStatus_PendingActivation = "pending_activation"
+	DistributionRootStatus_NotFound          = "not_found"
+)
+
+type ClaimProofVerification struct {
+	// Valid is true if the claim proves inclusion in the submitted root. The claim is only claimable if the root
+	// is also active.
+	Valid         bool       `json:"valid"`
+	Reason        string     `json:"reason,omitempty"`
+	RootIndex     uint32     `json:"rootIndex"`
+	ComputedRoot  string     `json:"computedRoot,omitempty"`
+	RootStatus    string     `json:"rootStatus"`
+	SubmittedRoot string     `json:"submittedRoot,omitempty"`
+	ActivatedAt   *time.Time `json:"activatedAt,omitempty"`
+}
+
+// VerifyClaimProof recomputes the root of a claim and checks it against the distribution root submitted with the
+// claim's root index, reporting whether that root is active, disabled or still inside its activation delay.
+//
+// A claim that does not verify is reported in the returned ClaimProofVerification. An error is only returned if
+// the distribution root could not be looked up.
+func (rps *RewardsProofsStore) VerifyClaimProof(claim *rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim) (*ClaimProofVerification, error) {
+	verification := &ClaimProofVerification{
+		RootIndex:  claim.RootIndex,
+		RootStatus: DistributionRootStatus_NotFound,
+	}
+
+	computedRoot, err := ComputeClaimRoot(claim)
+	if err != nil {
+		var invalidErr *ErrInvalidClaimProof
+		if !errors.As(err, &invalidErr) {
+			return nil, err
+		}
+		verification.Reason = err.Error()
+	} else {
+		verification.ComputedRoot = utils.ConvertBytesToString(computedRoot)
+	}
+
+	root, err := rps.rewardsCalculator.FindDistributionRootByRootIndex(uint64(claim.RootIndex))
+	if err != nil {
+		rps.logger.Sugar().Errorw("Failed to find distribution root for claim proof",
+			zap.Uint32("rootIndex", claim.RootIndex),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if root == nil {
+		if verification.Reason == "" {
+			verification.Reason = fmt.Sprintf("no distribution root has been submitted with root index %d", claim.RootIndex)
+		}
+		return verification, nil
+	}
+
+	verification.SubmittedRoot = root.Root
+	activatedAt := root.ActivatedAt.UTC()
+	verification.ActivatedAt = &activatedAt
+	switch {
+	case root.Disabled:
+		verification.RootStatus = DistributionRootStatus_Disabled
+	case root.ActivatedAt.After(time.Now()):
+		verification.RootStatus = DistributionRootStatus_PendingActivation
+	default:
+		verification.RootStatus = DistributionRootStatus_Active
+	}
+
+	if verification.ComputedRoot == "" {
+		return verification, nil
+	}
+	if !strings.EqualFold(verification.ComputedRoot, root.Root) {
+		verification.Reason = fmt.Sprintf("claim proves root %s, but root index %d is %s", verification.ComputedRoot, claim.RootIndex, root.Root)
+		return verification, nil
+	}
+	verification.Valid = true
+	return verification, nil
+}
+
+// ParseClaimProofStrings converts a claim in the format produced by claimgen.FormatProofForSolidity back into the
+// claim the RewardsCoordinator takes. The root of the formatted claim is ignored, since it is recomputed from the
+// proofs when the claim is verified.
+func ParseClaimProofStrings(formatted *claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings) (*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim, error) {
+	earnerTreeProof, err := hexutil.Decode(formatted.EarnerTreeProof)
+	if err != nil {
+		return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("earnerTreeProof: %s", err.Error())}
+	}
+	earnerTokenRoot, err := hexutil.Decode(formatted.EarnerLeaf.EarnerTokenRoot)
+	if err != nil || len(earnerTokenRoot) != 32 {
+		return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("earnerTokenRoot '%s' is not a 32 byte hex string", formatted.EarnerLeaf.EarnerTokenRoot)}
+	}
+
+	claim := &rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim{
+		RootIndex:       formatted.RootIndex,
+		EarnerIndex:     formatted.EarnerIndex,
+		EarnerTreeProof: earnerTreeProof,
+		EarnerLeaf: rewardsCoordinator.IRewardsCoordinatorEarnerTreeMerkleLeaf{
+			Earner: formatted.EarnerLeaf.Earner,
+		},
+		TokenIndices:    formatted.TokenIndices,
+		TokenTreeProofs: make([][]byte, 0, len(formatted.TokenTreeProofs)),
+		TokenLeaves:     make([]rewardsCoordinator.IRewardsCoordinatorTokenTreeMerkleLeaf, 0, len(formatted.TokenLeaves)),
+	}
+	copy(claim.EarnerLeaf.EarnerTokenRoot[:], earnerTokenRoot)
+
+	for i, p := range formatted.TokenTreeProofs {
+		proof, err := hexutil.Decode(p)
+		if err != nil {
+			return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("tokenTreeProofs[%d]: %s", i, err.Error())}
+		}
+		claim.TokenTreeProofs = append(claim.TokenTreeProofs, proof)
+	}
+	for _, leaf := range formatted.TokenLeaves {
+		amount, ok := new(big.Int).SetString(leaf.CumulativeEarnings, 10)
+		if !ok {
+			return nil, &ErrInvalidClaimProof{Message: fmt.Sprintf("token %s has an invalid cumulative amount '%s'", leaf.Token.String(), leaf.CumulativeEarnings)}
+		}
+		claim.TokenLeaves = append(claim.TokenLeaves, rewardsCoordinator.IRewardsCoordinatorTokenTreeMerkleLeaf{
+			Token:              leaf.Token,
+			CumulativeEarnings: amount,
+		})
+	}
+	return claim, nil
+}
@@ -0,0 +1,187 @@
+package proofs
+
+import (
+	"math/big"
+	"testing"
+
+	rewardsCoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testTokenA = gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	testTokenB = gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func newTestDistribution(t *testing.T) (*distribution.Distribution, *ProofData) {
+	distro := distribution.NewDistribution()
+	err := distro.LoadLines([]*distribution.EarnerLine{
+		{Earner: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Token: testTokenA.String(), CumulativeAmount: "100"},
+		{Earner: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Token: testTokenB.String(), CumulativeAmount: "200"},
+		{Earner: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Token: testTokenA.String(), CumulativeAmount: "300"},
+		{Earner: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Token: testTokenB.String(), CumulativeAmount: "350"},
+		{Earner: "0xcccccccccccccccccccccccccccccccccccccccc", Token: testTokenA.String(), CumulativeAmount: "400"},
+		{Earner: "0xcccccccccccccccccccccccccccccccccccccccc", Token: testTokenB.String(), CumulativeAmount: "500"},
+	})
+	assert.Nil(t, err)
+	accountTree, tokenTree, err := distro.Merklize()
+	assert.Nil(t, err)
+	return distro, &ProofData{
+		AccountTree:  accountTree,
+		TokenTree:    tokenTree,
+		Distribution: distro,
+	}
+}
+
+func Test_ComputeClaimRoot(t *testing.T) {
+	distro, data := newTestDistribution(t)
+
+	for _, earner := range []string{
+		"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		"0xcccccccccccccccccccccccccccccccccccccccc",
+	} {
+		tokens := []gethcommon.Address{testTokenA, testTokenB}
+		claim, err := claimgen.GetProofForEarner(distro, 1, data.AccountTree, data.TokenTree, gethcommon.HexToAddress(earner), tokens)
+		assert.Nil(t, err)
+
+		root, err := ComputeClaimRoot(claim)
+		assert.Nil(t, err)
+		assert.Equal(t, data.AccountTree.Root(), root)
+		assert.Nil(t, verifyClaimProof(data.AccountTree.Root(), claim))
+	}
+
+	t.Run("Rejects a claim with an altered amount", func(t *testing.T) {
+		claim, err := claimgen.GetProofForEarner(distro, 1, data.AccountTree, data.TokenTree,
+			gethcommon.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), []gethcommon.Address{testTokenA},
+		)
+		assert.Nil(t, err)
+		claim.TokenLeaves[0].CumulativeEarnings = big.NewInt(101)
+
+		_, err = ComputeClaimRoot(claim)
+		var invalidErr *ErrInvalidClaimProof
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+	t.Run("Rejects a claim with an empty proof", func(t *testing.T) {
+		single := distribution.NewDistribution()
+		err := single.LoadLines([]*distribution.EarnerLine{
+			{Earner: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Token: testTokenA.String(), CumulativeAmount: "100"},
+		})
+		assert.Nil(t, err)
+		accountTree, tokenTree, err := single.Merklize()
+		assert.Nil(t, err)
+
+		// the only leaf of a tree is its own root, so the proofs are empty
+		claim, err := claimgen.GetProofForEarner(single, 1, accountTree, tokenTree,
+			gethcommon.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), []gethcommon.Address{testTokenA},
+		)
+		assert.Nil(t, err)
+		assert.Len(t, claim.EarnerTreeProof, 0)
+
+		_, err = ComputeClaimRoot(claim)
+		var invalidErr *ErrInvalidClaimProof
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+	t.Run("Rejects a claim with an index outside of its proof", func(t *testing.T) {
+		claim, err := claimgen.GetProofForEarner(distro, 1, data.AccountTree, data.TokenTree,
+			gethcommon.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), []gethcommon.Address{testTokenA},
+		)
+		assert.Nil(t, err)
+		// the earner tree has 4 leaves, so the proof has 2 hashes and index 4 walks up the same path as index 0
+		assert.Len(t, claim.EarnerTreeProof, 64)
+		claim.EarnerIndex += 4
+
+		_, err = ComputeClaimRoot(claim)
+		var invalidErr *ErrInvalidClaimProof
+		assert.ErrorAs(t, err, &invalidErr)
+
+		claim.EarnerIndex -= 4
+		claim.TokenIndices[0] += 2
+		_, err = ComputeClaimRoot(claim)
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+	t.Run("Rejects a claim for a different root", func(t *testing.T) {
+		claim, err := claimgen.GetProofForEarner(distro, 1, data.AccountTree, data.TokenTree,
+			gethcommon.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), []gethcommon.Address{testTokenA},
+		)
+		assert.Nil(t, err)
+		claim.EarnerIndex = 1
+
+		err = verifyClaimProof(data.AccountTree.Root(), claim)
+		var invalidErr *ErrInvalidClaimProof
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+}
+
+func Test_EncodeProcessClaimCalldata(t *testing.T) {
+	distro, data := newTestDistribution(t)
+	claim, err := claimgen.GetProofForEarner(distro, 1, data.AccountTree, data.TokenTree,
+		gethcommon.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), []gethcommon.Address{testTokenA, testTokenB},
+	)
+	assert.Nil(t, err)
+	recipient := gethcommon.HexToAddress("0xdddddddddddddddddddddddddddddddddddddddd")
+
+	t.Run("processClaim matches the contract bindings", func(t *testing.T) {
+		calldata, err := EncodeProcessClaimCalldata(claim, recipient)
+		assert.Nil(t, err)
+
+		bindingsAbi, err := rewardsCoordinator.IRewardsCoordinatorMetaData.GetAbi()
+		assert.Nil(t, err)
+		expected, err := bindingsAbi.Pack("processClaim", *claim, recipient)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, calldata)
+	})
+	t.Run("processClaims", func(t *testing.T) {
+		calldata, err := EncodeProcessClaimsCalldata([]*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim{claim, claim}, recipient)
+		assert.Nil(t, err)
+
+		a, err := getProcessClaimsAbi()
+		assert.Nil(t, err)
+		method := a.Methods["processClaims"]
+		assert.Equal(t, method.ID, calldata[:4])
+
+		args, err := method.Inputs.Unpack(calldata[4:])
+		assert.Nil(t, err)
+		assert.Len(t, args, 2)
+		assert.Equal(t, recipient, args[1])
+	})
+}
+
+func Test_ParseClaimProofStrings(t *testing.T) {
+	distro, data := newTestDistribution(t)
+	claim, err := claimgen.GetProofForEarner(distro, 1, data.AccountTree, data.TokenTree,
+		gethcommon.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc"), []gethcommon.Address{testTokenA, testTokenB},
+	)
+	assert.Nil(t, err)
+
+	t.Run("Round trips a formatted claim", func(t *testing.T) {
+		parsed, err := ParseClaimProofStrings(claimgen.FormatProofForSolidity(data.AccountTree.Root(), claim))
+		assert.Nil(t, err)
+		assert.Equal(t, claim.EarnerLeaf, parsed.EarnerLeaf)
+		assert.Equal(t, claim.EarnerTreeProof, parsed.EarnerTreeProof)
+		assert.Equal(t, claim.TokenTreeProofs, parsed.TokenTreeProofs)
+
+		root, err := ComputeClaimRoot(parsed)
+		assert.Nil(t, err)
+		assert.Equal(t, data.AccountTree.Root(), root)
+	})
+	t.Run("Rejects an invalid earner token root", func(t *testing.T) {
+		formatted := claimgen.FormatProofForSolidity(data.AccountTree.Root(), claim)
+		formatted.EarnerLeaf.EarnerTokenRoot = "0x1234"
+
+		_, err := ParseClaimProofStrings(formatted)
+		var invalidErr *ErrInvalidClaimProof
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+	t.Run("Rejects an invalid cumulative amount", func(t *testing.T) {
+		formatted := claimgen.FormatProofForSolidity(data.AccountTree.Root(), claim)
+		formatted.TokenLeaves[0].CumulativeEarnings = "0x10"
+
+		_, err := ParseClaimProofStrings(formatted)
+		var invalidErr *ErrInvalidClaimProof
+		assert.ErrorAs(t, err, &invalidErr)
+	})
+}
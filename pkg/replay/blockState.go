@@ -0,0 +1,242 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// MerkleTreeSlot is a hex encoded types.MerkleTreeInput
+type MerkleTreeSlot struct {
+	SlotID string `json:"slotId"`
+	Value  string `json:"value"`
+}
+
+// ModelStateRoot is a hex encoded stateManager.ModelStateRoot
+type ModelStateRoot struct {
+	ModelName string            `json:"modelName"`
+	Root      string            `json:"root"`
+	Leaf      string            `json:"leaf"`
+	Slots     []*MerkleTreeSlot `json:"slots"`
+}
+
+// BlockStateRoots is the state root for a block along with the per-model roots and slots it was built from.
+type BlockStateRoots struct {
+	BlockNumber uint64            `json:"blockNumber"`
+	BlockHash   string            `json:"blockHash"`
+	StateRoot   string            `json:"stateRoot"`
+	Models      []*ModelStateRoot `json:"models"`
+}
+
+func encodeBytes(b []byte) string {
+	if b == nil {
+		return ""
+	}
+	return utils.ConvertBytesToString(b)
+}
+
+func newModelStateRoot(msr *stateManager.ModelStateRoot) *ModelStateRoot {
+	slots := make([]*MerkleTreeSlot, 0, len(msr.Inputs))
+	for _, input := range msr.Inputs {
+		slots = append(slots, &MerkleTreeSlot{
+			SlotID: string(input.SlotID),
+			Value:  encodeBytes(input.Value),
+		})
+	}
+	return &ModelStateRoot{
+		ModelName: msr.ModelName,
+		Root:      encodeBytes(msr.Root),
+		Leaf:      encodeBytes(msr.Leaf),
+		Slots:     slots,
+	}
+}
+
+// RebuildBlockStateRoots rebuilds the in-memory state for a single stored block from its transaction logs and
+// returns the resulting state root along with the root and slots of every model. Nothing is written to the database.
+//
+// The Replayer's EigenStateManager may be shared with a running pipeline; the models' in-memory state is held
+// exclusively for the duration of the rebuild.
+func (r *Replayer) RebuildBlockStateRoots(blockNumber uint64) (*BlockStateRoots, error) {
+	var result *BlockStateRoots
+	err := r.rebuildBlock(blockNumber, func(block *storage.Block) error {
+		stateRoot, err := r.stateManager.GenerateStateRoot(blockNumber, block.Hash)
+		if err != nil {
+			return err
+		}
+		modelRoots, err := r.stateManager.GetModelStateRoots(blockNumber)
+		if err != nil {
+			return err
+		}
+
+		result = &BlockStateRoots{
+			BlockNumber: blockNumber,
+			BlockHash:   block.Hash,
+			StateRoot:   string(stateRoot),
+			Models:      make([]*ModelStateRoot, 0, len(modelRoots)),
+		}
+		for _, msr := range modelRoots {
+			result.Models = append(result.Models, newModelStateRoot(msr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GenerateStateChangeProof rebuilds the in-memory state for a single stored block and generates a proof that the
+// state change for the slot in the model is part of the block's state root.
+//
+// The rebuilt state root must match the state root stored for the block, otherwise the proof would not verify against
+// the root that was served to clients.
+func (r *Replayer) GenerateStateChangeProof(blockNumber uint64, modelName string, slotID types.SlotID) (*stateManager.StateChangeProof, error) {
+	var proof *stateManager.StateChangeProof
+	err := r.rebuildBlock(blockNumber, func(block *storage.Block) error {
+		storedRoot, err := r.stateManager.GetStateRootForBlock(blockNumber)
+		if err != nil {
+			return fmt.Errorf("failed to get stored state root for block %d: %w", blockNumber, err)
+		}
+
+		proof, err = r.stateManager.GenerateStateChangeProof(blockNumber, block.Hash, modelName, slotID)
+		if err != nil {
+			return err
+		}
+		if rebuiltRoot := utils.ConvertBytesToString(proof.StateRoot); !strings.EqualFold(rebuiltRoot, storedRoot.StateRoot) {
+			return fmt.Errorf("rebuilt state root '%s' does not match stored state root '%s' for block %d",
+				rebuiltRoot, storedRoot.StateRoot, blockNumber)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// rebuildBlock replays the stored logs for a single block into the in-memory state of the models, calls fn,
+// and then discards the in-memory state.
+func (r *Replayer) rebuildBlock(blockNumber uint64, fn func(block *storage.Block) error) error {
+	blocks, logsByBlock, err := r.loadBlocksAndLogs(blockNumber, blockNumber)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("block %d has not been indexed", blockNumber)
+	}
+	block := blocks[0]
+
+	r.stateManager.LockProcessing()
+	defer r.stateManager.UnlockProcessing()
+
+	if err := r.stateManager.InitProcessingForBlock(blockNumber); err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.stateManager.CleanupProcessedStateForBlock(blockNumber)
+	}()
+
+	if err := r.handleLogs(blockNumber, logsByBlock[blockNumber]); err != nil {
+		return err
+	}
+	return fn(block)
+}
+
+func (r *Replayer) handleLogs(blockNumber uint64, logs []*storage.TransactionLog) error {
+	for _, log := range logs {
+		if err := r.stateManager.HandleLogStateChange(log); err != nil {
+			r.logger.Sugar().Errorw("Failed to handle log state change",
+				zap.Uint64("blockNumber", blockNumber),
+				zap.String("transactionHash", log.TransactionHash),
+				zap.Uint64("logIndex", log.LogIndex),
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+	return nil
+}
+
+// SlotDiff describes a slot whose value differs between two versions of a model's state tree.
+// An empty value means the slot does not exist on that side.
+type SlotDiff struct {
+	SlotID      string `json:"slotId"`
+	LocalValue  string `json:"localValue"`
+	RemoteValue string `json:"remoteValue"`
+}
+
+// ModelDiff describes a model whose root differs between two versions of a block's state.
+type ModelDiff struct {
+	ModelName  string      `json:"modelName"`
+	LocalRoot  string      `json:"localRoot"`
+	RemoteRoot string      `json:"remoteRoot"`
+	Slots      []*SlotDiff `json:"slots"`
+}
+
+// DiffBlockStateRoots compares the per-model roots of two versions of the same block and returns the
+// models, and the slots within them, that differ.
+func DiffBlockStateRoots(local *BlockStateRoots, remote *BlockStateRoots) []*ModelDiff {
+	remoteModels := make(map[string]*ModelStateRoot, len(remote.Models))
+	for _, m := range remote.Models {
+		remoteModels[m.ModelName] = m
+	}
+	localModels := make(map[string]*ModelStateRoot, len(local.Models))
+	modelNames := make([]string, 0, len(local.Models))
+	for _, m := range local.Models {
+		localModels[m.ModelName] = m
+		modelNames = append(modelNames, m.ModelName)
+	}
+	for _, m := range remote.Models {
+		if _, ok := localModels[m.ModelName]; !ok {
+			modelNames = append(modelNames, m.ModelName)
+		}
+	}
+
+	diffs := make([]*ModelDiff, 0)
+	for _, name := range modelNames {
+		l, hasLocal := localModels[name]
+		rm, hasRemote := remoteModels[name]
+		if !hasLocal {
+			l = &ModelStateRoot{ModelName: name}
+		}
+		if !hasRemote {
+			rm = &ModelStateRoot{ModelName: name}
+		}
+		if strings.EqualFold(l.Root, rm.Root) {
+			continue
+		}
+		diffs = append(diffs, &ModelDiff{
+			ModelName:  name,
+			LocalRoot:  l.Root,
+			RemoteRoot: rm.Root,
+			Slots:      diffSlots(l.Slots, rm.Slots),
+		})
+	}
+	return diffs
+}
+
+func diffSlots(local []*MerkleTreeSlot, remote []*MerkleTreeSlot) []*SlotDiff {
+	remoteValues := make(map[string]string, len(remote))
+	for _, s := range remote {
+		remoteValues[s.SlotID] = s.Value
+	}
+	diffs := make([]*SlotDiff, 0)
+	seen := make(map[string]bool, len(local))
+	for _, s := range local {
+		seen[s.SlotID] = true
+		if rv, ok := remoteValues[s.SlotID]; !ok || !strings.EqualFold(rv, s.Value) {
+			diffs = append(diffs, &SlotDiff{SlotID: s.SlotID, LocalValue: s.Value, RemoteValue: rv})
+		}
+	}
+	for _, s := range remote {
+		if !seen[s.SlotID] {
+			diffs = append(diffs, &SlotDiff{SlotID: s.SlotID, RemoteValue: s.Value})
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,257 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Number of blocks to load transaction logs for at once
+const replayBlockBatchSize = uint64(1000)
+
+type ReplayConfig struct {
+	StartBlock uint64
+	EndBlock   uint64
+	// Names of the models to rebuild. If empty, all models are rebuilt.
+	Models []string
+}
+
+// StateRootMismatch describes a block where the replayed state root differs from the stored state root.
+type StateRootMismatch struct {
+	BlockNumber       uint64
+	BlockHash         string
+	StoredStateRoot   string
+	ReplayedStateRoot string
+}
+
+type ReplayResult struct {
+	BlocksReplayed uint64
+	LogsReplayed   uint64
+	Mismatches     []*StateRootMismatch
+}
+
+// Replayer rebuilds eigen state from the transaction logs already stored in the database, without
+// needing an Ethereum node.
+type Replayer struct {
+	stateManager *stateManager.EigenStateManager
+	db           *gorm.DB
+	logger       *zap.Logger
+}
+
+func NewReplayer(sm *stateManager.EigenStateManager, grm *gorm.DB, l *zap.Logger) *Replayer {
+	return &Replayer{
+		stateManager: sm,
+		db:           grm,
+		logger:       l,
+	}
+}
+
+// selectModels returns the models to rebuild keyed by their registered index.
+func (r *Replayer) selectModels(names []string) (map[int]types.IEigenStateModel, error) {
+	selected := make(map[int]types.IEigenStateModel)
+	if len(names) == 0 {
+		for index, model := range r.stateManager.StateModels {
+			selected[index] = model
+		}
+		return selected, nil
+	}
+
+	indexesByName := make(map[string]int, len(r.stateManager.StateModels))
+	for index, model := range r.stateManager.StateModels {
+		indexesByName[model.GetModelName()] = index
+	}
+	for _, name := range names {
+		index, ok := indexesByName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown model '%s'", name)
+		}
+		selected[index] = r.stateManager.StateModels[index]
+	}
+	return selected, nil
+}
+
+// Replay rebuilds the state of the selected models for the block range by streaming the stored transaction
+// logs, in order, through the EigenStateManager.
+//
+// Every model processes the logs so that a complete state root can be generated for each block, but only the
+// selected models have their state deleted and re-committed. Each block's state is deleted and re-committed in
+// a single transaction so that a replay that fails partway leaves the remaining blocks untouched. The generated state root for each block is compared
+// with the stored state root and any differences are returned as mismatches; stored state roots are never modified.
+func (r *Replayer) Replay(ctx context.Context, cfg *ReplayConfig) (*ReplayResult, error) {
+	if cfg.EndBlock < cfg.StartBlock {
+		return nil, fmt.Errorf("invalid block range; end block %d must be greater than or equal to start block %d", cfg.EndBlock, cfg.StartBlock)
+	}
+	models, err := r.selectModels(cfg.Models)
+	if err != nil {
+		return nil, err
+	}
+
+	modelNames := make([]string, 0, len(models))
+	for _, index := range r.stateManager.GetSortedModelIndexes() {
+		if model, ok := models[index]; ok {
+			modelNames = append(modelNames, model.GetModelName())
+		}
+	}
+	r.logger.Sugar().Infow("Replaying state",
+		zap.Uint64("startBlock", cfg.StartBlock),
+		zap.Uint64("endBlock", cfg.EndBlock),
+		zap.Strings("models", modelNames),
+	)
+
+	result := &ReplayResult{
+		Mismatches: make([]*StateRootMismatch, 0),
+	}
+	startTime := time.Now()
+
+	for batchStart := cfg.StartBlock; batchStart <= cfg.EndBlock; batchStart += replayBlockBatchSize {
+		batchEnd := min(batchStart+replayBlockBatchSize-1, cfg.EndBlock)
+
+		blocks, logsByBlock, err := r.loadBlocksAndLogs(batchStart, batchEnd)
+		if err != nil {
+			return result, err
+		}
+
+		for _, block := range blocks {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			logs := logsByBlock[block.Number]
+			mismatch, err := r.replayBlock(block, logs, models)
+			if err != nil {
+				r.logger.Sugar().Errorw("Failed to replay block", zap.Uint64("blockNumber", block.Number), zap.Error(err))
+				return result, err
+			}
+			result.BlocksReplayed++
+			result.LogsReplayed += uint64(len(logs))
+			if mismatch != nil {
+				r.logger.Sugar().Errorw("State root mismatch",
+					zap.Uint64("blockNumber", mismatch.BlockNumber),
+					zap.String("storedStateRoot", mismatch.StoredStateRoot),
+					zap.String("replayedStateRoot", mismatch.ReplayedStateRoot),
+				)
+				result.Mismatches = append(result.Mismatches, mismatch)
+			}
+		}
+
+		r.logger.Sugar().Infow("Replayed blocks",
+			zap.Uint64("startBlock", batchStart),
+			zap.Uint64("endBlock", batchEnd),
+			zap.Uint64("blocksRemaining", cfg.EndBlock-batchEnd),
+			zap.Int("mismatches", len(result.Mismatches)),
+			zap.Int64("elapsedMs", time.Since(startTime).Milliseconds()),
+		)
+	}
+	return result, nil
+}
+
+// loadBlocksAndLogs loads the stored blocks in the range along with their transaction logs, in the same order
+// that they were originally processed in.
+func (r *Replayer) loadBlocksAndLogs(startBlock uint64, endBlock uint64) ([]*storage.Block, map[uint64][]*storage.TransactionLog, error) {
+	blocks := make([]*storage.Block, 0)
+	res := r.db.Model(&storage.Block{}).
+		Where("number >= ? and number <= ?", startBlock, endBlock).
+		Order("number asc").
+		Find(&blocks)
+	if res.Error != nil {
+		r.logger.Sugar().Errorw("Failed to load blocks", zap.Error(res.Error))
+		return nil, nil, res.Error
+	}
+
+	logs := make([]*storage.TransactionLog, 0)
+	res = r.db.Model(&storage.TransactionLog{}).
+		Where("block_number >= ? and block_number <= ?", startBlock, endBlock).
+		Order("block_number asc, transaction_index asc, log_index asc").
+		Find(&logs)
+	if res.Error != nil {
+		r.logger.Sugar().Errorw("Failed to load transaction logs", zap.Error(res.Error))
+		return nil, nil, res.Error
+	}
+
+	logsByBlock := make(map[uint64][]*storage.TransactionLog)
+	for _, log := range logs {
+		logsByBlock[log.BlockNumber] = append(logsByBlock[log.BlockNumber], log)
+	}
+	return blocks, logsByBlock, nil
+}
+
+func (r *Replayer) replayBlock(
+	block *storage.Block,
+	logs []*storage.TransactionLog,
+	models map[int]types.IEigenStateModel,
+) (*StateRootMismatch, error) {
+	blockNumber := block.Number
+
+	r.stateManager.LockProcessing()
+	defer r.stateManager.UnlockProcessing()
+
+	if err := r.stateManager.InitProcessingForBlock(blockNumber); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.stateManager.CleanupProcessedStateForBlock(blockNumber)
+	}()
+
+	if err := r.handleLogs(blockNumber, logs); err != nil {
+		return nil, err
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, index := range r.stateManager.GetSortedModelIndexes() {
+			model, ok := models[index]
+			if !ok {
+				continue
+			}
+			if err := model.DeleteState(blockNumber, blockNumber, tx); err != nil {
+				r.logger.Sugar().Errorw("Failed to delete state for model",
+					zap.String("model", model.GetModelName()),
+					zap.Uint64("blockNumber", blockNumber),
+					zap.Error(err),
+				)
+				return err
+			}
+			if err := model.CommitFinalState(blockNumber, tx); err != nil {
+				r.logger.Sugar().Errorw("Failed to commit final state",
+					zap.String("model", model.GetModelName()),
+					zap.Uint64("blockNumber", blockNumber),
+					zap.Error(err),
+				)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stateRoot, err := r.stateManager.GenerateStateRoot(blockNumber, block.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatch := &StateRootMismatch{
+		BlockNumber:       blockNumber,
+		BlockHash:         block.Hash,
+		ReplayedStateRoot: string(stateRoot),
+	}
+	storedRoot, err := r.stateManager.GetStateRootForBlock(blockNumber)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return mismatch, nil
+		}
+		return nil, err
+	}
+	if !strings.EqualFold(storedRoot.StateRoot, string(stateRoot)) {
+		mismatch.StoredStateRoot = storedRoot.StateRoot
+		return mismatch, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,213 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/operatorShares"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stakerShares"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setup() (
+	string,
+	*gorm.DB,
+	*zap.Logger,
+	*config.Config,
+	error,
+) {
+	cfg := config.NewConfig()
+	cfg.Chain = config.Chain_Mainnet
+	cfg.Debug = false
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, grm, l, cfg, nil
+}
+
+func Test_Replay(t *testing.T) {
+	dbName, grm, l, cfg, err := setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm := stateManager.NewEigenStateManager(l, grm)
+	if err := eigenState.LoadEigenStateModels(sm, grm, l, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	startBlock := uint64(100)
+	endBlock := uint64(102)
+
+	// Store a few blocks without any logs along with the state root that the pipeline would have generated
+	for blockNumber := startBlock; blockNumber <= endBlock; blockNumber++ {
+		block := &storage.Block{
+			Number:    blockNumber,
+			Hash:      fmt.Sprintf("0x%064x", blockNumber),
+			BlockTime: time.Unix(int64(1700000000+blockNumber*12), 0),
+		}
+		if res := grm.Model(&storage.Block{}).Create(block); res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		if err := sm.InitProcessingForBlock(blockNumber); err != nil {
+			t.Fatal(err)
+		}
+		root, err := sm.GenerateStateRoot(blockNumber, block.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sm.WriteStateRoot(blockNumber, block.Hash, root, grm); err != nil {
+			t.Fatal(err)
+		}
+		_ = sm.CleanupProcessedStateForBlock(blockNumber)
+	}
+
+	t.Run("Should replay blocks and match the stored state roots", func(t *testing.T) {
+		r := NewReplayer(sm, grm, l)
+		result, err := r.Replay(context.Background(), &ReplayConfig{
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), result.BlocksReplayed)
+		assert.Equal(t, 0, len(result.Mismatches))
+	})
+	t.Run("Should rebuild state from state-changing logs", func(t *testing.T) {
+		blockNumber := uint64(101)
+		blockHash := fmt.Sprintf("0x%064x", blockNumber)
+
+		transaction := &storage.Transaction{
+			BlockNumber:      blockNumber,
+			TransactionHash:  "0x07d0052ceff59634f64853b2bf716717096d74623f0294fda3bbf895d4c0c2df",
+			TransactionIndex: 134,
+			FromAddress:      "0x858646372cc42e1a627fce94aa7a7033e7cf075a",
+		}
+		if res := grm.Model(&storage.Transaction{}).Create(transaction); res.Error != nil {
+			t.Fatal(res.Error)
+		}
+		log := &storage.TransactionLog{
+			TransactionHash:  transaction.TransactionHash,
+			TransactionIndex: transaction.TransactionIndex,
+			BlockNumber:      blockNumber,
+			Address:          cfg.GetContractsMapForChain().DelegationManager,
+			Arguments:        `[{"Name": "operator", "Type": "address", "Value": "0x32f766cf7BC7dEE7F65573587BECd7AdB2a5CC7f", "Indexed": true}, {"Name": "staker", "Type": "address", "Value": null, "Indexed": false}, {"Name": "strategy", "Type": "address", "Value": null, "Indexed": false}, {"Name": "shares", "Type": "uint256", "Value": null, "Indexed": false}]`,
+			EventName:        "OperatorSharesIncreased",
+			LogIndex:         279,
+			OutputData:       `{"shares": 2625783258116897034, "staker": "0x269df236ae8bd066e9de7670a7cbfd8cbafd11c2", "strategy": "0x13760f50a9d7377e4f20cb8cf9e4c26586c658ff"}`,
+		}
+		if res := grm.Model(&storage.TransactionLog{}).Create(log); res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		// Process the log the way the pipeline would and store the resulting state root
+		if err := sm.InitProcessingForBlock(blockNumber); err != nil {
+			t.Fatal(err)
+		}
+		if err := sm.HandleLogStateChange(log); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sm.CommitFinalState(blockNumber, grm); err != nil {
+			t.Fatal(err)
+		}
+		root, err := sm.GenerateStateRoot(blockNumber, blockHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = sm.CleanupProcessedStateForBlock(blockNumber)
+		res := grm.Model(&stateManager.StateRoot{}).Where("eth_block_number = ?", blockNumber).Update("state_root", string(root))
+		assert.Nil(t, res.Error)
+
+		countDeltas := func() int64 {
+			var count int64
+			res := grm.Table("operator_share_deltas").Where("block_number = ?", blockNumber).Count(&count)
+			assert.Nil(t, res.Error)
+			return count
+		}
+		assert.Equal(t, int64(1), countDeltas())
+
+		// Corrupt the committed state so that the replay has to rebuild it
+		res = grm.Exec("update operator_share_deltas set shares = '1' where block_number = ?", blockNumber)
+		assert.Nil(t, res.Error)
+
+		r := NewReplayer(sm, grm, l)
+		result, err := r.Replay(context.Background(), &ReplayConfig{
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+			Models:     []string{operatorShares.OperatorSharesModelName},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), result.BlocksReplayed)
+		assert.Equal(t, uint64(1), result.LogsReplayed)
+		assert.Equal(t, 0, len(result.Mismatches))
+
+		var shares string
+		res = grm.Raw("select shares from operator_share_deltas where block_number = ?", blockNumber).Scan(&shares)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, "2625783258116897034", shares)
+		assert.Equal(t, int64(1), countDeltas())
+	})
+	t.Run("Should leave the state of blocks that were not replayed untouched", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r := NewReplayer(sm, grm, l)
+		result, err := r.Replay(ctx, &ReplayConfig{
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+			Models:     []string{operatorShares.OperatorSharesModelName},
+		})
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, uint64(0), result.BlocksReplayed)
+
+		var count int64
+		res := grm.Table("operator_share_deltas").Where("block_number = ?", 101).Count(&count)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, int64(1), count)
+	})
+	t.Run("Should report a mismatched state root", func(t *testing.T) {
+		res := grm.Model(&stateManager.StateRoot{}).Where("eth_block_number = ?", 101).Update("state_root", "0xbad")
+		assert.Nil(t, res.Error)
+
+		r := NewReplayer(sm, grm, l)
+		result, err := r.Replay(context.Background(), &ReplayConfig{
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+			Models:     []string{stakerShares.StakerSharesModelName},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result.Mismatches))
+		assert.Equal(t, uint64(101), result.Mismatches[0].BlockNumber)
+		assert.Equal(t, "0xbad", result.Mismatches[0].StoredStateRoot)
+	})
+	t.Run("Should fail for an unknown model", func(t *testing.T) {
+		r := NewReplayer(sm, grm, l)
+		_, err := r.Replay(context.Background(), &ReplayConfig{
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+			Models:     []string{"NotAModel"},
+		})
+		assert.NotNil(t, err)
+	})
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,233 @@
+package rewards
+
+import (
+	"database/sql"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"go.uber.org/zap"
+)
+
+var _11_goldActiveODOperatorSetRewardsQuery = `
+CREATE TABLE {{.destTableName}} AS
+WITH 
+-- Step 2: Modify active rewards and compute tokens per day
+active_rewards_modified AS (
+    SELECT 
+        *,
+        CAST(@cutoffDate AS TIMESTAMP(6)) AS global_end_inclusive -- Inclusive means we DO USE this day as a snapshot
+    FROM operator_directed_operator_set_rewards
+    -- Rewards that ended before rewardsStart and were submitted by then were paid out in full by the previous snapshot
+    WHERE (end_timestamp >= TIMESTAMP '{{.rewardsStart}}' OR block_time > TIMESTAMP '{{.rewardsStart}}')
+      AND start_timestamp <= TIMESTAMP '{{.cutoffDate}}'
+      AND block_time <= TIMESTAMP '{{.cutoffDate}}' -- Always ensure we're not using future data. Should never happen since we're never backfilling, but here for safety and consistency.
+),
+
+-- Step 3: Cut each reward's start and end windows to handle the global range
+active_rewards_updated_end_timestamps AS (
+    SELECT
+        avs,
+        operator_set_id,
+        operator,
+        /**
+         * Cut the start and end windows to handle
+         * A. Retroactive rewards that came recently whose start date is less than start_timestamp
+         * B. Don't make any rewards past end_timestamp for this run
+         */
+        start_timestamp AS reward_start_exclusive,
+        LEAST(global_end_inclusive, end_timestamp) AS reward_end_inclusive,
+        amount,
+        token,
+        multiplier,
+        strategy,
+        reward_hash,
+        duration,
+        global_end_inclusive,
+        block_date AS reward_submission_date
+    FROM active_rewards_modified
+),
+
+-- Step 4: For each reward hash, find the latest snapshot
+active_rewards_updated_start_timestamps AS (
+    SELECT
+        ap.avs,
+        ap.operator_set_id,
+        ap.operator,
+        COALESCE(MAX(g.snapshot), ap.reward_start_exclusive) AS reward_start_exclusive,
+        ap.reward_end_inclusive,
+        ap.token,
+        -- We use floor to ensure we are always underestimating total tokens per day
+        FLOOR(ap.amount) AS amount_decimal,
+        ap.multiplier,
+        ap.strategy,
+        ap.reward_hash,
+        ap.duration,
+        ap.global_end_inclusive,
+        ap.reward_submission_date
+    FROM active_rewards_updated_end_timestamps ap
+    LEFT JOIN gold_table g 
+        ON g.reward_hash = ap.reward_hash
+    GROUP BY 
+        ap.avs, 
+        ap.operator_set_id,
+        ap.operator, 
+        ap.reward_end_inclusive, 
+        ap.token, 
+        ap.amount,
+        ap.multiplier, 
+        ap.strategy, 
+        ap.reward_hash, 
+        ap.duration,
+        ap.global_end_inclusive, 
+        ap.reward_start_exclusive, 
+        ap.reward_submission_date
+),
+
+-- Step 5: Filter out invalid reward ranges
+active_reward_ranges AS (
+    /** Take out (reward_start_exclusive, reward_end_inclusive) windows where
+	* 1. reward_start_exclusive >= reward_end_inclusive: The reward period is done or we will handle on a subsequent run
+	*/
+    SELECT * 
+    FROM active_rewards_updated_start_timestamps
+    WHERE reward_start_exclusive < reward_end_inclusive
+),
+
+-- Step 6: Explode out the ranges for a day per inclusive date
+exploded_active_range_rewards AS (
+    SELECT
+        *
+    FROM active_reward_ranges
+    CROSS JOIN generate_series(
+        DATE(reward_start_exclusive), 
+        DATE(reward_end_inclusive), 
+        INTERVAL '1' DAY
+    ) AS day
+),
+
+-- Step 7: Prepare cleaned active rewards
+active_rewards_cleaned AS (
+    SELECT
+        avs,
+        operator_set_id,
+        operator,
+        CAST(day AS DATE) AS snapshot,
+        token,
+        amount_decimal,
+        multiplier,
+        strategy,
+        duration,
+        reward_hash,
+        reward_submission_date
+    FROM exploded_active_range_rewards
+    -- Remove snapshots on the start day
+    WHERE day != reward_start_exclusive
+),
+
+-- Step 8: Find the snapshots where the operator was registered to the operator set and at least one of the
+-- reward's strategies was registered to the operator set
+registered_operator_snapshots AS (
+    SELECT DISTINCT
+        ar.reward_hash,
+        ar.operator,
+        ar.snapshot
+    FROM active_rewards_cleaned ar
+    JOIN operator_set_operator_registration_snapshots osor
+    ON
+        ar.avs = osor.avs
+        AND ar.operator_set_id = osor.operator_set_id
+        AND ar.snapshot = osor.snapshot
+        AND ar.operator = osor.operator
+    JOIN operator_set_strategy_registration_snapshots ossr
+    ON
+        ar.avs = ossr.avs
+        AND ar.operator_set_id = ossr.operator_set_id
+        AND ar.snapshot = ossr.snapshot
+        AND ar.strategy = ossr.strategy
+),
+
+-- Step 9: Count the number of snapshots that the operator was registered
+op_set_num_registered_snapshots AS (
+    SELECT
+        reward_hash,
+        operator,
+        COUNT(*) AS num_registered_snapshots
+    FROM registered_operator_snapshots
+    GROUP BY reward_hash, operator
+),
+
+-- Step 10: Divide amount to pay by the number of snapshots that the operator was registered
+active_rewards_with_registered_snapshots AS (
+    SELECT
+        arc.*,
+        COALESCE(nrs.num_registered_snapshots, 0) as num_registered_snapshots
+    FROM active_rewards_cleaned arc
+    LEFT JOIN op_set_num_registered_snapshots nrs
+    ON
+        arc.reward_hash = nrs.reward_hash
+        AND arc.operator = nrs.operator
+),
+
+-- Step 11: Divide amount to pay by the number of snapshots that the operator was registered
+active_rewards_final AS (
+    SELECT
+        ar.*,
+        CASE
+            -- If the operator was not registered for any snapshots, just get regular tokens per day to refund the AVS
+            WHEN ar.num_registered_snapshots = 0 THEN floor(ar.amount_decimal / (duration / 86400))
+            ELSE floor(ar.amount_decimal / ar.num_registered_snapshots)
+        END AS tokens_per_registered_snapshot_decimal
+    FROM active_rewards_with_registered_snapshots ar
+)
+
+SELECT * FROM active_rewards_final
+`
+
+// Generate11ActiveODOperatorSetRewards generates active operator-directed operator set rewards for the
+// gold_11_active_od_operator_set_rewards table.
+//
+// A snapshot only counts towards an operator's share of a reward if the operator was registered to the operator set
+// and at least one of the reward's strategies was registered to the operator set on that snapshot.
+//
+// If startDate is provided, rewards that were paid out in full by the last completed snapshot are skipped.
+func (r *RewardsCalculator) Generate11ActiveODOperatorSetRewards(snapshotDate string, startDate string) error {
+	rewardsV2_1Enabled, err := r.globalConfig.IsRewardsV2_1EnabledForCutoffDate(snapshotDate)
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to check if rewards v2.1 is enabled", "error", err)
+		return err
+	}
+	if !rewardsV2_1Enabled {
+		r.logger.Sugar().Infow("Rewards v2.1 is not enabled for this cutoff date, skipping Generate11ActiveODOperatorSetRewards")
+		return nil
+	}
+
+	allTableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
+	destTableName := allTableNames[rewardsUtils.Table_11_ActiveODOperatorSetRewards]
+
+	// The start of each reward is updated later in the query from the snapshots already in the gold table
+	rewardsStart := snapshotTablesStartDate(startDate)
+
+	r.logger.Sugar().Infow("Generating active operator set rewards",
+		zap.String("rewardsStart", rewardsStart),
+		zap.String("cutoffDate", snapshotDate),
+		zap.String("destTableName", destTableName),
+	)
+
+	query, err := rewardsUtils.RenderQueryTemplate(_11_goldActiveODOperatorSetRewardsQuery, map[string]interface{}{
+		"destTableName": destTableName,
+		"rewardsStart":  rewardsStart,
+		"cutoffDate":    snapshotDate,
+	})
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to render query template", "error", err)
+		return err
+	}
+
+	res := r.grm.Exec(query,
+		sql.Named("cutoffDate", snapshotDate),
+	)
+	if res.Error != nil {
+		r.logger.Sugar().Errorw("Failed to generate active od operator set rewards", "error", res.Error)
+		return res.Error
+	}
+	return nil
+}
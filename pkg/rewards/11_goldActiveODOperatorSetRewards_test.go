@@ -0,0 +1,128 @@
+package rewards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// hydrateOperatorSetRewards inserts a reward submitted to operator set 1 of 0xavs for 2025-03-02 and 2025-03-03,
+// along with the snapshots that the operator set reward stages read:
+//   - 0xoperator_1 is only registered to the operator set on 2025-03-02, with a 20% split
+//   - 0xoperator_2 is never registered to the operator set, so its reward is refunded to the AVS
+//   - 0xstrategy_2 is never registered to the operator set, so it doesn't count towards staker weights
+func hydrateOperatorSetRewards(grm *gorm.DB, l *zap.Logger) error {
+	res := grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)})
+	if res.Error != nil {
+		l.Sugar().Errorw("Failed to insert block", "error", zap.Error(res.Error))
+		return res.Error
+	}
+
+	queries := []string{
+		`INSERT INTO operator_directed_operator_set_reward_submissions (avs, operator_set_id, reward_hash, token, operator, operator_index, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, block_number, transaction_hash, log_index)
+		VALUES
+			('0xavs', 1, '0xreward', '0xtoken', '0xoperator_1', 0, 2000000, '0xstrategy_1', 0, 1000000000000000000, '2025-03-01', '2025-03-03', 172800, 1, '0xtx', 0),
+			('0xavs', 1, '0xreward', '0xtoken', '0xoperator_1', 0, 2000000, '0xstrategy_2', 1, 2000000000000000000, '2025-03-01', '2025-03-03', 172800, 1, '0xtx', 0),
+			('0xavs', 1, '0xreward', '0xtoken', '0xoperator_2', 1, 1000000, '0xstrategy_1', 0, 1000000000000000000, '2025-03-01', '2025-03-03', 172800, 1, '0xtx', 0),
+			('0xavs', 1, '0xreward', '0xtoken', '0xoperator_2', 1, 1000000, '0xstrategy_2', 1, 2000000000000000000, '2025-03-01', '2025-03-03', 172800, 1, '0xtx', 0)`,
+		`INSERT INTO operator_set_operator_registration_snapshots (operator, avs, operator_set_id, snapshot)
+		VALUES ('0xoperator_1', '0xavs', 1, '2025-03-02')`,
+		`INSERT INTO operator_set_strategy_registration_snapshots (strategy, avs, operator_set_id, snapshot)
+		VALUES ('0xstrategy_1', '0xavs', 1, '2025-03-02'), ('0xstrategy_1', '0xavs', 1, '2025-03-03')`,
+		`INSERT INTO operator_set_split_snapshots (operator, avs, operator_set_id, split, snapshot)
+		VALUES ('0xoperator_1', '0xavs', 1, 2000, '2025-03-02')`,
+		`INSERT INTO staker_delegation_snapshots (staker, operator, snapshot)
+		VALUES ('0xstaker_1', '0xoperator_1', '2025-03-02'), ('0xstaker_2', '0xoperator_1', '2025-03-02')`,
+		`INSERT INTO staker_share_snapshots (staker, strategy, shares, snapshot)
+		VALUES
+			('0xstaker_1', '0xstrategy_1', 1000000000000000000, '2025-03-02'),
+			('0xstaker_1', '0xstrategy_2', 5000000000000000000, '2025-03-02'),
+			('0xstaker_2', '0xstrategy_1', 3000000000000000000, '2025-03-02')`,
+	}
+	for _, query := range queries {
+		res := grm.Exec(query)
+		if res.Error != nil {
+			l.Sugar().Errorw("Failed to execute sql", "error", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func Test_Gold11ActiveODOperatorSetRewards(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-05"
+
+	rewards, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetRewards(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rewards.GenerateAndInsertOperatorDirectedOperatorSetRewards(snapshotDate)
+		assert.Nil(t, err)
+
+		var count int
+		res := grm.Raw(`select count(*) from operator_directed_operator_set_rewards`).Scan(&count)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, 4, count)
+	})
+
+	t.Run("Should split rewards over the snapshots the operator was registered for", func(t *testing.T) {
+		err := rewards.Generate11ActiveODOperatorSetRewards(snapshotDate, "")
+		assert.Nil(t, err)
+
+		type activeReward struct {
+			Operator                           string
+			Strategy                           string
+			Snapshot                           string
+			TokensPerRegisteredSnapshotDecimal string
+			NumRegisteredSnapshots             uint64
+		}
+		var activeRewards []activeReward
+		tableName := rewardsUtils.GetGoldTableNames(snapshotDate)[rewardsUtils.Table_11_ActiveODOperatorSetRewards]
+		res := grm.Raw(`
+			select
+				operator,
+				strategy,
+				to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+				tokens_per_registered_snapshot_decimal::text as tokens_per_registered_snapshot_decimal,
+				num_registered_snapshots
+			from ` + tableName + `
+			order by operator, strategy, snapshot
+		`).Scan(&activeRewards)
+		assert.Nil(t, res.Error)
+
+		// 0xoperator_1 is paid its whole amount for the one snapshot it was registered for, while the amount of
+		// 0xoperator_2 is spread over the duration of the reward to be refunded
+		assert.Equal(t, []activeReward{
+			{"0xoperator_1", "0xstrategy_1", "2025-03-02", "2000000", 1},
+			{"0xoperator_1", "0xstrategy_1", "2025-03-03", "2000000", 1},
+			{"0xoperator_1", "0xstrategy_2", "2025-03-02", "2000000", 1},
+			{"0xoperator_1", "0xstrategy_2", "2025-03-03", "2000000", 1},
+			{"0xoperator_2", "0xstrategy_1", "2025-03-02", "500000", 0},
+			{"0xoperator_2", "0xstrategy_1", "2025-03-03", "500000", 0},
+			{"0xoperator_2", "0xstrategy_2", "2025-03-02", "500000", 0},
+			{"0xoperator_2", "0xstrategy_2", "2025-03-03", "500000", 0},
+		}, activeRewards)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,67 @@
+package rewards
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Gold12OperatorODOperatorSetRewardAmounts(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-05"
+
+	rewards, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetRewards(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rewards.GenerateAndInsertOperatorDirectedOperatorSetRewards(snapshotDate)
+		assert.Nil(t, err)
+
+		err = rewards.Generate11ActiveODOperatorSetRewards(snapshotDate, "")
+		assert.Nil(t, err)
+	})
+
+	t.Run("Should pay operators their split once per registered snapshot", func(t *testing.T) {
+		err := rewards.GenerateGold12OperatorODOperatorSetRewardAmountsTable(snapshotDate)
+		assert.Nil(t, err)
+
+		type operatorAmount struct {
+			Operator       string
+			Snapshot       string
+			OperatorTokens string
+		}
+		var operatorAmounts []operatorAmount
+		tableName := rewardsUtils.GetGoldTableNames(snapshotDate)[rewardsUtils.Table_12_OperatorODOperatorSetRewardAmounts]
+		res := grm.Raw(`
+			select
+				operator,
+				to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+				operator_tokens::text as operator_tokens
+			from ` + tableName + `
+			order by operator, snapshot
+		`).Scan(&operatorAmounts)
+		assert.Nil(t, res.Error)
+
+		// the 20% operator set split applies instead of the default 10%
+		assert.Equal(t, []operatorAmount{
+			{"0xoperator_1", "2025-03-02", "400000"},
+		}, operatorAmounts)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
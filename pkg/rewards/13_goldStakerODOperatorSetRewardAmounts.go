@@ -0,0 +1,160 @@
+package rewards
+
+import (
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"go.uber.org/zap"
+)
+
+const _13_goldStakerODOperatorSetRewardAmountsQuery = `
+CREATE TABLE {{.destTableName}} AS
+
+-- Step 1: Get the rows where operators and the reward strategies have registered to the operator set.
+-- Strategies that are not registered to the operator set do not count towards staker weights.
+WITH reward_snapshot_operators AS (
+    SELECT
+        ap.reward_hash,
+        ap.snapshot AS snapshot,
+        ap.token,
+        ap.tokens_per_registered_snapshot_decimal,
+        ap.avs AS avs,
+        ap.operator_set_id,
+        ap.operator AS operator,
+        ap.strategy,
+        ap.multiplier,
+        ap.reward_submission_date
+    FROM {{.activeODRewardsTable}} ap
+    JOIN operator_set_operator_registration_snapshots osor
+        ON ap.avs = osor.avs
+       AND ap.operator_set_id = osor.operator_set_id
+       AND ap.snapshot = osor.snapshot
+       AND ap.operator = osor.operator
+    JOIN operator_set_strategy_registration_snapshots ossr
+        ON ap.avs = ossr.avs
+       AND ap.operator_set_id = ossr.operator_set_id
+       AND ap.snapshot = ossr.snapshot
+       AND ap.strategy = ossr.strategy
+),
+
+-- Calculate the total staker split for each operator reward with dynamic split logic
+-- If no operator set split is found, fall back to the default split and then to 1000 (10%)
+staker_splits AS (
+    SELECT 
+        rso.*,
+        rso.tokens_per_registered_snapshot_decimal - FLOOR(rso.tokens_per_registered_snapshot_decimal * COALESCE(oss.split, dos.split, 1000) / CAST(10000 AS DECIMAL)) AS staker_split
+    FROM reward_snapshot_operators rso
+    LEFT JOIN operator_set_split_snapshots oss
+        ON rso.operator = oss.operator 
+       AND rso.avs = oss.avs 
+       AND rso.operator_set_id = oss.operator_set_id
+       AND rso.snapshot = oss.snapshot
+    LEFT JOIN default_operator_split_snapshots dos ON (rso.snapshot = dos.snapshot)
+),
+-- Get the stakers that were delegated to the operator for the snapshot
+staker_delegated_operators AS (
+    SELECT
+        ors.*,
+        sds.staker
+    FROM staker_splits ors
+    JOIN staker_delegation_snapshots sds
+        ON ors.operator = sds.operator 
+       AND ors.snapshot = sds.snapshot
+),
+
+-- Get the shares for stakers delegated to the operator
+staker_avs_strategy_shares AS (
+    SELECT
+        sdo.*,
+        sss.shares
+    FROM staker_delegated_operators sdo
+    JOIN staker_share_snapshots sss
+        ON sdo.staker = sss.staker 
+       AND sdo.snapshot = sss.snapshot 
+       AND sdo.strategy = sss.strategy
+    -- Filter out negative shares and zero multiplier to avoid division by zero
+    WHERE sss.shares > 0 AND sdo.multiplier != 0
+),
+
+-- Calculate the weight of each staker
+staker_weights AS (
+    SELECT 
+        *,
+        SUM(multiplier * shares) OVER (PARTITION BY staker, reward_hash, snapshot) AS staker_weight
+    FROM staker_avs_strategy_shares
+),
+-- Get distinct stakers since their weights are already calculated
+distinct_stakers AS (
+    SELECT *
+    FROM (
+        SELECT 
+            *,
+            -- We can use an arbitrary order here since the staker_weight is the same for each (staker, strategy, hash, snapshot)
+            -- We use strategy ASC for better debuggability
+            ROW_NUMBER() OVER (
+                PARTITION BY reward_hash, snapshot, staker 
+                ORDER BY strategy ASC
+            ) AS rn
+        FROM staker_weights
+    ) t
+    WHERE rn = 1
+    ORDER BY reward_hash, snapshot, staker
+),
+-- Calculate the sum of all staker weights for each reward and snapshot
+staker_weight_sum AS (
+    SELECT 
+        *,
+        SUM(staker_weight) OVER (PARTITION BY reward_hash, operator, snapshot) AS total_weight
+    FROM distinct_stakers
+),
+-- Calculate staker proportion of tokens for each reward and snapshot
+staker_proportion AS (
+    SELECT 
+        *,
+        FLOOR((staker_weight / total_weight) * 1000000000000000) / 1000000000000000 AS staker_proportion
+    FROM staker_weight_sum
+),
+-- Calculate the staker reward amounts
+staker_reward_amounts AS (
+    SELECT 
+        *,
+        FLOOR(staker_proportion * staker_split) AS staker_tokens
+    FROM staker_proportion
+)
+-- Output the final table
+SELECT * FROM staker_reward_amounts
+`
+
+func (rc *RewardsCalculator) GenerateGold13StakerODOperatorSetRewardAmountsTable(snapshotDate string) error {
+	rewardsV2_1Enabled, err := rc.globalConfig.IsRewardsV2_1EnabledForCutoffDate(snapshotDate)
+	if err != nil {
+		rc.logger.Sugar().Errorw("Failed to check if rewards v2.1 is enabled", "error", err)
+		return err
+	}
+	if !rewardsV2_1Enabled {
+		rc.logger.Sugar().Infow("Rewards v2.1 is not enabled for this cutoff date, skipping GenerateGold13StakerODOperatorSetRewardAmountsTable")
+		return nil
+	}
+
+	allTableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
+	destTableName := allTableNames[rewardsUtils.Table_13_StakerODOperatorSetRewardAmounts]
+
+	rc.logger.Sugar().Infow("Generating Staker OD operator set reward amounts",
+		zap.String("cutoffDate", snapshotDate),
+		zap.String("destTableName", destTableName),
+	)
+
+	query, err := rewardsUtils.RenderQueryTemplate(_13_goldStakerODOperatorSetRewardAmountsQuery, map[string]interface{}{
+		"destTableName":        destTableName,
+		"activeODRewardsTable": allTableNames[rewardsUtils.Table_11_ActiveODOperatorSetRewards],
+	})
+	if err != nil {
+		rc.logger.Sugar().Errorw("Failed to render query template", "error", err)
+		return err
+	}
+
+	res := rc.grm.Exec(query)
+	if res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to create gold_staker_od_operator_set_reward_amounts", "error", res.Error)
+		return res.Error
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package rewards
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Gold13StakerODOperatorSetRewardAmounts(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-05"
+
+	rewards, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetRewards(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rewards.GenerateAndInsertOperatorDirectedOperatorSetRewards(snapshotDate)
+		assert.Nil(t, err)
+
+		err = rewards.Generate11ActiveODOperatorSetRewards(snapshotDate, "")
+		assert.Nil(t, err)
+	})
+
+	t.Run("Should weigh stakers by their shares in the registered strategies", func(t *testing.T) {
+		err := rewards.GenerateGold13StakerODOperatorSetRewardAmountsTable(snapshotDate)
+		assert.Nil(t, err)
+
+		type stakerAmount struct {
+			Staker       string
+			Strategy     string
+			Snapshot     string
+			StakerTokens string
+		}
+		var stakerAmounts []stakerAmount
+		tableName := rewardsUtils.GetGoldTableNames(snapshotDate)[rewardsUtils.Table_13_StakerODOperatorSetRewardAmounts]
+		res := grm.Raw(`
+			select
+				staker,
+				strategy,
+				to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+				staker_tokens::text as staker_tokens
+			from ` + tableName + `
+			order by staker, strategy, snapshot
+		`).Scan(&stakerAmounts)
+		assert.Nil(t, res.Error)
+
+		// the shares of 0xstaker_1 in 0xstrategy_2 are left out since the strategy isn't registered to the operator set
+		assert.Equal(t, []stakerAmount{
+			{"0xstaker_1", "0xstrategy_1", "2025-03-02", "400000"},
+			{"0xstaker_2", "0xstrategy_1", "2025-03-02", "1200000"},
+		}, stakerAmounts)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
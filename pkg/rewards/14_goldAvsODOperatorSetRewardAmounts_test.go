@@ -0,0 +1,70 @@
+package rewards
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Gold14AvsODOperatorSetRewardAmounts(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-05"
+
+	rewards, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetRewards(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rewards.GenerateAndInsertOperatorDirectedOperatorSetRewards(snapshotDate)
+		assert.Nil(t, err)
+
+		err = rewards.Generate11ActiveODOperatorSetRewards(snapshotDate, "")
+		assert.Nil(t, err)
+	})
+
+	t.Run("Should refund the rewards of operators that were never registered to the operator set", func(t *testing.T) {
+		err := rewards.GenerateGold14AvsODOperatorSetRewardAmountsTable(snapshotDate)
+		assert.Nil(t, err)
+
+		type avsAmount struct {
+			Avs       string
+			Operator  string
+			Snapshot  string
+			AvsTokens string
+		}
+		var avsAmounts []avsAmount
+		tableName := rewardsUtils.GetGoldTableNames(snapshotDate)[rewardsUtils.Table_14_AvsODOperatorSetRewardAmounts]
+		res := grm.Raw(`
+			select
+				avs,
+				operator,
+				to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+				avs_tokens::text as avs_tokens
+			from ` + tableName + `
+			order by operator, snapshot
+		`).Scan(&avsAmounts)
+		assert.Nil(t, res.Error)
+
+		// refunded once per snapshot rather than once per strategy
+		assert.Equal(t, []avsAmount{
+			{"0xavs", "0xoperator_2", "2025-03-02", "500000"},
+			{"0xavs", "0xoperator_2", "2025-03-03", "500000"},
+		}, avsAmounts)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -14,7 +14,8 @@ WITH active_rewards_modified as (
            cast(@cutoffDate AS TIMESTAMP(6)) as global_end_inclusive -- Inclusive means we DO USE this day as a snapshot
     FROM combined_rewards
         WHERE
-    		end_timestamp >= TIMESTAMP '{{.rewardsStart}}'
+    		-- Rewards that ended before rewardsStart and were submitted by then were paid out in full by the previous snapshot
+    		(end_timestamp >= TIMESTAMP '{{.rewardsStart}}' or block_time > TIMESTAMP '{{.rewardsStart}}')
     		and start_timestamp <= TIMESTAMP '{{.cutoffDate}}'
     		and block_time <= TIMESTAMP '{{.cutoffDate}}' -- Always ensure we're not using future data. Should never happen since we're never backfilling, but here for safety and consistency.
 ),
@@ -98,13 +99,14 @@ select * from active_rewards_final
 // Generate1ActiveRewards generates active rewards for the gold_1_active_rewards table
 //
 // @param snapshotDate: The upper bound of when to calculate rewards to
-// @param startDate: The lower bound of when to calculate rewards from. If we're running rewards for the first time,
-// this will be "1970-01-01". If this is a subsequent run, this will be the last snapshot date.
-func (r *RewardsCalculator) Generate1ActiveRewards(snapshotDate string) error {
+// @param startDate: The lower bound of when to calculate rewards from. If we're running rewards from scratch,
+// this will be empty. If this is an incremental run, this will be the last completed snapshot date.
+func (r *RewardsCalculator) Generate1ActiveRewards(snapshotDate string, startDate string) error {
 	allTableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
 	destTableName := allTableNames[rewardsUtils.Table_1_ActiveRewards]
 
-	rewardsStart := "1970-01-01 00:00:00" // This will always start as this date and get's updated later in the query
+	// The start of each reward is updated later in the query from the snapshots already in the gold table
+	rewardsStart := snapshotTablesStartDate(startDate)
 
 	r.logger.Sugar().Infow("Generating active rewards",
 		zap.String("rewardsStart", rewardsStart),
@@ -16,7 +16,8 @@ active_rewards_modified AS (
         *,
         CAST(@cutoffDate AS TIMESTAMP(6)) AS global_end_inclusive -- Inclusive means we DO USE this day as a snapshot
     FROM operator_directed_rewards
-    WHERE end_timestamp >= TIMESTAMP '{{.rewardsStart}}'
+    -- Rewards that ended before rewardsStart and were submitted by then were paid out in full by the previous snapshot
+    WHERE (end_timestamp >= TIMESTAMP '{{.rewardsStart}}' OR block_time > TIMESTAMP '{{.rewardsStart}}')
       AND start_timestamp <= TIMESTAMP '{{.cutoffDate}}'
       AND block_time <= TIMESTAMP '{{.cutoffDate}}' -- Always ensure we're not using future data. Should never happen since we're never backfilling, but here for safety and consistency.
 ),
@@ -169,9 +170,9 @@ SELECT * FROM active_rewards_final
 // Generate7ActiveODRewards generates active operator-directed rewards for the gold_7_active_od_rewards table
 //
 // @param snapshotDate: The upper bound of when to calculate rewards to
-// @param startDate: The lower bound of when to calculate rewards from. If we're running rewards for the first time,
-// this will be "1970-01-01". If this is a subsequent run, this will be the last snapshot date.
-func (r *RewardsCalculator) Generate7ActiveODRewards(snapshotDate string) error {
+// @param startDate: The lower bound of when to calculate rewards from. If we're running rewards from scratch,
+// this will be empty. If this is an incremental run, this will be the last completed snapshot date.
+func (r *RewardsCalculator) Generate7ActiveODRewards(snapshotDate string, startDate string) error {
 	rewardsV2Enabled, err := r.globalConfig.IsRewardsV2EnabledForCutoffDate(snapshotDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to check if rewards v2 is enabled", "error", err)
@@ -185,7 +186,8 @@ func (r *RewardsCalculator) Generate7ActiveODRewards(snapshotDate string) error
 	allTableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
 	destTableName := allTableNames[rewardsUtils.Table_7_ActiveODRewards]
 
-	rewardsStart := "1970-01-01 00:00:00" // This will always start as this date and get's updated later in the query
+	// The start of each reward is updated later in the query from the snapshots already in the gold table
+	rewardsStart := snapshotTablesStartDate(startDate)
 
 	r.logger.Sugar().Infow("Generating active rewards",
 		zap.String("rewardsStart", rewardsStart),
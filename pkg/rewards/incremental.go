@@ -0,0 +1,383 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres/helpers"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// snapshotTablesGenesisDate is the start date used when the snapshot tables are regenerated from scratch
+const snapshotTablesGenesisDate = "1970-01-01"
+
+func snapshotTablesStartDate(startDate string) string {
+	if startDate == "" {
+		return snapshotTablesGenesisDate
+	}
+	return startDate
+}
+
+// SnapshotTableCutoff records the cutoff date that the snapshot tables (staker_share_snapshots, etc) were last
+// generated for. Incremental calculations can only build on the snapshot tables if they were generated for the
+// previous completed snapshot.
+type SnapshotTableCutoff struct {
+	Id         uint64
+	CutoffDate string
+	StartDate  string
+	CreatedAt  time.Time
+}
+
+func (SnapshotTableCutoff) TableName() string {
+	return "rewards_snapshot_table_cutoffs"
+}
+
+type snapshotTable struct {
+	name     string
+	generate func(snapshotDate string, startDate string) error
+}
+
+// incrementalSnapshotTables returns the snapshot tables that have one row per day and can be generated incrementally.
+//
+// Every row of these tables only depends on data from before its snapshot day, so the rows from before the previous
+// cutoff date do not change when the tables are generated for a later cutoff date.
+func (rc *RewardsCalculator) incrementalSnapshotTables() []*snapshotTable {
+	return []*snapshotTable{
+		{name: "operator_avs_registration_snapshots", generate: rc.generateAndInsertOperatorAvsRegistrationSnapshots},
+		{name: "operator_avs_strategy_snapshots", generate: rc.generateAndInsertOperatorAvsStrategySnapshots},
+		{name: "operator_share_snapshots", generate: rc.generateAndInsertOperatorShareSnapshots},
+		{name: "staker_share_snapshots", generate: rc.generateAndInsertStakerShareSnapshots},
+		{name: "staker_delegation_snapshots", generate: rc.generateAndInsertStakerDelegationSnapshots},
+		{name: "operator_avs_split_snapshots", generate: rc.generateAndInsertOperatorAvsSplitSnapshots},
+		{name: "operator_pi_split_snapshots", generate: rc.generateAndInsertOperatorPISplitSnapshots},
+		{name: "default_operator_split_snapshots", generate: rc.generateAndInsertDefaultOperatorSplitSnapshots},
+		{name: "operator_set_operator_registration_snapshots", generate: rc.generateAndInsertOperatorSetOperatorRegistrationSnapshots},
+		{name: "operator_set_strategy_registration_snapshots", generate: rc.generateAndInsertOperatorSetStrategyRegistrationSnapshots},
+		{name: "operator_set_split_snapshots", generate: rc.generateAndInsertOperatorSetSplitSnapshots},
+	}
+}
+
+// generateAndInsertSnapshotsFromQuery replaces the snapshots of the table from startDate onward with the results of
+// the query, keeping the earlier snapshots. If startDate is empty, the whole table is replaced.
+func (rc *RewardsCalculator) generateAndInsertSnapshotsFromQuery(
+	tableName string,
+	query string,
+	variables []interface{},
+	startDate string,
+) error {
+	if startDate == "" {
+		return rc.generateAndInsertFromQuery(tableName, query, variables)
+	}
+
+	_, err := helpers.WrapTxAndCommit(func(tx *gorm.DB) (interface{}, error) {
+		res := tx.Exec(fmt.Sprintf(`delete from %s where snapshot >= @startDate`, tableName), sql.Named("startDate", startDate))
+		if res.Error != nil {
+			rc.logger.Sugar().Errorw("Failed to delete snapshots", "tableName", tableName, "error", res.Error)
+			return nil, res.Error
+		}
+		rc.logger.Sugar().Debugw("Deleted snapshots",
+			zap.String("tableName", tableName),
+			zap.String("startDate", startDate),
+			zap.Int64("recordsDeleted", res.RowsAffected),
+		)
+
+		res = tx.Exec(fmt.Sprintf(`insert into %s %s`, tableName, query), variables...)
+		if res.Error != nil {
+			rc.logger.Sugar().Errorw("Failed to insert snapshots", "tableName", tableName, "error", res.Error)
+			return nil, res.Error
+		}
+		rc.logger.Sugar().Debugw("Inserted snapshots",
+			zap.String("tableName", tableName),
+			zap.String("startDate", startDate),
+			zap.Int64("recordsInserted", res.RowsAffected),
+		)
+		return nil, nil
+	}, rc.grm, nil)
+	return err
+}
+
+// getIncrementalStartDate returns the date from which the snapshot tables need to be generated for the given
+// snapshot date, or an empty string if they need to be generated from scratch.
+//
+// The snapshot tables are seeded from the last completed snapshot before the snapshot date, which is only possible
+// if the snapshot tables were last generated for that snapshot.
+func (rc *RewardsCalculator) getIncrementalStartDate(snapshotDate string) (string, error) {
+	if rc.globalConfig.Rewards.ForceFullCalculation {
+		rc.logger.Sugar().Infow("Full rewards calculation forced", zap.String("snapshotDate", snapshotDate))
+		return "", nil
+	}
+
+	var previousSnapshot *storage.GeneratedRewardsSnapshots
+	res := rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).
+		Where("status = ?", storage.RewardSnapshotStatusCompleted.String()).
+		Where("snapshot_date < ?", snapshotDate).
+		Order("snapshot_date desc").
+		First(&previousSnapshot)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			rc.logger.Sugar().Infow("No previous completed snapshot found, generating snapshot tables from scratch",
+				zap.String("snapshotDate", snapshotDate),
+			)
+			return "", nil
+		}
+		return "", res.Error
+	}
+
+	cutoff, err := rc.getSnapshotTableCutoff()
+	if err != nil {
+		return "", err
+	}
+	if cutoff == nil || cutoff.CutoffDate != previousSnapshot.SnapshotDate {
+		lastCutoffDate := ""
+		if cutoff != nil {
+			lastCutoffDate = cutoff.CutoffDate
+		}
+		rc.logger.Sugar().Infow("Snapshot tables were not generated for the previous completed snapshot, generating snapshot tables from scratch",
+			zap.String("snapshotDate", snapshotDate),
+			zap.String("previousSnapshotDate", previousSnapshot.SnapshotDate),
+			zap.String("snapshotTablesCutoffDate", lastCutoffDate),
+		)
+		return "", nil
+	}
+	return previousSnapshot.SnapshotDate, nil
+}
+
+func (rc *RewardsCalculator) getSnapshotTableCutoff() (*SnapshotTableCutoff, error) {
+	var cutoff *SnapshotTableCutoff
+	res := rc.grm.Model(&SnapshotTableCutoff{}).Order("id desc").First(&cutoff)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, res.Error
+	}
+	return cutoff, nil
+}
+
+// clearSnapshotTableCutoff forgets the cutoff date of the snapshot tables so that the next calculation regenerates
+// them from scratch. It is called before the snapshot tables are modified so that a failed generation is never
+// built upon.
+func (rc *RewardsCalculator) clearSnapshotTableCutoff() error {
+	res := rc.grm.Exec(`delete from rewards_snapshot_table_cutoffs`)
+	return res.Error
+}
+
+func (rc *RewardsCalculator) setSnapshotTableCutoff(cutoffDate string, startDate string) error {
+	_, err := helpers.WrapTxAndCommit(func(tx *gorm.DB) (interface{}, error) {
+		if res := tx.Exec(`delete from rewards_snapshot_table_cutoffs`); res.Error != nil {
+			return nil, res.Error
+		}
+		res := tx.Model(&SnapshotTableCutoff{}).Create(&SnapshotTableCutoff{
+			CutoffDate: cutoffDate,
+			StartDate:  startDate,
+		})
+		return nil, res.Error
+	}, rc.grm, nil)
+	return err
+}
+
+// clearSnapshotTableCutoffFromBlockHeight forgets the cutoff date of the snapshot tables if blocks before it are
+// being deleted, since the snapshot tables may contain data from those blocks.
+func (rc *RewardsCalculator) clearSnapshotTableCutoffFromBlockHeight(blockHeight uint64, tx *gorm.DB) error {
+	query := `
+		delete from rewards_snapshot_table_cutoffs
+		where cutoff_date::timestamp(6) > (select min(block_time) from blocks where number >= @blockHeight)
+	`
+	res := tx.Exec(query, sql.Named("blockHeight", blockHeight))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		rc.logger.Sugar().Infow("Cleared snapshot table cutoff, the next rewards calculation will regenerate the snapshot tables",
+			zap.Uint64("blockHeight", blockHeight),
+		)
+	}
+	return nil
+}
+
+// TableMismatch describes a table whose incrementally generated rows differ from a full recompute
+type TableMismatch struct {
+	TableName          string
+	MissingRows        int64
+	UnexpectedRows     int64
+	IncrementalRecords int64
+	FullRecords        int64
+}
+
+type ErrIncrementalCalculationMismatch struct {
+	SnapshotDate string
+	Mismatches   []*TableMismatch
+}
+
+func (e *ErrIncrementalCalculationMismatch) Error() string {
+	tableNames := make([]string, 0, len(e.Mismatches))
+	for _, mismatch := range e.Mismatches {
+		tableNames = append(tableNames, mismatch.TableName)
+	}
+	return fmt.Sprintf("incremental rewards calculation for snapshot date '%s' does not match a full recompute for tables: %s",
+		e.SnapshotDate, strings.Join(tableNames, ", "))
+}
+
+// compareTables compares the fully recomputed table with its incrementally generated copy. Returns nil if both
+// contain the same rows.
+func (rc *RewardsCalculator) compareTables(tableName string, incrementalTableName string) (*TableMismatch, error) {
+	mismatch := &TableMismatch{TableName: tableName}
+	counts := []struct {
+		dest  *int64
+		query string
+	}{
+		{&mismatch.MissingRows, fmt.Sprintf(`select count(*) from (select * from %s except all select * from %s) as t`, tableName, incrementalTableName)},
+		{&mismatch.UnexpectedRows, fmt.Sprintf(`select count(*) from (select * from %s except all select * from %s) as t`, incrementalTableName, tableName)},
+		{&mismatch.IncrementalRecords, fmt.Sprintf(`select count(*) from %s`, incrementalTableName)},
+		{&mismatch.FullRecords, fmt.Sprintf(`select count(*) from %s`, tableName)},
+	}
+	for _, c := range counts {
+		if res := rc.grm.Raw(c.query).Scan(c.dest); res.Error != nil {
+			return nil, res.Error
+		}
+	}
+	if mismatch.MissingRows == 0 && mismatch.UnexpectedRows == 0 {
+		return nil, nil
+	}
+	rc.logger.Sugar().Errorw("Incrementally generated table does not match a full recompute",
+		zap.String("tableName", mismatch.TableName),
+		zap.Int64("missingRows", mismatch.MissingRows),
+		zap.Int64("unexpectedRows", mismatch.UnexpectedRows),
+		zap.Int64("incrementalRecords", mismatch.IncrementalRecords),
+		zap.Int64("fullRecords", mismatch.FullRecords),
+	)
+	return mismatch, nil
+}
+
+// verifyIncrementalSnapshotTable regenerates the table from scratch and compares it to the incrementally
+// generated rows. The table is left with the fully recomputed rows.
+func (rc *RewardsCalculator) verifyIncrementalSnapshotTable(table *snapshotTable, snapshotDate string) (*TableMismatch, error) {
+	incrementalTableName := fmt.Sprintf("%s_incremental", table.name)
+
+	queries := []string{
+		fmt.Sprintf(`drop table if exists %s`, incrementalTableName),
+		fmt.Sprintf(`create table %s as select * from %s`, incrementalTableName, table.name),
+	}
+	for _, query := range queries {
+		if res := rc.grm.Exec(query); res.Error != nil {
+			return nil, res.Error
+		}
+	}
+	defer rc.dropIncrementalTable(incrementalTableName)
+
+	if err := table.generate(snapshotDate, ""); err != nil {
+		return nil, err
+	}
+	return rc.compareTables(table.name, incrementalTableName)
+}
+
+// verifyIncrementalSnapshotTables compares every incrementally generated snapshot table to a full recompute.
+func (rc *RewardsCalculator) verifyIncrementalSnapshotTables(ctx context.Context, snapshotDate string) ([]*TableMismatch, error) {
+	mismatches := make([]*TableMismatch, 0)
+	for _, table := range rc.incrementalSnapshotTables() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		mismatch, err := rc.verifyIncrementalSnapshotTable(table, snapshotDate)
+		if err != nil {
+			rc.logger.Sugar().Errorw("Failed to verify incremental snapshot table", "tableName", table.name, "error", err)
+			return nil, err
+		}
+		if mismatch != nil {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches, nil
+}
+
+type goldTable struct {
+	name     string
+	generate func(snapshotDate string, startDate string) error
+}
+
+// incrementalGoldTables returns the gold tables that skip the rewards paid out in full by the last completed snapshot
+// when they are generated incrementally.
+func (rc *RewardsCalculator) incrementalGoldTables(snapshotDate string) []*goldTable {
+	tableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
+	return []*goldTable{
+		{name: tableNames[rewardsUtils.Table_1_ActiveRewards], generate: rc.Generate1ActiveRewards},
+		{name: tableNames[rewardsUtils.Table_7_ActiveODRewards], generate: rc.Generate7ActiveODRewards},
+		{name: tableNames[rewardsUtils.Table_11_ActiveODOperatorSetRewards], generate: rc.Generate11ActiveODOperatorSetRewards},
+	}
+}
+
+// verifyIncrementalGoldTables regenerates every incrementally generated gold table from scratch and compares it to
+// the incrementally generated rows. The tables are left with the fully recomputed rows.
+func (rc *RewardsCalculator) verifyIncrementalGoldTables(ctx context.Context, snapshotDate string) ([]*TableMismatch, error) {
+	mismatches := make([]*TableMismatch, 0)
+	for _, table := range rc.incrementalGoldTables(snapshotDate) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// tables for forks that are not active yet are never generated
+		var exists bool
+		if res := rc.grm.Raw(`select to_regclass(?) is not null`, table.name).Scan(&exists); res.Error != nil {
+			return nil, res.Error
+		}
+		if !exists {
+			continue
+		}
+
+		incrementalTableName := fmt.Sprintf("%s_incremental", table.name)
+		queries := []string{
+			fmt.Sprintf(`drop table if exists %s`, incrementalTableName),
+			fmt.Sprintf(`alter table %s rename to %s`, table.name, incrementalTableName),
+		}
+		for _, query := range queries {
+			if res := rc.grm.Exec(query); res.Error != nil {
+				return nil, res.Error
+			}
+		}
+
+		err := table.generate(snapshotDate, "")
+		var mismatch *TableMismatch
+		if err == nil {
+			mismatch, err = rc.compareTables(table.name, incrementalTableName)
+		}
+		rc.dropIncrementalTable(incrementalTableName)
+		if err != nil {
+			rc.logger.Sugar().Errorw("Failed to verify incremental gold table", "tableName", table.name, "error", err)
+			return nil, err
+		}
+		if mismatch != nil {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches, nil
+}
+
+func (rc *RewardsCalculator) dropIncrementalTable(incrementalTableName string) {
+	if res := rc.grm.Exec(fmt.Sprintf(`drop table if exists %s`, incrementalTableName)); res.Error != nil {
+		rc.logger.Sugar().Warnw("Failed to drop incremental table", "tableName", incrementalTableName, "error", res.Error)
+	}
+}
+
+// verifyIncrementalCalculation runs verify and fails if any incrementally generated table differs from a full
+// recompute, so that a snapshot is never completed from tables that an incremental run got wrong.
+func (rc *RewardsCalculator) verifyIncrementalCalculation(
+	ctx context.Context,
+	snapshotDate string,
+	verify func(ctx context.Context, snapshotDate string) ([]*TableMismatch, error),
+) error {
+	mismatches, err := verify(ctx, snapshotDate)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) > 0 {
+		return &ErrIncrementalCalculationMismatch{SnapshotDate: snapshotDate, Mismatches: mismatches}
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/metrics/metricsTypes"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxInvariantViolationExamples is the number of violations recorded for each failed invariant
+const maxInvariantViolationExamples = 10
+
+// RewardsInvariant is a property of the rewards output that must hold after every calculation.
+//
+// Check is called once the gold table has been generated for the snapshot date and returns nil if the invariant
+// holds. Invariants must only read from the database.
+type RewardsInvariant interface {
+	Name() string
+	Check(ctx context.Context, grm *gorm.DB, snapshotDate string) (*RewardsInvariantViolation, error)
+}
+
+// RewardsInvariantViolation is a failed invariant recorded against the generated_rewards_snapshots row
+type RewardsInvariantViolation struct {
+	Id                         uint64 `gorm:"primaryKey"`
+	GeneratedRewardsSnapshotId uint64
+	Invariant                  string
+	ViolationCount             int64
+
+	// Examples holds up to maxInvariantViolationExamples violations, one per line
+	Examples  string
+	CreatedAt time.Time
+}
+
+func (*RewardsInvariantViolation) TableName() string {
+	return "rewards_invariant_violations"
+}
+
+type ErrRewardsInvariantsViolated struct {
+	SnapshotDate string
+	Invariants   []string
+}
+
+func (e *ErrRewardsInvariantsViolated) Error() string {
+	return fmt.Sprintf("rewards for snapshot date '%s' violate invariants: %s", e.SnapshotDate, strings.Join(e.Invariants, ", "))
+}
+
+// SqlRewardsInvariant is an invariant checked with a query that returns one row per violation, with a
+// single "violation" column describing it.
+//
+// The query is rendered as a template with the snapshotDate and goldStagingTable of the calculation, the latter
+// holding exactly the gold rows the calculation added, and is run with the snapshotDate named parameter.
+type SqlRewardsInvariant struct {
+	name  string
+	query string
+}
+
+func NewSqlRewardsInvariant(name string, query string) *SqlRewardsInvariant {
+	return &SqlRewardsInvariant{name: name, query: query}
+}
+
+func (i *SqlRewardsInvariant) Name() string {
+	return i.name
+}
+
+func (i *SqlRewardsInvariant) Check(ctx context.Context, grm *gorm.DB, snapshotDate string) (*RewardsInvariantViolation, error) {
+	query, err := rewardsUtils.RenderQueryTemplate(i.query, map[string]interface{}{
+		"snapshotDate":     snapshotDate,
+		"goldStagingTable": rewardsUtils.GetGoldTableNames(snapshotDate)[rewardsUtils.Table_15_GoldStaging],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Violation string
+		Total     int64
+	}
+	res := grm.WithContext(ctx).Raw(fmt.Sprintf(`
+		select violation, count(*) over () as total
+		from (%s) as violations
+		limit @limit
+	`, query),
+		sql.Named("snapshotDate", snapshotDate),
+		sql.Named("limit", maxInvariantViolationExamples),
+	).Scan(&rows)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	examples := make([]string, 0, len(rows))
+	for _, row := range rows {
+		examples = append(examples, row.Violation)
+	}
+	return &RewardsInvariantViolation{
+		Invariant:      i.name,
+		ViolationCount: rows[0].Total,
+		Examples:       strings.Join(examples, "\n"),
+	}, nil
+}
+
+const (
+	_invariant_noNegativeAmountsQuery = `
+		select concat('earner ', earner, ' has amount ', amount, ' for reward ', reward_hash, ' on ', snapshot) as violation
+		from {{.goldStagingTable}}
+		where amount < 0
+	`
+
+	_invariant_cumulativeAmountsNonDecreasingQuery = `
+		select concat('cumulative amount of earner ', earner, ' for token ', token, ' decreased by ', -sum(amount)) as violation
+		from {{.goldStagingTable}}
+		group by earner, token
+		having sum(amount) < 0
+	`
+
+	// Amounts are rounded down when they are split, so the total paid out can be less than the submitted
+	// amount but never more.
+	_invariant_rewardHashWithinSubmittedAmountQuery = `
+		with submitted as (
+			select reward_hash, max(amount) as amount
+			from reward_submissions
+			group by reward_hash
+			union all
+			select reward_hash, sum(amount) as amount
+			from (
+				select distinct reward_hash, operator, amount
+				from operator_directed_reward_submissions
+			) as od
+			group by reward_hash
+			union all
+			select reward_hash, sum(amount) as amount
+			from (
+				select distinct reward_hash, operator, amount
+				from operator_directed_operator_set_reward_submissions
+			) as odos
+			group by reward_hash
+		),
+		paid as (
+			select g.reward_hash, sum(g.amount) as amount
+			from gold_table as g
+			where
+				g.reward_hash in (select distinct reward_hash from {{.goldStagingTable}})
+				and g.snapshot <= @snapshotDate
+			group by g.reward_hash
+		)
+		select concat('reward ', p.reward_hash, ' paid ', p.amount, ' of a submitted ', coalesce(s.amount, 0)) as violation
+		from paid as p
+		left join submitted as s on (s.reward_hash = p.reward_hash)
+		where s.amount is null or p.amount > s.amount
+	`
+
+	_invariant_earnersKnownQuery = `
+		with earners as (
+			select distinct earner from {{.goldStagingTable}}
+		)
+		select concat('earner ', e.earner, ' is not a known staker, operator or AVS') as violation
+		from earners as e
+		where
+			not exists (select 1 from staker_shares as ss where ss.staker = e.earner)
+			and not exists (select 1 from operator_shares as os where os.operator = e.earner)
+			and not exists (select 1 from avs_operator_state_changes as aosc where aosc.operator = e.earner)
+			and not exists (select 1 from reward_submissions as rs where rs.avs = e.earner)
+			and not exists (select 1 from operator_directed_reward_submissions as ods where ods.avs = e.earner)
+			and not exists (select 1 from operator_directed_operator_set_reward_submissions as odoss where odoss.avs = e.earner)
+	`
+
+	_invariant_splitsWithinBoundsQuery = `
+		select concat('operator ', operator, ' has split ', split, ' for AVS ', avs, ' on ', snapshot) as violation
+		from operator_avs_split_snapshots
+		where snapshot <= @snapshotDate and (split < 0 or split > 10000)
+		union all
+		select concat('operator ', operator, ' has programmatic incentive split ', split, ' on ', snapshot) as violation
+		from operator_pi_split_snapshots
+		where snapshot <= @snapshotDate and (split < 0 or split > 10000)
+		union all
+		select concat('default operator split is ', split, ' on ', snapshot) as violation
+		from default_operator_split_snapshots
+		where snapshot <= @snapshotDate and (split < 0 or split > 10000)
+		union all
+		select concat('operator ', operator, ' has split ', split, ' for operator set ', avs, '-', operator_set_id, ' on ', snapshot) as violation
+		from operator_set_split_snapshots
+		where snapshot <= @snapshotDate and (split < 0 or split > 10000)
+	`
+)
+
+// DefaultRewardsInvariants returns the invariants checked after every rewards calculation
+func DefaultRewardsInvariants() []RewardsInvariant {
+	return []RewardsInvariant{
+		NewSqlRewardsInvariant("noNegativeAmounts", _invariant_noNegativeAmountsQuery),
+		NewSqlRewardsInvariant("cumulativeAmountsNonDecreasing", _invariant_cumulativeAmountsNonDecreasingQuery),
+		NewSqlRewardsInvariant("rewardHashWithinSubmittedAmount", _invariant_rewardHashWithinSubmittedAmountQuery),
+		NewSqlRewardsInvariant("earnersKnown", _invariant_earnersKnownQuery),
+		NewSqlRewardsInvariant("splitsWithinBounds", _invariant_splitsWithinBoundsQuery),
+	}
+}
+
+// RegisterRewardsInvariant adds an invariant that is checked after every rewards calculation
+func (rc *RewardsCalculator) RegisterRewardsInvariant(invariant RewardsInvariant) {
+	rc.invariants = append(rc.invariants, invariant)
+}
+
+// checkRewardsInvariants checks every registered invariant against the rewards generated for the snapshot date,
+// replacing any violations recorded by a previous attempt at the snapshot.
+//
+// An ErrRewardsInvariantsViolated is returned if any invariant fails and rewards.fail_on_invariant_violation is set.
+func (rc *RewardsCalculator) checkRewardsInvariants(ctx context.Context, snapshotDate string) error {
+	if !rc.globalConfig.Rewards.CheckInvariants {
+		return nil
+	}
+
+	snapshot, err := rc.GetRewardSnapshotStatus(snapshotDate)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return &ErrRewardsSnapshotNotFound{SnapshotDate: snapshotDate}
+	}
+	res := rc.grm.Where("generated_rewards_snapshot_id = ?", snapshot.Id).Delete(&RewardsInvariantViolation{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	violated := make([]string, 0)
+	steps := make([]*calculationStep, 0, len(rc.invariants))
+	for _, invariant := range rc.invariants {
+		steps = append(steps, &calculationStep{
+			name: fmt.Sprintf("%s invariant", invariant.Name()),
+			run: func() error {
+				violation, err := invariant.Check(ctx, rc.grm, snapshotDate)
+				if err != nil {
+					return err
+				}
+				if violation == nil {
+					return nil
+				}
+				rc.logger.Sugar().Errorw("Rewards invariant violated",
+					zap.String("snapshotDate", snapshotDate),
+					zap.String("invariant", invariant.Name()),
+					zap.Int64("violationCount", violation.ViolationCount),
+					zap.String("examples", violation.Examples),
+				)
+				_ = rc.metricsSink.Incr(metricsTypes.Metric_Incr_RewardsInvariantViolation, []metricsTypes.MetricsLabel{
+					{Name: "invariant", Value: invariant.Name()},
+				}, 1)
+
+				violation.GeneratedRewardsSnapshotId = snapshot.Id
+				violated = append(violated, invariant.Name())
+				return rc.grm.Create(violation).Error
+			},
+		})
+	}
+	if err := rc.runCalculationSteps(ctx, CalculationStage_Invariants, steps); err != nil {
+		return err
+	}
+
+	if len(violated) > 0 && rc.globalConfig.Rewards.FailOnInvariantViolation {
+		return &ErrRewardsInvariantsViolated{SnapshotDate: snapshotDate, Invariants: violated}
+	}
+	return nil
+}
+
+// ListRewardsInvariantViolations returns the invariant violations recorded for the snapshot date
+func (rc *RewardsCalculator) ListRewardsInvariantViolations(snapshotDate string) ([]*RewardsInvariantViolation, error) {
+	violations := make([]*RewardsInvariantViolation, 0)
+	res := rc.grm.Model(&RewardsInvariantViolation{}).
+		Joins("join generated_rewards_snapshots as grs on grs.id = rewards_invariant_violations.generated_rewards_snapshot_id").
+		Where("grs.snapshot_date = ?", snapshotDate).
+		Order("rewards_invariant_violations.id").
+		Find(&violations)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return violations, nil
+}
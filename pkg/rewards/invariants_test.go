@@ -0,0 +1,144 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RewardsInvariants(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Rewards.CheckInvariants = true
+	cfg.Rewards.FailOnInvariantViolation = true
+
+	rc, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2024-08-03"
+	goldStagingTable := rewardsUtils.GetGoldTableNames(snapshotDate)[rewardsUtils.Table_15_GoldStaging]
+
+	insertGoldRow := func(t *testing.T, earner string, rewardHash string, amount string, snapshot string) {
+		for _, table := range []string{"gold_table", goldStagingTable} {
+			res := grm.Exec(fmt.Sprintf(`insert into %s (earner, snapshot, reward_hash, token, amount) values (?, ?, ?, '0xtoken', ?)`, table),
+				earner, snapshot, rewardHash, amount)
+			assert.Nil(t, res.Error)
+		}
+	}
+	checkInvariant := func(t *testing.T, name string) *RewardsInvariantViolation {
+		for _, invariant := range DefaultRewardsInvariants() {
+			if invariant.Name() == name {
+				violation, err := invariant.Check(context.Background(), grm, snapshotDate)
+				assert.Nil(t, err)
+				return violation
+			}
+		}
+		t.Fatalf("unknown invariant '%s'", name)
+		return nil
+	}
+
+	res := grm.Exec(fmt.Sprintf(`create table %s (earner varchar, snapshot date, reward_hash varchar, token varchar, amount numeric)`, goldStagingTable))
+	assert.Nil(t, res.Error)
+	res = grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)})
+	assert.Nil(t, res.Error)
+	res = grm.Exec(`
+		insert into reward_submissions (avs, reward_hash, token, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, block_number, reward_type, transaction_hash, log_index)
+		values
+			('0xavs', '0xreward', '0xtoken', 100, '0xstrategy1', 0, 1, '2024-08-01', '2024-08-05', 345600, 1, 'avs', '0xtx', 0),
+			('0xavs', '0xreward', '0xtoken', 100, '0xstrategy2', 1, 1, '2024-08-01', '2024-08-05', 345600, 1, 'avs', '0xtx', 0)
+	`)
+	assert.Nil(t, res.Error)
+	res = grm.Exec(`insert into staker_shares (staker, strategy, shares, strategy_index, transaction_hash, log_index, block_time, block_date, block_number)
+		values ('0xstaker', '0xstrategy1', 1, 0, '0xtx', 1, '2024-08-01', '2024-08-01', 1)`)
+	assert.Nil(t, res.Error)
+
+	t.Run("Should hold for sane rewards", func(t *testing.T) {
+		insertGoldRow(t, "0xstaker", "0xreward", "40", "2024-08-02")
+		insertGoldRow(t, "0xavs", "0xreward", "10", "2024-08-03")
+
+		for _, invariant := range DefaultRewardsInvariants() {
+			violation, err := invariant.Check(context.Background(), grm, snapshotDate)
+			assert.Nil(t, err, invariant.Name())
+			assert.Nil(t, violation, invariant.Name())
+		}
+	})
+	t.Run("Should catch rewards paying out more than was submitted", func(t *testing.T) {
+		insertGoldRow(t, "0xstaker", "0xreward", "60", "2024-08-03")
+
+		violation := checkInvariant(t, "rewardHashWithinSubmittedAmount")
+		assert.NotNil(t, violation)
+		assert.Equal(t, int64(1), violation.ViolationCount)
+		assert.Contains(t, violation.Examples, "0xreward")
+	})
+	t.Run("Should catch negative amounts and unknown earners", func(t *testing.T) {
+		insertGoldRow(t, "0xunknown", "0xreward", "-5", "2024-08-03")
+
+		violation := checkInvariant(t, "noNegativeAmounts")
+		assert.NotNil(t, violation)
+		assert.Equal(t, int64(1), violation.ViolationCount)
+
+		violation = checkInvariant(t, "cumulativeAmountsNonDecreasing")
+		assert.NotNil(t, violation)
+		assert.Contains(t, violation.Examples, "0xunknown")
+
+		violation = checkInvariant(t, "earnersKnown")
+		assert.NotNil(t, violation)
+		assert.Contains(t, violation.Examples, "0xunknown")
+	})
+	t.Run("Should catch splits outside of 0 to 10000 bps", func(t *testing.T) {
+		res := grm.Exec(`insert into default_operator_split_snapshots (split, snapshot) values (10001, '2024-08-02')`)
+		assert.Nil(t, res.Error)
+
+		violation := checkInvariant(t, "splitsWithinBounds")
+		assert.NotNil(t, violation)
+		assert.Equal(t, int64(1), violation.ViolationCount)
+	})
+	t.Run("Should record violations against the snapshot and fail it", func(t *testing.T) {
+		res := grm.Create(&storage.GeneratedRewardsSnapshots{SnapshotDate: snapshotDate, Status: storage.RewardSnapshotStatusProcessing.String()})
+		assert.Nil(t, res.Error)
+
+		err := rc.checkRewardsInvariants(context.Background(), snapshotDate)
+		var violatedErr *ErrRewardsInvariantsViolated
+		assert.True(t, errors.As(err, &violatedErr))
+		assert.ElementsMatch(t, []string{
+			"noNegativeAmounts",
+			"cumulativeAmountsNonDecreasing",
+			"rewardHashWithinSubmittedAmount",
+			"earnersKnown",
+			"splitsWithinBounds",
+		}, violatedErr.Invariants)
+
+		// checking again replaces the violations of the previous check
+		err = rc.checkRewardsInvariants(context.Background(), snapshotDate)
+		assert.NotNil(t, err)
+
+		violations, err := rc.ListRewardsInvariantViolations(snapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(violations))
+	})
+	t.Run("Should only record violations when not failing on them", func(t *testing.T) {
+		cfg.Rewards.FailOnInvariantViolation = false
+		defer func() { cfg.Rewards.FailOnInvariantViolation = true }()
+
+		assert.Nil(t, rc.checkRewardsInvariants(context.Background(), snapshotDate))
+
+		violations, err := rc.ListRewardsInvariantViolations(snapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(violations))
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -99,21 +99,26 @@ SELECT
 	avs,
 	d AS snapshot
 FROM cleaned_records
-CROSS JOIN generate_series(DATE(start_time), DATE(end_time) - interval '1' day, interval '1' day) AS d
+CROSS JOIN generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS d
 `
 
 func (r *RewardsCalculator) GenerateAndInsertOperatorAvsRegistrationSnapshots(snapshotDate string) error {
+	return r.generateAndInsertOperatorAvsRegistrationSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertOperatorAvsRegistrationSnapshots(snapshotDate string, startDate string) error {
 	tableName := "operator_avs_registration_snapshots"
 
 	query, err := rewardsUtils.RenderQueryTemplate(operatorAvsRegistrationSnapshotsQuery, map[string]interface{}{
 		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
 	})
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to render operator AVS registration snapshots query", "error", err)
 		return err
 	}
 
-	err = r.generateAndInsertFromQuery(tableName, query, nil)
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to generate operator_avs_registration_snapshots", "error", err)
 		return err
@@ -138,26 +138,31 @@ final_results as (
 		strategy,
 		d AS snapshot
 	FROM cleaned_records
-		CROSS JOIN generate_series(DATE(start_time), DATE(end_time) - interval '1' day, interval '1' day) AS d
+		CROSS JOIN generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS d
 )
 select * from final_results
 `
 
 func (r *RewardsCalculator) GenerateAndInsertOperatorAvsStrategySnapshots(snapshotDate string) error {
+	return r.generateAndInsertOperatorAvsStrategySnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertOperatorAvsStrategySnapshots(snapshotDate string, startDate string) error {
 	tableName := "operator_avs_strategy_snapshots"
 	contractAddresses := r.globalConfig.GetContractsMapForChain()
 
 	query, err := rewardsUtils.RenderQueryTemplate(operatorAvsStrategyWindowsQuery, map[string]interface{}{
 		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
 	})
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to render operator AVS strategy snapshots query", "error", err)
 		return err
 	}
 
-	err = r.generateAndInsertFromQuery(tableName, query, []interface{}{
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, []interface{}{
 		sql.Named("avsDirectoryAddress", contractAddresses.AvsDirectory),
-	})
+	}, startDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to generate operator_avs_registration_snapshots", "error", err)
 		return err
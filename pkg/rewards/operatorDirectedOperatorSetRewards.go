@@ -0,0 +1,75 @@
+package rewards
+
+import "github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+
+const operatorDirectedOperatorSetRewardsQuery = `
+	with _operator_directed_operator_set_rewards as (
+		SELECT
+			odosrs.avs,
+			odosrs.operator_set_id,
+			odosrs.reward_hash,
+			odosrs.token,
+			odosrs.operator,
+			odosrs.operator_index,
+			odosrs.amount,
+			odosrs.strategy,
+			odosrs.strategy_index,
+			odosrs.multiplier,
+			odosrs.start_timestamp::TIMESTAMP(6),
+			odosrs.end_timestamp::TIMESTAMP(6),
+			odosrs.duration,
+			odosrs.block_number,
+			b.block_time::TIMESTAMP(6),
+			TO_CHAR(b.block_time, 'YYYY-MM-DD') AS block_date
+		FROM operator_directed_operator_set_reward_submissions AS odosrs
+		JOIN blocks AS b ON(b.number = odosrs.block_number)
+		WHERE b.block_time < TIMESTAMP '{{.cutoffDate}}'
+	)
+	select
+		avs,
+		operator_set_id,
+		reward_hash,
+		token,
+		operator,
+		operator_index,
+		amount,
+		strategy,
+		strategy_index,
+		multiplier,
+		start_timestamp::TIMESTAMP(6),
+		end_timestamp::TIMESTAMP(6),
+		duration,
+		block_number,
+		block_time,
+		block_date
+	from _operator_directed_operator_set_rewards
+`
+
+func (r *RewardsCalculator) GenerateAndInsertOperatorDirectedOperatorSetRewards(snapshotDate string) error {
+	tableName := "operator_directed_operator_set_rewards"
+
+	query, err := rewardsUtils.RenderQueryTemplate(operatorDirectedOperatorSetRewardsQuery, map[string]interface{}{
+		"cutoffDate": snapshotDate,
+	})
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to render operator directed operator set rewards query", "error", err)
+		return err
+	}
+
+	err = r.generateAndInsertFromQuery(tableName, query, nil)
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to generate operator directed operator set rewards", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (rc *RewardsCalculator) ListOperatorDirectedOperatorSetRewards() ([]*OperatorDirectedOperatorSetRewards, error) {
+	var rewards []*OperatorDirectedOperatorSetRewards
+	res := rc.grm.Model(&OperatorDirectedOperatorSetRewards{}).Find(&rewards)
+	if res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to list operator directed operator set rewards", "error", res.Error)
+		return nil, res.Error
+	}
+	return rewards, nil
+}
@@ -59,23 +59,28 @@ final_results as (
 	FROM
 		cleaned_records
 			CROSS JOIN
-		generate_series(DATE(start_time), DATE(end_time) - interval '1' day, interval '1' day) AS d
+		generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS d
 )
 select * from final_results
 `
 
 func (r *RewardsCalculator) GenerateAndInsertOperatorPISplitSnapshots(snapshotDate string) error {
+	return r.generateAndInsertOperatorPISplitSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertOperatorPISplitSnapshots(snapshotDate string, startDate string) error {
 	tableName := "operator_pi_split_snapshots"
 
 	query, err := rewardsUtils.RenderQueryTemplate(operatorPISplitSnapshotQuery, map[string]interface{}{
 		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
 	})
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to render query template", "error", err)
 		return err
 	}
 
-	err = r.generateAndInsertFromQuery(tableName, query, nil)
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to generate operator_pi_split_snapshots", "error", err)
 		return err
@@ -0,0 +1,100 @@
+package rewards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func hydrateOperatorSetOperatorRegistrations(grm *gorm.DB, l *zap.Logger) error {
+	blocks := []*storage.Block{
+		{Number: 1, Hash: "0x1", BlockTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Number: 2, Hash: "0x2", BlockTime: time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{Number: 3, Hash: "0x3", BlockTime: time.Date(2025, 3, 6, 10, 0, 0, 0, time.UTC)},
+		{Number: 4, Hash: "0x4", BlockTime: time.Date(2025, 3, 6, 14, 0, 0, 0, time.UTC)},
+	}
+	if res := grm.Create(&blocks); res.Error != nil {
+		l.Sugar().Errorw("Failed to insert blocks", "error", zap.Error(res.Error))
+		return res.Error
+	}
+
+	query := `
+		INSERT INTO operator_set_operator_registrations (operator, avs, operator_set_id, is_active, block_number, transaction_hash, log_index)
+		VALUES
+			('0xoperator_1', '0xavs', 1, true, 1, '0xtx_1', 0),
+			('0xoperator_1', '0xavs', 1, false, 2, '0xtx_2', 0),
+			-- registered to another operator set of the same AVS and never deregistered
+			('0xoperator_1', '0xavs', 2, true, 2, '0xtx_2', 1),
+			-- deregistered on the day of the registration
+			('0xoperator_2', '0xavs', 1, true, 3, '0xtx_3', 0),
+			('0xoperator_2', '0xavs', 1, false, 4, '0xtx_4', 0)
+	`
+	res := grm.Exec(query)
+	if res.Error != nil {
+		l.Sugar().Errorw("Failed to execute sql", "error", zap.Error(res.Error))
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorSetOperatorRegistrationSnapshots(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-10"
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetOperatorRegistrations(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var count int
+		res := grm.Raw(`select count(*) from operator_set_operator_registrations`).Scan(&count)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("Should calculate correct operator set operator registration windows", func(t *testing.T) {
+		rewards, _ := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+
+		err := rewards.GenerateAndInsertOperatorSetOperatorRegistrationSnapshots(snapshotDate)
+		assert.Nil(t, err)
+
+		type snapshot struct {
+			Operator      string
+			OperatorSetId uint64
+			Snapshot      string
+		}
+		var snapshots []snapshot
+		res := grm.Raw(`
+			select operator, operator_set_id, to_char(snapshot, 'YYYY-MM-DD') as snapshot
+			from operator_set_operator_registration_snapshots
+			order by operator, operator_set_id, snapshot
+		`).Scan(&snapshots)
+		assert.Nil(t, res.Error)
+
+		// registrations are rounded up to the next day and deregistrations down, and open registrations last until the
+		// snapshot date
+		assert.Equal(t, []snapshot{
+			{"0xoperator_1", 1, "2025-03-02"},
+			{"0xoperator_1", 1, "2025-03-03"},
+			{"0xoperator_1", 1, "2025-03-04"},
+			{"0xoperator_1", 2, "2025-03-06"},
+			{"0xoperator_1", 2, "2025-03-07"},
+			{"0xoperator_1", 2, "2025-03-08"},
+			{"0xoperator_1", 2, "2025-03-09"},
+		}, snapshots)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,105 @@
+package rewards
+
+import "github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+
+// operatorSetSplitSnapshotQuery works like operatorAvsSplitSnapshotQuery, with the split set per operator set
+// instead of per AVS.
+const operatorSetSplitSnapshotQuery = `
+WITH operator_set_splits_with_block_info as (
+	select
+		oss.operator,
+		oss.avs,
+		oss.operator_set_id,
+		oss.activated_at::timestamp(6) as activated_at,
+		oss.new_operator_set_split_bips as split,
+		oss.block_number,
+		oss.log_index,
+		b.block_time::timestamp(6) as block_time
+	from operator_set_splits as oss
+	join blocks as b on (b.number = oss.block_number)
+	where activated_at < TIMESTAMP '{{.cutoffDate}}'
+),
+-- Rank the records for each combination of (operator, operator set, activation date) by activation time, block time and log index
+ranked_operator_set_split_records as (
+	SELECT
+		*,
+		ROW_NUMBER() OVER (PARTITION BY operator, avs, operator_set_id, cast(activated_at AS DATE) ORDER BY activated_at DESC, block_time DESC, log_index DESC) AS rn
+	FROM operator_set_splits_with_block_info
+),
+-- Get the latest record for each day & round up to the snapshot day
+snapshotted_records as (
+	SELECT
+		operator,
+		avs,
+		operator_set_id,
+		split,
+		block_time,
+		date_trunc('day', activated_at) + INTERVAL '1' day AS snapshot_time
+	from ranked_operator_set_split_records
+	where rn = 1
+),
+-- Get the range for each operator, operator set pairing
+operator_set_split_windows as (
+	SELECT
+		operator, avs, operator_set_id, split, snapshot_time as start_time,
+		CASE
+			-- If the range does not have the end, use the current timestamp truncated to 0 UTC
+			WHEN LEAD(snapshot_time) OVER (PARTITION BY operator, avs, operator_set_id ORDER BY snapshot_time) is null THEN date_trunc('day', TIMESTAMP '{{.cutoffDate}}')
+			ELSE LEAD(snapshot_time) OVER (PARTITION BY operator, avs, operator_set_id ORDER BY snapshot_time)
+			END AS end_time
+	FROM snapshotted_records
+),
+-- Clean up any records where start_time >= end_time
+cleaned_records as (
+	SELECT * FROM operator_set_split_windows
+	WHERE start_time < end_time
+),
+-- Generate a snapshot for each day in the range
+final_results as (
+	SELECT
+		operator,
+		avs,
+		operator_set_id,
+		split,
+		d AS snapshot
+	FROM
+		cleaned_records
+			CROSS JOIN
+		generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS d
+)
+select * from final_results
+`
+
+func (r *RewardsCalculator) GenerateAndInsertOperatorSetSplitSnapshots(snapshotDate string) error {
+	return r.generateAndInsertOperatorSetSplitSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertOperatorSetSplitSnapshots(snapshotDate string, startDate string) error {
+	tableName := "operator_set_split_snapshots"
+
+	query, err := rewardsUtils.RenderQueryTemplate(operatorSetSplitSnapshotQuery, map[string]interface{}{
+		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
+	})
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to render query template", "error", err)
+		return err
+	}
+
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to generate operator_set_split_snapshots", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *RewardsCalculator) ListOperatorSetSplitSnapshots() ([]*OperatorSetSplitSnapshots, error) {
+	var snapshots []*OperatorSetSplitSnapshots
+	res := r.grm.Model(&OperatorSetSplitSnapshots{}).Find(&snapshots)
+	if res.Error != nil {
+		r.logger.Sugar().Errorw("Failed to list operator set split snapshots", "error", res.Error)
+		return nil, res.Error
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,96 @@
+package rewards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func hydrateOperatorSetSplits(grm *gorm.DB, l *zap.Logger) error {
+	blocks := []*storage.Block{
+		{Number: 1, Hash: "0x1", BlockTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Number: 2, Hash: "0x2", BlockTime: time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)},
+	}
+	if res := grm.Create(&blocks); res.Error != nil {
+		l.Sugar().Errorw("Failed to insert blocks", "error", zap.Error(res.Error))
+		return res.Error
+	}
+
+	query := `
+		INSERT INTO operator_set_splits (operator, avs, operator_set_id, activated_at, old_operator_set_split_bips, new_operator_set_split_bips, block_number, transaction_hash, log_index)
+		VALUES
+			('0xoperator', '0xavs', 1, '2025-03-02 10:00:00', 1000, 500, 1, '0xtx_1', 0),
+			-- replaced by the next split activated on the same day
+			('0xoperator', '0xavs', 1, '2025-03-05 10:00:00', 500, 3000, 2, '0xtx_2', 0),
+			('0xoperator', '0xavs', 1, '2025-03-05 10:00:00', 3000, 2000, 2, '0xtx_2', 1),
+			-- activated after the snapshot date
+			('0xoperator', '0xavs', 2, '2025-03-12 10:00:00', 1000, 8000, 2, '0xtx_2', 2)
+	`
+	res := grm.Exec(query)
+	if res.Error != nil {
+		l.Sugar().Errorw("Failed to execute sql", "error", zap.Error(res.Error))
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorSetSplitSnapshots(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-10"
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetSplits(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var count int
+		res := grm.Raw(`select count(*) from operator_set_splits`).Scan(&count)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, 4, count)
+	})
+
+	t.Run("Should calculate correct operator set split windows", func(t *testing.T) {
+		rewards, _ := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+
+		err := rewards.GenerateAndInsertOperatorSetSplitSnapshots(snapshotDate)
+		assert.Nil(t, err)
+
+		type snapshot struct {
+			OperatorSetId uint64
+			Split         uint64
+			Snapshot      string
+		}
+		var snapshots []snapshot
+		res := grm.Raw(`
+			select operator_set_id, split, to_char(snapshot, 'YYYY-MM-DD') as snapshot
+			from operator_set_split_snapshots
+			order by operator_set_id, snapshot
+		`).Scan(&snapshots)
+		assert.Nil(t, res.Error)
+
+		// splits apply from the day after they are activated
+		assert.Equal(t, []snapshot{
+			{1, 500, "2025-03-03"},
+			{1, 500, "2025-03-04"},
+			{1, 500, "2025-03-05"},
+			{1, 2000, "2025-03-06"},
+			{1, 2000, "2025-03-07"},
+			{1, 2000, "2025-03-08"},
+			{1, 2000, "2025-03-09"},
+		}, snapshots)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
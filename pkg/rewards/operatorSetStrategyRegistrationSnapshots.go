@@ -0,0 +1,129 @@
+package rewards
+
+import "github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+
+// Operator Set Strategy Registration Windows: Ranges at which a strategy has been part of an operator set, built
+// the same way as the operator set operator registration windows.
+const operatorSetStrategyRegistrationSnapshotsQuery = `
+WITH state_changes as (
+	select
+		osr.*,
+		osr.is_active AS registered,
+		b.block_time::timestamp(6) as block_time,
+		to_char(b.block_time, 'YYYY-MM-DD') AS block_date
+	from operator_set_strategy_registrations as osr
+	left join blocks as b on (b.number = osr.block_number)
+	-- pipeline bronze table uses this to filter the correct records
+	where b.block_time < TIMESTAMP '{{.cutoffDate}}'
+),
+marked_statuses AS (
+    SELECT
+        strategy,
+        avs,
+        operator_set_id,
+        registered,
+        block_time,
+        block_date,
+        -- Mark the next action as next_block_time
+        LEAD(block_time) OVER (PARTITION BY strategy, avs, operator_set_id ORDER BY block_time ASC, log_index ASC) AS next_block_time,
+        -- The below lead/lag combinations are only used in the next CTE
+        -- Get the next row's registered status and block_date
+        LEAD(registered) OVER (PARTITION BY strategy, avs, operator_set_id ORDER BY block_time ASC, log_index ASC) AS next_registration_status,
+        LEAD(block_date) OVER (PARTITION BY strategy, avs, operator_set_id ORDER BY block_time ASC, log_index ASC) AS next_block_date,
+        -- Get the previous row's registered status and block_date
+        LAG(registered) OVER (PARTITION BY strategy, avs, operator_set_id ORDER BY block_time ASC, log_index ASC) AS prev_registered,
+        LAG(block_date) OVER (PARTITION BY strategy, avs, operator_set_id ORDER BY block_time ASC, log_index ASC) AS prev_block_date
+    FROM state_changes
+),
+-- Ignore a (registration,deregistration) pairs that happen on the exact same date
+ removed_same_day_deregistrations AS (
+	 SELECT * from marked_statuses
+	 WHERE NOT (
+		 -- Remove the registration part
+		 (registered = TRUE AND
+		  COALESCE(next_registration_status = FALSE, false) AND -- default to false if null
+		  COALESCE(block_date = next_block_date, false)) OR
+			 -- Remove the deregistration part
+		 (registered = FALSE AND
+		  COALESCE(prev_registered = TRUE, false) and
+		  COALESCE(block_date = prev_block_date, false)
+			 )
+		 )
+ ),
+-- Combine corresponding registrations into a single record
+-- start_time is the beginning of the record
+ registration_periods AS (
+	SELECT
+		strategy,
+		avs,
+		operator_set_id,
+		block_time AS start_time,
+		-- Mark the next_block_time as the end_time for the range
+		-- Use coalesce because if the next_block_time for a registration is not closed, then we use cutoff_date
+		COALESCE(next_block_time, '{{.cutoffDate}}')::timestamp AS end_time,
+		registered
+	FROM removed_same_day_deregistrations
+	WHERE registered = TRUE
+ ),
+-- Round UP each start_time and round DOWN each end_time
+registration_windows_extra as (
+	SELECT
+		strategy,
+		avs,
+		operator_set_id,
+		date_trunc('day', start_time) + interval '1' day as start_time,
+		-- End time is end time non inclusive because the strategy is not registered to the operator set at the end time OR it is current timestamp rounded up
+		date_trunc('day', end_time) as end_time
+	FROM registration_periods
+),
+-- Ignore start_time and end_time that last less than a day
+operator_set_registration_windows as (
+	 SELECT * from registration_windows_extra
+	 WHERE start_time != end_time
+),
+cleaned_records AS (
+	SELECT * FROM operator_set_registration_windows
+	WHERE start_time < end_time
+)
+SELECT
+	strategy,
+	avs,
+	operator_set_id,
+	d AS snapshot
+FROM cleaned_records
+CROSS JOIN generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS d
+`
+
+func (r *RewardsCalculator) GenerateAndInsertOperatorSetStrategyRegistrationSnapshots(snapshotDate string) error {
+	return r.generateAndInsertOperatorSetStrategyRegistrationSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertOperatorSetStrategyRegistrationSnapshots(snapshotDate string, startDate string) error {
+	tableName := "operator_set_strategy_registration_snapshots"
+
+	query, err := rewardsUtils.RenderQueryTemplate(operatorSetStrategyRegistrationSnapshotsQuery, map[string]interface{}{
+		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
+	})
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to render operator set strategy registration snapshots query", "error", err)
+		return err
+	}
+
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
+	if err != nil {
+		r.logger.Sugar().Errorw("Failed to generate operator_set_strategy_registration_snapshots", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (rc *RewardsCalculator) ListOperatorSetStrategyRegistrationSnapshots() ([]*OperatorSetStrategyRegistrationSnapshots, error) {
+	var snapshots []*OperatorSetStrategyRegistrationSnapshots
+	res := rc.grm.Model(&OperatorSetStrategyRegistrationSnapshots{}).Find(&snapshots)
+	if res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to list operator set strategy registration snapshots", "error", res.Error)
+		return nil, res.Error
+	}
+	return snapshots, nil
+}
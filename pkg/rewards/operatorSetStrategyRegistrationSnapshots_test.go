@@ -0,0 +1,100 @@
+package rewards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func hydrateOperatorSetStrategyRegistrations(grm *gorm.DB, l *zap.Logger) error {
+	blocks := []*storage.Block{
+		{Number: 1, Hash: "0x1", BlockTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Number: 2, Hash: "0x2", BlockTime: time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{Number: 3, Hash: "0x3", BlockTime: time.Date(2025, 3, 6, 10, 0, 0, 0, time.UTC)},
+		{Number: 4, Hash: "0x4", BlockTime: time.Date(2025, 3, 6, 14, 0, 0, 0, time.UTC)},
+	}
+	if res := grm.Create(&blocks); res.Error != nil {
+		l.Sugar().Errorw("Failed to insert blocks", "error", zap.Error(res.Error))
+		return res.Error
+	}
+
+	query := `
+		INSERT INTO operator_set_strategy_registrations (strategy, avs, operator_set_id, is_active, block_number, transaction_hash, log_index)
+		VALUES
+			('0xstrategy_1', '0xavs', 1, true, 1, '0xtx_1', 0),
+			('0xstrategy_1', '0xavs', 1, false, 2, '0xtx_2', 0),
+			-- added to another operator set of the same AVS and never removed
+			('0xstrategy_1', '0xavs', 2, true, 2, '0xtx_2', 1),
+			-- removed on the day it was added
+			('0xstrategy_2', '0xavs', 1, true, 3, '0xtx_3', 0),
+			('0xstrategy_2', '0xavs', 1, false, 4, '0xtx_4', 0)
+	`
+	res := grm.Exec(query)
+	if res.Error != nil {
+		l.Sugar().Errorw("Failed to execute sql", "error", zap.Error(res.Error))
+		return res.Error
+	}
+	return nil
+}
+
+func Test_OperatorSetStrategyRegistrationSnapshots(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := "2025-03-10"
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateOperatorSetStrategyRegistrations(grm, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var count int
+		res := grm.Raw(`select count(*) from operator_set_strategy_registrations`).Scan(&count)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("Should calculate correct operator set strategy registration windows", func(t *testing.T) {
+		rewards, _ := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+
+		err := rewards.GenerateAndInsertOperatorSetStrategyRegistrationSnapshots(snapshotDate)
+		assert.Nil(t, err)
+
+		type snapshot struct {
+			Strategy      string
+			OperatorSetId uint64
+			Snapshot      string
+		}
+		var snapshots []snapshot
+		res := grm.Raw(`
+			select strategy, operator_set_id, to_char(snapshot, 'YYYY-MM-DD') as snapshot
+			from operator_set_strategy_registration_snapshots
+			order by strategy, operator_set_id, snapshot
+		`).Scan(&snapshots)
+		assert.Nil(t, res.Error)
+
+		// additions are rounded up to the next day and removals down, and strategies that were never removed last until the
+		// snapshot date
+		assert.Equal(t, []snapshot{
+			{"0xstrategy_1", 1, "2025-03-02"},
+			{"0xstrategy_1", 1, "2025-03-03"},
+			{"0xstrategy_1", 1, "2025-03-04"},
+			{"0xstrategy_1", 2, "2025-03-06"},
+			{"0xstrategy_1", 2, "2025-03-07"},
+			{"0xstrategy_1", 2, "2025-03-08"},
+			{"0xstrategy_1", 2, "2025-03-09"},
+		}, snapshots)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -44,21 +44,26 @@ SELECT
 FROM
     cleaned_records
         CROSS JOIN
-    generate_series(DATE(start_time), DATE(end_time) - interval '1' day, interval '1' day) AS day
+    generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS day
 `
 
 func (r *RewardsCalculator) GenerateAndInsertOperatorShareSnapshots(snapshotDate string) error {
+	return r.generateAndInsertOperatorShareSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertOperatorShareSnapshots(snapshotDate string, startDate string) error {
 	tableName := "operator_share_snapshots"
 
 	query, err := rewardsUtils.RenderQueryTemplate(operatorShareSnapshotsQuery, map[string]interface{}{
 		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
 	})
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to render operator share snapshots query", "error", err)
 		return err
 	}
 
-	err = r.generateAndInsertFromQuery(tableName, query, nil)
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to generate operator_share_snapshots", "error", err)
 		return err
@@ -0,0 +1,258 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PendingReward is the estimated amount an earner has accrued in a token since the snapshot of the last submitted
+// distribution root, up to SnapshotDate.
+//
+// Pending rewards are not part of any distribution root and are not verified against one. They are only valid
+// while DistributionRootIndex is the newest submitted distribution root and GeneratedRewardsSnapshotId is the newest
+// completed rewards snapshot.
+type PendingReward struct {
+	Earner                     string
+	Token                      string
+	Amount                     string
+	SnapshotDate               string
+	GeneratedRewardsSnapshotId uint64
+	// DistributionRootIndex is the root the estimate starts from, or nil if no root had been submitted
+	DistributionRootIndex *uint64
+	CalculatedAt          time.Time
+}
+
+func (*PendingReward) TableName() string {
+	return "pending_rewards"
+}
+
+type ErrPendingRewardsStale struct {
+	SnapshotDate string
+}
+
+func (e *ErrPendingRewardsStale) Error() string {
+	return fmt.Sprintf("rewards were calculated or a distribution root was submitted while pending rewards for snapshot date '%s' were being estimated", e.SnapshotDate)
+}
+
+// getLatestGeneratedRewardsSnapshotId returns the id of the newest completed rewards snapshot, or 0 if rewards were
+// never calculated
+func (rc *RewardsCalculator) getLatestGeneratedRewardsSnapshotId() (uint64, error) {
+	var id sql.NullInt64
+	res := rc.grm.Raw(`select max(id) from generated_rewards_snapshots where status = 'complete'`).Scan(&id)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return uint64(id.Int64), nil
+}
+
+// findLatestDistributionRoot returns the newest submitted distribution root that was not disabled, whether or not
+// it is activated yet, or nil if there is none
+func (rc *RewardsCalculator) findLatestDistributionRoot() (*types.SubmittedDistributionRoot, error) {
+	var roots []*types.SubmittedDistributionRoot
+	res := rc.grm.Raw(`
+		select
+			sdr.*
+		from submitted_distribution_roots as sdr
+		left join disabled_distribution_roots as ddr on (sdr.root_index = ddr.root_index)
+		where ddr.root_index is null
+		order by sdr.block_number desc, sdr.root_index desc
+		limit 1
+	`).Scan(&roots)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+	return roots[0], nil
+}
+
+// createPendingRewardsSchema creates a scratch schema in which the snapshot tables are generated from scratch and
+// the gold tables only hold the rewards since the snapshot of the distribution root.
+//
+// The gold pipeline resumes every reward submission from its latest snapshot in the gold table, so the scratch
+// gold_table is seeded with a zero amount marker row at the latest snapshot up to rootSnapshotDate of every reward
+// hash. Marker rows have an empty earner and are left out of the estimate. Without a root, nothing is seeded and
+// every reward so far is pending.
+func (rc *RewardsCalculator) createPendingRewardsSchema(conn *gorm.DB, schemaName string, snapshotDate string, rootSnapshotDate string) error {
+	placeholderTables := append([]string{}, simulationPlaceholderTables...)
+	placeholderTables = append(placeholderTables, simulationSnapshotPlaceholderTables...)
+	for _, table := range rc.incrementalSnapshotTables() {
+		placeholderTables = append(placeholderTables, table.name)
+	}
+	// the gold tables of the snapshot date are shadowed too, otherwise replacing them would drop the production tables
+	for _, tableName := range rewardsUtils.GetGoldTableNames(snapshotDate) {
+		placeholderTables = append(placeholderTables, tableName)
+	}
+
+	queries := []string{fmt.Sprintf(`create schema %s`, schemaName)}
+	for _, tableName := range placeholderTables {
+		queries = append(queries, fmt.Sprintf(`create table %s.%s ()`, schemaName, tableName))
+	}
+	for _, tableName := range []string{"gold_table", "rewards_snapshot_table_cutoffs"} {
+		queries = append(queries, fmt.Sprintf(`create table %s.%s (like %s including defaults)`, schemaName, tableName, tableName))
+	}
+	for _, query := range queries {
+		if res := conn.Exec(query); res.Error != nil {
+			rc.logger.Sugar().Errorw("Failed to create pending rewards schema", "query", query, "error", res.Error)
+			return res.Error
+		}
+	}
+
+	if rootSnapshotDate == "" {
+		return nil
+	}
+	res := conn.Exec(fmt.Sprintf(`
+		insert into %s.gold_table (earner, snapshot, reward_hash, token, amount)
+		select '', max(snapshot), reward_hash, '', 0
+		from gold_table
+		where snapshot <= @rootSnapshotDate::date
+		group by reward_hash
+	`, schemaName), sql.Named("rootSnapshotDate", rootSnapshotDate))
+	if res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to seed pending rewards gold table", "error", res.Error)
+		return res.Error
+	}
+	return nil
+}
+
+// CalculatePendingRewards estimates the rewards every earner has accrued since the snapshot of the last submitted
+// distribution root, up to the latest snapshot date, and replaces the contents of the pending_rewards table with
+// them.
+//
+// The calculation runs in a scratch schema, so none of the tables used to calculate and validate distribution
+// roots are written to. It does not hold the generation lock, since it regenerates the snapshot tables and would
+// block rewards calculations for as long as a full calculation takes. Instead, the estimate is discarded with an
+// ErrPendingRewardsStale if a rewards calculation started or a distribution root was submitted while it was running.
+func (rc *RewardsCalculator) CalculatePendingRewards(ctx context.Context) (string, error) {
+	snapshotDate := GetSnapshotFromCurrentDateTime()
+
+	if rc.GetIsGenerating() {
+		err := &ErrRewardsCalculationInProgress{}
+		rc.logger.Sugar().Infow(err.Error())
+		return snapshotDate, err
+	}
+
+	// the estimate would be missing every event that has not been indexed yet
+	var indexedBlockTime sql.NullTime
+	if res := rc.grm.Raw(`select max(block_time) from blocks`).Scan(&indexedBlockTime); res.Error != nil {
+		return snapshotDate, res.Error
+	}
+	if !indexedBlockTime.Valid || indexedBlockTime.Time.UTC().Format(time.DateOnly) < snapshotDate {
+		return snapshotDate, fmt.Errorf("blocks have not been indexed up to snapshot date '%s'", snapshotDate)
+	}
+
+	generatedSnapshotId, err := rc.getLatestGeneratedRewardsSnapshotId()
+	if err != nil {
+		return snapshotDate, err
+	}
+
+	root, err := rc.findLatestDistributionRoot()
+	if err != nil {
+		return snapshotDate, err
+	}
+	var rootIndex *uint64
+	rootSnapshotDate := ""
+	if root != nil {
+		rootIndex = &root.RootIndex
+		rootSnapshotDate = root.GetSnapshotDate()
+
+		// the rewards up to the snapshot of the root are read from the gold table, so they must have been calculated
+		generatedSnapshot, err := rc.GetGeneratedRewardsForSnapshotDate(rootSnapshotDate)
+		if err != nil {
+			return snapshotDate, err
+		}
+		if generatedSnapshot == nil {
+			return snapshotDate, fmt.Errorf("rewards have not been calculated for snapshot date '%s' of distribution root %d",
+				rootSnapshotDate, root.RootIndex,
+			)
+		}
+	}
+
+	schemaName, err := newScratchSchemaName("rewards_pending")
+	if err != nil {
+		return snapshotDate, err
+	}
+
+	startTime := time.Now()
+	pending := make([]*PendingReward, 0)
+	err = rc.runInScratchSchema(ctx, schemaName,
+		func(conn *gorm.DB) error {
+			return rc.createPendingRewardsSchema(conn, schemaName, snapshotDate, rootSnapshotDate)
+		},
+		func(pendingRc *RewardsCalculator) error {
+			if err := pendingRc.generateSnapshotData(ctx, snapshotDate, ""); err != nil {
+				return err
+			}
+			if err := pendingRc.generateGoldTables(ctx, snapshotDate, ""); err != nil {
+				return err
+			}
+			res := pendingRc.grm.Raw(`
+				select
+					earner,
+					token,
+					cast(sum(amount) as varchar) as amount
+				from gold_table
+				where earner != ''
+				group by earner, token
+				having sum(amount) > 0
+			`).Scan(&pending)
+			return res.Error
+		},
+	)
+	if err != nil {
+		rc.logger.Sugar().Errorw("Failed to calculate pending rewards", zap.String("snapshotDate", snapshotDate), zap.Error(err))
+		return snapshotDate, err
+	}
+
+	latestGeneratedSnapshotId, err := rc.getLatestGeneratedRewardsSnapshotId()
+	if err != nil {
+		return snapshotDate, err
+	}
+	latestRoot, err := rc.findLatestDistributionRoot()
+	if err != nil {
+		return snapshotDate, err
+	}
+	rootChanged := (latestRoot == nil) != (root == nil) || (latestRoot != nil && latestRoot.RootIndex != root.RootIndex)
+	if latestGeneratedSnapshotId != generatedSnapshotId || rootChanged || rc.GetIsGenerating() {
+		err := &ErrPendingRewardsStale{SnapshotDate: snapshotDate}
+		rc.logger.Sugar().Warnw(err.Error())
+		return snapshotDate, err
+	}
+
+	calculatedAt := time.Now()
+	for _, p := range pending {
+		p.SnapshotDate = snapshotDate
+		p.GeneratedRewardsSnapshotId = generatedSnapshotId
+		p.DistributionRootIndex = rootIndex
+		p.CalculatedAt = calculatedAt
+	}
+	err = rc.grm.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Exec(`delete from pending_rewards`); res.Error != nil {
+			return res.Error
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(pending, 1000).Error
+	})
+	if err != nil {
+		rc.logger.Sugar().Errorw("Failed to save pending rewards", zap.String("snapshotDate", snapshotDate), zap.Error(err))
+		return snapshotDate, err
+	}
+
+	rc.logger.Sugar().Infow("Calculated pending rewards",
+		zap.String("snapshotDate", snapshotDate),
+		zap.String("rootSnapshotDate", rootSnapshotDate),
+		zap.Int("rows", len(pending)),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return snapshotDate, nil
+}
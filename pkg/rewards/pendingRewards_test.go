@@ -0,0 +1,91 @@
+package rewards
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PendingRewards(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)})
+	assert.Nil(t, res.Error)
+	submitDistributionRoot := func(t *testing.T, rootIndex uint64, calculationEnd time.Time) {
+		res := grm.Exec(`
+			insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+			values (?, 1, ?, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', ?)
+		`, fmt.Sprintf("0xroot%d", rootIndex), rootIndex, calculationEnd, calculationEnd, rootIndex)
+		assert.Nil(t, res.Error)
+	}
+
+	t.Run("Should find no distribution root before one is submitted", func(t *testing.T) {
+		root, err := rc.findLatestDistributionRoot()
+		assert.Nil(t, err)
+		assert.Nil(t, root)
+	})
+	t.Run("Should base the estimate on the latest root that was not disabled", func(t *testing.T) {
+		submitDistributionRoot(t, 0, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		submitDistributionRoot(t, 1, time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC))
+		res := grm.Exec(`insert into disabled_distribution_roots (root_index, log_index, transaction_hash, block_number) values (1, 2, '0xtx', 1)`)
+		assert.Nil(t, res.Error)
+
+		root, err := rc.findLatestDistributionRoot()
+		assert.Nil(t, err)
+		assert.NotNil(t, root)
+		assert.Equal(t, uint64(0), root.RootIndex)
+		assert.Equal(t, "2025-02-01", root.GetSnapshotDate())
+	})
+	t.Run("Should only resume reward submissions from gold rows up to the snapshot of the root", func(t *testing.T) {
+		goldRows := []struct {
+			rewardHash string
+			snapshot   string
+		}{
+			{"0xreward1", "2025-01-31"},
+			{"0xreward1", "2025-02-01"},
+			// calculated after the snapshot of the root, so still pending
+			{"0xreward1", "2025-02-02"},
+			{"0xreward2", "2025-02-02"},
+		}
+		for _, row := range goldRows {
+			res := grm.Exec(`insert into gold_table (earner, snapshot, reward_hash, token, amount) values ('0xearner', ?, ?, '0xtoken', 100)`,
+				row.snapshot, row.rewardHash,
+			)
+			assert.Nil(t, res.Error)
+		}
+
+		schemaName, err := newScratchSchemaName("rewards_pending")
+		assert.Nil(t, err)
+		defer rc.dropScratchSchema(grm, schemaName)
+
+		err = rc.createPendingRewardsSchema(grm, schemaName, "2025-02-10", "2025-02-01")
+		assert.Nil(t, err)
+
+		type marker struct {
+			RewardHash string
+			Snapshot   time.Time
+		}
+		var markers []*marker
+		res := grm.Raw(fmt.Sprintf(`select reward_hash, snapshot from %s.gold_table where earner = '' and amount = 0`, schemaName)).Scan(&markers)
+		assert.Nil(t, res.Error)
+		assert.Len(t, markers, 1)
+		assert.Equal(t, "0xreward1", markers[0].RewardHash)
+		assert.Equal(t, "2025-02-01", markers[0].Snapshot.UTC().Format(time.DateOnly))
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
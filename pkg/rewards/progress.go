@@ -0,0 +1,66 @@
+package rewards
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+type CalculationStage string
+
+const (
+	CalculationStage_SnapshotData    CalculationStage = "snapshotData"
+	CalculationStage_GoldTables      CalculationStage = "goldTables"
+	CalculationStage_Invariants      CalculationStage = "invariants"
+	CalculationStage_StakerOperators CalculationStage = "stakerOperators"
+)
+
+// ProgressReporter receives progress updates while rewards are being calculated.
+//
+// ReportProgress is called with zero completed steps when a stage starts and after each step of the stage completes.
+type ProgressReporter interface {
+	ReportProgress(stage CalculationStage, completedSteps int, totalSteps int)
+}
+
+type progressReporterKey struct{}
+
+// WithProgressReporter returns a context that reports the progress of rewards calculations to the given reporter
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+func reportProgress(ctx context.Context, stage CalculationStage, completedSteps int, totalSteps int) {
+	if reporter, ok := ctx.Value(progressReporterKey{}).(ProgressReporter); ok && reporter != nil {
+		reporter.ReportProgress(stage, completedSteps, totalSteps)
+	}
+}
+
+type calculationStep struct {
+	name string
+	run  func() error
+}
+
+// runCalculationSteps runs the steps of a stage in order, reporting progress after each one.
+//
+// The context is checked before each step so that a calculation can be cancelled between steps. A step that
+// is already running is allowed to finish.
+func (rc *RewardsCalculator) runCalculationSteps(ctx context.Context, stage CalculationStage, steps []*calculationStep) error {
+	reportProgress(ctx, stage, 0, len(steps))
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			rc.logger.Sugar().Infow("Rewards calculation cancelled",
+				zap.String("stage", string(stage)),
+				zap.String("step", step.name),
+			)
+			return err
+		}
+		if err := step.run(); err != nil {
+			rc.logger.Sugar().Errorw(fmt.Sprintf("Failed to generate %s", step.name), "error", err)
+			return err
+		}
+		rc.logger.Sugar().Debugw(fmt.Sprintf("Generated %s", step.name))
+		reportProgress(ctx, stage, i+1, len(steps))
+	}
+	return nil
+}
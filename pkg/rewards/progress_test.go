@@ -0,0 +1,84 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type progressUpdate struct {
+	stage          CalculationStage
+	completedSteps int
+	totalSteps     int
+}
+
+type recordingProgressReporter struct {
+	updates []progressUpdate
+	onStep  func(completedSteps int)
+}
+
+func (r *recordingProgressReporter) ReportProgress(stage CalculationStage, completedSteps int, totalSteps int) {
+	r.updates = append(r.updates, progressUpdate{stage, completedSteps, totalSteps})
+	if r.onStep != nil {
+		r.onStep(completedSteps)
+	}
+}
+
+func Test_RunCalculationSteps(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+	rc := &RewardsCalculator{logger: l}
+
+	newSteps := func(ran *[]string, names ...string) []*calculationStep {
+		steps := make([]*calculationStep, 0, len(names))
+		for _, name := range names {
+			steps = append(steps, &calculationStep{name: name, run: func() error {
+				*ran = append(*ran, name)
+				return nil
+			}})
+		}
+		return steps
+	}
+
+	t.Run("Should run every step and report progress", func(t *testing.T) {
+		ran := make([]string, 0)
+		reporter := &recordingProgressReporter{}
+		ctx := WithProgressReporter(context.Background(), reporter)
+
+		err := rc.runCalculationSteps(ctx, CalculationStage_GoldTables, newSteps(&ran, "a", "b", "c"))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, ran)
+		assert.Equal(t, []progressUpdate{
+			{CalculationStage_GoldTables, 0, 3},
+			{CalculationStage_GoldTables, 1, 3},
+			{CalculationStage_GoldTables, 2, 3},
+			{CalculationStage_GoldTables, 3, 3},
+		}, reporter.updates)
+	})
+	t.Run("Should stop between steps when cancelled", func(t *testing.T) {
+		ran := make([]string, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		reporter := &recordingProgressReporter{onStep: func(completedSteps int) {
+			if completedSteps == 1 {
+				cancel()
+			}
+		}}
+		ctx = WithProgressReporter(ctx, reporter)
+
+		err := rc.runCalculationSteps(ctx, CalculationStage_SnapshotData, newSteps(&ran, "a", "b", "c"))
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, []string{"a"}, ran)
+	})
+	t.Run("Should stop at the first failing step", func(t *testing.T) {
+		ran := make([]string, 0)
+		steps := newSteps(&ran, "a", "b")
+		steps[0].run = func() error { return errors.New("failed") }
+
+		err := rc.runCalculationSteps(context.Background(), CalculationStage_StakerOperators, steps)
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, len(ran))
+	})
+}
@@ -1,6 +1,7 @@
 package rewards
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -35,10 +36,18 @@ type RewardsCalculator struct {
 	sog          *stakerOperators.StakerOperatorsGenerator
 	globalConfig *config.Config
 	metricsSink  *metrics.MetricsSink
+	invariants   []RewardsInvariant
 
 	isGenerating atomic.Bool
+	isSimulating atomic.Bool
+	// connection holding the rewards generation advisory lock while rewards are being generated
+	generationLockConn *sql.Conn
 }
 
+// rewardsGenerationLockId is the postgres advisory lock held while rewards are being generated, so that a
+// calculation started by another process sharing the database, such as the CLI, can't run at the same time.
+const rewardsGenerationLockId = int64(0x72657761726473)
+
 func NewRewardsCalculator(
 	cfg *config.Config,
 	grm *gorm.DB,
@@ -54,6 +63,7 @@ func NewRewardsCalculator(
 		sog:          sog,
 		globalConfig: cfg,
 		metricsSink:  ms,
+		invariants:   DefaultRewardsInvariants(),
 	}
 
 	return rc, nil
@@ -63,11 +73,50 @@ func (rc *RewardsCalculator) GetIsGenerating() bool {
 	return rc.isGenerating.Load()
 }
 
-func (rc *RewardsCalculator) acquireGenerationLock() {
-	rc.isGenerating.Store(true)
+// acquireGenerationLock acquires the rewards generation lock, or returns ErrRewardsCalculationInProgress if rewards
+// are already being generated by this process or by another process sharing the database.
+//
+// Session level advisory locks belong to a connection, so a connection is held for as long as the lock is.
+func (rc *RewardsCalculator) acquireGenerationLock() error {
+	if !rc.isGenerating.CompareAndSwap(false, true) {
+		return &ErrRewardsCalculationInProgress{}
+	}
+
+	sqlDb, err := rc.grm.DB()
+	if err != nil {
+		rc.isGenerating.Store(false)
+		return err
+	}
+	conn, err := sqlDb.Conn(context.Background())
+	if err != nil {
+		rc.isGenerating.Store(false)
+		return err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(context.Background(), `select pg_try_advisory_lock($1)`, rewardsGenerationLockId).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		rc.isGenerating.Store(false)
+		return err
+	}
+	if !acquired {
+		_ = conn.Close()
+		rc.isGenerating.Store(false)
+		return &ErrRewardsCalculationInProgress{}
+	}
+	rc.generationLockConn = conn
+	return nil
 }
 
 func (rc *RewardsCalculator) releaseGenerationLock() {
+	if rc.generationLockConn != nil {
+		if _, err := rc.generationLockConn.ExecContext(context.Background(), `select pg_advisory_unlock($1)`, rewardsGenerationLockId); err != nil {
+			rc.logger.Sugar().Errorw("Failed to release rewards generation advisory lock", zap.Error(err))
+		}
+		// closing the connection also releases the lock if unlocking failed
+		_ = rc.generationLockConn.Close()
+		rc.generationLockConn = nil
+	}
 	rc.isGenerating.Store(false)
 }
 
@@ -82,21 +131,26 @@ func (e *ErrRewardsCalculationInProgress) Error() string {
 // @param snapshotDate: The date for which to calculate rewards, formatted as "YYYY-MM-DD".
 //
 // If there is no previous DistributionRoot, the rewards are calculated from EigenLayer Genesis.
-func (rc *RewardsCalculator) calculateRewardsForSnapshotDate(snapshotDate string) error {
-	if rc.GetIsGenerating() {
-		err := &ErrRewardsCalculationInProgress{}
+func (rc *RewardsCalculator) calculateRewardsForSnapshotDate(ctx context.Context, snapshotDate string) error {
+	if err := rc.acquireGenerationLock(); err != nil {
 		rc.logger.Sugar().Infow(err.Error())
 		return err
 	}
+	rc.logger.Sugar().Infow("Acquired rewards generation lock", zap.String("snapshotDate", snapshotDate))
+	defer rc.releaseGenerationLock()
+
+	return rc.calculateRewardsForSnapshotDateLocked(ctx, snapshotDate)
+}
+
+// calculateRewardsForSnapshotDateLocked calculates the rewards for a given snapshot date. The caller must hold the
+// rewards generation lock.
+func (rc *RewardsCalculator) calculateRewardsForSnapshotDateLocked(ctx context.Context, snapshotDate string) error {
 	startTime := time.Now()
 	defer func() {
 		_ = rc.metricsSink.Timing(metricsTypes.Metric_Timing_RewardsCalcDuration, time.Since(startTime), []metricsTypes.MetricsLabel{
 			{Name: "snapshotDate", Value: snapshotDate},
 		})
 	}()
-	rc.acquireGenerationLock()
-	rc.logger.Sugar().Infow("Acquired rewards generation lock", zap.String("snapshotDate", snapshotDate))
-	defer rc.releaseGenerationLock()
 
 	// First make sure that the snapshot date is valid as provided.
 	// The time should be at 00:00:00 UTC. and should be in the past.
@@ -113,6 +167,8 @@ func (rc *RewardsCalculator) calculateRewardsForSnapshotDate(snapshotDate string
 	if err != nil {
 		return err
 	}
+	retryFailedSnapshot := false
+	restartCancelledSnapshot := false
 	if status != nil {
 		if status.Status == storage.RewardSnapshotStatusCompleted.String() {
 			rc.logger.Sugar().Infow("Rewards already calculated for snapshot date", zap.String("snapshotDate", snapshotDate))
@@ -125,13 +181,26 @@ func (rc *RewardsCalculator) calculateRewardsForSnapshotDate(snapshotDate string
 			return errors.New(msg)
 		}
 		if status.Status == storage.RewardSnapshotStatusFailed.String() {
-			msg := "Snapshot was already calculated and previously failed"
-			rc.logger.Sugar().Errorw(msg, zap.String("snapshotDate", snapshotDate))
+			if !rc.canRetrySnapshot(status) {
+				var err error = &ErrRewardsSnapshotRetriesExhausted{SnapshotDate: snapshotDate, RetryCount: status.RetryCount}
+				if !status.Retryable {
+					err = &ErrRewardsSnapshotNotRetryable{SnapshotDate: snapshotDate}
+				}
+				rc.logger.Sugar().Errorw("Snapshot was already calculated and previously failed",
+					zap.String("snapshotDate", snapshotDate),
+					zap.Int("retryCount", status.RetryCount),
+					zap.Error(err),
+				)
+				return err
+			}
+			retryFailedSnapshot = true
+		} else if status.Status == storage.RewardSnapshotStatusCancelled.String() {
+			restartCancelledSnapshot = true
+		} else {
+			msg := "Rewards calculation failed for snapshot date - unknown status"
+			rc.logger.Sugar().Errorw(msg, zap.String("snapshotDate", snapshotDate), zap.Any("status", status))
 			return errors.New(msg)
 		}
-		msg := "Rewards calculation failed for snapshot date - unknown status"
-		rc.logger.Sugar().Errorw(msg, zap.String("snapshotDate", snapshotDate), zap.Any("status", status))
-		return errors.New(msg)
 	}
 
 	latestBlock, err := rc.blockStore.GetLatestBlock()
@@ -151,19 +220,38 @@ func (rc *RewardsCalculator) calculateRewardsForSnapshotDate(snapshotDate string
 		zap.String("snapshot_date", snapshotDate),
 	)
 
+	if retryFailedSnapshot {
+		if err := rc.restartFailedSnapshot(status); err != nil {
+			rc.logger.Sugar().Errorw("Failed to restart failed snapshot", "error", err)
+			return err
+		}
+		return rc.generateRewards(ctx, snapshotDate)
+	}
+	if restartCancelledSnapshot {
+		if err := rc.restartCancelledSnapshot(status); err != nil {
+			rc.logger.Sugar().Errorw("Failed to restart cancelled snapshot", "error", err)
+			return err
+		}
+		return rc.generateRewards(ctx, snapshotDate)
+	}
+
 	// Calculate the rewards for the period.
-	return rc.calculateRewards(snapshotDate)
+	return rc.calculateRewards(ctx, snapshotDate)
 }
 
-func (rc *RewardsCalculator) CalculateRewardsForSnapshotDate(snapshotDate string) error {
-	err := rc.calculateRewardsForSnapshotDate(snapshotDate)
+// CalculateRewardsForSnapshotDate calculates the rewards for the given snapshot date.
+//
+// The calculation can be cancelled through the context between steps, and progress is reported to the
+// ProgressReporter attached to the context with WithProgressReporter, if any.
+func (rc *RewardsCalculator) CalculateRewardsForSnapshotDate(ctx context.Context, snapshotDate string) error {
+	err := rc.calculateRewardsForSnapshotDate(ctx, snapshotDate)
 	return err
 }
 
-func (rc *RewardsCalculator) CalculateRewardsForLatestSnapshot() (string, error) {
+func (rc *RewardsCalculator) CalculateRewardsForLatestSnapshot(ctx context.Context) (string, error) {
 	snapshotDate := GetSnapshotFromCurrentDateTime()
 
-	return snapshotDate, rc.CalculateRewardsForSnapshotDate(snapshotDate)
+	return snapshotDate, rc.CalculateRewardsForSnapshotDate(ctx, snapshotDate)
 }
 
 func GetSnapshotFromCurrentDateTime() string {
@@ -235,8 +323,18 @@ func (rc *RewardsCalculator) MerkelizeRewardsForSnapshot(snapshotDate string) (
 	return accountTree, tokenTree, distro, err
 }
 
+// GetMaxSnapshotDateForCutoffDate returns the latest snapshot in the gold staging table generated for the cutoff date.
+//
+// The staging table is looked up by pattern since its numerical index has changed over time.
 func (rc *RewardsCalculator) GetMaxSnapshotDateForCutoffDate(cutoffDate string) (string, error) {
-	goldStagingTableName := rewardsUtils.GetGoldTableNames(cutoffDate)[rewardsUtils.Table_11_GoldStaging]
+	tableNames, err := rewardsUtils.FindRewardsTableNamesForSearchPatterns(map[string]string{
+		rewardsUtils.Table_15_GoldStaging: rewardsUtils.GoldTableNameSearchPattern[rewardsUtils.Table_15_GoldStaging],
+	}, cutoffDate, rc.globalConfig.DatabaseConfig.SchemaName, rc.grm)
+	if err != nil {
+		rc.logger.Sugar().Errorw("Failed to find gold staging table", "error", err)
+		return "", err
+	}
+	goldStagingTableName := tableNames[rewardsUtils.Table_15_GoldStaging]
 
 	var maxSnapshotStr string
 	query := fmt.Sprintf(`select to_char(max(snapshot), 'YYYY-MM-DD') as snapshot from %s`, goldStagingTableName)
@@ -248,7 +346,7 @@ func (rc *RewardsCalculator) GetMaxSnapshotDateForCutoffDate(cutoffDate string)
 	return maxSnapshotStr, nil
 }
 
-func (rc *RewardsCalculator) BackfillAllStakerOperators() error {
+func (rc *RewardsCalculator) BackfillAllStakerOperators(ctx context.Context) error {
 	var generatedSnapshots []storage.GeneratedRewardsSnapshots
 	query := `select * from generated_rewards_snapshots where status = 'complete' order by snapshot_date asc`
 	res := rc.grm.Raw(query).Scan(&generatedSnapshots)
@@ -259,37 +357,36 @@ func (rc *RewardsCalculator) BackfillAllStakerOperators() error {
 
 	// First acquire a lock. If we cant, return and let the caller retry.
 	rc.logger.Sugar().Infow("Acquiring rewards generation lock for staker operator backfill")
-	if rc.GetIsGenerating() {
-		err := &ErrRewardsCalculationInProgress{}
+	if err := rc.acquireGenerationLock(); err != nil {
 		rc.logger.Sugar().Infow(err.Error())
 		return err
 	}
-	rc.acquireGenerationLock()
 	defer rc.releaseGenerationLock()
 
 	// take the largest snapshot date and generate the snapshot tables, which will be all-inclusive
 	latestSnapshotDate := generatedSnapshots[len(generatedSnapshots)-1].SnapshotDate
 
 	rc.logger.Sugar().Infow("Generating snapshot data for backfill", "snapshotDate", latestSnapshotDate)
-	if err := rc.generateSnapshotData(latestSnapshotDate); err != nil {
+	if err := rc.generateSnapshotData(ctx, latestSnapshotDate, ""); err != nil {
 		rc.logger.Sugar().Errorw("Failed to generate snapshot data", "error", err)
 		return err
 	}
 
 	// iterate over each snapshot and generate the staker operators table data for each
+	snapshotDates := make([]string, 0, len(generatedSnapshots))
 	for _, snapshot := range generatedSnapshots {
-		rc.logger.Sugar().Infow("Generating staker operators table for snapshot", "snapshotDate", snapshot.SnapshotDate)
-		if err := rc.sog.GenerateStakerOperatorsTable(snapshot.SnapshotDate); err != nil {
-			rc.logger.Sugar().Errorw("Failed to generate staker operators table", "error", err)
-			return err
-		}
+		snapshotDates = append(snapshotDates, snapshot.SnapshotDate)
+	}
+	if err := rc.generateStakerOperatorsTables(ctx, snapshotDates...); err != nil {
+		rc.logger.Sugar().Errorw("Failed to generate staker operators table", "error", err)
+		return err
 	}
 	return nil
 }
 
 // GenerateStakerOperatorsTableForPastSnapshot generates the staker operators table for a past snapshot date, OR
 // generates the rewards and the related staker-operator table data if the snapshot is greater than the latest snapshot.
-func (rc *RewardsCalculator) GenerateStakerOperatorsTableForPastSnapshot(cutoffDate string) error {
+func (rc *RewardsCalculator) GenerateStakerOperatorsTableForPastSnapshot(ctx context.Context, cutoffDate string) error {
 	// find the first snapshot that is >= to the provided cutoff date
 	var generatedSnapshot storage.GeneratedRewardsSnapshots
 	query := `select * from generated_rewards_snapshots where snapshot_date >= ? and status = 'complete' order by snapshot_date asc limit 1`
@@ -300,7 +397,7 @@ func (rc *RewardsCalculator) GenerateStakerOperatorsTableForPastSnapshot(cutoffD
 	}
 	if res.RowsAffected == 0 || errors.Is(res.Error, gorm.ErrRecordNotFound) {
 		rc.logger.Sugar().Infow("No snapshot found for cutoff date, rewards need to be calculated", "cutoffDate", cutoffDate)
-		return rc.CalculateRewardsForSnapshotDate(cutoffDate)
+		return rc.CalculateRewardsForSnapshotDate(ctx, cutoffDate)
 	}
 
 	// since rewards are already calculated and the corresponding tables are tied to the snapshot date,
@@ -313,32 +410,29 @@ func (rc *RewardsCalculator) GenerateStakerOperatorsTableForPastSnapshot(cutoffD
 	// Since this was a previous calculation, we have the date-suffixed gold tables, but not necessarily the snapshot tables.
 	// In order for our calculations to work, we need to generate the snapshot tables for the cutoff date.
 	//
-	// First check to see if there is already a rewards generation in progress. If there is, return an error and let the caller try again.
-	if rc.GetIsGenerating() {
-		err := &ErrRewardsCalculationInProgress{}
+	// Acquire the generation lock and proceed with generating snapshot tables and then the staker operators table.
+	// If there is already a rewards generation in progress, return an error and let the caller try again.
+	if err := rc.acquireGenerationLock(); err != nil {
 		rc.logger.Sugar().Infow(err.Error())
 		return err
 	}
-
-	// Acquire the generation lock and proceed with generating snapshot tables and then the staker operators table.
-	rc.acquireGenerationLock()
 	defer rc.releaseGenerationLock()
 
 	rc.logger.Sugar().Infow("Acquired rewards generation lock", "cutoffDate", cutoffDate)
 
-	if err := rc.generateSnapshotData(cutoffDate); err != nil {
+	if err := rc.generateSnapshotData(ctx, cutoffDate, ""); err != nil {
 		rc.logger.Sugar().Errorw("Failed to generate snapshot data", "error", err)
 		return err
 	}
 
-	if err := rc.sog.GenerateStakerOperatorsTable(cutoffDate); err != nil {
+	if err := rc.generateStakerOperatorsTables(ctx, cutoffDate); err != nil {
 		rc.logger.Sugar().Errorw("Failed to generate staker operators table", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (rc *RewardsCalculator) findGeneratedRewardSnapshotByBlock(blockHeight uint64) (*storage.GeneratedRewardsSnapshots, error) {
+func (rc *RewardsCalculator) findGeneratedRewardSnapshotByBlock(blockHeight uint64, tx *gorm.DB) (*storage.GeneratedRewardsSnapshots, error) {
 	distributionRootsQuery := `
 		select
 			block_number,
@@ -357,7 +451,7 @@ func (rc *RewardsCalculator) findGeneratedRewardSnapshotByBlock(blockHeight uint
 
 	rewardsCoordinatorAddress := rc.globalConfig.GetContractsMapForChain().RewardsCoordinator
 	rows := make([]DistributionRoot, 0)
-	res := rc.grm.Raw(distributionRootsQuery,
+	res := tx.Raw(distributionRootsQuery,
 		sql.Named("rewardsCoordinatorAddress", rewardsCoordinatorAddress),
 		sql.Named("blockHeight", blockHeight),
 	).Scan(&rows)
@@ -373,7 +467,7 @@ func (rc *RewardsCalculator) findGeneratedRewardSnapshotByBlock(blockHeight uint
 	snapshotDate := time.Unix(firstRow.RewardsCalculationEndTimestamp, 0).UTC().Add(time.Hour * 24).Format(time.DateOnly)
 
 	var generatedRewardSnapshots storage.GeneratedRewardsSnapshots
-	res = rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).Where("snapshot_date = ?", snapshotDate).First(&generatedRewardSnapshots)
+	res = tx.Model(&storage.GeneratedRewardsSnapshots{}).Where("snapshot_date = ?", snapshotDate).First(&generatedRewardSnapshots)
 	if res.Error != nil && !errors.Is(res.Error, gorm.ErrRecordNotFound) {
 		return nil, res.Error
 	}
@@ -393,10 +487,16 @@ func (rc *RewardsCalculator) findRewardsTablesBySnapshotDate(snapshotDate string
 	}
 	snakeCaseSnapshotDate := strings.ReplaceAll(snapshotDate, "-", "_")
 	var rewardsTables []string
-	query := `select table_name from information_schema.tables where table_schema = @tableSchema and table_name like @tableNamePattern`
+	query := `
+		select table_name from information_schema.tables
+		where
+			table_schema = @tableSchema
+			and (table_name like @goldTableNamePattern or table_name like @sotTableNamePattern)
+	`
 	res := rc.grm.Raw(query,
 		sql.Named("tableSchema", schemaName),
-		sql.Named("tableNamePattern", fmt.Sprintf("gold_%%%s", snakeCaseSnapshotDate)),
+		sql.Named("goldTableNamePattern", fmt.Sprintf("gold_%%%s", snakeCaseSnapshotDate)),
+		sql.Named("sotTableNamePattern", fmt.Sprintf("sot_%%%s", snakeCaseSnapshotDate)),
 	).Scan(&rewardsTables)
 	if res.Error != nil {
 		rc.logger.Sugar().Errorw("Failed to get rewards tables", "error", res.Error)
@@ -405,8 +505,13 @@ func (rc *RewardsCalculator) findRewardsTablesBySnapshotDate(snapshotDate string
 	return rewardsTables, nil
 }
 
-func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight uint64) error {
-	generatedSnapshot, err := rc.findGeneratedRewardSnapshotByBlock(blockHeight)
+func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight uint64, tx *gorm.DB) error {
+	if err := rc.clearSnapshotTableCutoffFromBlockHeight(blockHeight, tx); err != nil {
+		rc.logger.Sugar().Errorw("Failed to clear snapshot table cutoff", "error", err)
+		return err
+	}
+
+	generatedSnapshot, err := rc.findGeneratedRewardSnapshotByBlock(blockHeight, tx)
 	if err != nil {
 		rc.logger.Sugar().Errorw("Failed to find generated snapshot", "error", err)
 		return err
@@ -418,7 +523,7 @@ func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight u
 
 	// find all generated snapshots that are, or were created after, the generated snapshot
 	var snapshotsToDelete []*storage.GeneratedRewardsSnapshots
-	res := rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).Where("id >= ?", generatedSnapshot.Id).Find(&snapshotsToDelete)
+	res := tx.Model(&storage.GeneratedRewardsSnapshots{}).Where("id >= ?", generatedSnapshot.Id).Find(&snapshotsToDelete)
 	if res.Error != nil {
 		rc.logger.Sugar().Errorw("Failed to find generated snapshots", "error", res.Error)
 		return res.Error
@@ -426,7 +531,7 @@ func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight u
 
 	// if the target snapshot is '2024-12-01', then we need to find the one that came before it to delete everything that came after
 	var lowerBoundSnapshot *storage.GeneratedRewardsSnapshots
-	res = rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).Where("snapshot_date < ?", generatedSnapshot.SnapshotDate).Order("snapshot_date desc").First(&lowerBoundSnapshot)
+	res = tx.Model(&storage.GeneratedRewardsSnapshots{}).Where("snapshot_date < ?", generatedSnapshot.SnapshotDate).Order("snapshot_date desc").First(&lowerBoundSnapshot)
 	if res.Error != nil && !errors.Is(res.Error, gorm.ErrRecordNotFound) {
 		rc.logger.Sugar().Errorw("Failed to find lower bound snapshot", "error", res.Error)
 		return res.Error
@@ -447,7 +552,7 @@ func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight u
 		for _, tableName := range tableNames {
 			rc.logger.Sugar().Infow("Dropping rewards table", "tableName", tableName)
 			dropQuery := fmt.Sprintf(`drop table %s`, tableName)
-			res := rc.grm.Exec(dropQuery)
+			res := tx.Exec(dropQuery)
 			if res.Error != nil {
 				rc.logger.Sugar().Errorw("Failed to drop rewards table", "error", res.Error)
 				return res.Error
@@ -455,7 +560,7 @@ func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight u
 		}
 
 		// delete from generated_rewards_snapshots
-		res = rc.grm.Delete(&storage.GeneratedRewardsSnapshots{}, snapshot.Id)
+		res = tx.Delete(&storage.GeneratedRewardsSnapshots{}, snapshot.Id)
 		if res.Error != nil {
 			rc.logger.Sugar().Errorw("Failed to delete generated snapshot", "error", res.Error)
 			return res.Error
@@ -470,11 +575,11 @@ func (rc *RewardsCalculator) DeleteCorruptedRewardsFromBlockHeight(blockHeight u
 	// purge from gold table
 	if lowerBoundSnapshot != nil {
 		rc.logger.Sugar().Infow("Purging rewards from gold table where snapshot >=", "snapshotDate", lowerBoundSnapshot.SnapshotDate)
-		res = rc.grm.Exec(`delete from gold_table where snapshot >= @snapshotDate`, sql.Named("snapshotDate", lowerBoundSnapshot.SnapshotDate))
+		res = tx.Exec(`delete from gold_table where snapshot >= @snapshotDate`, sql.Named("snapshotDate", lowerBoundSnapshot.SnapshotDate))
 	} else {
 		// if the lower bound is nil, ther we're deleting everything
 		rc.logger.Sugar().Infow("Purging all rewards from gold table")
-		res = rc.grm.Exec(`delete from gold_table`)
+		res = tx.Exec(`delete from gold_table`)
 	}
 
 	if res.Error != nil {
@@ -518,27 +623,69 @@ func (rc *RewardsCalculator) FetchRewardsForSnapshot(snapshotDate string) ([]*re
 	return goldRows, nil
 }
 
-func (rc *RewardsCalculator) calculateRewards(snapshotDate string) error {
+func (rc *RewardsCalculator) calculateRewards(ctx context.Context, snapshotDate string) error {
 	_, err := rc.CreateRewardSnapshotStatus(snapshotDate)
 	if err != nil {
 		rc.logger.Sugar().Errorw("Failed to create reward snapshot status", "error", err)
 		return err
 	}
+	return rc.generateRewards(ctx, snapshotDate)
+}
+
+// markSnapshotStopped marks a snapshot whose calculation returned an error as cancelled if the calculation was
+// cancelled, or as failed otherwise.
+//
+// The calculation is deterministic, so a snapshot that violated invariants would violate them again and is not
+// retried automatically.
+func (rc *RewardsCalculator) markSnapshotStopped(snapshotDate string, err error) {
+	if errors.Is(err, context.Canceled) {
+		_ = rc.UpdateRewardSnapshotStatus(snapshotDate, storage.RewardSnapshotStatusCancelled)
+		return
+	}
+	var invariantsErr *ErrRewardsInvariantsViolated
+	rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).
+		Where("snapshot_date = ?", snapshotDate).
+		Updates(map[string]interface{}{
+			"status":    storage.RewardSnapshotStatusFailed.String(),
+			"retryable": !errors.As(err, &invariantsErr),
+		})
+}
+
+// generateRewards generates the rewards for a snapshot whose status is processing, marking it as complete, failed
+// or cancelled
+func (rc *RewardsCalculator) generateRewards(ctx context.Context, snapshotDate string) error {
+	startDate, err := rc.getIncrementalStartDate(snapshotDate)
+	if err != nil {
+		rc.markSnapshotStopped(snapshotDate, err)
+		rc.logger.Sugar().Errorw("Failed to get incremental start date", "error", err)
+		return err
+	}
+	rc.logger.Sugar().Infow("Generating snapshot data",
+		zap.String("snapshotDate", snapshotDate),
+		zap.String("startDate", startDate),
+		zap.Bool("incremental", startDate != ""),
+	)
 
-	if err = rc.generateSnapshotData(snapshotDate); err != nil {
-		_ = rc.UpdateRewardSnapshotStatus(snapshotDate, storage.RewardSnapshotStatusFailed)
+	if err = rc.generateSnapshotData(ctx, snapshotDate, startDate); err != nil {
+		rc.markSnapshotStopped(snapshotDate, err)
 		rc.logger.Sugar().Errorw("Failed to generate snapshot data", "error", err)
 		return err
 	}
 
-	if err = rc.generateGoldTables(snapshotDate); err != nil {
-		_ = rc.UpdateRewardSnapshotStatus(snapshotDate, storage.RewardSnapshotStatusFailed)
+	if err = rc.generateGoldTables(ctx, snapshotDate, startDate); err != nil {
+		rc.markSnapshotStopped(snapshotDate, err)
 		rc.logger.Sugar().Errorw("Failed to generate gold tables", "error", err)
 		return err
 	}
 
-	if err = rc.sog.GenerateStakerOperatorsTable(snapshotDate); err != nil {
-		_ = rc.UpdateRewardSnapshotStatus(snapshotDate, storage.RewardSnapshotStatusFailed)
+	if err = rc.checkRewardsInvariants(ctx, snapshotDate); err != nil {
+		rc.markSnapshotStopped(snapshotDate, err)
+		rc.logger.Sugar().Errorw("Failed to check rewards invariants", "error", err)
+		return err
+	}
+
+	if err = rc.generateStakerOperatorsTables(ctx, snapshotDate); err != nil {
+		rc.markSnapshotStopped(snapshotDate, err)
 		rc.logger.Sugar().Errorw("Failed to generate staker operators table", "error", err)
 		return err
 	}
@@ -567,152 +714,109 @@ func (rc *RewardsCalculator) isValidSnapshotDate(snapshotDate time.Time) bool {
 	return true
 }
 
-func (rc *RewardsCalculator) generateSnapshotData(snapshotDate string) error {
-	var err error
-
-	if err = rc.GenerateAndInsertCombinedRewards(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate combined rewards", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated combined rewards")
-
-	if err = rc.GenerateAndInsertStakerShares(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate staker shares", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated staker shares")
-
-	if err = rc.GenerateAndInsertOperatorShares(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator shares", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated operator shares")
-
-	if err = rc.GenerateAndInsertOperatorAvsRegistrationSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator AVS registration snapshots", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated operator AVS registration snapshots")
-
-	if err = rc.GenerateAndInsertOperatorAvsStrategySnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator AVS strategy snapshots", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated operator AVS strategy snapshots")
-
-	if err = rc.GenerateAndInsertOperatorShareSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator share snapshots", "error", err)
+// generateSnapshotData generates the snapshot tables for the given snapshot date.
+//
+// If startDate is provided, the tables with a row per day only have their rows from startDate onward regenerated,
+// otherwise every table is regenerated from scratch.
+func (rc *RewardsCalculator) generateSnapshotData(ctx context.Context, snapshotDate string, startDate string) error {
+	if err := rc.clearSnapshotTableCutoff(); err != nil {
+		rc.logger.Sugar().Errorw("Failed to clear snapshot table cutoff", "error", err)
 		return err
 	}
-	rc.logger.Sugar().Debugw("Generated operator share snapshots")
 
-	if err = rc.GenerateAndInsertStakerShareSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate staker share snapshots", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated staker share snapshots")
+	steps := []*calculationStep{
+		{name: "combined rewards", run: func() error { return rc.GenerateAndInsertCombinedRewards(snapshotDate) }},
+		{name: "staker shares", run: func() error { return rc.GenerateAndInsertStakerShares(snapshotDate) }},
+		{name: "operator shares", run: func() error { return rc.GenerateAndInsertOperatorShares(snapshotDate) }},
+		{name: "operator AVS registration snapshots", run: func() error { return rc.generateAndInsertOperatorAvsRegistrationSnapshots(snapshotDate, startDate) }},
+		{name: "operator AVS strategy snapshots", run: func() error { return rc.generateAndInsertOperatorAvsStrategySnapshots(snapshotDate, startDate) }},
+		{name: "operator share snapshots", run: func() error { return rc.generateAndInsertOperatorShareSnapshots(snapshotDate, startDate) }},
+		{name: "staker share snapshots", run: func() error { return rc.generateAndInsertStakerShareSnapshots(snapshotDate, startDate) }},
+		{name: "staker delegation snapshots", run: func() error { return rc.generateAndInsertStakerDelegationSnapshots(snapshotDate, startDate) }},
 
-	if err = rc.GenerateAndInsertStakerDelegationSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate staker delegation snapshots", "error", err)
-		return err
-	}
-	rc.logger.Sugar().Debugw("Generated staker delegation snapshots")
+		// ------------------------------------------------------------------------
+		// Rewards V2 snapshots
+		// ------------------------------------------------------------------------
+		{name: "operator directed rewards", run: func() error { return rc.GenerateAndInsertOperatorDirectedRewards(snapshotDate) }},
+		{name: "operator avs split snapshots", run: func() error { return rc.generateAndInsertOperatorAvsSplitSnapshots(snapshotDate, startDate) }},
+		{name: "operator pi snapshots", run: func() error { return rc.generateAndInsertOperatorPISplitSnapshots(snapshotDate, startDate) }},
+		{name: "default operator split snapshots", run: func() error { return rc.generateAndInsertDefaultOperatorSplitSnapshots(snapshotDate, startDate) }},
 
-	// ------------------------------------------------------------------------
-	// Rewards V2 snapshots
-	// ------------------------------------------------------------------------
-	if err = rc.GenerateAndInsertOperatorDirectedRewards(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator directed rewards", "error", err)
-		return err
+		// ------------------------------------------------------------------------
+		// Rewards V2.1 snapshots
+		// ------------------------------------------------------------------------
+		{name: "operator directed operator set rewards", run: func() error { return rc.GenerateAndInsertOperatorDirectedOperatorSetRewards(snapshotDate) }},
+		{name: "operator set operator registration snapshots", run: func() error {
+			return rc.generateAndInsertOperatorSetOperatorRegistrationSnapshots(snapshotDate, startDate)
+		}},
+		{name: "operator set strategy registration snapshots", run: func() error {
+			return rc.generateAndInsertOperatorSetStrategyRegistrationSnapshots(snapshotDate, startDate)
+		}},
+		{name: "operator set split snapshots", run: func() error { return rc.generateAndInsertOperatorSetSplitSnapshots(snapshotDate, startDate) }},
 	}
-	rc.logger.Sugar().Debugw("Generated operator directed rewards")
-	if err = rc.GenerateAndInsertOperatorAvsSplitSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator avs split snapshots", "error", err)
-		return err
+	if startDate != "" && rc.globalConfig.Rewards.VerifyIncrementalCalculation {
+		steps = append(steps, &calculationStep{name: "incremental snapshot verification", run: func() error {
+			return rc.verifyIncrementalCalculation(ctx, snapshotDate, rc.verifyIncrementalSnapshotTables)
+		}})
 	}
-	rc.logger.Sugar().Debugw("Generated operator avs split snapshots")
-
-	if err = rc.GenerateAndInsertOperatorPISplitSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator pi snapshots", "error", err)
+	if err := rc.runCalculationSteps(ctx, CalculationStage_SnapshotData, steps); err != nil {
 		return err
 	}
-	rc.logger.Sugar().Debugw("Generated operator pi snapshots")
 
-	if err = rc.GenerateAndInsertDefaultOperatorSplitSnapshots(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate default operator split snapshots", "error", err)
+	if err := rc.setSnapshotTableCutoff(snapshotDate, startDate); err != nil {
+		rc.logger.Sugar().Errorw("Failed to record snapshot table cutoff", "error", err)
 		return err
 	}
-	rc.logger.Sugar().Debugw("Generated default operator split snapshots")
-
 	return nil
 }
 
-func (rc *RewardsCalculator) generateGoldTables(snapshotDate string) error {
+// generateGoldTables generates the gold tables for the given snapshot date.
+//
+// If startDate is provided, the active rewards stages skip the rewards that were paid out in full by the last
+// completed snapshot, otherwise every reward is considered.
+func (rc *RewardsCalculator) generateGoldTables(ctx context.Context, snapshotDate string, startDate string) error {
 	forks, err := rc.globalConfig.GetRewardsSqlForkDates()
 	if err != nil {
 		return err
 	}
-	if err := rc.Generate1ActiveRewards(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate active rewards", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold2StakerRewardAmountsTable(snapshotDate, forks); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate staker reward amounts", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold3OperatorRewardAmountsTable(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator reward amounts", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold4RewardsForAllTable(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate rewards for all", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold5RfaeStakersTable(snapshotDate, forks); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate RFAE stakers", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold6RfaeOperatorsTable(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate RFAE operators", "error", err)
-		return err
-	}
-
-	if err := rc.Generate7ActiveODRewards(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate active od rewards", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold8OperatorODRewardAmountsTable(snapshotDate, forks); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate operator od reward amounts", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold9StakerODRewardAmountsTable(snapshotDate, forks); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate staker od reward amounts", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold10AvsODRewardAmountsTable(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate avs od reward amounts", "error", err)
-		return err
-	}
-
-	if err := rc.GenerateGold11StagingTable(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate gold staging", "error", err)
-		return err
-	}
+	steps := []*calculationStep{
+		{name: "active rewards", run: func() error { return rc.Generate1ActiveRewards(snapshotDate, startDate) }},
+		{name: "staker reward amounts", run: func() error { return rc.GenerateGold2StakerRewardAmountsTable(snapshotDate, forks) }},
+		{name: "operator reward amounts", run: func() error { return rc.GenerateGold3OperatorRewardAmountsTable(snapshotDate) }},
+		{name: "rewards for all", run: func() error { return rc.GenerateGold4RewardsForAllTable(snapshotDate) }},
+		{name: "RFAE stakers", run: func() error { return rc.GenerateGold5RfaeStakersTable(snapshotDate, forks) }},
+		{name: "RFAE operators", run: func() error { return rc.GenerateGold6RfaeOperatorsTable(snapshotDate) }},
+		{name: "active od rewards", run: func() error { return rc.Generate7ActiveODRewards(snapshotDate, startDate) }},
+		{name: "operator od reward amounts", run: func() error { return rc.GenerateGold8OperatorODRewardAmountsTable(snapshotDate, forks) }},
+		{name: "staker od reward amounts", run: func() error { return rc.GenerateGold9StakerODRewardAmountsTable(snapshotDate, forks) }},
+		{name: "avs od reward amounts", run: func() error { return rc.GenerateGold10AvsODRewardAmountsTable(snapshotDate) }},
+		{name: "active od operator set rewards", run: func() error { return rc.Generate11ActiveODOperatorSetRewards(snapshotDate, startDate) }},
+		{name: "operator od operator set reward amounts", run: func() error { return rc.GenerateGold12OperatorODOperatorSetRewardAmountsTable(snapshotDate) }},
+		{name: "staker od operator set reward amounts", run: func() error { return rc.GenerateGold13StakerODOperatorSetRewardAmountsTable(snapshotDate) }},
+		{name: "avs od operator set reward amounts", run: func() error { return rc.GenerateGold14AvsODOperatorSetRewardAmountsTable(snapshotDate) }},
+	}
+	if startDate != "" && rc.globalConfig.Rewards.VerifyIncrementalCalculation {
+		steps = append(steps, &calculationStep{name: "incremental gold table verification", run: func() error {
+			return rc.verifyIncrementalCalculation(ctx, snapshotDate, rc.verifyIncrementalGoldTables)
+		}})
+	}
+	steps = append(steps,
+		&calculationStep{name: "gold staging", run: func() error { return rc.GenerateGold15StagingTable(snapshotDate) }},
+		&calculationStep{name: "final table", run: func() error { return rc.GenerateGold16FinalTable(snapshotDate) }},
+	)
+	return rc.runCalculationSteps(ctx, CalculationStage_GoldTables, steps)
+}
 
-	if err := rc.GenerateGold12FinalTable(snapshotDate); err != nil {
-		rc.logger.Sugar().Errorw("Failed to generate final table", "error", err)
-		return err
+// generateStakerOperatorsTables generates the staker operators table for each of the given snapshot dates
+func (rc *RewardsCalculator) generateStakerOperatorsTables(ctx context.Context, snapshotDates ...string) error {
+	steps := make([]*calculationStep, 0, len(snapshotDates))
+	for _, snapshotDate := range snapshotDates {
+		steps = append(steps, &calculationStep{
+			name: fmt.Sprintf("staker operators table for %s", snapshotDate),
+			run:  func() error { return rc.sog.GenerateStakerOperatorsTable(snapshotDate) },
+		})
 	}
-
-	return nil
+	return rc.runCalculationSteps(ctx, CalculationStage_StakerOperators, steps)
 }
 
 func (rc *RewardsCalculator) generateAndInsertFromQuery(
@@ -801,6 +905,30 @@ type DistributionRoot struct {
 	Disabled bool
 }
 
+// FindDistributionRootByRootIndex returns the submitted distribution root with the given index, whether or not it
+// is disabled or activated, or nil if there is none
+func (rc *RewardsCalculator) FindDistributionRootByRootIndex(rootIndex uint64) (*DistributionRoot, error) {
+	query := `
+		select
+			sdr.*,
+			case when ddr.root_index is not null then true else false end as disabled
+		from submitted_distribution_roots as sdr
+		left join disabled_distribution_roots as ddr on (sdr.root_index = ddr.root_index)
+		where sdr.root_index = @rootIndex
+		limit 1
+	`
+	var roots []*DistributionRoot
+	res := rc.grm.Raw(query, sql.Named("rootIndex", rootIndex)).Scan(&roots)
+	if res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to find distribution root", zap.Uint64("rootIndex", rootIndex), zap.Error(res.Error))
+		return nil, res.Error
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+	return roots[0], nil
+}
+
 // ListDistributionRoots returns a list of submitted distribution roots. If a non-zero blockHeight is provided,
 // DistributionRoots for only that blockHeight will be returned
 func (rc *RewardsCalculator) ListDistributionRoots(blockHeight uint64) ([]*DistributionRoot, error) {
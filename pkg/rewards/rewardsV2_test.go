@@ -1,6 +1,7 @@
 package rewards
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -86,7 +87,7 @@ func Test_RewardsV2(t *testing.T) {
 
 			t.Logf("Generating rewards - snapshotDate: %s", snapshotDate)
 			// Generate snapshots
-			err = rc.generateSnapshotData(snapshotDate)
+			err = rc.generateSnapshotData(context.Background(), snapshotDate, "")
 			assert.Nil(t, err)
 
 			goldTableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
@@ -99,7 +100,7 @@ func Test_RewardsV2(t *testing.T) {
 			assert.Nil(t, err)
 
 			fmt.Printf("Running gold_1_active_rewards\n")
-			err = rc.Generate1ActiveRewards(snapshotDate)
+			err = rc.Generate1ActiveRewards(snapshotDate, "")
 			assert.Nil(t, err)
 			rows, err := getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_1_ActiveRewards])
 			assert.Nil(t, err)
@@ -153,7 +154,7 @@ func Test_RewardsV2(t *testing.T) {
 			assert.Nil(t, err)
 
 			fmt.Printf("Running gold_7_active_od_rewards\n")
-			err = rc.Generate7ActiveODRewards(snapshotDate)
+			err = rc.Generate7ActiveODRewards(snapshotDate, "")
 			assert.Nil(t, err)
 			if rewardsV2Enabled {
 				rows, err = getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_7_ActiveODRewards])
@@ -192,16 +193,16 @@ func Test_RewardsV2(t *testing.T) {
 			}
 			testStart = time.Now()
 
-			fmt.Printf("Running gold_11_staging\n")
-			err = rc.GenerateGold11StagingTable(snapshotDate)
+			fmt.Printf("Running gold_15_staging\n")
+			err = rc.GenerateGold15StagingTable(snapshotDate)
 			assert.Nil(t, err)
-			rows, err = getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_11_GoldStaging])
+			rows, err = getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_15_GoldStaging])
 			assert.Nil(t, err)
-			fmt.Printf("\tRows in gold_11_staging: %v - [time: %v]\n", rows, time.Since(testStart))
+			fmt.Printf("\tRows in gold_15_staging: %v - [time: %v]\n", rows, time.Since(testStart))
 			testStart = time.Now()
 
 			fmt.Printf("Running gold_12_final_table\n")
-			err = rc.GenerateGold12FinalTable(snapshotDate)
+			err = rc.GenerateGold16FinalTable(snapshotDate)
 			assert.Nil(t, err)
 			rows, err = getRowCountForTable(grm, "gold_table")
 			assert.Nil(t, err)
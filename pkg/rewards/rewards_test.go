@@ -1,6 +1,7 @@
 package rewards
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/Layr-Labs/sidecar/internal/metrics"
@@ -16,6 +17,7 @@ import (
 	"github.com/Layr-Labs/sidecar/pkg/postgres"
 	"github.com/Layr-Labs/sidecar/pkg/rewards/stakerOperators"
 	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
 	postgres2 "github.com/Layr-Labs/sidecar/pkg/storage/postgres"
 	"github.com/Layr-Labs/sidecar/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -166,6 +168,48 @@ func setupRewardsV2() (
 	return dbname, cfg, grm, l, sink, nil
 }
 
+// setupOperatorSetRewards runs against a custom chain since operator set rewards are not scheduled on any other chain
+// yet. Every rewards fork is active from the start of the chain.
+func setupOperatorSetRewards() (
+	string,
+	*config.Config,
+	*gorm.DB,
+	*zap.Logger,
+	*metrics.MetricsSink,
+	error,
+) {
+	cfg := tests.GetConfig()
+	cfg.Rewards.GenerateStakerOperatorsTable = true
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		RewardsForkDates: map[config.ForkName]string{
+			config.RewardsFork_Amazon:      "1970-01-01",
+			config.RewardsFork_Nile:        "1970-01-01",
+			config.RewardsFork_Panama:      "1970-01-01",
+			config.RewardsFork_Arno:        "1970-01-01",
+			config.RewardsFork_Trinity:     "1970-01-01",
+			config.RewardsFork_Mississippi: "1970-01-01",
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	sink, _ := metrics.NewMetricsSink(&metrics.MetricsSinkConfig{}, nil)
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, nil, err
+	}
+
+	return dbname, cfg, grm, l, sink, nil
+}
+
 func Test_Rewards(t *testing.T) {
 	if !rewardsTestsEnabled() {
 		t.Skipf("Skipping %s", t.Name())
@@ -241,7 +285,7 @@ func Test_Rewards(t *testing.T) {
 
 			t.Logf("Generating rewards - snapshotDate: %s", snapshotDate)
 			// Generate snapshots
-			err = rc.generateSnapshotData(snapshotDate)
+			err = rc.generateSnapshotData(context.Background(), snapshotDate, "")
 			assert.Nil(t, err)
 
 			goldTableNames := rewardsUtils.GetGoldTableNames(snapshotDate)
@@ -254,7 +298,7 @@ func Test_Rewards(t *testing.T) {
 			assert.Nil(t, err)
 
 			fmt.Printf("Running gold_1_active_rewards\n")
-			err = rc.Generate1ActiveRewards(snapshotDate)
+			err = rc.Generate1ActiveRewards(snapshotDate, "")
 			assert.Nil(t, err)
 			rows, err := getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_1_ActiveRewards])
 			assert.Nil(t, err)
@@ -305,7 +349,7 @@ func Test_Rewards(t *testing.T) {
 			assert.Nil(t, err)
 
 			fmt.Printf("Running gold_7_active_od_rewards\n")
-			err = rc.Generate7ActiveODRewards(snapshotDate)
+			err = rc.Generate7ActiveODRewards(snapshotDate, "")
 			assert.Nil(t, err)
 			if rewardsV2Enabled {
 				rows, err = getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_7_ActiveODRewards])
@@ -344,16 +388,16 @@ func Test_Rewards(t *testing.T) {
 			}
 			testStart = time.Now()
 
-			fmt.Printf("Running gold_11_staging\n")
-			err = rc.GenerateGold11StagingTable(snapshotDate)
+			fmt.Printf("Running gold_15_staging\n")
+			err = rc.GenerateGold15StagingTable(snapshotDate)
 			assert.Nil(t, err)
-			rows, err = getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_11_GoldStaging])
+			rows, err = getRowCountForTable(grm, goldTableNames[rewardsUtils.Table_15_GoldStaging])
 			assert.Nil(t, err)
-			fmt.Printf("\tRows in gold_11_staging: %v - [time: %v]\n", rows, time.Since(testStart))
+			fmt.Printf("\tRows in gold_15_staging: %v - [time: %v]\n", rows, time.Since(testStart))
 			testStart = time.Now()
 
 			fmt.Printf("Running gold_12_final_table\n")
-			err = rc.GenerateGold12FinalTable(snapshotDate)
+			err = rc.GenerateGold16FinalTable(snapshotDate)
 			assert.Nil(t, err)
 			rows, err = getRowCountForTable(grm, "gold_table")
 			assert.Nil(t, err)
@@ -478,14 +522,142 @@ func Test_RewardsCalculatorLock(t *testing.T) {
 	// --------
 
 	go func() {
-		_ = rc.CalculateRewardsForSnapshotDate("2024-08-02")
+		_ = rc.CalculateRewardsForSnapshotDate(context.Background(), "2024-08-02")
 	}()
 	time.Sleep(1 * time.Second)
 	t.Logf("Attempting to calculate second rewards for snapshot date: 2024-08-02")
-	err = rc.calculateRewardsForSnapshotDate("2024-08-02")
+	err = rc.calculateRewardsForSnapshotDate(context.Background(), "2024-08-02")
 	assert.True(t, errors.Is(err, &ErrRewardsCalculationInProgress{}))
 
 	t.Cleanup(func() {
 		postgres.TeardownTestDatabase(dbFileName, cfg, grm, l)
 	})
 }
+
+func Test_IncrementalSnapshotData(t *testing.T) {
+	if !rewardsTestsEnabled() {
+		t.Skipf("Skipping %s", t.Name())
+		return
+	}
+
+	dbFileName, cfg, grm, l, sink, err := setupRewards()
+	fmt.Printf("Using db file: %+v\n", dbFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs := postgres2.NewPostgresBlockStore(grm, l, cfg)
+
+	sog := stakerOperators.NewStakerOperatorGenerator(grm, l, cfg)
+	rc, err := NewRewardsCalculator(cfg, grm, bs, sog, sink, l)
+	assert.Nil(t, err)
+
+	// Setup all tables and source data
+	_, err = hydrateAllBlocksTable(grm, l)
+	assert.Nil(t, err)
+
+	err = hydrateOperatorAvsStateChangesTable(grm, l)
+	assert.Nil(t, err)
+
+	err = hydrateOperatorAvsRestakedStrategies(grm, l)
+	assert.Nil(t, err)
+
+	err = hydrateOperatorShareDeltas(grm, l)
+	assert.Nil(t, err)
+
+	err = hydrateStakerDelegations(grm, l)
+	assert.Nil(t, err)
+
+	err = hydrateStakerShareDeltas(grm, l)
+	assert.Nil(t, err)
+
+	err = hydrateRewardSubmissionsTable(grm, l)
+	assert.Nil(t, err)
+
+	t.Log("Hydrated tables")
+
+	previousSnapshotDate := "2024-07-25"
+	snapshotDate := "2024-08-02"
+
+	t.Run("Should generate snapshot tables from scratch without a previous snapshot", func(t *testing.T) {
+		startDate, err := rc.getIncrementalStartDate(previousSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, "", startDate)
+
+		err = rc.generateSnapshotData(context.Background(), previousSnapshotDate, startDate)
+		assert.Nil(t, err)
+
+		err = rc.generateGoldTables(context.Background(), previousSnapshotDate, startDate)
+		assert.Nil(t, err)
+
+		cutoff, err := rc.getSnapshotTableCutoff()
+		assert.Nil(t, err)
+		assert.Equal(t, previousSnapshotDate, cutoff.CutoffDate)
+
+		_, err = rc.CreateRewardSnapshotStatus(previousSnapshotDate)
+		assert.Nil(t, err)
+		err = rc.UpdateRewardSnapshotStatus(previousSnapshotDate, storage.RewardSnapshotStatusCompleted)
+		assert.Nil(t, err)
+	})
+	t.Run("Should seed the snapshot tables from the previous completed snapshot", func(t *testing.T) {
+		startDate, err := rc.getIncrementalStartDate(snapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, previousSnapshotDate, startDate)
+
+		cfg.Rewards.ForceFullCalculation = true
+		forcedStartDate, err := rc.getIncrementalStartDate(snapshotDate)
+		cfg.Rewards.ForceFullCalculation = false
+		assert.Nil(t, err)
+		assert.Equal(t, "", forcedStartDate)
+
+		// verification fails the calculation if any incrementally generated table differs from a full recompute
+		cfg.Rewards.VerifyIncrementalCalculation = true
+		defer func() { cfg.Rewards.VerifyIncrementalCalculation = false }()
+
+		err = rc.generateSnapshotData(context.Background(), snapshotDate, startDate)
+		assert.Nil(t, err)
+
+		err = rc.generateGoldTables(context.Background(), snapshotDate, startDate)
+		assert.Nil(t, err)
+	})
+	t.Run("Should match a full recompute", func(t *testing.T) {
+		mismatches, err := rc.verifyIncrementalSnapshotTables(context.Background(), snapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(mismatches))
+		for _, mismatch := range mismatches {
+			t.Logf("Mismatched snapshot table: %+v", mismatch)
+		}
+	})
+	t.Run("Should fail when an incremental table does not match a full recompute", func(t *testing.T) {
+		res := grm.Exec(`delete from staker_share_snapshots where ctid = (select ctid from staker_share_snapshots limit 1)`)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, int64(1), res.RowsAffected)
+
+		err := rc.verifyIncrementalCalculation(context.Background(), snapshotDate, rc.verifyIncrementalSnapshotTables)
+		var mismatchErr *ErrIncrementalCalculationMismatch
+		assert.True(t, errors.As(err, &mismatchErr))
+		assert.Equal(t, 1, len(mismatchErr.Mismatches))
+		assert.Equal(t, "staker_share_snapshots", mismatchErr.Mismatches[0].TableName)
+		assert.Equal(t, int64(1), mismatchErr.Mismatches[0].MissingRows)
+
+		// the table is left with the fully recomputed rows
+		err = rc.verifyIncrementalCalculation(context.Background(), snapshotDate, rc.verifyIncrementalSnapshotTables)
+		assert.Nil(t, err)
+	})
+	t.Run("Should forget the snapshot table cutoff when earlier blocks are deleted", func(t *testing.T) {
+		var blockNumber uint64
+		res := grm.Raw(`select max(number) from blocks where block_time < ?`, snapshotDate).Scan(&blockNumber)
+		assert.Nil(t, res.Error)
+
+		err := rc.clearSnapshotTableCutoffFromBlockHeight(blockNumber, grm)
+		assert.Nil(t, err)
+
+		cutoff, err := rc.getSnapshotTableCutoff()
+		assert.Nil(t, err)
+		assert.Nil(t, cutoff)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbFileName, cfg, grm, l)
+	})
+}
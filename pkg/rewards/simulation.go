@@ -0,0 +1,525 @@
+package rewards
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// calculationIntervalSeconds is the CALCULATION_INTERVAL_SECONDS of the RewardsCoordinator. Reward submissions must
+// start on, and last a multiple of, this interval.
+const calculationIntervalSeconds = 86400
+
+const (
+	SimulatedRewardType_Avs        = "avs"
+	SimulatedRewardType_AllStakers = "all_stakers"
+	SimulatedRewardType_AllEarners = "all_earners"
+)
+
+type SimulatedStrategy struct {
+	Strategy   string `json:"strategy"`
+	Multiplier string `json:"multiplier"`
+}
+
+// SimulatedRewardSubmission is a hypothetical createAVSRewardsSubmission, createRewardsForAllSubmission or
+// createRewardsForAllEarners call, depending on its RewardType.
+type SimulatedRewardSubmission struct {
+	Avs            string               `json:"avs"`
+	Token          string               `json:"token"`
+	Amount         string               `json:"amount"`
+	RewardType     string               `json:"rewardType"`
+	StartTimestamp uint64               `json:"startTimestamp"`
+	Duration       uint64               `json:"duration"`
+	Strategies     []*SimulatedStrategy `json:"strategies"`
+}
+
+type SimulatedOperatorReward struct {
+	Operator string `json:"operator"`
+	Amount   string `json:"amount"`
+}
+
+// SimulatedOperatorDirectedRewardSubmission is a hypothetical createOperatorDirectedAVSRewardsSubmission call
+type SimulatedOperatorDirectedRewardSubmission struct {
+	Avs             string                     `json:"avs"`
+	Token           string                     `json:"token"`
+	StartTimestamp  uint64                     `json:"startTimestamp"`
+	Duration        uint64                     `json:"duration"`
+	Strategies      []*SimulatedStrategy       `json:"strategies"`
+	OperatorRewards []*SimulatedOperatorReward `json:"operatorRewards"`
+}
+
+// RewardsSimulation describes hypothetical reward submissions and the cutoff date to calculate their rewards up to
+type RewardsSimulation struct {
+	CutoffDate                        string                                       `json:"cutoffDate"`
+	RewardSubmissions                 []*SimulatedRewardSubmission                 `json:"rewardSubmissions"`
+	OperatorDirectedRewardSubmissions []*SimulatedOperatorDirectedRewardSubmission `json:"operatorDirectedRewardSubmissions"`
+}
+
+type SimulatedEarnerReward struct {
+	Earner string `json:"earner"`
+	Token  string `json:"token"`
+	Amount string `json:"amount"`
+}
+
+// RewardsSimulationResult holds the rewards each earner would receive from the simulated submissions up to the
+// cutoff date, on top of the rewards they already earned.
+type RewardsSimulationResult struct {
+	CutoffDate string                   `json:"cutoffDate"`
+	Rewards    []*SimulatedEarnerReward `json:"rewards"`
+}
+
+type ErrInvalidRewardsSimulation struct {
+	Message string
+}
+
+func (e *ErrInvalidRewardsSimulation) Error() string {
+	return fmt.Sprintf("invalid rewards simulation: %s", e.Message)
+}
+
+type ErrRewardsSimulationInProgress struct{}
+
+func (e *ErrRewardsSimulationInProgress) Error() string {
+	return "rewards simulation already in progress"
+}
+
+func invalidSimulation(format string, args ...interface{}) error {
+	return &ErrInvalidRewardsSimulation{Message: fmt.Sprintf(format, args...)}
+}
+
+func validatePositiveAmount(name string, amount string) error {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() <= 0 {
+		return invalidSimulation("%s must be a positive integer, got '%s'", name, amount)
+	}
+	return nil
+}
+
+func validateSimulatedTimeRange(name string, startTimestamp uint64, duration uint64) error {
+	if startTimestamp%calculationIntervalSeconds != 0 {
+		return invalidSimulation("%s start timestamp must be a multiple of %d", name, calculationIntervalSeconds)
+	}
+	if duration == 0 || duration%calculationIntervalSeconds != 0 {
+		return invalidSimulation("%s duration must be a positive multiple of %d", name, calculationIntervalSeconds)
+	}
+	return nil
+}
+
+func validateSimulatedStrategies(name string, strategies []*SimulatedStrategy) error {
+	if len(strategies) == 0 {
+		return invalidSimulation("%s must have at least one strategy", name)
+	}
+	for i, s := range strategies {
+		if s == nil || s.Strategy == "" {
+			return invalidSimulation("%s strategy %d must have an address", name, i)
+		}
+		if err := validatePositiveAmount(fmt.Sprintf("%s strategy %d multiplier", name, i), s.Multiplier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the simulation against the rules that the RewardsCoordinator enforces for real submissions
+func (s *RewardsSimulation) Validate() error {
+	if _, err := time.Parse(time.DateOnly, s.CutoffDate); err != nil {
+		return invalidSimulation("cutoff date must be formatted as YYYY-MM-DD, got '%s'", s.CutoffDate)
+	}
+	if len(s.RewardSubmissions) == 0 && len(s.OperatorDirectedRewardSubmissions) == 0 {
+		return invalidSimulation("at least one reward submission is required")
+	}
+
+	for i, rs := range s.RewardSubmissions {
+		name := fmt.Sprintf("reward submission %d", i)
+		if rs == nil || rs.Avs == "" || rs.Token == "" {
+			return invalidSimulation("%s must have an AVS and a token", name)
+		}
+		switch rs.RewardType {
+		case SimulatedRewardType_Avs, SimulatedRewardType_AllStakers, SimulatedRewardType_AllEarners:
+		default:
+			return invalidSimulation("%s has unknown reward type '%s'", name, rs.RewardType)
+		}
+		if err := validatePositiveAmount(fmt.Sprintf("%s amount", name), rs.Amount); err != nil {
+			return err
+		}
+		if err := validateSimulatedTimeRange(name, rs.StartTimestamp, rs.Duration); err != nil {
+			return err
+		}
+		if err := validateSimulatedStrategies(name, rs.Strategies); err != nil {
+			return err
+		}
+	}
+
+	for i, odrs := range s.OperatorDirectedRewardSubmissions {
+		name := fmt.Sprintf("operator directed reward submission %d", i)
+		if odrs == nil || odrs.Avs == "" || odrs.Token == "" {
+			return invalidSimulation("%s must have an AVS and a token", name)
+		}
+		if err := validateSimulatedTimeRange(name, odrs.StartTimestamp, odrs.Duration); err != nil {
+			return err
+		}
+		if err := validateSimulatedStrategies(name, odrs.Strategies); err != nil {
+			return err
+		}
+		if len(odrs.OperatorRewards) == 0 {
+			return invalidSimulation("%s must have at least one operator reward", name)
+		}
+		for j, or := range odrs.OperatorRewards {
+			if or == nil || or.Operator == "" {
+				return invalidSimulation("%s operator reward %d must have an operator", name, j)
+			}
+			if err := validatePositiveAmount(fmt.Sprintf("%s operator reward %d amount", name, j), or.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// The simulation schema needs a copy of every table that the simulation writes to. Without a copy, a query against
+// the unqualified table name would fall through the search_path to the production table.
+//
+// The tables that are replaced wholesale are shadowed with an empty placeholder since they are dropped before being
+// written. The tables that are inserted into are shadowed with an empty copy of the production table, and so are the
+// submission tables that are only read, so that the production submissions aren't paid out again in the result.
+var (
+	simulationPlaceholderTables = []string{
+		"combined_rewards",
+		"operator_directed_rewards",
+		"operator_directed_operator_set_rewards",
+	}
+	simulationSnapshotPlaceholderTables = []string{
+		"staker_shares",
+		"operator_shares",
+	}
+	simulationCopiedTables = []string{
+		"reward_submissions",
+		"operator_directed_reward_submissions",
+		"operator_directed_operator_set_reward_submissions",
+		"gold_table",
+		"rewards_snapshot_table_cutoffs",
+	}
+)
+
+func newScratchSchemaName(prefix string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s_%s", prefix, hex.EncodeToString(b)), nil
+}
+
+func simulatedRewardHash(kind string, index int) string {
+	return fmt.Sprintf("simulated_%s_%d", kind, index)
+}
+
+// SimulateRewards calculates the rewards that hypothetical reward submissions would pay out up to the cutoff date.
+//
+// The submissions are inserted into a scratch schema that shadows every table the rewards pipeline writes to, and the
+// pipeline runs on a connection whose search_path puts the scratch schema first, so production tables are only read.
+// Since every reward submission is distributed independently of the others, the gold rows of the simulated
+// submissions are exactly what their earners would receive on top of their current rewards.
+//
+// Simulations don't take the rewards generation lock, so they never hold up a production calculation. Only one
+// simulation runs at a time since each one can take as long as a full rewards calculation.
+//
+// The snapshot tables are copied into the scratch schema if they were last generated for the cutoff date, otherwise
+// they are generated in the scratch schema.
+func (rc *RewardsCalculator) SimulateRewards(ctx context.Context, simulation *RewardsSimulation) (*RewardsSimulationResult, error) {
+	if err := simulation.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !rc.isSimulating.CompareAndSwap(false, true) {
+		err := &ErrRewardsSimulationInProgress{}
+		rc.logger.Sugar().Infow(err.Error())
+		return nil, err
+	}
+	defer rc.isSimulating.Store(false)
+
+	cutoffDate := simulation.CutoffDate
+
+	var blockNumber sql.NullInt64
+	res := rc.grm.Raw(`select max(number) from blocks where block_time < @cutoffDate`, sql.Named("cutoffDate", cutoffDate)).Scan(&blockNumber)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if !blockNumber.Valid {
+		return nil, invalidSimulation("no blocks have been indexed before the cutoff date '%s'", cutoffDate)
+	}
+
+	schemaName, err := newScratchSchemaName("rewards_simulation")
+	if err != nil {
+		return nil, err
+	}
+
+	var result *RewardsSimulationResult
+	var reuseSnapshotTables bool
+	err = rc.runInScratchSchema(ctx, schemaName,
+		func(conn *gorm.DB) error {
+			var err error
+			reuseSnapshotTables, err = rc.createSimulationSchema(conn, schemaName, cutoffDate)
+			return err
+		},
+		func(simulationRc *RewardsCalculator) error {
+			var err error
+			result, err = simulationRc.runSimulation(ctx, simulation, uint64(blockNumber.Int64), reuseSnapshotTables)
+			return err
+		},
+	)
+	if err != nil {
+		rc.logger.Sugar().Errorw("Failed to simulate rewards", zap.String("cutoffDate", cutoffDate), zap.Error(err))
+		return nil, err
+	}
+	return result, nil
+}
+
+// runInScratchSchema creates a scratch schema with createSchema and calls run with a RewardsCalculator whose
+// connection has the scratch schema first in its search_path, so that unqualified writes land in the scratch schema.
+// The scratch schema is dropped afterwards.
+func (rc *RewardsCalculator) runInScratchSchema(
+	ctx context.Context,
+	schemaName string,
+	createSchema func(conn *gorm.DB) error,
+	run func(scratchRc *RewardsCalculator) error,
+) error {
+	return rc.grm.WithContext(ctx).Connection(func(tx *gorm.DB) error {
+		conn := tx.Session(&gorm.Session{})
+
+		var searchPath string
+		if res := conn.Raw(`show search_path`).Scan(&searchPath); res.Error != nil {
+			return res.Error
+		}
+
+		// cleaning up must not be skipped when the run is cancelled
+		cleanupConn := conn.WithContext(context.Background())
+
+		if err := createSchema(conn); err != nil {
+			rc.dropScratchSchema(cleanupConn, schemaName)
+			return err
+		}
+		defer rc.dropScratchSchema(cleanupConn, schemaName)
+
+		if res := conn.Exec(fmt.Sprintf(`set search_path to %s, %s`, schemaName, searchPath)); res.Error != nil {
+			return res.Error
+		}
+		// the connection goes back to the pool afterwards, so the search_path has to be restored even if the run fails
+		defer func() {
+			if res := cleanupConn.Exec(fmt.Sprintf(`set search_path to %s`, searchPath)); res.Error != nil {
+				rc.logger.Sugar().Errorw("Failed to restore search_path after using scratch schema",
+					zap.String("schemaName", schemaName),
+					zap.Error(res.Error),
+				)
+			}
+		}()
+
+		return run(&RewardsCalculator{
+			logger:       rc.logger.With(zap.String("scratchSchema", schemaName)),
+			grm:          conn,
+			blockStore:   rc.blockStore,
+			globalConfig: rc.globalConfig,
+			metricsSink:  rc.metricsSink,
+		})
+	})
+}
+
+// createSimulationSchema creates the simulation schema and returns whether the snapshot tables were copied into it
+// from the production tables, which is only done if they were last generated for the cutoff date.
+//
+// A production calculation can replace the snapshot tables at any time since simulations don't hold the rewards
+// generation lock. The copies are therefore made in a repeatable read transaction, so that they are read from the same
+// database snapshot as the cutoff date, after locking the tables so that they can't be dropped while being copied.
+func (rc *RewardsCalculator) createSimulationSchema(conn *gorm.DB, schemaName string, cutoffDate string) (bool, error) {
+	snapshotTables := make([]string, 0)
+	for _, table := range rc.incrementalSnapshotTables() {
+		snapshotTables = append(snapshotTables, table.name)
+	}
+
+	// the gold tables of the cutoff date are shadowed too, otherwise replacing them would drop the production tables
+	placeholderTables := append([]string{}, simulationPlaceholderTables...)
+	for _, tableName := range rewardsUtils.GetGoldTableNames(cutoffDate) {
+		placeholderTables = append(placeholderTables, tableName)
+	}
+
+	reuseSnapshotTables := false
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		lockQuery := fmt.Sprintf(`lock table %s, rewards_snapshot_table_cutoffs in access share mode`, strings.Join(snapshotTables, ", "))
+		if res := tx.Exec(lockQuery); res.Error != nil {
+			rc.logger.Sugar().Errorw("Failed to lock rewards snapshot tables", "error", res.Error)
+			return res.Error
+		}
+
+		var cutoffs []*SnapshotTableCutoff
+		if res := tx.Model(&SnapshotTableCutoff{}).Order("id desc").Limit(1).Find(&cutoffs); res.Error != nil {
+			return res.Error
+		}
+		reuseSnapshotTables = len(cutoffs) == 1 && cutoffs[0].CutoffDate == cutoffDate
+
+		queries := []string{fmt.Sprintf(`create schema %s`, schemaName)}
+		for _, tableName := range placeholderTables {
+			queries = append(queries, fmt.Sprintf(`create table %s.%s ()`, schemaName, tableName))
+		}
+		for _, tableName := range simulationCopiedTables {
+			queries = append(queries, fmt.Sprintf(`create table %s.%s (like %s including defaults)`, schemaName, tableName, tableName))
+		}
+		if reuseSnapshotTables {
+			for _, tableName := range snapshotTables {
+				queries = append(queries, fmt.Sprintf(`create table %s.%s as select * from %s`, schemaName, tableName, tableName))
+			}
+		} else {
+			for _, tableName := range append(snapshotTables, simulationSnapshotPlaceholderTables...) {
+				queries = append(queries, fmt.Sprintf(`create table %s.%s ()`, schemaName, tableName))
+			}
+		}
+		for _, query := range queries {
+			if res := tx.Exec(query); res.Error != nil {
+				rc.logger.Sugar().Errorw("Failed to create rewards simulation schema", "query", query, "error", res.Error)
+				return res.Error
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return false, err
+	}
+
+	rc.logger.Sugar().Infow("Created rewards simulation schema",
+		zap.String("schemaName", schemaName),
+		zap.Bool("reuseSnapshotTables", reuseSnapshotTables),
+	)
+	return reuseSnapshotTables, nil
+}
+
+func (rc *RewardsCalculator) dropScratchSchema(conn *gorm.DB, schemaName string) {
+	if res := conn.Exec(fmt.Sprintf(`drop schema if exists %s cascade`, schemaName)); res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to drop rewards scratch schema", zap.String("schemaName", schemaName), zap.Error(res.Error))
+	}
+}
+
+// runSimulation runs the rewards pipeline against the simulation schema. It must be called on a RewardsCalculator
+// whose connection has the simulation schema first in its search_path.
+func (rc *RewardsCalculator) runSimulation(
+	ctx context.Context,
+	simulation *RewardsSimulation,
+	blockNumber uint64,
+	reuseSnapshotTables bool,
+) (*RewardsSimulationResult, error) {
+	cutoffDate := simulation.CutoffDate
+
+	if err := rc.insertSimulatedSubmissions(simulation, blockNumber); err != nil {
+		return nil, err
+	}
+
+	if reuseSnapshotTables {
+		err := rc.runCalculationSteps(ctx, CalculationStage_SnapshotData, []*calculationStep{
+			{name: "combined rewards", run: func() error { return rc.GenerateAndInsertCombinedRewards(cutoffDate) }},
+			{name: "operator directed rewards", run: func() error { return rc.GenerateAndInsertOperatorDirectedRewards(cutoffDate) }},
+			{name: "operator directed operator set rewards", run: func() error {
+				return rc.GenerateAndInsertOperatorDirectedOperatorSetRewards(cutoffDate)
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err := rc.generateSnapshotData(ctx, cutoffDate, ""); err != nil {
+		return nil, err
+	}
+
+	if err := rc.generateGoldTables(ctx, cutoffDate, ""); err != nil {
+		return nil, err
+	}
+
+	rewards := make([]*SimulatedEarnerReward, 0)
+	res := rc.grm.Raw(`
+		select
+			earner,
+			token,
+			cast(sum(amount) as varchar) as amount
+		from gold_table
+		group by earner, token
+		order by earner, token
+	`).Scan(&rewards)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return &RewardsSimulationResult{
+		CutoffDate: cutoffDate,
+		Rewards:    rewards,
+	}, nil
+}
+
+// insertSimulatedSubmissions inserts the simulated submissions as if they were submitted in the given block
+func (rc *RewardsCalculator) insertSimulatedSubmissions(simulation *RewardsSimulation, blockNumber uint64) error {
+	for i, rs := range simulation.RewardSubmissions {
+		startTimestamp := time.Unix(int64(rs.StartTimestamp), 0).UTC()
+		endTimestamp := time.Unix(int64(rs.StartTimestamp+rs.Duration), 0).UTC()
+		for strategyIndex, strategy := range rs.Strategies {
+			res := rc.grm.Exec(`
+				insert into reward_submissions (avs, reward_hash, token, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, is_for_all, block_number, reward_type)
+				values (@avs, @rewardHash, @token, @amount, @strategy, @strategyIndex, @multiplier, @startTimestamp, @endTimestamp, @duration, @isForAll, @blockNumber, @rewardType)
+			`,
+				sql.Named("avs", strings.ToLower(rs.Avs)),
+				sql.Named("rewardHash", simulatedRewardHash("reward_submission", i)),
+				sql.Named("token", strings.ToLower(rs.Token)),
+				sql.Named("amount", rs.Amount),
+				sql.Named("strategy", strings.ToLower(strategy.Strategy)),
+				sql.Named("strategyIndex", strategyIndex),
+				sql.Named("multiplier", strategy.Multiplier),
+				sql.Named("startTimestamp", startTimestamp),
+				sql.Named("endTimestamp", endTimestamp),
+				sql.Named("duration", rs.Duration),
+				sql.Named("isForAll", rs.RewardType == SimulatedRewardType_AllStakers),
+				sql.Named("blockNumber", blockNumber),
+				sql.Named("rewardType", rs.RewardType),
+			)
+			if res.Error != nil {
+				rc.logger.Sugar().Errorw("Failed to insert simulated reward submission", "index", i, "error", res.Error)
+				return res.Error
+			}
+		}
+	}
+
+	for i, odrs := range simulation.OperatorDirectedRewardSubmissions {
+		rewardHash := simulatedRewardHash("operator_directed_reward_submission", i)
+		startTimestamp := time.Unix(int64(odrs.StartTimestamp), 0).UTC()
+		endTimestamp := time.Unix(int64(odrs.StartTimestamp+odrs.Duration), 0).UTC()
+		for operatorIndex, operatorReward := range odrs.OperatorRewards {
+			for strategyIndex, strategy := range odrs.Strategies {
+				res := rc.grm.Exec(`
+					insert into operator_directed_reward_submissions (avs, reward_hash, token, operator, operator_index, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, block_number, transaction_hash, log_index)
+					values (@avs, @rewardHash, @token, @operator, @operatorIndex, @amount, @strategy, @strategyIndex, @multiplier, @startTimestamp, @endTimestamp, @duration, @blockNumber, @transactionHash, @logIndex)
+				`,
+					sql.Named("avs", strings.ToLower(odrs.Avs)),
+					sql.Named("rewardHash", rewardHash),
+					sql.Named("token", strings.ToLower(odrs.Token)),
+					sql.Named("operator", strings.ToLower(operatorReward.Operator)),
+					sql.Named("operatorIndex", operatorIndex),
+					sql.Named("amount", operatorReward.Amount),
+					sql.Named("strategy", strings.ToLower(strategy.Strategy)),
+					sql.Named("strategyIndex", strategyIndex),
+					sql.Named("multiplier", strategy.Multiplier),
+					sql.Named("startTimestamp", startTimestamp),
+					sql.Named("endTimestamp", endTimestamp),
+					sql.Named("duration", odrs.Duration),
+					sql.Named("blockNumber", blockNumber),
+					sql.Named("transactionHash", rewardHash),
+					sql.Named("logIndex", i),
+				)
+				if res.Error != nil {
+					rc.logger.Sugar().Errorw("Failed to insert simulated operator directed reward submission", "index", i, "error", res.Error)
+					return res.Error
+				}
+			}
+		}
+	}
+	return nil
+}
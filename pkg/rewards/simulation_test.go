@@ -0,0 +1,276 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRewardsSimulation() *RewardsSimulation {
+	return &RewardsSimulation{
+		CutoffDate: "2024-08-10",
+		RewardSubmissions: []*SimulatedRewardSubmission{
+			{
+				Avs:            "0xavs",
+				Token:          "0xtoken",
+				Amount:         "1000000000000000000000",
+				RewardType:     SimulatedRewardType_Avs,
+				StartTimestamp: uint64(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC).Unix()),
+				Duration:       7 * calculationIntervalSeconds,
+				Strategies: []*SimulatedStrategy{
+					{Strategy: "0xstrategy", Multiplier: "1000000000000000000"},
+				},
+			},
+		},
+		OperatorDirectedRewardSubmissions: []*SimulatedOperatorDirectedRewardSubmission{
+			{
+				Avs:            "0xavs",
+				Token:          "0xtoken",
+				StartTimestamp: uint64(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC).Unix()),
+				Duration:       calculationIntervalSeconds,
+				Strategies: []*SimulatedStrategy{
+					{Strategy: "0xstrategy", Multiplier: "1000000000000000000"},
+				},
+				OperatorRewards: []*SimulatedOperatorReward{
+					{Operator: "0xoperator", Amount: "1000"},
+				},
+			},
+		},
+	}
+}
+
+func Test_RewardsSimulationValidate(t *testing.T) {
+	t.Run("Should accept a valid simulation", func(t *testing.T) {
+		assert.Nil(t, newTestRewardsSimulation().Validate())
+	})
+
+	invalidSimulations := map[string]func(s *RewardsSimulation){
+		"invalid cutoff date":  func(s *RewardsSimulation) { s.CutoffDate = "2024-08" },
+		"no submissions":       func(s *RewardsSimulation) { s.RewardSubmissions = nil; s.OperatorDirectedRewardSubmissions = nil },
+		"unknown reward type":  func(s *RewardsSimulation) { s.RewardSubmissions[0].RewardType = "unknown" },
+		"zero amount":          func(s *RewardsSimulation) { s.RewardSubmissions[0].Amount = "0" },
+		"non-numeric amount":   func(s *RewardsSimulation) { s.RewardSubmissions[0].Amount = "1e18" },
+		"unaligned start":      func(s *RewardsSimulation) { s.RewardSubmissions[0].StartTimestamp += 1 },
+		"unaligned duration":   func(s *RewardsSimulation) { s.RewardSubmissions[0].Duration = 3600 },
+		"no strategies":        func(s *RewardsSimulation) { s.RewardSubmissions[0].Strategies = nil },
+		"zero multiplier":      func(s *RewardsSimulation) { s.RewardSubmissions[0].Strategies[0].Multiplier = "0" },
+		"no operator rewards":  func(s *RewardsSimulation) { s.OperatorDirectedRewardSubmissions[0].OperatorRewards = nil },
+		"no operator":          func(s *RewardsSimulation) { s.OperatorDirectedRewardSubmissions[0].OperatorRewards[0].Operator = "" },
+		"zero operator amount": func(s *RewardsSimulation) { s.OperatorDirectedRewardSubmissions[0].OperatorRewards[0].Amount = "0" },
+	}
+	for name, invalidate := range invalidSimulations {
+		t.Run(name, func(t *testing.T) {
+			simulation := newTestRewardsSimulation()
+			invalidate(simulation)
+
+			err := simulation.Validate()
+			var invalidErr *ErrInvalidRewardsSimulation
+			assert.True(t, errors.As(err, &invalidErr))
+		})
+	}
+}
+
+func Test_RewardsSimulation(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	countRows := func(t *testing.T, tableName string) int64 {
+		var count int64
+		res := grm.Raw(`select count(*) from ` + tableName).Scan(&count)
+		assert.Nil(t, res.Error)
+		return count
+	}
+
+	t.Run("Should reject a cutoff date without indexed blocks", func(t *testing.T) {
+		_, err := rc.SimulateRewards(context.Background(), newTestRewardsSimulation())
+		var invalidErr *ErrInvalidRewardsSimulation
+		assert.True(t, errors.As(err, &invalidErr))
+	})
+	t.Run("Should simulate rewards without touching production tables", func(t *testing.T) {
+		res := grm.Create(&storage.Block{
+			Number:    1,
+			Hash:      "0x1",
+			BlockTime: time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC),
+		})
+		assert.Nil(t, res.Error)
+
+		result, err := rc.SimulateRewards(context.Background(), newTestRewardsSimulation())
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-08-10", result.CutoffDate)
+		assert.NotNil(t, result.Rewards)
+
+		assert.Equal(t, int64(0), countRows(t, "reward_submissions"))
+		assert.Equal(t, int64(0), countRows(t, "operator_directed_reward_submissions"))
+		assert.Equal(t, int64(0), countRows(t, "gold_table"))
+		assert.Equal(t, int64(0), countRows(t, "rewards_snapshot_table_cutoffs"))
+
+		var schemaCount int64
+		res = grm.Raw(`select count(*) from information_schema.schemata where schema_name like 'rewards_simulation_%'`).Scan(&schemaCount)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, int64(0), schemaCount)
+		assert.False(t, rc.GetIsGenerating())
+	})
+	t.Run("Should only run one simulation at a time", func(t *testing.T) {
+		rc.isSimulating.Store(true)
+		defer rc.isSimulating.Store(false)
+
+		_, err := rc.SimulateRewards(context.Background(), newTestRewardsSimulation())
+		var inProgressErr *ErrRewardsSimulationInProgress
+		assert.True(t, errors.As(err, &inProgressErr))
+	})
+	t.Run("Should pay out the simulated submissions to the earners of the snapshot tables", func(t *testing.T) {
+		cutoffDate := "2025-02-10"
+
+		res := grm.Create(&storage.Block{
+			Number:    2,
+			Hash:      "0x2",
+			BlockTime: time.Date(2025, 2, 5, 12, 0, 0, 0, time.UTC),
+		})
+		assert.Nil(t, res.Error)
+
+		// the snapshot tables were last generated for the cutoff date, so the simulation copies them
+		res = grm.Create(&SnapshotTableCutoff{CutoffDate: cutoffDate})
+		assert.Nil(t, res.Error)
+		snapshotRows := []string{
+			`insert into operator_avs_registration_snapshots (avs, operator, snapshot) values ('0xavs', '0xoperator', '2025-02-02')`,
+			`insert into staker_delegation_snapshots (staker, operator, snapshot) values ('0xstaker_1', '0xoperator', '2025-02-02'), ('0xstaker_2', '0xoperator', '2025-02-02')`,
+			`insert into staker_share_snapshots (staker, strategy, shares, snapshot) values ('0xstaker_1', '0xstrategy', 1000000000000000000, '2025-02-02'), ('0xstaker_2', '0xstrategy', 3000000000000000000, '2025-02-02')`,
+		}
+		for _, query := range snapshotRows {
+			res = grm.Exec(query)
+			assert.Nil(t, res.Error)
+		}
+
+		simulation := &RewardsSimulation{
+			CutoffDate: cutoffDate,
+			OperatorDirectedRewardSubmissions: []*SimulatedOperatorDirectedRewardSubmission{
+				{
+					Avs:            "0xavs",
+					Token:          "0xtoken",
+					StartTimestamp: uint64(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix()),
+					Duration:       calculationIntervalSeconds,
+					Strategies: []*SimulatedStrategy{
+						{Strategy: "0xstrategy", Multiplier: "1000000000000000000"},
+					},
+					OperatorRewards: []*SimulatedOperatorReward{
+						{Operator: "0xoperator", Amount: "1000000"},
+						// not registered to the AVS, so the reward is refunded to the AVS
+						{Operator: "0xunregistered_operator", Amount: "500000"},
+					},
+				},
+			},
+		}
+
+		// a production calculation holding the generation lock doesn't hold up simulations
+		err := rc.acquireGenerationLock()
+		assert.Nil(t, err)
+		defer rc.releaseGenerationLock()
+
+		result, err := rc.SimulateRewards(context.Background(), simulation)
+		assert.Nil(t, err)
+
+		// the operator keeps the default 10% split and the stakers share the rest by the weight of their shares
+		assert.Equal(t, []*SimulatedEarnerReward{
+			{Earner: "0xavs", Token: "0xtoken", Amount: "500000"},
+			{Earner: "0xoperator", Token: "0xtoken", Amount: "100000"},
+			{Earner: "0xstaker_1", Token: "0xtoken", Amount: "225000"},
+			{Earner: "0xstaker_2", Token: "0xtoken", Amount: "675000"},
+		}, result.Rewards)
+
+		assert.Equal(t, int64(0), countRows(t, "gold_table"))
+		assert.Equal(t, int64(2), countRows(t, "staker_share_snapshots"))
+		assert.Equal(t, int64(1), countRows(t, "rewards_snapshot_table_cutoffs"))
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
+
+func Test_RewardsSimulationOperatorSetRewards(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupOperatorSetRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should not pay out the production operator set reward submissions", func(t *testing.T) {
+		cutoffDate := "2025-02-10"
+
+		res := grm.Create(&storage.Block{
+			Number:    1,
+			Hash:      "0x1",
+			BlockTime: time.Date(2025, 2, 5, 12, 0, 0, 0, time.UTC),
+		})
+		assert.Nil(t, res.Error)
+
+		res = grm.Create(&SnapshotTableCutoff{CutoffDate: cutoffDate})
+		assert.Nil(t, res.Error)
+		fixtures := []string{
+			`insert into operator_avs_registration_snapshots (avs, operator, snapshot) values ('0xavs', '0xoperator', '2025-02-02')`,
+			`insert into operator_set_operator_registration_snapshots (operator, avs, operator_set_id, snapshot) values ('0xoperator', '0xavs', 1, '2025-02-02')`,
+			`insert into operator_set_strategy_registration_snapshots (strategy, avs, operator_set_id, snapshot) values ('0xstrategy', '0xavs', 1, '2025-02-02')`,
+			`insert into staker_delegation_snapshots (staker, operator, snapshot) values ('0xstaker_1', '0xoperator', '2025-02-02'), ('0xstaker_2', '0xoperator', '2025-02-02')`,
+			`insert into staker_share_snapshots (staker, strategy, shares, snapshot) values ('0xstaker_1', '0xstrategy', 1000000000000000000, '2025-02-02'), ('0xstaker_2', '0xstrategy', 3000000000000000000, '2025-02-02')`,
+			// submitted to the same operator, so it would be added to the simulated rewards if it was paid out
+			`insert into operator_directed_operator_set_reward_submissions (avs, operator_set_id, reward_hash, token, operator, operator_index, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, block_number, transaction_hash, log_index)
+			values ('0xavs', 1, '0xoperator_set_reward', '0xtoken', '0xoperator', 0, 2000000, '0xstrategy', 0, 1000000000000000000, '2025-02-01', '2025-02-02', 86400, 1, '0xtx', 0)`,
+		}
+		for _, query := range fixtures {
+			res = grm.Exec(query)
+			assert.Nil(t, res.Error)
+		}
+
+		simulation := &RewardsSimulation{
+			CutoffDate: cutoffDate,
+			OperatorDirectedRewardSubmissions: []*SimulatedOperatorDirectedRewardSubmission{
+				{
+					Avs:            "0xavs",
+					Token:          "0xtoken",
+					StartTimestamp: uint64(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix()),
+					Duration:       calculationIntervalSeconds,
+					Strategies: []*SimulatedStrategy{
+						{Strategy: "0xstrategy", Multiplier: "1000000000000000000"},
+					},
+					OperatorRewards: []*SimulatedOperatorReward{
+						{Operator: "0xoperator", Amount: "1000000"},
+					},
+				},
+			},
+		}
+
+		result, err := rc.SimulateRewards(context.Background(), simulation)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*SimulatedEarnerReward{
+			{Earner: "0xoperator", Token: "0xtoken", Amount: "100000"},
+			{Earner: "0xstaker_1", Token: "0xtoken", Amount: "225000"},
+			{Earner: "0xstaker_2", Token: "0xtoken", Amount: "675000"},
+		}, result.Rewards)
+
+		var count int64
+		res = grm.Raw(`select count(*) from operator_directed_operator_set_reward_submissions`).Scan(&count)
+		assert.Nil(t, res.Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,192 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+)
+
+type ErrRewardsSnapshotNotFound struct {
+	SnapshotDate string
+}
+
+func (e *ErrRewardsSnapshotNotFound) Error() string {
+	return fmt.Sprintf("no rewards snapshot found for snapshot date '%s'", e.SnapshotDate)
+}
+
+type ErrRewardsSnapshotNotFailed struct {
+	SnapshotDate string
+	Status       string
+}
+
+func (e *ErrRewardsSnapshotNotFailed) Error() string {
+	return fmt.Sprintf("rewards snapshot for snapshot date '%s' has status '%s', only failed snapshots can be retried", e.SnapshotDate, e.Status)
+}
+
+type ErrRewardsSnapshotRetriesExhausted struct {
+	SnapshotDate string
+	RetryCount   int
+}
+
+func (e *ErrRewardsSnapshotRetriesExhausted) Error() string {
+	return fmt.Sprintf("rewards snapshot for snapshot date '%s' previously failed and was already retried %d times; "+
+		"use the retry-rewards-snapshot command or the retry rewards snapshot RPC to retry it", e.SnapshotDate, e.RetryCount)
+}
+
+type ErrRewardsSnapshotNotRetryable struct {
+	SnapshotDate string
+}
+
+func (e *ErrRewardsSnapshotNotRetryable) Error() string {
+	return fmt.Sprintf("rewards snapshot for snapshot date '%s' failed in a way that retrying can't fix; "+
+		"use the retry-rewards-snapshot command or the retry rewards snapshot RPC to retry it once the cause is fixed", e.SnapshotDate)
+}
+
+// canRetrySnapshot returns true if a failed snapshot can be retried automatically
+func (rc *RewardsCalculator) canRetrySnapshot(snapshot *storage.GeneratedRewardsSnapshots) bool {
+	return snapshot.Retryable && snapshot.RetryCount < rc.globalConfig.Rewards.SnapshotRetryLimit
+}
+
+// dropRewardsTablesForSnapshot drops the partially generated gold_* and sot_* tables for the snapshot date
+func (rc *RewardsCalculator) dropRewardsTablesForSnapshot(snapshotDate string) error {
+	tableNames, err := rc.findRewardsTablesBySnapshotDate(snapshotDate)
+	if err != nil {
+		return err
+	}
+	for _, tableName := range tableNames {
+		rc.logger.Sugar().Infow("Dropping rewards table", "tableName", tableName)
+		res := rc.grm.Exec(fmt.Sprintf(`drop table if exists %s`, tableName))
+		if res.Error != nil {
+			rc.logger.Sugar().Errorw("Failed to drop rewards table", "tableName", tableName, "error", res.Error)
+			return res.Error
+		}
+	}
+	return nil
+}
+
+// purgeIncompleteGoldRows deletes the rows that an incomplete calculation for the snapshot date inserted into the
+// gold table.
+//
+// A calculation only inserts rows for the days after the last completed snapshot before it, up to and including its
+// own snapshot date, so rows outside of that range belong to other snapshots and are kept.
+func (rc *RewardsCalculator) purgeIncompleteGoldRows(snapshotDate string) error {
+	query := `
+		delete from gold_table
+		where
+			snapshot > coalesce(
+				(
+					select max(snapshot_date)
+					from generated_rewards_snapshots
+					where
+						status = 'complete'
+						and snapshot_date < @snapshotDate
+				)::date,
+				date '1970-01-01'
+			)
+			and snapshot <= @snapshotDate::date
+	`
+	res := rc.grm.Exec(query, sql.Named("snapshotDate", snapshotDate))
+	if res.Error != nil {
+		rc.logger.Sugar().Errorw("Failed to purge incomplete rows from gold table", "error", res.Error)
+		return res.Error
+	}
+	rc.logger.Sugar().Infow("Purged incomplete rows from gold table",
+		zap.String("snapshotDate", snapshotDate),
+		zap.Int64("recordsDeleted", res.RowsAffected),
+	)
+	return nil
+}
+
+// cleanupFailedSnapshot removes everything a failed calculation for the snapshot date left behind
+func (rc *RewardsCalculator) cleanupFailedSnapshot(snapshotDate string) error {
+	if err := rc.dropRewardsTablesForSnapshot(snapshotDate); err != nil {
+		return err
+	}
+	return rc.purgeIncompleteGoldRows(snapshotDate)
+}
+
+// restartFailedSnapshot cleans up a failed snapshot and marks it as processing again, counting the retry
+func (rc *RewardsCalculator) restartFailedSnapshot(snapshot *storage.GeneratedRewardsSnapshots) error {
+	rc.logger.Sugar().Infow("Retrying failed rewards snapshot",
+		zap.String("snapshotDate", snapshot.SnapshotDate),
+		zap.Int("retryCount", snapshot.RetryCount+1),
+		zap.Int("retryLimit", rc.globalConfig.Rewards.SnapshotRetryLimit),
+	)
+	if err := rc.cleanupFailedSnapshot(snapshot.SnapshotDate); err != nil {
+		return err
+	}
+	res := rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).
+		Where("snapshot_date = ?", snapshot.SnapshotDate).
+		Updates(map[string]interface{}{
+			"status":      storage.RewardSnapshotStatusProcessing.String(),
+			"retry_count": snapshot.RetryCount + 1,
+		})
+	return res.Error
+}
+
+// restartCancelledSnapshot cleans up a cancelled snapshot and marks it as processing again. Cancelling a calculation
+// is deliberate, so restarting it does not count as a retry.
+func (rc *RewardsCalculator) restartCancelledSnapshot(snapshot *storage.GeneratedRewardsSnapshots) error {
+	rc.logger.Sugar().Infow("Restarting cancelled rewards snapshot", zap.String("snapshotDate", snapshot.SnapshotDate))
+	if err := rc.cleanupFailedSnapshot(snapshot.SnapshotDate); err != nil {
+		return err
+	}
+	res := rc.grm.Model(&storage.GeneratedRewardsSnapshots{}).
+		Where("snapshot_date = ?", snapshot.SnapshotDate).
+		Update("status", storage.RewardSnapshotStatusProcessing.String())
+	return res.Error
+}
+
+// ResetFailedRewardSnapshot removes everything a failed calculation for the snapshot date left behind, including
+// its status, so that the snapshot is calculated from scratch the next time it is requested.
+func (rc *RewardsCalculator) ResetFailedRewardSnapshot(snapshotDate string) error {
+	if err := rc.acquireGenerationLock(); err != nil {
+		rc.logger.Sugar().Infow(err.Error())
+		return err
+	}
+	defer rc.releaseGenerationLock()
+
+	return rc.resetFailedRewardSnapshotLocked(snapshotDate)
+}
+
+// resetFailedRewardSnapshotLocked resets a failed snapshot. The caller must hold the rewards generation lock.
+func (rc *RewardsCalculator) resetFailedRewardSnapshotLocked(snapshotDate string) error {
+	snapshot, err := rc.GetRewardSnapshotStatus(snapshotDate)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return &ErrRewardsSnapshotNotFound{SnapshotDate: snapshotDate}
+	}
+	if snapshot.Status != storage.RewardSnapshotStatusFailed.String() {
+		return &ErrRewardsSnapshotNotFailed{SnapshotDate: snapshotDate, Status: snapshot.Status}
+	}
+
+	rc.logger.Sugar().Infow("Resetting failed rewards snapshot", zap.String("snapshotDate", snapshotDate))
+	if err := rc.cleanupFailedSnapshot(snapshotDate); err != nil {
+		return err
+	}
+	res := rc.grm.Delete(&storage.GeneratedRewardsSnapshots{}, snapshot.Id)
+	return res.Error
+}
+
+// RetryFailedRewardSnapshot resets a failed snapshot and calculates it again, regardless of how many times it
+// was already retried automatically.
+//
+// The rewards generation lock is held across the reset and the calculation so that nothing else can calculate
+// rewards for the snapshot date in between.
+func (rc *RewardsCalculator) RetryFailedRewardSnapshot(ctx context.Context, snapshotDate string) error {
+	if err := rc.acquireGenerationLock(); err != nil {
+		rc.logger.Sugar().Infow(err.Error())
+		return err
+	}
+	defer rc.releaseGenerationLock()
+
+	if err := rc.resetFailedRewardSnapshotLocked(snapshotDate); err != nil {
+		return err
+	}
+	return rc.calculateRewardsForSnapshotDateLocked(ctx, snapshotDate)
+}
@@ -0,0 +1,195 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewards/stakerOperators"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SnapshotRetry(t *testing.T) {
+	dbName, cfg, grm, l, sink, err := setupRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Rewards.SnapshotRetryLimit = 2
+
+	sog := stakerOperators.NewStakerOperatorGenerator(grm, l, cfg)
+	rc, err := NewRewardsCalculator(cfg, grm, nil, sog, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completedSnapshotDate := "2024-08-01"
+	failedSnapshotDate := "2024-08-02"
+
+	createSnapshot := func(t *testing.T, snapshotDate string, status storage.RewardSnapshotStatus) {
+		res := grm.Create(&storage.GeneratedRewardsSnapshots{SnapshotDate: snapshotDate, Status: status.String()})
+		assert.Nil(t, res.Error)
+	}
+	insertGoldRow := func(t *testing.T, snapshot string) {
+		res := grm.Exec(`insert into gold_table (earner, snapshot, reward_hash, token, amount) values (?, ?, ?, ?, ?)`,
+			"0xearner", snapshot, fmt.Sprintf("0xreward_%s", snapshot), "0xtoken", "100")
+		assert.Nil(t, res.Error)
+	}
+
+	t.Run("Should only retry failed snapshots up to the retry limit", func(t *testing.T) {
+		assert.True(t, rc.canRetrySnapshot(&storage.GeneratedRewardsSnapshots{RetryCount: 1, Retryable: true}))
+		assert.False(t, rc.canRetrySnapshot(&storage.GeneratedRewardsSnapshots{RetryCount: 2, Retryable: true}))
+		assert.False(t, rc.canRetrySnapshot(&storage.GeneratedRewardsSnapshots{RetryCount: 0, Retryable: false}))
+	})
+	t.Run("Should reject resetting snapshots that have not failed", func(t *testing.T) {
+		err := rc.ResetFailedRewardSnapshot(failedSnapshotDate)
+		var notFoundErr *ErrRewardsSnapshotNotFound
+		assert.True(t, errors.As(err, &notFoundErr))
+
+		createSnapshot(t, completedSnapshotDate, storage.RewardSnapshotStatusCompleted)
+
+		err = rc.ResetFailedRewardSnapshot(completedSnapshotDate)
+		var notFailedErr *ErrRewardsSnapshotNotFailed
+		assert.True(t, errors.As(err, &notFailedErr))
+	})
+	t.Run("Should clean up a failed snapshot", func(t *testing.T) {
+		createSnapshot(t, failedSnapshotDate, storage.RewardSnapshotStatusFailed)
+
+		goldTableNames := rewardsUtils.GetGoldTableNames(failedSnapshotDate)
+		partialTables := []string{
+			goldTableNames[rewardsUtils.Table_1_ActiveRewards],
+			goldTableNames[rewardsUtils.Sot_1_StakerStrategyPayouts],
+		}
+		for _, tableName := range partialTables {
+			res := grm.Exec(fmt.Sprintf(`create table %s (id integer)`, tableName))
+			assert.Nil(t, res.Error)
+		}
+		completedTable := rewardsUtils.GetGoldTableNames(completedSnapshotDate)[rewardsUtils.Table_1_ActiveRewards]
+		res := grm.Exec(fmt.Sprintf(`create table %s (id integer)`, completedTable))
+		assert.Nil(t, res.Error)
+
+		laterSnapshotDate := "2024-08-04"
+		insertGoldRow(t, completedSnapshotDate)
+		insertGoldRow(t, failedSnapshotDate)
+		insertGoldRow(t, laterSnapshotDate)
+
+		err := rc.ResetFailedRewardSnapshot(failedSnapshotDate)
+		assert.Nil(t, err)
+
+		tableNames, err := rc.findRewardsTablesBySnapshotDate(failedSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(tableNames))
+
+		tableNames, err = rc.findRewardsTablesBySnapshotDate(completedSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{completedTable}, tableNames)
+
+		goldRows, err := rc.ListGoldRows()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(goldRows))
+		goldRowSnapshots := []string{}
+		for _, row := range goldRows {
+			goldRowSnapshots = append(goldRowSnapshots, row.Snapshot.Format("2006-01-02"))
+		}
+		assert.ElementsMatch(t, []string{completedSnapshotDate, laterSnapshotDate}, goldRowSnapshots)
+
+		snapshot, err := rc.GetRewardSnapshotStatus(failedSnapshotDate)
+		assert.Nil(t, err)
+		assert.Nil(t, snapshot)
+	})
+	t.Run("Should count automatic retries", func(t *testing.T) {
+		createSnapshot(t, failedSnapshotDate, storage.RewardSnapshotStatusFailed)
+		snapshot, err := rc.GetRewardSnapshotStatus(failedSnapshotDate)
+		assert.Nil(t, err)
+
+		err = rc.restartFailedSnapshot(snapshot)
+		assert.Nil(t, err)
+
+		snapshot, err = rc.GetRewardSnapshotStatus(failedSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, storage.RewardSnapshotStatusProcessing.String(), snapshot.Status)
+		assert.Equal(t, 1, snapshot.RetryCount)
+	})
+	t.Run("Should mark cancelled calculations as cancelled and restart them without counting a retry", func(t *testing.T) {
+		cancelledSnapshotDate := "2024-08-03"
+		createSnapshot(t, cancelledSnapshotDate, storage.RewardSnapshotStatusProcessing)
+
+		rc.markSnapshotStopped(cancelledSnapshotDate, fmt.Errorf("failed to generate gold tables: %w", context.Canceled))
+		snapshot, err := rc.GetRewardSnapshotStatus(cancelledSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, storage.RewardSnapshotStatusCancelled.String(), snapshot.Status)
+
+		err = rc.ResetFailedRewardSnapshot(cancelledSnapshotDate)
+		var notFailedErr *ErrRewardsSnapshotNotFailed
+		assert.True(t, errors.As(err, &notFailedErr))
+
+		err = rc.restartCancelledSnapshot(snapshot)
+		assert.Nil(t, err)
+
+		snapshot, err = rc.GetRewardSnapshotStatus(cancelledSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, storage.RewardSnapshotStatusProcessing.String(), snapshot.Status)
+		assert.Equal(t, 0, snapshot.RetryCount)
+
+		rc.markSnapshotStopped(cancelledSnapshotDate, errors.New("failed to generate gold tables"))
+		snapshot, err = rc.GetRewardSnapshotStatus(cancelledSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, storage.RewardSnapshotStatusFailed.String(), snapshot.Status)
+	})
+	t.Run("Should not retry snapshots that violated invariants automatically", func(t *testing.T) {
+		invariantsSnapshotDate := "2024-08-05"
+		createSnapshot(t, invariantsSnapshotDate, storage.RewardSnapshotStatusProcessing)
+
+		rc.markSnapshotStopped(invariantsSnapshotDate, &ErrRewardsInvariantsViolated{
+			SnapshotDate: invariantsSnapshotDate,
+			Invariants:   []string{"rewardHashWithinSubmittedAmount"},
+		})
+		snapshot, err := rc.GetRewardSnapshotStatus(invariantsSnapshotDate)
+		assert.Nil(t, err)
+		assert.Equal(t, storage.RewardSnapshotStatusFailed.String(), snapshot.Status)
+		assert.False(t, snapshot.Retryable)
+		assert.Equal(t, 0, snapshot.RetryCount)
+		assert.False(t, rc.canRetrySnapshot(snapshot))
+
+		err = rc.calculateRewardsForSnapshotDate(context.Background(), invariantsSnapshotDate)
+		var notRetryableErr *ErrRewardsSnapshotNotRetryable
+		assert.True(t, errors.As(err, &notRetryableErr))
+
+		// other failures stay retryable
+		otherSnapshotDate := "2024-08-06"
+		createSnapshot(t, otherSnapshotDate, storage.RewardSnapshotStatusProcessing)
+		rc.markSnapshotStopped(otherSnapshotDate, errors.New("failed to generate gold tables"))
+		snapshot, err = rc.GetRewardSnapshotStatus(otherSnapshotDate)
+		assert.Nil(t, err)
+		assert.True(t, snapshot.Retryable)
+		assert.True(t, rc.canRetrySnapshot(snapshot))
+	})
+	t.Run("Should not generate rewards while another process sharing the database holds the lock", func(t *testing.T) {
+		otherRc, err := NewRewardsCalculator(cfg, grm, nil, sog, sink, l)
+		assert.Nil(t, err)
+
+		err = otherRc.acquireGenerationLock()
+		assert.Nil(t, err)
+
+		var inProgressErr *ErrRewardsCalculationInProgress
+		err = rc.ResetFailedRewardSnapshot(failedSnapshotDate)
+		assert.True(t, errors.As(err, &inProgressErr))
+		assert.False(t, rc.GetIsGenerating())
+
+		err = rc.RetryFailedRewardSnapshot(context.Background(), failedSnapshotDate)
+		assert.True(t, errors.As(err, &inProgressErr))
+
+		otherRc.releaseGenerationLock()
+
+		err = rc.acquireGenerationLock()
+		assert.Nil(t, err)
+		rc.releaseGenerationLock()
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
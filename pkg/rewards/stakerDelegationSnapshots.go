@@ -54,23 +54,28 @@ final_results as (
 	FROM
 		cleaned_records
 			CROSS JOIN
-		generate_series(DATE(start_time), DATE(end_time) - interval '1' day, interval '1' day) AS d
+		generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS d
 )
 select * from final_results
 `
 
 func (r *RewardsCalculator) GenerateAndInsertStakerDelegationSnapshots(snapshotDate string) error {
+	return r.generateAndInsertStakerDelegationSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertStakerDelegationSnapshots(snapshotDate string, startDate string) error {
 	tableName := "staker_delegation_snapshots"
 
 	query, err := rewardsUtils.RenderQueryTemplate(stakerDelegationSnapshotsQuery, map[string]interface{}{
 		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
 	})
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to render operator share snapshots query", "error", err)
 		return nil
 	}
 
-	err = r.generateAndInsertFromQuery(tableName, query, nil)
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to generate staker_delegation_snapshots", "error", err)
 		return err
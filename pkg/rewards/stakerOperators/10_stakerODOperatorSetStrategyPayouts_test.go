@@ -0,0 +1,70 @@
+package stakerOperators
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StakerODOperatorSetStrategyPayouts(t *testing.T) {
+	dbName, cfg, grm, l, err := setupStakerOperators()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cutoffDate := "2025-03-05"
+	tableNames := rewardsUtils.GetGoldTableNames(cutoffDate)
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateGoldTable(grm, l, tableNames[rewardsUtils.Table_13_StakerODOperatorSetRewardAmounts], `
+			reward_hash varchar, snapshot date, token varchar, avs varchar, operator_set_id bigint, operator varchar,
+			strategy varchar, multiplier numeric, staker varchar, shares numeric, staker_tokens numeric
+		`, `
+			('0xreward', '2025-03-02', '0xtoken', '0xavs', 1, '0xoperator_1', '0xstrategy_1', 1000000000000000000, '0xstaker_1', 1000000000000000000, 400000),
+			('0xreward', '2025-03-02', '0xtoken', '0xavs', 1, '0xoperator_1', '0xstrategy_1', 1000000000000000000, '0xstaker_2', 3000000000000000000, 1200000)
+		`)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should take the staker amounts as they are", func(t *testing.T) {
+		sog := NewStakerOperatorGenerator(grm, l, cfg)
+
+		err := sog.GenerateAndInsert10StakerODOperatorSetStrategyPayouts(cutoffDate)
+		assert.Nil(t, err)
+
+		type payout struct {
+			Staker        string
+			Operator      string
+			OperatorSetId uint64
+			Strategy      string
+			Shares        string
+			StakerTokens  string
+		}
+		var payouts []payout
+		res := grm.Raw(`
+			select
+				staker,
+				operator,
+				operator_set_id,
+				strategy,
+				shares::text as shares,
+				staker_tokens::text as staker_tokens
+			from ` + tableNames[rewardsUtils.Sot_10_StakerODOperatorSetStrategyPayouts] + `
+			order by staker
+		`).Scan(&payouts)
+		assert.Nil(t, res.Error)
+
+		assert.Equal(t, []payout{
+			{"0xstaker_1", "0xoperator_1", 1, "0xstrategy_1", "1000000000000000000", "400000"},
+			{"0xstaker_2", "0xoperator_1", 1, "0xstrategy_1", "3000000000000000000", "1200000"},
+		}, payouts)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
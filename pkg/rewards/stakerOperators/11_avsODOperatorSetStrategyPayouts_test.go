@@ -0,0 +1,63 @@
+package stakerOperators
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AvsODOperatorSetStrategyPayouts(t *testing.T) {
+	dbName, cfg, grm, l, err := setupStakerOperators()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cutoffDate := "2025-03-05"
+	tableNames := rewardsUtils.GetGoldTableNames(cutoffDate)
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateGoldTable(grm, l, tableNames[rewardsUtils.Table_14_AvsODOperatorSetRewardAmounts], `
+			reward_hash varchar, snapshot date, token varchar, avs varchar, operator_set_id bigint, operator varchar,
+			avs_tokens numeric
+		`, `
+			('0xreward', '2025-03-02', '0xtoken', '0xavs', 1, '0xoperator_2', 500000),
+			('0xreward', '2025-03-03', '0xtoken', '0xavs', 1, '0xoperator_2', 500000)
+		`)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should take the AVS refunds as they are", func(t *testing.T) {
+		sog := NewStakerOperatorGenerator(grm, l, cfg)
+
+		err := sog.GenerateAndInsert11AvsODOperatorSetStrategyPayouts(cutoffDate)
+		assert.Nil(t, err)
+
+		var payouts []*AvsODOperatorSetStrategyPayout
+		res := grm.Raw(`
+			select
+				reward_hash,
+				to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+				token,
+				avs,
+				operator_set_id,
+				operator,
+				avs_tokens::text as avs_tokens
+			from ` + tableNames[rewardsUtils.Sot_11_AvsODOperatorSetStrategyPayouts] + `
+			order by snapshot
+		`).Scan(&payouts)
+		assert.Nil(t, res.Error)
+
+		assert.Equal(t, []*AvsODOperatorSetStrategyPayout{
+			{RewardHash: "0xreward", Snapshot: "2025-03-02", Token: "0xtoken", Avs: "0xavs", OperatorSetId: 1, Operator: "0xoperator_2", AvsTokens: "500000"},
+			{RewardHash: "0xreward", Snapshot: "2025-03-03", Token: "0xtoken", Avs: "0xavs", OperatorSetId: 1, Operator: "0xoperator_2", AvsTokens: "500000"},
+		}, payouts)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
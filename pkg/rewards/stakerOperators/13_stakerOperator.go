@@ -50,30 +50,30 @@ type StakerOperator struct {
 	Snapshot   time.Time
 }
 
-func (sog *StakerOperatorsGenerator) GenerateAndInsert10StakerOperator(cutoffDate string) error {
-	sog.logger.Sugar().Infow("Generating and inserting 10_stakerOperator",
+func (sog *StakerOperatorsGenerator) GenerateAndInsert13StakerOperator(cutoffDate string) error {
+	sog.logger.Sugar().Infow("Generating and inserting 13_stakerOperator",
 		zap.String("cutoffDate", cutoffDate),
 	)
 	allTableNames := rewardsUtils.GetGoldTableNames(cutoffDate)
-	destTableName := rewardsUtils.Sot_10_StakerOperatorTable
+	destTableName := rewardsUtils.Sot_13_StakerOperatorTable
 
-	sog.logger.Sugar().Infow("Generating 10_stakerOperator",
+	sog.logger.Sugar().Infow("Generating 13_stakerOperator",
 		zap.String("destTableName", destTableName),
 		zap.String("cutoffDate", cutoffDate),
 	)
 
 	query, err := rewardsUtils.RenderQueryTemplate(_7_stakerOperator, map[string]interface{}{
 		"destTableName":         destTableName,
-		"stakerOperatorStaging": allTableNames[rewardsUtils.Sot_9_StakerOperatorStaging],
+		"stakerOperatorStaging": allTableNames[rewardsUtils.Sot_12_StakerOperatorStaging],
 	})
 	if err != nil {
-		sog.logger.Sugar().Errorw("Failed to render 10_stakerOperator query", "error", err)
+		sog.logger.Sugar().Errorw("Failed to render 13_stakerOperator query", "error", err)
 		return err
 	}
 
 	res := sog.db.Exec(query)
 	if res.Error != nil {
-		sog.logger.Sugar().Errorw("Failed to generate 10_stakerOperator",
+		sog.logger.Sugar().Errorw("Failed to generate 13_stakerOperator",
 			zap.String("cutoffDate", cutoffDate),
 			zap.Error(res.Error),
 		)
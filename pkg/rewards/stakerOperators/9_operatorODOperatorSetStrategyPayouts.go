@@ -0,0 +1,88 @@
+package stakerOperators
+
+import (
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"time"
+)
+
+// _9_operatorODOperatorSetStrategyPayoutQuery is the query that generates the operator OD operator set strategy payouts.
+//
+// Like the AVS-level operator-directed payouts, the operator's share of an operator set reward does not depend on
+// its strategies, so it is taken as is.
+const _9_operatorODOperatorSetStrategyPayoutQuery = `
+create table {{.destTableName}} as
+select
+	od.operator,
+	od.reward_hash,
+	od.snapshot,
+	od.token,
+	od.avs,
+	od.operator_set_id,
+	od.strategy,
+	od.multiplier,
+	od.reward_submission_date,
+	od.split_pct,
+	od.operator_tokens
+from {{.operatorODOperatorSetRewardAmountsTable}} as od
+`
+
+type OperatorODOperatorSetStrategyPayout struct {
+	RewardHash           string
+	Snapshot             time.Time
+	Token                string
+	Avs                  string
+	OperatorSetId        uint64
+	Strategy             string
+	Multiplier           string
+	RewardSubmissionDate time.Time
+	SplitPct             string
+	OperatorTokens       string
+}
+
+func (sog *StakerOperatorsGenerator) GenerateAndInsert9OperatorODOperatorSetStrategyPayouts(cutoffDate string) error {
+	rewardsV2_1Enabled, err := sog.globalConfig.IsRewardsV2_1EnabledForCutoffDate(cutoffDate)
+	if err != nil {
+		sog.logger.Sugar().Errorw("Failed to check if rewards v2.1 is enabled", "error", err)
+		return err
+	}
+	if !rewardsV2_1Enabled {
+		sog.logger.Sugar().Infow("Skipping 9_operatorODOperatorSetStrategyPayouts generation as rewards v2.1 is not enabled")
+		return nil
+	}
+	allTableNames := rewardsUtils.GetGoldTableNames(cutoffDate)
+	destTableName := allTableNames[rewardsUtils.Sot_9_OperatorODOperatorSetStrategyPayouts]
+
+	sog.logger.Sugar().Infow("Generating and inserting 9_operatorODOperatorSetStrategyPayouts",
+		"cutoffDate", cutoffDate,
+	)
+
+	if err := rewardsUtils.DropTableIfExists(sog.db, destTableName, sog.logger); err != nil {
+		sog.logger.Sugar().Errorw("Failed to drop table", "error", err)
+		return err
+	}
+
+	rewardsTables, err := sog.FindRewardsTableNamesForSearchPattersn(map[string]string{
+		rewardsUtils.Table_12_OperatorODOperatorSetRewardAmounts: rewardsUtils.GoldTableNameSearchPattern[rewardsUtils.Table_12_OperatorODOperatorSetRewardAmounts],
+	}, cutoffDate)
+	if err != nil {
+		sog.logger.Sugar().Errorw("Failed to find staker operator table names", "error", err)
+		return err
+	}
+
+	query, err := rewardsUtils.RenderQueryTemplate(_9_operatorODOperatorSetStrategyPayoutQuery, map[string]interface{}{
+		"destTableName": destTableName,
+		"operatorODOperatorSetRewardAmountsTable": rewardsTables[rewardsUtils.Table_12_OperatorODOperatorSetRewardAmounts],
+	})
+	if err != nil {
+		sog.logger.Sugar().Errorw("Failed to render 9_operatorODOperatorSetStrategyPayouts query", "error", err)
+		return err
+	}
+
+	res := sog.db.Exec(query)
+
+	if res.Error != nil {
+		sog.logger.Sugar().Errorw("Failed to generate 9_operatorODOperatorSetStrategyPayouts", "error", res.Error)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package stakerOperators
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OperatorODOperatorSetStrategyPayouts(t *testing.T) {
+	dbName, cfg, grm, l, err := setupStakerOperators()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cutoffDate := "2025-03-05"
+	tableNames := rewardsUtils.GetGoldTableNames(cutoffDate)
+
+	t.Run("Should hydrate dependency tables", func(t *testing.T) {
+		err := hydrateGoldTable(grm, l, tableNames[rewardsUtils.Table_12_OperatorODOperatorSetRewardAmounts], `
+			reward_hash varchar, snapshot date, token varchar, tokens_per_registered_snapshot_decimal numeric, avs varchar,
+			operator_set_id bigint, operator varchar, strategy varchar, multiplier numeric, reward_submission_date text,
+			rn bigint, split_pct numeric, operator_tokens numeric
+		`, `
+			('0xreward', '2025-03-02', '0xtoken', 2000000, '0xavs', 1, '0xoperator_1', '0xstrategy_1', 1000000000000000000, '2025-03-01', 1, 0.2, 400000),
+			('0xreward', '2025-03-03', '0xtoken', 2000000, '0xavs', 1, '0xoperator_1', '0xstrategy_1', 1000000000000000000, '2025-03-01', 1, 0.2, 400000)
+		`)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should take the operator amounts as they are", func(t *testing.T) {
+		sog := NewStakerOperatorGenerator(grm, l, cfg)
+
+		err := sog.GenerateAndInsert9OperatorODOperatorSetStrategyPayouts(cutoffDate)
+		assert.Nil(t, err)
+
+		type payout struct {
+			Operator       string
+			OperatorSetId  uint64
+			Strategy       string
+			Snapshot       string
+			OperatorTokens string
+		}
+		var payouts []payout
+		res := grm.Raw(`
+			select
+				operator,
+				operator_set_id,
+				strategy,
+				to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+				operator_tokens::text as operator_tokens
+			from ` + tableNames[rewardsUtils.Sot_9_OperatorODOperatorSetStrategyPayouts] + `
+			order by operator, snapshot
+		`).Scan(&payouts)
+		assert.Nil(t, res.Error)
+
+		assert.Equal(t, []payout{
+			{"0xoperator_1", 1, "0xstrategy_1", "2025-03-02", "400000"},
+			{"0xoperator_1", 1, "0xstrategy_1", "2025-03-03", "400000"},
+		}, payouts)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
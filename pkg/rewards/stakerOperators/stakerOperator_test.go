@@ -0,0 +1,66 @@
+package stakerOperators
+
+import (
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// setupStakerOperators runs against a custom chain since operator set rewards are not scheduled on any other chain
+// yet. Every rewards fork is active from the start of the chain.
+func setupStakerOperators() (
+	string,
+	*config.Config,
+	*gorm.DB,
+	*zap.Logger,
+	error,
+) {
+	cfg := tests.GetConfig()
+	cfg.Rewards.GenerateStakerOperatorsTable = true
+	cfg.Chain = config.Chain_Custom
+	cfg.ChainProfile = &config.ChainProfile{
+		RewardsForkDates: map[config.ForkName]string{
+			config.RewardsFork_Amazon:      "1970-01-01",
+			config.RewardsFork_Nile:        "1970-01-01",
+			config.RewardsFork_Panama:      "1970-01-01",
+			config.RewardsFork_Arno:        "1970-01-01",
+			config.RewardsFork_Trinity:     "1970-01-01",
+			config.RewardsFork_Mississippi: "1970-01-01",
+		},
+		ModelForks: map[config.ForkName]uint64{
+			config.ModelFork_Austin: 0,
+			config.ModelFork_Boston: 0,
+		},
+	}
+
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, cfg, grm, l, nil
+}
+
+// hydrateGoldTable creates a gold table of the cutoff date from the given columns and rows, standing in for the
+// output of the gold stage that a staker operator stage reads
+func hydrateGoldTable(grm *gorm.DB, l *zap.Logger, tableName string, columns string, rows string) error {
+	queries := []string{
+		`CREATE TABLE ` + tableName + ` (` + columns + `)`,
+		`INSERT INTO ` + tableName + ` VALUES ` + rows,
+	}
+	for _, query := range queries {
+		res := grm.Exec(query)
+		if res.Error != nil {
+			l.Sugar().Errorw("Failed to execute sql", "error", zap.Error(res.Error))
+			return res.Error
+		}
+	}
+	return nil
+}
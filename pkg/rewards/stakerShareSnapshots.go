@@ -44,21 +44,26 @@ SELECT
 FROM
     cleaned_records
 CROSS JOIN
-    generate_series(DATE(start_time), DATE(end_time) - interval '1' day, interval '1' day) AS day
+    generate_series(GREATEST(DATE(start_time), DATE '{{.startDate}}'), DATE(end_time) - interval '1' day, interval '1' day) AS day
 `
 
 func (r *RewardsCalculator) GenerateAndInsertStakerShareSnapshots(snapshotDate string) error {
+	return r.generateAndInsertStakerShareSnapshots(snapshotDate, "")
+}
+
+func (r *RewardsCalculator) generateAndInsertStakerShareSnapshots(snapshotDate string, startDate string) error {
 	tableName := "staker_share_snapshots"
 
 	query, err := rewardsUtils.RenderQueryTemplate(stakerShareSnapshotsQuery, map[string]interface{}{
 		"cutoffDate": snapshotDate,
+		"startDate":  snapshotTablesStartDate(startDate),
 	})
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to render query template", "error", err)
 		return err
 	}
 
-	err = r.generateAndInsertFromQuery(tableName, query, nil)
+	err = r.generateAndInsertSnapshotsFromQuery(tableName, query, nil, startDate)
 	if err != nil {
 		r.logger.Sugar().Errorw("Failed to generate staker_share_snapshots", "error", err)
 		return err
@@ -1,16 +1,25 @@
 package rewardsCalculatorQueue
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"go.uber.org/zap"
+)
 
 func (rcq *RewardsCalculatorQueue) Process() {
+	rcq.requeueRecoveredJobs()
+
 	for {
 		select {
 		case <-rcq.done:
 			rcq.logger.Sugar().Infow("Closing rewards calculation queue")
 			return
 		case msg := <-rcq.queue:
-			rcq.logger.Sugar().Infow("Processing rewards calculation message", "data", msg.Data)
-			response := rcq.processMessage(msg)
+			rcq.logger.Sugar().Infow("Processing rewards calculation message", "data", msg.Data, "jobId", msg.JobId)
+			response := rcq.processJob(msg)
 
 			if msg.ResponseChan != nil {
 				select {
@@ -26,30 +35,114 @@ func (rcq *RewardsCalculatorQueue) Process() {
 	}
 }
 
-func (rcq *RewardsCalculatorQueue) processMessage(msg *RewardsCalculationMessage) *RewardsCalculatorResponse {
+// requeueRecoveredJobs puts jobs that were still queued when the sidecar stopped back on the queue
+func (rcq *RewardsCalculatorQueue) requeueRecoveredJobs() {
+	jobs, err := rcq.recoverJobs()
+	if err != nil {
+		rcq.logger.Sugar().Errorw("Failed to recover rewards calculation jobs", zap.Error(err))
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+	rcq.logger.Sugar().Infow("Requeueing recovered rewards calculation jobs", zap.Int("count", len(jobs)))
+
+	// the queue is buffered, so push from a goroutine to avoid blocking if there are more jobs than it can hold
+	go func() {
+		for _, job := range jobs {
+			select {
+			case <-rcq.done:
+				return
+			case rcq.queue <- &RewardsCalculationMessage{
+				Data: RewardsCalculationData{
+					CalculationType: RewardsCalculationType(job.CalculationType),
+					CutoffDate:      job.CutoffDate,
+				},
+				JobId: job.Id,
+			}:
+			}
+		}
+	}()
+}
+
+// processJob runs the message's job, recording its status and progress
+func (rcq *RewardsCalculatorQueue) processJob(msg *RewardsCalculationMessage) *RewardsCalculatorResponse {
+	started, err := rcq.startJob(msg.JobId)
+	if err != nil {
+		rcq.logger.Sugar().Errorw("Failed to start rewards calculation job", zap.Uint64("jobId", msg.JobId), zap.Error(err))
+		return &RewardsCalculatorResponse{Error: err}
+	}
+	if !started {
+		rcq.logger.Sugar().Infow("Skipping rewards calculation job that is no longer queued", zap.Uint64("jobId", msg.JobId))
+		return &RewardsCalculatorResponse{
+			Data:  &RewardsCalculatorResponseData{JobId: msg.JobId},
+			Error: &ErrJobCancelled{JobId: msg.JobId},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rcq.runningMu.Lock()
+	rcq.runningJobId = msg.JobId
+	rcq.cancelRunning = cancel
+	rcq.runningMu.Unlock()
+	defer func() {
+		rcq.runningMu.Lock()
+		rcq.runningJobId = 0
+		rcq.cancelRunning = nil
+		rcq.runningMu.Unlock()
+	}()
+
+	ctx = rewards.WithProgressReporter(ctx, &jobProgress{rcq: rcq, jobId: msg.JobId, cancel: cancel})
+	response := rcq.processMessage(ctx, msg)
+	if response.Data == nil {
+		response.Data = &RewardsCalculatorResponseData{}
+	}
+	response.Data.JobId = msg.JobId
+
+	status := RewardsCalculationJobStatus_Succeeded
+	if response.Error != nil {
+		status = RewardsCalculationJobStatus_Failed
+		if errors.Is(response.Error, context.Canceled) {
+			status = RewardsCalculationJobStatus_Cancelled
+			response.Error = &ErrJobCancelled{JobId: msg.JobId}
+		}
+	}
+	rcq.finishJob(msg.JobId, status, response)
+	return response
+}
+
+func (rcq *RewardsCalculatorQueue) processMessage(ctx context.Context, msg *RewardsCalculationMessage) *RewardsCalculatorResponse {
 	response := &RewardsCalculatorResponse{}
 	cutoffDate := msg.Data.CutoffDate
 
 	switch msg.Data.CalculationType {
 	case RewardsCalculationType_CalculateRewards:
 		if cutoffDate == "" || cutoffDate == "latest" {
-			cutoffDateUsed, err := rcq.rewardsCalculator.CalculateRewardsForLatestSnapshot()
+			cutoffDateUsed, err := rcq.rewardsCalculator.CalculateRewardsForLatestSnapshot(ctx)
 			response.Error = err
 			response.Data = &RewardsCalculatorResponseData{CutoffDate: cutoffDateUsed}
 		} else {
-			response.Error = rcq.rewardsCalculator.CalculateRewardsForSnapshotDate(msg.Data.CutoffDate)
+			response.Error = rcq.rewardsCalculator.CalculateRewardsForSnapshotDate(ctx, msg.Data.CutoffDate)
 			response.Data = &RewardsCalculatorResponseData{CutoffDate: msg.Data.CutoffDate}
 		}
 	case RewardsCalculationType_BackfillStakerOperators:
-		response.Error = rcq.rewardsCalculator.BackfillAllStakerOperators()
+		response.Error = rcq.rewardsCalculator.BackfillAllStakerOperators(ctx)
 		response.Data = &RewardsCalculatorResponseData{}
 	case RewardsCalculationType_BackfillStakerOperatorsSnapshot:
 		if cutoffDate == "" {
 			response.Error = fmt.Errorf("cutoffDate date is required")
 			break
 		}
-		response.Error = rcq.rewardsCalculator.GenerateStakerOperatorsTableForPastSnapshot(msg.Data.CutoffDate)
+		response.Error = rcq.rewardsCalculator.GenerateStakerOperatorsTableForPastSnapshot(ctx, msg.Data.CutoffDate)
 		response.Data = &RewardsCalculatorResponseData{}
+	case RewardsCalculationType_RetryFailedSnapshot:
+		if cutoffDate == "" {
+			response.Error = fmt.Errorf("cutoffDate date is required")
+			break
+		}
+		response.Error = rcq.rewardsCalculator.RetryFailedRewardSnapshot(ctx, cutoffDate)
+		response.Data = &RewardsCalculatorResponseData{CutoffDate: cutoffDate}
 	default:
 		response.Error = fmt.Errorf("unknown calculation type %s", msg.Data.CalculationType)
 	}
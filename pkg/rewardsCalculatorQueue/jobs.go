@@ -0,0 +1,277 @@
+package rewardsCalculatorQueue
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RewardsCalculationJobStatus string
+
+const (
+	RewardsCalculationJobStatus_Queued    RewardsCalculationJobStatus = "queued"
+	RewardsCalculationJobStatus_Running   RewardsCalculationJobStatus = "running"
+	RewardsCalculationJobStatus_Succeeded RewardsCalculationJobStatus = "succeeded"
+	RewardsCalculationJobStatus_Failed    RewardsCalculationJobStatus = "failed"
+	RewardsCalculationJobStatus_Cancelled RewardsCalculationJobStatus = "cancelled"
+)
+
+func (s RewardsCalculationJobStatus) String() string {
+	return string(s)
+}
+
+// IsValid returns true if the status is one of the known job statuses
+func (s RewardsCalculationJobStatus) IsValid() bool {
+	switch s {
+	case RewardsCalculationJobStatus_Queued,
+		RewardsCalculationJobStatus_Running,
+		RewardsCalculationJobStatus_Succeeded,
+		RewardsCalculationJobStatus_Failed,
+		RewardsCalculationJobStatus_Cancelled:
+		return true
+	}
+	return false
+}
+
+// RewardsCalculationJob is the persisted record of a message sent to the RewardsCalculatorQueue
+type RewardsCalculationJob struct {
+	Id               uint64 `gorm:"primaryKey"`
+	CalculationType  string
+	CutoffDate       string
+	Status           string
+	Stage            string
+	CompletedSteps   int
+	TotalSteps       int
+	ResultCutoffDate string
+	Error            string
+	CancelRequested  bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	StartedAt        *time.Time
+	FinishedAt       *time.Time
+}
+
+func (*RewardsCalculationJob) TableName() string {
+	return "rewards_calculation_jobs"
+}
+
+var ErrJobNotFound = errors.New("rewards calculation job not found")
+
+type ErrInvalidJobStatus struct {
+	Status string
+}
+
+func (e *ErrInvalidJobStatus) Error() string {
+	return fmt.Sprintf("invalid rewards calculation job status '%s'", e.Status)
+}
+
+type ErrJobFinished struct {
+	JobId  uint64
+	Status string
+}
+
+func (e *ErrJobFinished) Error() string {
+	return fmt.Sprintf("rewards calculation job %d has already finished with status '%s'", e.JobId, e.Status)
+}
+
+type ErrJobCancelled struct {
+	JobId uint64
+}
+
+func (e *ErrJobCancelled) Error() string {
+	return fmt.Sprintf("rewards calculation job %d was cancelled", e.JobId)
+}
+
+func (rcq *RewardsCalculatorQueue) createJob(data RewardsCalculationData) (*RewardsCalculationJob, error) {
+	job := &RewardsCalculationJob{
+		CalculationType: string(data.CalculationType),
+		CutoffDate:      data.CutoffDate,
+		Status:          RewardsCalculationJobStatus_Queued.String(),
+	}
+	res := rcq.grm.Model(&RewardsCalculationJob{}).Clauses(clause.Returning{}).Create(job)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return job, nil
+}
+
+// GetJob returns the job with the given id
+func (rcq *RewardsCalculatorQueue) GetJob(jobId uint64) (*RewardsCalculationJob, error) {
+	job := &RewardsCalculationJob{}
+	res := rcq.grm.Model(&RewardsCalculationJob{}).Where("id = ?", jobId).First(job)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, res.Error
+	}
+	return job, nil
+}
+
+// ListJobs returns the most recent jobs, newest first, optionally filtered by status
+func (rcq *RewardsCalculatorQueue) ListJobs(status RewardsCalculationJobStatus, limit int) ([]*RewardsCalculationJob, error) {
+	jobs := make([]*RewardsCalculationJob, 0)
+	query := rcq.grm.Model(&RewardsCalculationJob{}).Order("id desc")
+	if status != "" {
+		if !status.IsValid() {
+			return nil, &ErrInvalidJobStatus{Status: status.String()}
+		}
+		query = query.Where("status = ?", status.String())
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if res := query.Find(&jobs); res.Error != nil {
+		return nil, res.Error
+	}
+	return jobs, nil
+}
+
+// CancelJob cancels a queued job, or requests cancellation of a running job.
+//
+// A running job stops before its next calculation step and is then marked as cancelled. Jobs that have
+// already finished cannot be cancelled.
+func (rcq *RewardsCalculatorQueue) CancelJob(jobId uint64) (*RewardsCalculationJob, error) {
+	now := time.Now()
+	res := rcq.grm.Model(&RewardsCalculationJob{}).
+		Where("id = ? and status = ?", jobId, RewardsCalculationJobStatus_Queued.String()).
+		Updates(map[string]interface{}{
+			"status":           RewardsCalculationJobStatus_Cancelled.String(),
+			"cancel_requested": true,
+			"finished_at":      now,
+			"updated_at":       now,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		res = rcq.grm.Model(&RewardsCalculationJob{}).
+			Where("id = ? and status = ?", jobId, RewardsCalculationJobStatus_Running.String()).
+			Updates(map[string]interface{}{
+				"cancel_requested": true,
+				"updated_at":       now,
+			})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected > 0 {
+			rcq.cancelRunningJob(jobId)
+		}
+	}
+
+	job, err := rcq.GetJob(jobId)
+	if err != nil {
+		return nil, err
+	}
+	if !job.CancelRequested {
+		return nil, &ErrJobFinished{JobId: jobId, Status: job.Status}
+	}
+	rcq.logger.Sugar().Infow("Cancelled rewards calculation job", zap.Uint64("jobId", jobId), zap.String("status", job.Status))
+	return job, nil
+}
+
+func (rcq *RewardsCalculatorQueue) cancelRunningJob(jobId uint64) {
+	rcq.runningMu.Lock()
+	defer rcq.runningMu.Unlock()
+	if rcq.runningJobId == jobId && rcq.cancelRunning != nil {
+		rcq.cancelRunning()
+	}
+}
+
+// startJob marks a queued job as running. Returns false if the job is no longer queued, e.g. it was cancelled.
+func (rcq *RewardsCalculatorQueue) startJob(jobId uint64) (bool, error) {
+	now := time.Now()
+	res := rcq.grm.Model(&RewardsCalculationJob{}).
+		Where("id = ? and status = ?", jobId, RewardsCalculationJobStatus_Queued.String()).
+		Updates(map[string]interface{}{
+			"status":     RewardsCalculationJobStatus_Running.String(),
+			"started_at": now,
+			"updated_at": now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (rcq *RewardsCalculatorQueue) finishJob(jobId uint64, status RewardsCalculationJobStatus, response *RewardsCalculatorResponse) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      status.String(),
+		"finished_at": now,
+		"updated_at":  now,
+	}
+	if response.Data != nil {
+		updates["result_cutoff_date"] = response.Data.CutoffDate
+	}
+	if response.Error != nil {
+		updates["error"] = response.Error.Error()
+	}
+	res := rcq.grm.Model(&RewardsCalculationJob{}).Where("id = ?", jobId).Updates(updates)
+	if res.Error != nil {
+		rcq.logger.Sugar().Errorw("Failed to update rewards calculation job", zap.Uint64("jobId", jobId), zap.Error(res.Error))
+	}
+}
+
+// recoverJobs fails jobs that were running when the sidecar stopped, since their progress was lost, and
+// returns the jobs that were still queued so they can be processed again.
+func (rcq *RewardsCalculatorQueue) recoverJobs() ([]*RewardsCalculationJob, error) {
+	now := time.Now()
+	res := rcq.grm.Model(&RewardsCalculationJob{}).
+		Where("status = ?", RewardsCalculationJobStatus_Running.String()).
+		Updates(map[string]interface{}{
+			"status":      RewardsCalculationJobStatus_Failed.String(),
+			"error":       "the sidecar stopped while the job was running",
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected > 0 {
+		rcq.logger.Sugar().Warnw("Failed rewards calculation jobs interrupted by a restart", zap.Int64("count", res.RowsAffected))
+	}
+
+	jobs := make([]*RewardsCalculationJob, 0)
+	res = rcq.grm.Model(&RewardsCalculationJob{}).
+		Where("status = ?", RewardsCalculationJobStatus_Queued.String()).
+		Order("id asc").
+		Find(&jobs)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return jobs, nil
+}
+
+// jobProgress records the progress of a running job and cancels it once cancellation has been requested.
+type jobProgress struct {
+	rcq    *RewardsCalculatorQueue
+	jobId  uint64
+	cancel func()
+}
+
+func (p *jobProgress) ReportProgress(stage rewards.CalculationStage, completedSteps int, totalSteps int) {
+	job := &RewardsCalculationJob{}
+	res := p.rcq.grm.Model(job).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "cancel_requested"}}}).
+		Where("id = ?", p.jobId).
+		Updates(map[string]interface{}{
+			"stage":           string(stage),
+			"completed_steps": completedSteps,
+			"total_steps":     totalSteps,
+			"updated_at":      time.Now(),
+		})
+	if res.Error != nil {
+		p.rcq.logger.Sugar().Errorw("Failed to update rewards calculation job progress", zap.Uint64("jobId", p.jobId), zap.Error(res.Error))
+		return
+	}
+	if job.CancelRequested {
+		p.cancel()
+	}
+}
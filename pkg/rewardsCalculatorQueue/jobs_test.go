@@ -0,0 +1,190 @@
+package rewardsCalculatorQueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/config"
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setup() (
+	string,
+	*config.Config,
+	*gorm.DB,
+	*zap.Logger,
+	error,
+) {
+	cfg := tests.GetConfig()
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, cfg, grm, l, nil
+}
+
+func Test_RewardsCalculationJobs(t *testing.T) {
+	dbName, cfg, grm, l, err := setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rcq := NewRewardsCalculatorQueue(nil, grm, l)
+
+	createJob := func(t *testing.T) *RewardsCalculationJob {
+		job, err := rcq.createJob(RewardsCalculationData{
+			CalculationType: RewardsCalculationType_CalculateRewards,
+			CutoffDate:      "2024-08-01",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+	getJob := func(t *testing.T, jobId uint64) *RewardsCalculationJob {
+		job, err := rcq.GetJob(jobId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+
+	t.Run("Should fail running jobs and return queued jobs when recovering", func(t *testing.T) {
+		runningJob := createJob(t)
+		started, err := rcq.startJob(runningJob.Id)
+		assert.Nil(t, err)
+		assert.True(t, started)
+
+		firstQueuedJob := createJob(t)
+		secondQueuedJob := createJob(t)
+
+		jobs, err := rcq.recoverJobs()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(jobs))
+		assert.Equal(t, firstQueuedJob.Id, jobs[0].Id)
+		assert.Equal(t, secondQueuedJob.Id, jobs[1].Id)
+
+		job := getJob(t, runningJob.Id)
+		assert.Equal(t, RewardsCalculationJobStatus_Failed.String(), job.Status)
+		assert.NotEmpty(t, job.Error)
+		assert.NotNil(t, job.FinishedAt)
+
+		rcq.finishJob(firstQueuedJob.Id, RewardsCalculationJobStatus_Succeeded, &RewardsCalculatorResponse{})
+		rcq.finishJob(secondQueuedJob.Id, RewardsCalculationJobStatus_Succeeded, &RewardsCalculatorResponse{})
+	})
+	t.Run("Should only start queued jobs", func(t *testing.T) {
+		job := createJob(t)
+
+		started, err := rcq.startJob(job.Id)
+		assert.Nil(t, err)
+		assert.True(t, started)
+
+		job = getJob(t, job.Id)
+		assert.Equal(t, RewardsCalculationJobStatus_Running.String(), job.Status)
+		assert.NotNil(t, job.StartedAt)
+
+		started, err = rcq.startJob(job.Id)
+		assert.Nil(t, err)
+		assert.False(t, started)
+	})
+	t.Run("Should cancel a queued job so that it never starts", func(t *testing.T) {
+		job := createJob(t)
+
+		cancelled, err := rcq.CancelJob(job.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, RewardsCalculationJobStatus_Cancelled.String(), cancelled.Status)
+		assert.True(t, cancelled.CancelRequested)
+		assert.NotNil(t, cancelled.FinishedAt)
+
+		started, err := rcq.startJob(job.Id)
+		assert.Nil(t, err)
+		assert.False(t, started)
+	})
+	t.Run("Should request cancellation of a running job", func(t *testing.T) {
+		job := createJob(t)
+		started, err := rcq.startJob(job.Id)
+		assert.Nil(t, err)
+		assert.True(t, started)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rcq.runningMu.Lock()
+		rcq.runningJobId = job.Id
+		rcq.cancelRunning = cancel
+		rcq.runningMu.Unlock()
+
+		cancelled, err := rcq.CancelJob(job.Id)
+		assert.Nil(t, err)
+		assert.True(t, cancelled.CancelRequested)
+		// the job is only marked as cancelled once it stops running
+		assert.Equal(t, RewardsCalculationJobStatus_Running.String(), cancelled.Status)
+		assert.True(t, errors.Is(ctx.Err(), context.Canceled))
+	})
+	t.Run("Should not cancel a finished job", func(t *testing.T) {
+		job := createJob(t)
+		rcq.finishJob(job.Id, RewardsCalculationJobStatus_Succeeded, &RewardsCalculatorResponse{
+			Data: &RewardsCalculatorResponseData{CutoffDate: "2024-08-01"},
+		})
+
+		_, err := rcq.CancelJob(job.Id)
+		var finishedErr *ErrJobFinished
+		assert.True(t, errors.As(err, &finishedErr))
+		assert.Equal(t, RewardsCalculationJobStatus_Succeeded.String(), finishedErr.Status)
+
+		_, err = rcq.CancelJob(job.Id + 1000)
+		assert.True(t, errors.Is(err, ErrJobNotFound))
+	})
+	t.Run("Should record progress and cancel the job once cancellation is requested", func(t *testing.T) {
+		job := createJob(t)
+		started, err := rcq.startJob(job.Id)
+		assert.Nil(t, err)
+		assert.True(t, started)
+
+		cancelCalls := 0
+		progress := &jobProgress{rcq: rcq, jobId: job.Id, cancel: func() { cancelCalls++ }}
+
+		progress.ReportProgress(rewards.CalculationStage_GoldTables, 3, 14)
+		job = getJob(t, job.Id)
+		assert.Equal(t, string(rewards.CalculationStage_GoldTables), job.Stage)
+		assert.Equal(t, 3, job.CompletedSteps)
+		assert.Equal(t, 14, job.TotalSteps)
+		assert.Equal(t, 0, cancelCalls)
+
+		res := grm.Model(&RewardsCalculationJob{}).Where("id = ?", job.Id).Update("cancel_requested", true)
+		assert.Nil(t, res.Error)
+
+		progress.ReportProgress(rewards.CalculationStage_GoldTables, 4, 14)
+		assert.Equal(t, 1, cancelCalls)
+		assert.Equal(t, 4, getJob(t, job.Id).CompletedSteps)
+	})
+	t.Run("Should list jobs filtered by status", func(t *testing.T) {
+		jobs, err := rcq.ListJobs(RewardsCalculationJobStatus_Cancelled, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(jobs))
+
+		jobs, err = rcq.ListJobs("", 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(jobs))
+		assert.Greater(t, jobs[0].Id, jobs[1].Id)
+
+		_, err = rcq.ListJobs("done", 0)
+		var invalidStatusErr *ErrInvalidJobStatus
+		assert.True(t, errors.As(err, &invalidStatusErr))
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -4,13 +4,15 @@ import (
 	"context"
 	"github.com/Layr-Labs/sidecar/pkg/rewards"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // NewRewardsCalculatorQueue creates a new RewardsCalculatorQueue
-func NewRewardsCalculatorQueue(rc *rewards.RewardsCalculator, logger *zap.Logger) *RewardsCalculatorQueue {
+func NewRewardsCalculatorQueue(rc *rewards.RewardsCalculator, grm *gorm.DB, logger *zap.Logger) *RewardsCalculatorQueue {
 	queue := &RewardsCalculatorQueue{
 		logger:            logger,
 		rewardsCalculator: rc,
+		grm:               grm,
 		// allow the queue to buffer up to 100 messages
 		queue: make(chan *RewardsCalculationMessage, 100),
 		done:  make(chan struct{}),
@@ -18,10 +20,18 @@ func NewRewardsCalculatorQueue(rc *rewards.RewardsCalculator, logger *zap.Logger
 	return queue
 }
 
-// Enqueue adds a new message to the queue and returns immediately
-func (rcq *RewardsCalculatorQueue) Enqueue(payload *RewardsCalculationMessage) {
-	rcq.logger.Sugar().Infow("Enqueueing rewards calculation message", "data", payload.Data)
+// Enqueue persists a job for the message, adds the message to the queue and returns immediately
+func (rcq *RewardsCalculatorQueue) Enqueue(payload *RewardsCalculationMessage) (*RewardsCalculationJob, error) {
+	job, err := rcq.createJob(payload.Data)
+	if err != nil {
+		rcq.logger.Sugar().Errorw("Failed to create rewards calculation job", "data", payload.Data, zap.Error(err))
+		return nil, err
+	}
+	payload.JobId = job.Id
+
+	rcq.logger.Sugar().Infow("Enqueueing rewards calculation message", "data", payload.Data, "jobId", job.Id)
 	rcq.queue <- payload
+	return job, nil
 }
 
 // EnqueueAndWait adds a new message to the queue and waits for a response or returns if the context is done
@@ -32,9 +42,11 @@ func (rcq *RewardsCalculatorQueue) EnqueueAndWait(ctx context.Context, data Rewa
 		Data:         data,
 		ResponseChan: responseChan,
 	}
-	rcq.Enqueue(payload)
+	if _, err := rcq.Enqueue(payload); err != nil {
+		return nil, err
+	}
 
-	rcq.logger.Sugar().Infow("Waiting for rewards calculation response", "data", data)
+	rcq.logger.Sugar().Infow("Waiting for rewards calculation response", "data", data, "jobId", payload.JobId)
 
 	select {
 	case response := <-responseChan:
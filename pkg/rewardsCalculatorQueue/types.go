@@ -1,8 +1,12 @@
 package rewardsCalculatorQueue
 
 import (
+	"context"
+	"sync"
+
 	"github.com/Layr-Labs/sidecar/pkg/rewards"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type RewardsCalculationType string
@@ -11,6 +15,7 @@ var (
 	RewardsCalculationType_CalculateRewards                RewardsCalculationType = "calculateRewards"
 	RewardsCalculationType_BackfillStakerOperators         RewardsCalculationType = "backfillStakerOperators"
 	RewardsCalculationType_BackfillStakerOperatorsSnapshot RewardsCalculationType = "backfillStakerOperatorsSnapshot"
+	RewardsCalculationType_RetryFailedSnapshot             RewardsCalculationType = "retryFailedSnapshot"
 )
 
 type RewardsCalculationData struct {
@@ -21,10 +26,14 @@ type RewardsCalculationData struct {
 type RewardsCalculationMessage struct {
 	Data         RewardsCalculationData
 	ResponseChan chan *RewardsCalculatorResponse
+
+	// JobId is the id of the persisted job for the message, set when the message is enqueued
+	JobId uint64
 }
 
 type RewardsCalculatorResponseData struct {
 	CutoffDate string
+	JobId      uint64
 }
 
 type RewardsCalculatorResponse struct {
@@ -35,6 +44,12 @@ type RewardsCalculatorResponse struct {
 type RewardsCalculatorQueue struct {
 	logger            *zap.Logger
 	rewardsCalculator *rewards.RewardsCalculator
+	grm               *gorm.DB
 	queue             chan *RewardsCalculationMessage
 	done              chan struct{}
+
+	// the job currently being processed, if any
+	runningMu     sync.Mutex
+	runningJobId  uint64
+	cancelRunning context.CancelFunc
 }
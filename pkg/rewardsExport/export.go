@@ -0,0 +1,129 @@
+// Package rewardsExport writes tabular rewards data to files in the formats consumed by reporting pipelines.
+package rewardsExport
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+type Format string
+
+const (
+	Format_Csv     Format = "csv"
+	Format_Jsonl   Format = "jsonl"
+	Format_Parquet Format = "parquet"
+)
+
+type Dataset string
+
+const (
+	// Dataset_Gold is the cumulative gold table, one row per earner, token and reward hash per snapshot
+	Dataset_Gold Dataset = "gold"
+
+	// Dataset_StakerOperator is the per-operator, per-strategy breakdown of the gold table
+	Dataset_StakerOperator Dataset = "staker_operator"
+
+	// Dataset_ClaimStatus is how much of the cumulative rewards of each earner and token have been claimed
+	Dataset_ClaimStatus Dataset = "claim_status"
+)
+
+var formats = []Format{Format_Csv, Format_Jsonl, Format_Parquet}
+
+var datasets = []Dataset{Dataset_Gold, Dataset_StakerOperator, Dataset_ClaimStatus}
+
+func ParseFormat(format string) (Format, error) {
+	for _, f := range formats {
+		if string(f) == format {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format '%s', expected one of %v", format, formats)
+}
+
+func ParseDataset(dataset string) (Dataset, error) {
+	for _, d := range datasets {
+		if string(d) == dataset {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export dataset '%s', expected one of %v", dataset, datasets)
+}
+
+// FileName returns the conventional name of an export file, e.g. gold_2025-01-01.csv.gz
+func FileName(dataset Dataset, snapshot string, format Format, compress bool) string {
+	name := fmt.Sprintf("%s_%s.%s", dataset, snapshot, format)
+	// parquet files are compressed internally and keep their extension so that readers recognize them
+	if compress && format != Format_Parquet {
+		name += ".gz"
+	}
+	return name
+}
+
+func ContentType(format Format, compress bool) string {
+	if compress && format != Format_Parquet {
+		return "application/gzip"
+	}
+	switch format {
+	case Format_Csv:
+		return "text/csv"
+	case Format_Jsonl:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// RecordWriter writes rows of string values, one value per column, in the order the columns were given.
+//
+// Close must be called once all rows are written to flush buffered data. It does not close the underlying writer.
+type RecordWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+// NewRecordWriter returns a RecordWriter for the format.
+//
+// When compress is set, CSV and JSON Lines output is gzipped as a whole, while Parquet output is a regular
+// Parquet file with gzip compressed pages.
+func NewRecordWriter(w io.Writer, format Format, columns []string, compress bool) (RecordWriter, error) {
+	if format == Format_Parquet {
+		return newParquetWriter(w, columns, compress)
+	}
+
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	var rw RecordWriter
+	var err error
+	switch format {
+	case Format_Csv:
+		rw, err = newCsvWriter(w, columns)
+	case Format_Jsonl:
+		rw = newJsonlWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s'", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if gz != nil {
+		return &gzipRecordWriter{RecordWriter: rw, gz: gz}, nil
+	}
+	return rw, nil
+}
+
+type gzipRecordWriter struct {
+	RecordWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipRecordWriter) Close() error {
+	if err := w.RecordWriter.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
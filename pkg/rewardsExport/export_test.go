@@ -0,0 +1,191 @@
+package rewardsExport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = []string{"earner", "token", "amount"}
+
+var testRecords = [][]string{
+	{"0xearner1", "0xtoken", "1000000000000000000000000"},
+	{"0xearner2", "0xtoken", "12, with a comma"},
+}
+
+func writeTestRecords(t *testing.T, format Format, compress bool) []byte {
+	var buf bytes.Buffer
+	rw, err := NewRecordWriter(&buf, format, testColumns, compress)
+	assert.Nil(t, err)
+	for _, record := range testRecords {
+		assert.Nil(t, rw.Write(record))
+	}
+	assert.Nil(t, rw.Close())
+	return buf.Bytes()
+}
+
+func Test_ParseFormatAndDataset(t *testing.T) {
+	format, err := ParseFormat("parquet")
+	assert.Nil(t, err)
+	assert.Equal(t, Format_Parquet, format)
+
+	_, err = ParseFormat("xlsx")
+	assert.NotNil(t, err)
+
+	dataset, err := ParseDataset("claim_status")
+	assert.Nil(t, err)
+	assert.Equal(t, Dataset_ClaimStatus, dataset)
+
+	_, err = ParseDataset("")
+	assert.NotNil(t, err)
+}
+
+func Test_FileName(t *testing.T) {
+	assert.Equal(t, "gold_2025-01-01.csv", FileName(Dataset_Gold, "2025-01-01", Format_Csv, false))
+	assert.Equal(t, "gold_2025-01-01.jsonl.gz", FileName(Dataset_Gold, "2025-01-01", Format_Jsonl, true))
+	assert.Equal(t, "gold_2025-01-01.parquet", FileName(Dataset_Gold, "2025-01-01", Format_Parquet, true))
+}
+
+func Test_CsvWriter(t *testing.T) {
+	t.Run("Should write a header and quote values", func(t *testing.T) {
+		out := writeTestRecords(t, Format_Csv, false)
+		assert.Equal(t, "earner,token,amount\n"+
+			"0xearner1,0xtoken,1000000000000000000000000\n"+
+			"0xearner2,0xtoken,\"12, with a comma\"\n", string(out))
+	})
+	t.Run("Should gzip the whole file", func(t *testing.T) {
+		gz, err := gzip.NewReader(bytes.NewReader(writeTestRecords(t, Format_Csv, true)))
+		assert.Nil(t, err)
+		out, err := io.ReadAll(gz)
+		assert.Nil(t, err)
+		assert.Equal(t, string(writeTestRecords(t, Format_Csv, false)), string(out))
+	})
+}
+
+func Test_JsonlWriter(t *testing.T) {
+	out := writeTestRecords(t, Format_Jsonl, false)
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	assert.Equal(t, len(testRecords), len(lines))
+
+	for i, line := range lines {
+		var row map[string]string
+		assert.Nil(t, json.Unmarshal([]byte(line), &row))
+		for j, column := range testColumns {
+			assert.Equal(t, testRecords[i][j], row[column])
+		}
+	}
+
+	var buf bytes.Buffer
+	rw, err := NewRecordWriter(&buf, Format_Jsonl, testColumns, false)
+	assert.Nil(t, err)
+	assert.NotNil(t, rw.Write([]string{"0xearner1"}))
+}
+
+// readTestParquet reads a Parquet file back with an independent reader and returns its rows as records in the order
+// of testColumns, along with the compression codecs of its column chunks
+func readTestParquet(t *testing.T, out []byte) ([][]string, []format.CompressionCodec) {
+	f, err := parquet.OpenFile(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columnIndexes := make([]int, 0, len(testColumns))
+	for _, column := range testColumns {
+		leaf, ok := f.Schema().Lookup(column)
+		assert.True(t, ok)
+		assert.Equal(t, parquet.String().Type(), leaf.Node.Type())
+		assert.True(t, leaf.Node.Required())
+		columnIndexes = append(columnIndexes, leaf.ColumnIndex)
+	}
+
+	codecs := make([]format.CompressionCodec, 0)
+	for _, rowGroup := range f.Metadata().RowGroups {
+		for _, column := range rowGroup.Columns {
+			codecs = append(codecs, column.MetaData.Codec)
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	defer reader.Close()
+
+	records := make([][]string, 0)
+	rows := make([]parquet.Row, 10)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			record := make([]string, 0, len(columnIndexes))
+			for _, columnIndex := range columnIndexes {
+				record = append(record, row[columnIndex].String())
+			}
+			records = append(records, record)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return records, codecs
+}
+
+func Test_ParquetWriter(t *testing.T) {
+	t.Run("Should round trip the records", func(t *testing.T) {
+		records, codecs := readTestParquet(t, writeTestRecords(t, Format_Parquet, false))
+		assert.Equal(t, testRecords, records)
+		for _, codec := range codecs {
+			assert.Equal(t, format.Uncompressed, codec)
+		}
+	})
+	t.Run("Should gzip the pages of a compressed file", func(t *testing.T) {
+		records, codecs := readTestParquet(t, writeTestRecords(t, Format_Parquet, true))
+		assert.Equal(t, testRecords, records)
+		assert.Equal(t, len(testColumns), len(codecs))
+		for _, codec := range codecs {
+			assert.Equal(t, format.Gzip, codec)
+		}
+	})
+	t.Run("Should split large exports into row groups", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, err := NewRecordWriter(&buf, Format_Parquet, testColumns, false)
+		assert.Nil(t, err)
+		expected := make([][]string, 0, parquetRowGroupSize+1)
+		for i := 0; i < parquetRowGroupSize+1; i++ {
+			record := []string{fmt.Sprintf("0xearner%d", i), "0xtoken", fmt.Sprintf("%d", i)}
+			expected = append(expected, record)
+			assert.Nil(t, rw.Write(record))
+		}
+		assert.Nil(t, rw.Close())
+
+		f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(f.Metadata().RowGroups))
+
+		records, _ := readTestParquet(t, buf.Bytes())
+		assert.Equal(t, expected, records)
+	})
+	t.Run("Should write a valid file without rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, err := NewRecordWriter(&buf, Format_Parquet, testColumns, false)
+		assert.Nil(t, err)
+		assert.Nil(t, rw.Close())
+
+		records, _ := readTestParquet(t, buf.Bytes())
+		assert.Equal(t, 0, len(records))
+	})
+	t.Run("Should reject records with the wrong number of values", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, err := NewRecordWriter(&buf, Format_Parquet, testColumns, false)
+		assert.Nil(t, err)
+		assert.NotNil(t, rw.Write([]string{"0xearner1"}))
+	})
+}
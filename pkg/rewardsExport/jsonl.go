@@ -0,0 +1,51 @@
+package rewardsExport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsonlWriter struct {
+	w *bufio.Writer
+
+	// keys holds the JSON encoded column names so they are not re-encoded for every row
+	keys [][]byte
+}
+
+func newJsonlWriter(w io.Writer, columns []string) *jsonlWriter {
+	keys := make([][]byte, 0, len(columns))
+	for _, c := range columns {
+		key, _ := json.Marshal(c)
+		keys = append(keys, key)
+	}
+	return &jsonlWriter{w: bufio.NewWriter(w), keys: keys}
+}
+
+// Write writes the record as a JSON object keyed by column name. Values are always strings, since amounts
+// do not fit in a JSON number without losing precision.
+func (jw *jsonlWriter) Write(record []string) error {
+	if len(record) != len(jw.keys) {
+		return fmt.Errorf("expected %d values, got %d", len(jw.keys), len(record))
+	}
+	jw.w.WriteByte('{')
+	for i, value := range record {
+		if i > 0 {
+			jw.w.WriteByte(',')
+		}
+		jw.w.Write(jw.keys[i])
+		jw.w.WriteByte(':')
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		jw.w.Write(encoded)
+	}
+	jw.w.WriteByte('}')
+	return jw.w.WriteByte('\n')
+}
+
+func (jw *jsonlWriter) Close() error {
+	return jw.w.Flush()
+}
@@ -0,0 +1,70 @@
+package rewardsExport
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRowGroupSize is the number of rows buffered in memory before they are written out as a row group
+const parquetRowGroupSize = 100_000
+
+// parquetWriter writes a Parquet file in which every column is a required UTF8 string.
+//
+// Rows are buffered by the underlying writer and written out as row groups of parquetRowGroupSize rows, so memory use
+// is bounded by the row group size rather than by the size of the export.
+type parquetWriter struct {
+	writer *parquet.Writer
+	// columnIndexes maps the position of a value in a record to its column in the schema, which orders columns by name
+	columnIndexes []int
+	row           parquet.Row
+}
+
+func newParquetWriter(w io.Writer, columns []string, compress bool) (*parquetWriter, error) {
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[column] = parquet.String()
+	}
+	if len(group) != len(columns) {
+		return nil, fmt.Errorf("parquet columns must be unique, got %v", columns)
+	}
+	schema := parquet.NewSchema("rewards", group)
+
+	columnIndexes := make([]int, 0, len(columns))
+	for _, column := range columns {
+		leaf, _ := schema.Lookup(column)
+		columnIndexes = append(columnIndexes, leaf.ColumnIndex)
+	}
+
+	options := []parquet.WriterOption{
+		schema,
+		parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		parquet.CreatedBy("sidecar", "", ""),
+	}
+	if compress {
+		options = append(options, parquet.Compression(&parquet.Gzip))
+	}
+
+	return &parquetWriter{
+		writer:        parquet.NewWriter(w, options...),
+		columnIndexes: columnIndexes,
+		row:           make(parquet.Row, len(columns)),
+	}, nil
+}
+
+func (pw *parquetWriter) Write(record []string) error {
+	if len(record) != len(pw.columnIndexes) {
+		return fmt.Errorf("expected %d values, got %d", len(pw.columnIndexes), len(record))
+	}
+	for i, value := range record {
+		columnIndex := pw.columnIndexes[i]
+		pw.row[columnIndex] = parquet.ByteArrayValue([]byte(value)).Level(0, 0, columnIndex)
+	}
+	_, err := pw.writer.WriteRows([]parquet.Row{pw.row})
+	return err
+}
+
+func (pw *parquetWriter) Close() error {
+	return pw.writer.Close()
+}
@@ -23,8 +23,15 @@ var (
 	Table_8_OperatorODRewardAmounts = "gold_8_operator_od_reward_amounts"
 	Table_9_StakerODRewardAmounts   = "gold_9_staker_od_reward_amounts"
 	Table_10_AvsODRewardAmounts     = "gold_10_avs_od_reward_amounts"
-	Table_11_GoldStaging            = "gold_11_staging"
-	Table_12_GoldTable              = "gold_table"
+
+	// Rewards v2.1
+	Table_11_ActiveODOperatorSetRewards         = "gold_11_active_od_operator_set_rewards"
+	Table_12_OperatorODOperatorSetRewardAmounts = "gold_12_operator_od_operator_set_reward_amounts"
+	Table_13_StakerODOperatorSetRewardAmounts   = "gold_13_staker_od_operator_set_reward_amounts"
+	Table_14_AvsODOperatorSetRewardAmounts      = "gold_14_avs_od_operator_set_reward_amounts"
+
+	Table_15_GoldStaging = "gold_15_staging"
+	Table_16_GoldTable   = "gold_table"
 
 	Sot_1_StakerStrategyPayouts       = "sot_1_staker_strategy_payouts"
 	Sot_2_OperatorStrategyPayouts     = "sot_2_operator_strategy_payouts"
@@ -34,8 +41,14 @@ var (
 	Sot_6_OperatorODStrategyPayouts   = "sot_6_operator_od_strategy_payouts"
 	Sot_7_StakerODStrategyPayouts     = "sot_7_staker_od_strategy_payouts"
 	Sot_8_AvsODStrategyPayouts        = "sot_8_avs_od_strategy_payouts"
-	Sot_9_StakerOperatorStaging       = "sot_9_staker_operator_staging"
-	Sot_10_StakerOperatorTable        = "staker_operator"
+
+	// Rewards v2.1
+	Sot_9_OperatorODOperatorSetStrategyPayouts = "sot_9_operator_od_operator_set_strategy_payouts"
+	Sot_10_StakerODOperatorSetStrategyPayouts  = "sot_10_staker_od_operator_set_strategy_payouts"
+	Sot_11_AvsODOperatorSetStrategyPayouts     = "sot_11_avs_od_operator_set_strategy_payouts"
+
+	Sot_12_StakerOperatorStaging = "sot_12_staker_operator_staging"
+	Sot_13_StakerOperatorTable   = "staker_operator"
 )
 
 var goldTableBaseNames = map[string]string{
@@ -49,8 +62,13 @@ var goldTableBaseNames = map[string]string{
 	Table_8_OperatorODRewardAmounts: Table_8_OperatorODRewardAmounts,
 	Table_9_StakerODRewardAmounts:   Table_9_StakerODRewardAmounts,
 	Table_10_AvsODRewardAmounts:     Table_10_AvsODRewardAmounts,
-	Table_11_GoldStaging:            Table_11_GoldStaging,
-	Table_12_GoldTable:              Table_12_GoldTable,
+
+	Table_11_ActiveODOperatorSetRewards:         Table_11_ActiveODOperatorSetRewards,
+	Table_12_OperatorODOperatorSetRewardAmounts: Table_12_OperatorODOperatorSetRewardAmounts,
+	Table_13_StakerODOperatorSetRewardAmounts:   Table_13_StakerODOperatorSetRewardAmounts,
+	Table_14_AvsODOperatorSetRewardAmounts:      Table_14_AvsODOperatorSetRewardAmounts,
+	Table_15_GoldStaging:                        Table_15_GoldStaging,
+	Table_16_GoldTable:                          Table_16_GoldTable,
 
 	Sot_1_StakerStrategyPayouts:       Sot_1_StakerStrategyPayouts,
 	Sot_2_OperatorStrategyPayouts:     Sot_2_OperatorStrategyPayouts,
@@ -60,7 +78,11 @@ var goldTableBaseNames = map[string]string{
 	Sot_6_OperatorODStrategyPayouts:   Sot_6_OperatorODStrategyPayouts,
 	Sot_7_StakerODStrategyPayouts:     Sot_7_StakerODStrategyPayouts,
 	Sot_8_AvsODStrategyPayouts:        Sot_8_AvsODStrategyPayouts,
-	Sot_9_StakerOperatorStaging:       Sot_9_StakerOperatorStaging,
+
+	Sot_9_OperatorODOperatorSetStrategyPayouts: Sot_9_OperatorODOperatorSetStrategyPayouts,
+	Sot_10_StakerODOperatorSetStrategyPayouts:  Sot_10_StakerODOperatorSetStrategyPayouts,
+	Sot_11_AvsODOperatorSetStrategyPayouts:     Sot_11_AvsODOperatorSetStrategyPayouts,
+	Sot_12_StakerOperatorStaging:               Sot_12_StakerOperatorStaging,
 }
 
 var GoldTableNameSearchPattern = map[string]string{
@@ -74,7 +96,12 @@ var GoldTableNameSearchPattern = map[string]string{
 	Table_8_OperatorODRewardAmounts: "gold_%_operator_od_reward_amounts",
 	Table_9_StakerODRewardAmounts:   "gold_%_staker_od_reward_amounts",
 	Table_10_AvsODRewardAmounts:     "gold_%_avs_od_reward_amounts",
-	Table_11_GoldStaging:            "gold_%_staging",
+
+	Table_11_ActiveODOperatorSetRewards:         "gold_%_active_od_operator_set_rewards",
+	Table_12_OperatorODOperatorSetRewardAmounts: "gold_%_operator_od_operator_set_reward_amounts",
+	Table_13_StakerODOperatorSetRewardAmounts:   "gold_%_staker_od_operator_set_reward_amounts",
+	Table_14_AvsODOperatorSetRewardAmounts:      "gold_%_avs_od_operator_set_reward_amounts",
+	Table_15_GoldStaging:                        "gold_%_staging",
 
 	Sot_1_StakerStrategyPayouts:       "sot_%_staker_strategy_payouts",
 	Sot_2_OperatorStrategyPayouts:     "sot_%_operator_strategy_payouts",
@@ -84,7 +111,11 @@ var GoldTableNameSearchPattern = map[string]string{
 	Sot_6_OperatorODStrategyPayouts:   "sot_%_operator_od_strategy_payouts",
 	Sot_7_StakerODStrategyPayouts:     "sot_%_staker_od_strategy_payouts",
 	Sot_8_AvsODStrategyPayouts:        "sot_%_avs_od_strategy_payouts",
-	Sot_9_StakerOperatorStaging:       "sot_%_staker_operator_staging",
+
+	Sot_9_OperatorODOperatorSetStrategyPayouts: "sot_%_operator_od_operator_set_strategy_payouts",
+	Sot_10_StakerODOperatorSetStrategyPayouts:  "sot_%_staker_od_operator_set_strategy_payouts",
+	Sot_11_AvsODOperatorSetStrategyPayouts:     "sot_%_avs_od_operator_set_strategy_payouts",
+	Sot_12_StakerOperatorStaging:               "sot_%_staker_operator_staging",
 }
 
 func GetGoldTableNames(snapshotDate string) map[string]string {
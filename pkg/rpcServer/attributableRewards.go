@@ -0,0 +1,224 @@
+package rpcServer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsCalculatorQueue"
+	"github.com/Layr-Labs/sidecar/pkg/service/rewardsDataService"
+	"github.com/Layr-Labs/sidecar/pkg/service/types"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The attributable rewards requests only carry the snapshot or the distribution root, so the filters and the
+// pagination are passed as request metadata. Over HTTP they are passed as query parameters, e.g.
+//
+//	GET /rewards/v1/attributable-rewards/2025-01-01?earner=0x123...&token=0x456...&page=2&page_size=500
+const (
+	attributableRewardsEarnerKey   = "earner"
+	attributableRewardsOperatorKey = "operator"
+	attributableRewardsAvsKey      = "avs"
+	attributableRewardsStrategyKey = "strategy"
+	attributableRewardsTokenKey    = "token"
+	attributableRewardsPageKey     = "page"
+	attributableRewardsPageSizeKey = "page_size"
+
+	// attributableRewardsNextPageHeader is set on responses that filled a whole page.
+	// Over HTTP it is returned as the Grpc-Metadata-X-Next-Page header.
+	attributableRewardsNextPageHeader = "x-next-page"
+)
+
+var attributableRewardsParamKeys = []string{
+	attributableRewardsEarnerKey,
+	attributableRewardsOperatorKey,
+	attributableRewardsAvsKey,
+	attributableRewardsStrategyKey,
+	attributableRewardsTokenKey,
+	attributableRewardsPageKey,
+	attributableRewardsPageSizeKey,
+}
+
+func isAttributableRewardsMethod(method string) bool {
+	return strings.HasSuffix(method, "/GetAttributableRewardsForSnapshot") ||
+		strings.HasSuffix(method, "/GetAttributableRewardsForDistributionRoot")
+}
+
+// attributableRewardsMetadataFromQuery copies the attributable rewards query parameters of an HTTP request
+// into the metadata passed to the gRPC handler
+func attributableRewardsMetadataFromQuery(r *http.Request, md map[string]string) {
+	query := r.URL.Query()
+	for _, key := range attributableRewardsParamKeys {
+		if value := query.Get(key); value != "" {
+			md[key] = value
+		}
+	}
+}
+
+func getMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// getAttributableRewardsParams reads the filters and pagination of an attributable rewards request from its metadata
+func getAttributableRewardsParams(ctx context.Context) (*rewardsDataService.AttributableRewardsFilters, *types.Pagination, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	filters := &rewardsDataService.AttributableRewardsFilters{
+		Earner:   getMetadataValue(md, attributableRewardsEarnerKey),
+		Operator: getMetadataValue(md, attributableRewardsOperatorKey),
+		Avs:      getMetadataValue(md, attributableRewardsAvsKey),
+		Strategy: getMetadataValue(md, attributableRewardsStrategyKey),
+		Token:    getMetadataValue(md, attributableRewardsTokenKey),
+	}
+
+	pagination := types.NewDefaultPagination()
+	var page, pageSize uint64
+	var err error
+	if p := getMetadataValue(md, attributableRewardsPageKey); p != "" {
+		if page, err = strconv.ParseUint(p, 10, 32); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s '%s'", attributableRewardsPageKey, p)
+		}
+	}
+	if p := getMetadataValue(md, attributableRewardsPageSizeKey); p != "" {
+		if pageSize, err = strconv.ParseUint(p, 10, 32); err != nil || pageSize == 0 {
+			return nil, nil, fmt.Errorf("invalid %s '%s'", attributableRewardsPageSizeKey, p)
+		}
+	}
+	pagination.Load(uint32(page), uint32(pageSize))
+
+	return filters, pagination, nil
+}
+
+// forwardAttributableRewardsParams returns a context that passes the filters and pagination of the incoming
+// request on to the primary sidecar
+func forwardAttributableRewardsParams(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	pairs := make([]string, 0)
+	for _, key := range attributableRewardsParamKeys {
+		if value := getMetadataValue(md, key); value != "" {
+			pairs = append(pairs, key, value)
+		}
+	}
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs(pairs...))
+}
+
+func (rpc *RpcServer) setAttributableRewardsNextPageHeader(ctx context.Context, nextPage ...string) {
+	if len(nextPage) == 0 || nextPage[0] == "" {
+		return
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(attributableRewardsNextPageHeader, nextPage[0])); err != nil {
+		rpc.Logger.Sugar().Debugw("Failed to set next page header", zap.Error(err))
+	}
+}
+
+// ensureStakerOperatorsSnapshot makes sure that the staker-operator data covering the snapshot has been generated,
+// generating it if needed. False is returned if the data is missing and this sidecar cannot generate it because
+// it is not the primary.
+func (rpc *RpcServer) ensureStakerOperatorsSnapshot(ctx context.Context, snapshot string) (bool, error) {
+	sos, err := rpc.rewardsDataService.GetStakerOperatorsSnapshot(ctx, snapshot)
+	if err != nil {
+		return false, status.Error(codes.Internal, err.Error())
+	}
+	if sos == nil {
+		return false, status.Error(codes.NotFound, fmt.Sprintf("rewards have not been calculated for snapshot '%s'", snapshot))
+	}
+	if sos.Generated {
+		return true, nil
+	}
+	if !rpc.globalConfig.SidecarPrimaryConfig.IsPrimary {
+		return false, nil
+	}
+	if !rpc.globalConfig.Rewards.GenerateStakerOperatorsTable {
+		return false, status.Error(codes.FailedPrecondition, fmt.Sprintf("staker operator data has not been generated for snapshot '%s' and generating it is disabled", sos.RewardsSnapshotDate))
+	}
+
+	rpc.Logger.Sugar().Infow("Generating missing staker operator data for attributable rewards",
+		zap.String("snapshot", snapshot),
+		zap.String("rewardsSnapshotDate", sos.RewardsSnapshotDate),
+	)
+	_, err = rpc.rewardsQueue.EnqueueAndWait(ctx, rewardsCalculatorQueue.RewardsCalculationData{
+		CalculationType: rewardsCalculatorQueue.RewardsCalculationType_BackfillStakerOperatorsSnapshot,
+		CutoffDate:      sos.RewardsSnapshotDate,
+	})
+	if err != nil {
+		if errors.Is(err, &rewards.ErrRewardsCalculationInProgress{}) {
+			return false, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return false, status.Error(codes.Internal, err.Error())
+	}
+
+	sos, err = rpc.rewardsDataService.GetStakerOperatorsSnapshot(ctx, snapshot)
+	if err != nil {
+		return false, status.Error(codes.Internal, err.Error())
+	}
+	if sos == nil || !sos.Generated {
+		return false, status.Error(codes.Internal, fmt.Sprintf("failed to generate staker operator data for snapshot '%s'", snapshot))
+	}
+	return true, nil
+}
+
+func (rpc *RpcServer) listAttributableRewards(ctx context.Context, snapshot string) ([]*rewardsV1.AttributableReward, error) {
+	filters, pagination, err := getAttributableRewardsParams(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	attributableRewards, err := rpc.rewardsDataService.ListAttributableRewardsForSnapshot(ctx, snapshot, filters, pagination)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if uint32(len(attributableRewards)) == pagination.PageSize {
+		rpc.setAttributableRewardsNextPageHeader(ctx, strconv.FormatUint(uint64(pagination.Page+1), 10))
+	}
+
+	rewardsRes := make([]*rewardsV1.AttributableReward, 0, len(attributableRewards))
+	for _, r := range attributableRewards {
+		rewardType, err := convertStakerOperatorRewardTypeToEnum(r.RewardType)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		rewardsRes = append(rewardsRes, &rewardsV1.AttributableReward{
+			Earner:     r.Earner,
+			Operator:   r.Operator,
+			Avs:        r.Avs,
+			Token:      r.Token,
+			Strategy:   r.Strategy,
+			Multiplier: r.Multiplier,
+			Amount:     r.Amount,
+			Shares:     r.Shares,
+			RewardHash: r.RewardHash,
+			Snapshot:   r.Snapshot,
+			RewardType: rewardType,
+		})
+	}
+	return rewardsRes, nil
+}
+
+// convertStakerOperatorRewardTypeToEnum maps the reward types of the staker_operator table, which also say who earned
+// the reward, to the type of the reward submission they came from
+func convertStakerOperatorRewardTypeToEnum(rewardType string) (rewardsV1.RewardType, error) {
+	switch rewardType {
+	case "staker_reward", "operator_reward", "staker_od_reward", "operator_od_reward", "avs_od_reward",
+		"staker_od_operator_set_reward", "operator_od_operator_set_reward", "avs_od_operator_set_reward":
+		return rewardsV1.RewardType_REWARD_TYPE_AVS, nil
+	case "reward_for_all":
+		return rewardsV1.RewardType_REWARD_TYPE_FOR_ALL, nil
+	case "rfae_staker", "rfae_operator":
+		return rewardsV1.RewardType_REWARD_TYPE_FOR_ALL_EARNERS, nil
+	default:
+		return -1, fmt.Errorf("unknown staker operator reward type '%s'", rewardType)
+	}
+}
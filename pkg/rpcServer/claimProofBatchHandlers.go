@@ -0,0 +1,176 @@
+package rpcServer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	rewardsCoordinator "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IRewardsCoordinator"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/sidecar/pkg/proofs"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+)
+
+const claimProofBatchPath = "/rewards/v1/claim-proofs"
+
+// maxClaimProofBatchSize is the number of earners a single batch can request proofs for
+const maxClaimProofBatchSize = 1000
+
+type ClaimProofBatchEarner struct {
+	Earner string   `json:"earner"`
+	Tokens []string `json:"tokens"`
+
+	// Recipient receives the tokens of the earner's processClaim calldata, defaulting to the batch recipient and
+	// then to the earner itself. It must match the batch recipient if one is set, since processClaims pays out
+	// every claim to the same recipient.
+	Recipient string `json:"recipient"`
+}
+
+type ClaimProofBatchRequest struct {
+	// RootIndex defaults to the newest claimable root
+	RootIndex *int64                   `json:"rootIndex"`
+	Earners   []*ClaimProofBatchEarner `json:"earners"`
+
+	// Calldata requests ABI encoded processClaim calldata for every proof, and processClaims calldata for the whole
+	// batch if Recipient is set. Every claim of the processClaims calldata is paid out to Recipient.
+	Calldata  bool   `json:"calldata"`
+	Recipient string `json:"recipient"`
+}
+
+type ClaimProofBatchResult struct {
+	Earner   string                                                 `json:"earner"`
+	Proof    *claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings `json:"proof,omitempty"`
+	Calldata string                                                 `json:"calldata,omitempty"`
+	Error    string                                                 `json:"error,omitempty"`
+}
+
+type ClaimProofBatchResponse struct {
+	Root                  string                   `json:"root"`
+	RootIndex             uint64                   `json:"rootIndex"`
+	SnapshotDate          string                   `json:"snapshotDate"`
+	Proofs                []*ClaimProofBatchResult `json:"proofs"`
+	ProcessClaimsCalldata string                   `json:"processClaimsCalldata,omitempty"`
+}
+
+func (rpc *RpcServer) registerClaimProofBatchHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodPost, claimProofBatchPath, rpc.GenerateClaimProofs)
+}
+
+func validateClaimProofBatchRequest(req *ClaimProofBatchRequest) error {
+	if len(req.Earners) == 0 {
+		return fmt.Errorf("at least one earner is required")
+	}
+	if len(req.Earners) > maxClaimProofBatchSize {
+		return fmt.Errorf("at most %d earners can be requested at once", maxClaimProofBatchSize)
+	}
+	if req.Recipient != "" && !gethcommon.IsHexAddress(req.Recipient) {
+		return fmt.Errorf("invalid recipient '%s'", req.Recipient)
+	}
+	for _, e := range req.Earners {
+		if !gethcommon.IsHexAddress(e.Earner) {
+			return fmt.Errorf("invalid earner '%s'", e.Earner)
+		}
+		if e.Recipient != "" && !gethcommon.IsHexAddress(e.Recipient) {
+			return fmt.Errorf("invalid recipient '%s' for earner '%s'", e.Recipient, e.Earner)
+		}
+		if req.Calldata && req.Recipient != "" && e.Recipient != "" &&
+			gethcommon.HexToAddress(e.Recipient) != gethcommon.HexToAddress(req.Recipient) {
+			return fmt.Errorf("recipient '%s' for earner '%s' does not match the batch recipient '%s'; "+
+				"the processClaims calldata pays every claim to the batch recipient", e.Recipient, e.Earner, req.Recipient)
+		}
+		for _, token := range e.Tokens {
+			if !gethcommon.IsHexAddress(token) {
+				return fmt.Errorf("invalid token '%s' for earner '%s'", token, e.Earner)
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateClaimProofs generates claim proofs for many earners against one distribution root, optionally with
+// the calldata to submit them to the RewardsCoordinator. Earners without tokens get a proof for every token they
+// have rewards in.
+//
+// Proofs that cannot be generated for an earner are reported in the earner's result rather than failing the batch.
+//
+// POST /rewards/v1/claim-proofs
+func (rpc *RpcServer) GenerateClaimProofs(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	var req ClaimProofBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid claim proof request body")
+		return
+	}
+	if err := validateClaimProofBatchRequest(&req); err != nil {
+		writeJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rootIndex := int64(-1)
+	if req.RootIndex != nil {
+		rootIndex = *req.RootIndex
+	}
+
+	requests := make([]*proofs.ClaimProofRequest, 0, len(req.Earners))
+	for _, e := range req.Earners {
+		requests = append(requests, &proofs.ClaimProofRequest{Earner: e.Earner, Tokens: e.Tokens})
+	}
+	batch, err := rpc.rewardsProofs.GenerateRewardsClaimProofs(requests, rootIndex)
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to generate claim proofs %s", err.Error()))
+		return
+	}
+
+	response := &ClaimProofBatchResponse{
+		Root:         hexutil.Encode(batch.Root),
+		RootIndex:    batch.RootIndex,
+		SnapshotDate: batch.SnapshotDate,
+		Proofs:       make([]*ClaimProofBatchResult, 0, len(batch.Results)),
+	}
+	claims := make([]*rewardsCoordinator.IRewardsCoordinatorRewardsMerkleClaim, 0, len(batch.Results))
+	for i, result := range batch.Results {
+		res := &ClaimProofBatchResult{Earner: result.Earner}
+		response.Proofs = append(response.Proofs, res)
+		if result.Err != nil {
+			res.Error = result.Err.Error()
+			continue
+		}
+		res.Proof = claimgen.FormatProofForSolidity(batch.Root, result.Claim)
+		claims = append(claims, result.Claim)
+
+		if !req.Calldata {
+			continue
+		}
+		recipient := req.Earners[i].Recipient
+		if recipient == "" {
+			recipient = req.Recipient
+		}
+		if recipient == "" {
+			recipient = result.Earner
+		}
+		calldata, err := proofs.EncodeProcessClaimCalldata(result.Claim, gethcommon.HexToAddress(recipient))
+		if err != nil {
+			writeJsonError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to encode processClaim calldata %s", err.Error()))
+			return
+		}
+		res.Calldata = hexutil.Encode(calldata)
+	}
+
+	if req.Calldata && req.Recipient != "" && len(claims) > 0 {
+		calldata, err := proofs.EncodeProcessClaimsCalldata(claims, gethcommon.HexToAddress(req.Recipient))
+		if err != nil {
+			writeJsonError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to encode processClaims calldata %s", err.Error()))
+			return
+		}
+		response.ProcessClaimsCalldata = hexutil.Encode(calldata)
+	}
+
+	rpc.Logger.Sugar().Infow("Generated claim proofs",
+		zap.Uint64("rootIndex", batch.RootIndex),
+		zap.Int("earners", len(req.Earners)),
+		zap.Int("proofs", len(claims)),
+	)
+	writeJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,202 @@
+package rpcServer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/metrics"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/proofs"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testEarnerA    = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testEarnerB    = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	testTokenA     = "0x1111111111111111111111111111111111111111"
+	testTokenB     = "0x2222222222222222222222222222222222222222"
+	testRecipientA = "0x000000000000000000000000000000000000000a"
+	testRecipientB = "0x000000000000000000000000000000000000000b"
+)
+
+func postClaimProofs(t *testing.T, rpc *RpcServer, req *ClaimProofBatchRequest) *httptest.ResponseRecorder {
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	rpc.GenerateClaimProofs(w, httptest.NewRequest(http.MethodPost, claimProofBatchPath, bytes.NewReader(body)), nil)
+	return w
+}
+
+// calldataRecipient returns the recipient of processClaim or processClaims calldata. Both take a dynamic claim
+// argument followed by the recipient, so the recipient is the second word after the selector.
+func calldataRecipient(t *testing.T, calldata string) gethcommon.Address {
+	decoded, err := hexutil.Decode(calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gethcommon.BytesToAddress(decoded[4+32 : 4+64])
+}
+
+func Test_ValidateClaimProofBatchRequest(t *testing.T) {
+	t.Run("Should reject earner recipients that differ from the batch recipient", func(t *testing.T) {
+		err := validateClaimProofBatchRequest(&ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA, Recipient: testRecipientA},
+				{Earner: testEarnerB, Recipient: testRecipientB},
+			},
+			Calldata:  true,
+			Recipient: testRecipientA,
+		})
+		assert.NotNil(t, err)
+	})
+	t.Run("Should accept earner recipients that match the batch recipient", func(t *testing.T) {
+		err := validateClaimProofBatchRequest(&ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA, Recipient: "0x000000000000000000000000000000000000000A"},
+				{Earner: testEarnerB},
+			},
+			Calldata:  true,
+			Recipient: testRecipientA,
+		})
+		assert.Nil(t, err)
+	})
+	t.Run("Should accept different earner recipients without a batch recipient", func(t *testing.T) {
+		err := validateClaimProofBatchRequest(&ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA, Recipient: testRecipientA},
+				{Earner: testEarnerB, Recipient: testRecipientB},
+			},
+			Calldata: true,
+		})
+		assert.Nil(t, err)
+	})
+}
+
+func Test_GenerateClaimProofs(t *testing.T) {
+	cfg := tests.GetConfig()
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+	sink, _ := metrics.NewMetricsSink(&metrics.MetricsSinkConfig{}, nil)
+
+	dbName, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := rewards.NewRewardsCalculator(cfg, grm, nil, nil, sink, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := &RpcServer{
+		Logger:        l,
+		rewardsProofs: proofs.NewRewardsProofsStore(rc, grm, l, cfg),
+	}
+
+	snapshotDate := time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC)
+	// every earner has two tokens, since the RewardsCoordinator rejects the empty proofs of single leaf trees
+	for _, earner := range []string{testEarnerA, testEarnerB} {
+		for _, token := range []string{testTokenA, testTokenB} {
+			res := grm.Exec(`insert into gold_table (earner, snapshot, reward_hash, token, amount) values (?, ?, '0xreward', ?, 100)`,
+				earner, snapshotDate.Format(time.DateOnly), token,
+			)
+			assert.Nil(t, res.Error)
+		}
+	}
+	res := grm.Create(&storage.GeneratedRewardsSnapshots{
+		SnapshotDate: snapshotDate.Format(time.DateOnly),
+		Status:       storage.RewardSnapshotStatusCompleted.String(),
+	})
+	assert.Nil(t, res.Error)
+	res = grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: snapshotDate.Add(24 * time.Hour)})
+	assert.Nil(t, res.Error)
+	res = grm.Exec(`
+		insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+		values ('0xroot', 1, 0, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', 0)
+	`, snapshotDate, snapshotDate.Add(24*time.Hour))
+	assert.Nil(t, res.Error)
+
+	t.Run("Should reject earner recipients that differ from the batch recipient", func(t *testing.T) {
+		w := postClaimProofs(t, rpc, &ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA},
+				{Earner: testEarnerB, Recipient: testRecipientB},
+			},
+			Calldata:  true,
+			Recipient: testRecipientA,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Should pay every claim of the batch calldata to the batch recipient", func(t *testing.T) {
+		w := postClaimProofs(t, rpc, &ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA, Recipient: testRecipientA},
+				{Earner: testEarnerB},
+			},
+			Calldata:  true,
+			Recipient: testRecipientA,
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ClaimProofBatchResponse
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, uint64(0), response.RootIndex)
+		assert.Len(t, response.Proofs, 2)
+		for _, proof := range response.Proofs {
+			assert.Empty(t, proof.Error)
+			assert.NotNil(t, proof.Proof)
+			assert.Equal(t, gethcommon.HexToAddress(testRecipientA), calldataRecipient(t, proof.Calldata))
+		}
+		assert.Equal(t, gethcommon.HexToAddress(testRecipientA), calldataRecipient(t, response.ProcessClaimsCalldata))
+	})
+	t.Run("Should pay each claim to its own recipient without a batch recipient", func(t *testing.T) {
+		w := postClaimProofs(t, rpc, &ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA, Recipient: testRecipientA},
+				{Earner: testEarnerB},
+			},
+			Calldata: true,
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ClaimProofBatchResponse
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Proofs, 2)
+		assert.Equal(t, gethcommon.HexToAddress(testRecipientA), calldataRecipient(t, response.Proofs[0].Calldata))
+		// without any recipient, the earner claims to itself
+		assert.Equal(t, gethcommon.HexToAddress(testEarnerB), calldataRecipient(t, response.Proofs[1].Calldata))
+		assert.Empty(t, response.ProcessClaimsCalldata)
+	})
+	t.Run("Should report earners without rewards in their own result", func(t *testing.T) {
+		w := postClaimProofs(t, rpc, &ClaimProofBatchRequest{
+			Earners: []*ClaimProofBatchEarner{
+				{Earner: testEarnerA},
+				{Earner: "0xcccccccccccccccccccccccccccccccccccccccc"},
+			},
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ClaimProofBatchResponse
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Proofs, 2)
+		assert.Empty(t, response.Proofs[0].Error)
+		assert.NotEmpty(t, response.Proofs[1].Error)
+		assert.Empty(t, response.Proofs[0].Calldata)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,50 @@
+package rpcServer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/sidecar/pkg/proofs"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+const claimProofVerifyPath = "/rewards/v1/claim-proofs/verify"
+
+func (rpc *RpcServer) registerClaimProofVerifyHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodPost, claimProofVerifyPath, rpc.VerifyClaimProof)
+}
+
+// VerifyClaimProof checks a claim proof against the distribution root submitted with its root index without
+// sending a transaction. The body is a proof in the format returned by POST /rewards/v1/claim-proofs.
+//
+// A proof that does not verify is still a 200, with the reason in the response.
+//
+// POST /rewards/v1/claim-proofs/verify
+func (rpc *RpcServer) VerifyClaimProof(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	var req claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid claim proof body")
+		return
+	}
+
+	claim, err := proofs.ParseClaimProofStrings(&req)
+	if err != nil {
+		var invalidErr *proofs.ErrInvalidClaimProof
+		if errors.As(err, &invalidErr) {
+			writeJsonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	verification, err := rpc.rewardsProofs.VerifyClaimProof(claim)
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to verify claim proof %s", err.Error()))
+		return
+	}
+	writeJson(w, http.StatusOK, verification)
+}
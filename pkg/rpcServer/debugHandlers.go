@@ -0,0 +1,46 @@
+package rpcServer
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+)
+
+const blockStateRootsPath = "/rpc/v1/debug/state-roots/{blockNumber}/models"
+
+// registerDebugHandlers registers plain HTTP handlers for debugging endpoints that are not part of the public protos.
+func (s *RpcServer) registerDebugHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodGet, blockStateRootsPath, s.GetBlockStateRoots)
+}
+
+// GetBlockStateRoots returns the per-model roots and slots that make up the state root for a block.
+//
+// GET /rpc/v1/debug/state-roots/{blockNumber}/models
+func (s *RpcServer) GetBlockStateRoots(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	blockNumber, err := strconv.ParseUint(pathParams["blockNumber"], 10, 64)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid block number")
+		return
+	}
+
+	stateRoots, err := s.protocolDataService.GetBlockStateRoots(r.Context(), blockNumber)
+	if err != nil {
+		s.Logger.Sugar().Errorw("Failed to get block state roots", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJson(w, http.StatusOK, stateRoots)
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeJsonError(w http.ResponseWriter, statusCode int, message string) {
+	writeJson(w, statusCode, map[string]string{"error": message})
+}
@@ -0,0 +1,72 @@
+package rpcServer
+
+import (
+	"net/http"
+
+	"github.com/Layr-Labs/sidecar/pkg/service/rewardsDataService"
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+const pendingRewardsPath = "/rewards/v1/earners/{earner_address}/summarized-rewards/pending"
+
+type SummarizedRewardWithPending struct {
+	Token     string `json:"token"`
+	Earned    string `json:"earned"`
+	Active    string `json:"active"`
+	Claimed   string `json:"claimed"`
+	Claimable string `json:"claimable"`
+
+	// PendingUnverified is estimated from the snapshot of the last submitted distribution root up to the latest
+	// snapshot and is not part of any distribution root
+	PendingUnverified   string `json:"pendingUnverified"`
+	PendingSnapshotDate string `json:"pendingSnapshotDate,omitempty"`
+}
+
+type SummarizedRewardsWithPendingResponse struct {
+	Earner string `json:"earner"`
+
+	// PendingVerified is always false and is there so clients cannot mistake the pending amounts for claimable ones
+	PendingVerified bool                           `json:"pendingVerified"`
+	Rewards         []*SummarizedRewardWithPending `json:"rewards"`
+}
+
+func (rpc *RpcServer) registerPendingRewardsHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodGet, pendingRewardsPath, rpc.GetSummarizedRewardsWithPendingForEarner)
+}
+
+// GetSummarizedRewardsWithPendingForEarner returns the summarized rewards of an earner at the current block height
+// along with the unverified estimate of what they have accrued since the last submitted distribution root.
+//
+// The response of the GetSummarizedRewardsForEarner RPC has no field for the estimate, so it is served separately.
+//
+// GET /rewards/v1/earners/{earner_address}/summarized-rewards/pending
+func (rpc *RpcServer) GetSummarizedRewardsWithPendingForEarner(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	earner := pathParams["earner_address"]
+	if earner == "" {
+		writeJsonError(w, http.StatusBadRequest, "earner address is required")
+		return
+	}
+
+	summarizedRewards, err := rpc.rewardsDataService.GetSummarizedRewards(r.Context(), earner, nil, 0)
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJson(w, http.StatusOK, &SummarizedRewardsWithPendingResponse{
+		Earner:          earner,
+		PendingVerified: false,
+		Rewards: utils.Map(summarizedRewards, func(r *rewardsDataService.SummarizedReward, i uint64) *SummarizedRewardWithPending {
+			return &SummarizedRewardWithPending{
+				Token:               r.Token,
+				Earned:              withDefaultValue(r.Earned, "0"),
+				Active:              withDefaultValue(r.Active, "0"),
+				Claimed:             withDefaultValue(r.Claimed, "0"),
+				Claimable:           withDefaultValue(r.Claimable, "0"),
+				PendingUnverified:   withDefaultValue(r.Pending, "0"),
+				PendingSnapshotDate: r.PendingSnapshotDate,
+			}
+		}),
+	})
+}
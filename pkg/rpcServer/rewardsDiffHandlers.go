@@ -0,0 +1,65 @@
+package rpcServer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Layr-Labs/sidecar/pkg/service/rewardsDataService"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+const rewardsDiffPath = "/rewards/v1/diff"
+
+func (rpc *RpcServer) registerRewardsDiffHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodGet, rewardsDiffPath, rpc.GetRewardsDiff)
+}
+
+// GetRewardsDiff returns, for every earner and token, how the cumulative rewards changed between two snapshots
+// or between the snapshots of two distribution roots.
+//
+// GET /rewards/v1/diff?from_snapshot=2025-01-01&to_snapshot=2025-01-08
+// GET /rewards/v1/diff?from_root_index=10&to_root_index=11
+func (rpc *RpcServer) GetRewardsDiff(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	query := r.URL.Query()
+	fromSnapshot, toSnapshot := query.Get("from_snapshot"), query.Get("to_snapshot")
+	fromRootIndex, toRootIndex := query.Get("from_root_index"), query.Get("to_root_index")
+
+	var diff *rewardsDataService.RewardsDiff
+	var err error
+	switch {
+	case fromSnapshot != "" && toSnapshot != "" && fromRootIndex == "" && toRootIndex == "":
+		diff, err = rpc.rewardsDataService.GetRewardsDiffForSnapshots(r.Context(), fromSnapshot, toSnapshot)
+	case fromRootIndex != "" && toRootIndex != "" && fromSnapshot == "" && toSnapshot == "":
+		from, parseErr := strconv.ParseUint(fromRootIndex, 10, 64)
+		if parseErr != nil {
+			writeJsonError(w, http.StatusBadRequest, fmt.Sprintf("invalid from_root_index '%s'", fromRootIndex))
+			return
+		}
+		to, parseErr := strconv.ParseUint(toRootIndex, 10, 64)
+		if parseErr != nil {
+			writeJsonError(w, http.StatusBadRequest, fmt.Sprintf("invalid to_root_index '%s'", toRootIndex))
+			return
+		}
+		diff, err = rpc.rewardsDataService.GetRewardsDiffForDistributionRoots(r.Context(), from, to)
+	default:
+		writeJsonError(w, http.StatusBadRequest, "either from_snapshot and to_snapshot or from_root_index and to_root_index are required")
+		return
+	}
+
+	if err != nil {
+		var invalidErr *rewardsDataService.ErrInvalidRewardsDiff
+		var notFoundErr *rewardsDataService.ErrDistributionRootNotFound
+		switch {
+		case errors.As(err, &invalidErr):
+			writeJsonError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &notFoundErr):
+			writeJsonError(w, http.StatusNotFound, err.Error())
+		default:
+			writeJsonError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJson(w, http.StatusOK, diff)
+}
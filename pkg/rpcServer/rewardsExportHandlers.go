@@ -0,0 +1,86 @@
+package rpcServer
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewardsExport"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+)
+
+const rewardsExportPath = "/rewards/v1/exports/{snapshot}"
+
+func (rpc *RpcServer) registerRewardsExportHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodGet, rewardsExportPath, rpc.ExportRewards)
+}
+
+// ExportRewards streams the gold table, the staker-operator breakdown or the claim status of every earner up to
+// the snapshot as a file download. The response is written as rows are read, so it is not subject to the message
+// size limits of GetRewardsForSnapshot.
+//
+// GET /rewards/v1/exports/2025-01-01?dataset=gold&format=csv&gzip=true
+func (rpc *RpcServer) ExportRewards(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	snapshot := pathParams["snapshot"]
+	query := r.URL.Query()
+	dataset, format, compress, err := parseExportParams(snapshot, query.Get("dataset"), query.Get("format"), query.Get("gzip"))
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", rewardsExport.ContentType(format, compress))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, rewardsExport.FileName(dataset, snapshot, format, compress)))
+
+	// once rows have been written the status can no longer be changed, so failures part way through
+	// can only be logged and surface to the client as a truncated download
+	ew := &exportResponseWriter{ResponseWriter: w}
+	if _, err := rpc.rewardsDataService.ExportRewards(r.Context(), snapshot, dataset, format, compress, ew); err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to export rewards",
+			zap.String("snapshot", snapshot),
+			zap.String("dataset", string(dataset)),
+			zap.Error(err),
+		)
+		if !ew.started {
+			w.Header().Del("Content-Disposition")
+			writeJsonError(w, http.StatusInternalServerError, err.Error())
+		}
+	}
+}
+
+// parseExportParams validates the parameters of an export. The format defaults to CSV and gzip to false.
+func parseExportParams(snapshot string, dataset string, format string, gzip string) (rewardsExport.Dataset, rewardsExport.Format, bool, error) {
+	if _, err := time.Parse(time.DateOnly, snapshot); err != nil {
+		return "", "", false, fmt.Errorf("invalid snapshot '%s', expected YYYY-MM-DD", snapshot)
+	}
+	parsedDataset, err := rewardsExport.ParseDataset(dataset)
+	if err != nil {
+		return "", "", false, err
+	}
+	parsedFormat := rewardsExport.Format_Csv
+	if format != "" {
+		if parsedFormat, err = rewardsExport.ParseFormat(format); err != nil {
+			return "", "", false, err
+		}
+	}
+	var compress bool
+	if gzip != "" {
+		if compress, err = strconv.ParseBool(gzip); err != nil {
+			return "", "", false, fmt.Errorf("invalid gzip '%s'", gzip)
+		}
+	}
+	return parsedDataset, parsedFormat, compress, nil
+}
+
+// exportResponseWriter records whether any part of the export has been written to the response
+type exportResponseWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *exportResponseWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,109 @@
+package rpcServer
+
+import (
+	"bufio"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewardsExport"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The export RPC streams files rather than structured messages, so it is registered directly on the gRPC server
+// instead of being generated from the protocol-apis definitions. The request is a google.protobuf.Struct with the
+// string fields snapshot, dataset, format and gzip, matching the parameters of the HTTP export. The file is streamed
+// back as google.protobuf.BytesValue chunks, and its name is sent in the x-export-filename header.
+const (
+	rewardsExportServiceName = "eigenlayer.sidecar.v1.rewards.RewardsExport"
+	// RewardsExport_ExportRewards_FullMethodName is the method to open a stream for with grpc.ClientConn.NewStream
+	RewardsExport_ExportRewards_FullMethodName = "/" + rewardsExportServiceName + "/ExportRewards"
+
+	rewardsExportFileNameHeader    = "x-export-filename"
+	rewardsExportContentTypeHeader = "x-export-content-type"
+	rewardsExportChunkSize         = 256 * 1024
+)
+
+type rewardsExportServer interface {
+	ExportRewardsStream(req *structpb.Struct, stream grpc.ServerStream) error
+}
+
+var rewardsExportServiceDesc = grpc.ServiceDesc{
+	ServiceName: rewardsExportServiceName,
+	HandlerType: (*rewardsExportServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportRewards",
+			Handler:       exportRewardsStreamHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+func exportRewardsStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := &structpb.Struct{}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(rewardsExportServer).ExportRewardsStream(req, stream)
+}
+
+// exportStreamWriter sends everything written to it as BytesValue messages on the stream
+type exportStreamWriter struct {
+	stream grpc.ServerStream
+}
+
+func (w *exportStreamWriter) Write(b []byte) (int, error) {
+	// the message is sent before Write returns, but the buffer may still be reused by the caller
+	chunk := make([]byte, len(b))
+	copy(chunk, b)
+	if err := w.stream.SendMsg(wrapperspb.Bytes(chunk)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// ExportRewardsStream streams the gold table, the staker-operator breakdown or the claim status of every earner up
+// to the snapshot over gRPC, in chunks of at most rewardsExportChunkSize bytes.
+func (rpc *RpcServer) ExportRewardsStream(req *structpb.Struct, stream grpc.ServerStream) error {
+	fields := req.GetFields()
+	snapshot := fields["snapshot"].GetStringValue()
+	dataset, format, compress, err := parseExportParams(
+		snapshot,
+		fields["dataset"].GetStringValue(),
+		fields["format"].GetStringValue(),
+		fields["gzip"].GetStringValue(),
+	)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	header := metadata.Pairs(
+		rewardsExportFileNameHeader, rewardsExport.FileName(dataset, snapshot, format, compress),
+		rewardsExportContentTypeHeader, rewardsExport.ContentType(format, compress),
+	)
+	if err := stream.SendHeader(header); err != nil {
+		return err
+	}
+
+	w := bufio.NewWriterSize(&exportStreamWriter{stream: stream}, rewardsExportChunkSize)
+	_, err = rpc.rewardsDataService.ExportRewards(stream.Context(), snapshot, dataset, format, compress, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to export rewards",
+			zap.String("snapshot", snapshot),
+			zap.String("dataset", string(dataset)),
+			zap.Error(err),
+		)
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
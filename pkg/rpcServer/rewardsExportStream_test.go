@@ -0,0 +1,113 @@
+package rpcServer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// fakeServerStream records the messages sent on a server stream
+type fakeServerStream struct {
+	grpc.ServerStream
+	sent [][]byte
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m.(*wrapperspb.BytesValue).GetValue())
+	return nil
+}
+
+func Test_ExportStreamWriter(t *testing.T) {
+	stream := &fakeServerStream{}
+	w := &exportStreamWriter{stream: stream}
+
+	buf := []byte("0xearner1,0xtoken,100\n")
+	n, err := w.Write(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, len(buf), n)
+
+	// the sent chunk must not change when the caller reuses its buffer
+	copy(buf, "0xearner2")
+	_, err = w.Write(buf)
+	assert.Nil(t, err)
+
+	assert.Equal(t, [][]byte{
+		[]byte("0xearner1,0xtoken,100\n"),
+		[]byte("0xearner2,0xtoken,100\n"),
+	}, stream.sent)
+}
+
+func Test_ExportRewardsStream(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+	rpc := &RpcServer{Logger: l}
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	grpcServer.RegisterService(&rewardsExportServiceDesc, rpc)
+	go func() { _ = grpcServer.Serve(listener) }()
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	exportRewards := func(t *testing.T, params map[string]interface{}) ([]byte, error) {
+		req, err := structpb.NewStruct(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream, err := conn.NewStream(context.Background(), &rewardsExportServiceDesc.Streams[0], RewardsExport_ExportRewards_FullMethodName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.SendMsg(req); err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.CloseSend(); err != nil {
+			t.Fatal(err)
+		}
+
+		var out bytes.Buffer
+		for {
+			chunk := &wrapperspb.BytesValue{}
+			if err := stream.RecvMsg(chunk); err != nil {
+				if errors.Is(err, io.EOF) {
+					return out.Bytes(), nil
+				}
+				return out.Bytes(), err
+			}
+			out.Write(chunk.GetValue())
+		}
+	}
+
+	invalidParams := map[string]map[string]interface{}{
+		"invalid snapshot": {"snapshot": "2025-01", "dataset": "gold"},
+		"unknown dataset":  {"snapshot": "2025-01-01", "dataset": "payouts"},
+		"unknown format":   {"snapshot": "2025-01-01", "dataset": "gold", "format": "xlsx"},
+		"invalid gzip":     {"snapshot": "2025-01-01", "dataset": "gold", "gzip": "maybe"},
+	}
+	for name, params := range invalidParams {
+		t.Run(name, func(t *testing.T) {
+			_, err := exportRewards(t, params)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
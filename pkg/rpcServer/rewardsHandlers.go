@@ -11,9 +11,12 @@ import (
 	"github.com/Layr-Labs/sidecar/pkg/service/rewardsDataService"
 	"github.com/Layr-Labs/sidecar/pkg/utils"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
 )
 
 func (rpc *RpcServer) GetRewardsRoot(ctx context.Context, req *rewardsV1.GetRewardsRootRequest) (*rewardsV1.GetRewardsRootResponse, error) {
@@ -61,14 +64,14 @@ func (rpc *RpcServer) GenerateRewards(ctx context.Context, req *rewardsV1.Genera
 
 	if waitForComplete {
 		data, qErr := rpc.rewardsQueue.EnqueueAndWait(ctx, msg)
-		cutoffDate = data.CutoffDate
+		if data != nil {
+			cutoffDate = data.CutoffDate
+			rpc.setRewardsJobIdHeader(ctx, data.JobId)
+		}
 		err = qErr
 	} else {
-		rpc.rewardsQueue.Enqueue(&rewardsCalculatorQueue.RewardsCalculationMessage{
-			Data:         msg,
-			ResponseChan: make(chan *rewardsCalculatorQueue.RewardsCalculatorResponse),
-		})
-		queued = true
+		err = rpc.enqueueRewardsCalculation(ctx, msg)
+		queued = err == nil
 	}
 
 	if err != nil {
@@ -163,11 +166,8 @@ func (rpc *RpcServer) GenerateStakerOperators(ctx context.Context, req *rewardsV
 	if req.GetWaitForComplete() {
 		_, err = rpc.rewardsQueue.EnqueueAndWait(ctx, msg)
 	} else {
-		rpc.rewardsQueue.Enqueue(&rewardsCalculatorQueue.RewardsCalculationMessage{
-			Data:         msg,
-			ResponseChan: make(chan *rewardsCalculatorQueue.RewardsCalculatorResponse),
-		})
-		queued = true
+		err = rpc.enqueueRewardsCalculation(ctx, msg)
+		queued = err == nil
 	}
 
 	if err != nil {
@@ -195,11 +195,8 @@ func (rpc *RpcServer) BackfillStakerOperators(ctx context.Context, req *rewardsV
 	if req.GetWaitForComplete() {
 		_, err = rpc.rewardsQueue.EnqueueAndWait(ctx, msg)
 	} else {
-		rpc.rewardsQueue.Enqueue(&rewardsCalculatorQueue.RewardsCalculationMessage{
-			Data:         msg,
-			ResponseChan: make(chan *rewardsCalculatorQueue.RewardsCalculatorResponse),
-		})
-		queued = true
+		err = rpc.enqueueRewardsCalculation(ctx, msg)
+		queued = err == nil
 	}
 
 	if err != nil {
@@ -239,12 +236,83 @@ func (rpc *RpcServer) GetRewardsForSnapshot(ctx context.Context, req *rewardsV1.
 	}, nil
 }
 
+// GetAttributableRewardsForSnapshot returns the staker-operator breakdown of every reward up to and including the snapshot.
+//
+// Filters and pagination are read from the request metadata, see getAttributableRewardsParams.
 func (rpc *RpcServer) GetAttributableRewardsForSnapshot(ctx context.Context, req *rewardsV1.GetAttributableRewardsForSnapshotRequest) (*rewardsV1.GetAttributableRewardsForSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetAttributableRewardsForSnapshot not implemented")
+	snapshot := req.GetSnapshot()
+	if snapshot == "" {
+		return nil, status.Error(codes.InvalidArgument, "snapshot is required")
+	}
+	if _, err := time.Parse(time.DateOnly, snapshot); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "snapshot must be a date formatted as YYYY-MM-DD")
+	}
+
+	generated, err := rpc.ensureStakerOperatorsSnapshot(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !generated {
+		// generating the staker-operator data writes to the database, so the primary has to handle the request
+		var header metadata.MD
+		res, err := rpc.sidecarClient.RewardsClient.GetAttributableRewardsForSnapshot(forwardAttributableRewardsParams(ctx), req, grpc.Header(&header))
+		if err != nil {
+			return nil, err
+		}
+		rpc.setAttributableRewardsNextPageHeader(ctx, header.Get(attributableRewardsNextPageHeader)...)
+		return res, nil
+	}
+
+	rewardsRes, err := rpc.listAttributableRewards(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &rewardsV1.GetAttributableRewardsForSnapshotResponse{
+		Rewards: rewardsRes,
+	}, nil
 }
 
+// GetAttributableRewardsForDistributionRoot returns the staker-operator breakdown of every reward included in the
+// distribution root.
+//
+// Filters and pagination are read from the request metadata, see getAttributableRewardsParams.
 func (rpc *RpcServer) GetAttributableRewardsForDistributionRoot(ctx context.Context, req *rewardsV1.GetAttributableRewardsForDistributionRootRequest) (*rewardsV1.GetAttributableRewardsForDistributionRootResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetAttributableRewardsForDistributionRoot not implemented")
+	root := req.GetDistributionRoot()
+	if root == "" {
+		return nil, status.Error(codes.InvalidArgument, "distribution root is required")
+	}
+
+	distributionRoot, err := rpc.rewardsDataService.GetDistributionRoot(ctx, root)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if distributionRoot == nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("no distribution root found for root '%s'", root))
+	}
+	snapshot := distributionRoot.GetSnapshotDate()
+
+	generated, err := rpc.ensureStakerOperatorsSnapshot(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !generated {
+		// generating the staker-operator data writes to the database, so the primary has to handle the request
+		var header metadata.MD
+		res, err := rpc.sidecarClient.RewardsClient.GetAttributableRewardsForDistributionRoot(forwardAttributableRewardsParams(ctx), req, grpc.Header(&header))
+		if err != nil {
+			return nil, err
+		}
+		rpc.setAttributableRewardsNextPageHeader(ctx, header.Get(attributableRewardsNextPageHeader)...)
+		return res, nil
+	}
+
+	rewardsRes, err := rpc.listAttributableRewards(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &rewardsV1.GetAttributableRewardsForDistributionRootResponse{
+		Rewards: rewardsRes,
+	}, nil
 }
 
 func (rpc *RpcServer) GetClaimableRewards(ctx context.Context, req *rewardsV1.GetClaimableRewardsRequest) (*rewardsV1.GetClaimableRewardsResponse, error) {
@@ -334,6 +402,11 @@ func (rpc *RpcServer) GetSummarizedRewardsForEarner(ctx context.Context, req *re
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	// tokens that only have pending rewards are left out since the response has no field for them
+	summarizedRewards = utils.Filter(summarizedRewards, func(r *rewardsDataService.SummarizedReward) bool {
+		return r.Earned != "" || r.Active != "" || r.Claimed != "" || r.Claimable != ""
+	})
+
 	return &rewardsV1.GetSummarizedRewardsForEarnerResponse{
 		Rewards: utils.Map(summarizedRewards, func(r *rewardsDataService.SummarizedReward, i uint64) *rewardsV1.SummarizedEarnerReward {
 
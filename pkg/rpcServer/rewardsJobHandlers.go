@@ -0,0 +1,285 @@
+package rpcServer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsCalculatorQueue"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	rewardsJobsPath      = "/rewards/v1/jobs"
+	rewardsJobPath       = "/rewards/v1/jobs/{jobId}"
+	rewardsJobCancelPath = "/rewards/v1/jobs/{jobId}/cancel"
+
+	rewardsSnapshotRetryPath               = "/rewards/v1/snapshots/{snapshotDate}/retry"
+	rewardsSnapshotInvariantViolationsPath = "/rewards/v1/snapshots/{snapshotDate}/invariant-violations"
+
+	// rewardsJobIdHeader is set on responses to requests that enqueue a rewards calculation.
+	// Over HTTP it is returned as the Grpc-Metadata-X-Rewards-Job-Id header.
+	rewardsJobIdHeader = "x-rewards-job-id"
+
+	defaultRewardsJobsLimit = 100
+)
+
+type rewardsJobResponse struct {
+	Id               uint64     `json:"id"`
+	CalculationType  string     `json:"calculationType"`
+	CutoffDate       string     `json:"cutoffDate"`
+	Status           string     `json:"status"`
+	Stage            string     `json:"stage"`
+	CompletedSteps   int        `json:"completedSteps"`
+	TotalSteps       int        `json:"totalSteps"`
+	ResultCutoffDate string     `json:"resultCutoffDate"`
+	Error            string     `json:"error"`
+	CancelRequested  bool       `json:"cancelRequested"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	StartedAt        *time.Time `json:"startedAt"`
+	FinishedAt       *time.Time `json:"finishedAt"`
+}
+
+type rewardsInvariantViolationResponse struct {
+	Invariant      string    `json:"invariant"`
+	ViolationCount int64     `json:"violationCount"`
+	Examples       []string  `json:"examples"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type listRewardsInvariantViolationsResponse struct {
+	Violations []*rewardsInvariantViolationResponse `json:"violations"`
+}
+
+type listRewardsJobsResponse struct {
+	Jobs []*rewardsJobResponse `json:"jobs"`
+}
+
+func newRewardsJobResponse(job *rewardsCalculatorQueue.RewardsCalculationJob) *rewardsJobResponse {
+	return &rewardsJobResponse{
+		Id:               job.Id,
+		CalculationType:  job.CalculationType,
+		CutoffDate:       job.CutoffDate,
+		Status:           job.Status,
+		Stage:            job.Stage,
+		CompletedSteps:   job.CompletedSteps,
+		TotalSteps:       job.TotalSteps,
+		ResultCutoffDate: job.ResultCutoffDate,
+		Error:            job.Error,
+		CancelRequested:  job.CancelRequested,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
+		StartedAt:        job.StartedAt,
+		FinishedAt:       job.FinishedAt,
+	}
+}
+
+// enqueueRewardsCalculation enqueues a rewards calculation without waiting for it and returns the job id to the caller
+func (rpc *RpcServer) enqueueRewardsCalculation(ctx context.Context, data rewardsCalculatorQueue.RewardsCalculationData) error {
+	job, err := rpc.rewardsQueue.Enqueue(&rewardsCalculatorQueue.RewardsCalculationMessage{
+		Data:         data,
+		ResponseChan: make(chan *rewardsCalculatorQueue.RewardsCalculatorResponse),
+	})
+	if err != nil {
+		return err
+	}
+	rpc.setRewardsJobIdHeader(ctx, job.Id)
+	return nil
+}
+
+func (rpc *RpcServer) setRewardsJobIdHeader(ctx context.Context, jobId uint64) {
+	if jobId == 0 {
+		return
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(rewardsJobIdHeader, strconv.FormatUint(jobId, 10))); err != nil {
+		rpc.Logger.Sugar().Debugw("Failed to set rewards job id header", zap.Uint64("jobId", jobId), zap.Error(err))
+	}
+}
+
+func (rpc *RpcServer) registerRewardsJobHandlers(mux *runtime.ServeMux) error {
+	if err := mux.HandlePath(http.MethodGet, rewardsJobsPath, rpc.ListRewardsJobs); err != nil {
+		return err
+	}
+	if err := mux.HandlePath(http.MethodGet, rewardsJobPath, rpc.GetRewardsJob); err != nil {
+		return err
+	}
+	if err := mux.HandlePath(http.MethodPost, rewardsJobCancelPath, rpc.CancelRewardsJob); err != nil {
+		return err
+	}
+	if err := mux.HandlePath(http.MethodGet, rewardsSnapshotInvariantViolationsPath, rpc.ListRewardsInvariantViolations); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodPost, rewardsSnapshotRetryPath, rpc.RetryRewardsSnapshot)
+}
+
+// ListRewardsJobs lists the most recent rewards calculation jobs, newest first.
+//
+// GET /rewards/v1/jobs?status=running&limit=10
+func (rpc *RpcServer) ListRewardsJobs(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	limit := defaultRewardsJobsLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			writeJsonError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+	jobStatus := rewardsCalculatorQueue.RewardsCalculationJobStatus(r.URL.Query().Get("status"))
+
+	jobs, err := rpc.rewardsQueue.ListJobs(jobStatus, limit)
+	if err != nil {
+		var invalidStatusErr *rewardsCalculatorQueue.ErrInvalidJobStatus
+		if errors.As(err, &invalidStatusErr) {
+			writeJsonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rpc.Logger.Sugar().Errorw("Failed to list rewards jobs", zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	res := &listRewardsJobsResponse{Jobs: make([]*rewardsJobResponse, 0, len(jobs))}
+	for _, job := range jobs {
+		res.Jobs = append(res.Jobs, newRewardsJobResponse(job))
+	}
+	writeJson(w, http.StatusOK, res)
+}
+
+// GetRewardsJob returns a single rewards calculation job.
+//
+// GET /rewards/v1/jobs/{jobId}
+func (rpc *RpcServer) GetRewardsJob(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	jobId, err := strconv.ParseUint(pathParams["jobId"], 10, 64)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := rpc.rewardsQueue.GetJob(jobId)
+	if err != nil {
+		if errors.Is(err, rewardsCalculatorQueue.ErrJobNotFound) {
+			writeJsonError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		rpc.Logger.Sugar().Errorw("Failed to get rewards job", zap.Uint64("jobId", jobId), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJson(w, http.StatusOK, newRewardsJobResponse(job))
+}
+
+// CancelRewardsJob cancels a queued rewards calculation job, or stops a running job before its next step.
+//
+// POST /rewards/v1/jobs/{jobId}/cancel
+func (rpc *RpcServer) CancelRewardsJob(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	jobId, err := strconv.ParseUint(pathParams["jobId"], 10, 64)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := rpc.rewardsQueue.CancelJob(jobId)
+	if err != nil {
+		if errors.Is(err, rewardsCalculatorQueue.ErrJobNotFound) {
+			writeJsonError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		var finishedErr *rewardsCalculatorQueue.ErrJobFinished
+		if errors.As(err, &finishedErr) {
+			writeJsonError(w, http.StatusConflict, err.Error())
+			return
+		}
+		rpc.Logger.Sugar().Errorw("Failed to cancel rewards job", zap.Uint64("jobId", jobId), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJson(w, http.StatusOK, newRewardsJobResponse(job))
+}
+
+// RetryRewardsSnapshot enqueues a job that drops everything a failed rewards calculation left behind for the
+// snapshot date and calculates it again.
+//
+// POST /rewards/v1/snapshots/{snapshotDate}/retry
+func (rpc *RpcServer) RetryRewardsSnapshot(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	if !rpc.globalConfig.SidecarPrimaryConfig.IsPrimary {
+		writeJsonError(w, http.StatusBadRequest, "rewards snapshots can only be retried on the primary sidecar")
+		return
+	}
+
+	snapshotDate := pathParams["snapshotDate"]
+	if _, err := time.Parse(time.DateOnly, snapshotDate); err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid snapshot date, expected YYYY-MM-DD")
+		return
+	}
+
+	snapshot, err := rpc.rewardsCalculator.GetRewardSnapshotStatus(snapshotDate)
+	if err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to get rewards snapshot", zap.String("snapshotDate", snapshotDate), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if snapshot == nil {
+		writeJsonError(w, http.StatusNotFound, (&rewards.ErrRewardsSnapshotNotFound{SnapshotDate: snapshotDate}).Error())
+		return
+	}
+	if snapshot.Status != storage.RewardSnapshotStatusFailed.String() {
+		writeJsonError(w, http.StatusConflict, (&rewards.ErrRewardsSnapshotNotFailed{SnapshotDate: snapshotDate, Status: snapshot.Status}).Error())
+		return
+	}
+
+	rpc.Logger.Sugar().Infow("Requesting retry of failed rewards snapshot", zap.String("snapshotDate", snapshotDate))
+	job, err := rpc.rewardsQueue.Enqueue(&rewardsCalculatorQueue.RewardsCalculationMessage{
+		Data: rewardsCalculatorQueue.RewardsCalculationData{
+			CalculationType: rewardsCalculatorQueue.RewardsCalculationType_RetryFailedSnapshot,
+			CutoffDate:      snapshotDate,
+		},
+		ResponseChan: make(chan *rewardsCalculatorQueue.RewardsCalculatorResponse),
+	})
+	if err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to enqueue rewards snapshot retry", zap.String("snapshotDate", snapshotDate), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJson(w, http.StatusAccepted, newRewardsJobResponse(job))
+}
+
+// ListRewardsInvariantViolations lists the invariants that the rewards calculated for the snapshot date violate.
+//
+// GET /rewards/v1/snapshots/{snapshotDate}/invariant-violations
+func (rpc *RpcServer) ListRewardsInvariantViolations(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	snapshotDate := pathParams["snapshotDate"]
+	if _, err := time.Parse(time.DateOnly, snapshotDate); err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid snapshot date, expected YYYY-MM-DD")
+		return
+	}
+
+	violations, err := rpc.rewardsCalculator.ListRewardsInvariantViolations(snapshotDate)
+	if err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to list rewards invariant violations", zap.String("snapshotDate", snapshotDate), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	res := &listRewardsInvariantViolationsResponse{
+		Violations: make([]*rewardsInvariantViolationResponse, 0, len(violations)),
+	}
+	for _, v := range violations {
+		res.Violations = append(res.Violations, &rewardsInvariantViolationResponse{
+			Invariant:      v.Invariant,
+			ViolationCount: v.ViolationCount,
+			Examples:       strings.Split(v.Examples, "\n"),
+			CreatedAt:      v.CreatedAt,
+		})
+	}
+	writeJson(w, http.StatusOK, res)
+}
@@ -0,0 +1,107 @@
+package rpcServer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+)
+
+const rewardsSimulationsPath = "/rewards/v1/simulations"
+
+// simulationRateLimiter limits how many simulations are started within a sliding window. Each simulation can take
+// as long as a full rewards calculation, so the limit keeps the endpoint from being used to load the database.
+type simulationRateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	started []time.Time
+}
+
+func newSimulationRateLimiter(limit int, window time.Duration) *simulationRateLimiter {
+	return &simulationRateLimiter{
+		limit:   limit,
+		window:  window,
+		started: make([]time.Time, 0, limit),
+	}
+}
+
+// Allow records a simulation starting at now and returns true if fewer than limit simulations were started within
+// the window before it.
+func (l *simulationRateLimiter) Allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	started := l.started[:0]
+	for _, t := range l.started {
+		if now.Sub(t) < l.window {
+			started = append(started, t)
+		}
+	}
+	l.started = started
+
+	if len(l.started) >= l.limit {
+		return false
+	}
+	l.started = append(l.started, now)
+	return true
+}
+
+func (rpc *RpcServer) registerRewardsSimulationHandlers(mux *runtime.ServeMux) error {
+	return mux.HandlePath(http.MethodPost, rewardsSimulationsPath, rpc.SimulateRewards)
+}
+
+// SimulateRewards calculates what each earner would receive from hypothetical reward submissions, without
+// changing any production tables. The request body is a rewards.RewardsSimulation.
+//
+// Simulations are limited to --rewards.simulations_per_hour and only one runs at a time.
+//
+// POST /rewards/v1/simulations
+func (rpc *RpcServer) SimulateRewards(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	// the simulation creates a scratch schema, so it has to run against the primary's database
+	if !rpc.globalConfig.SidecarPrimaryConfig.IsPrimary {
+		writeJsonError(w, http.StatusBadRequest, "rewards can only be simulated on the primary sidecar")
+		return
+	}
+
+	if rpc.simulationLimiter == nil {
+		writeJsonError(w, http.StatusForbidden, "rewards simulations are disabled on this sidecar")
+		return
+	}
+	if !rpc.simulationLimiter.Allow(time.Now()) {
+		writeJsonError(w, http.StatusTooManyRequests, "too many rewards simulations, try again later")
+		return
+	}
+
+	var simulation rewards.RewardsSimulation
+	if err := json.NewDecoder(r.Body).Decode(&simulation); err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid rewards simulation request body")
+		return
+	}
+
+	rpc.Logger.Sugar().Infow("Simulating rewards",
+		zap.String("cutoffDate", simulation.CutoffDate),
+		zap.Int("rewardSubmissions", len(simulation.RewardSubmissions)),
+		zap.Int("operatorDirectedRewardSubmissions", len(simulation.OperatorDirectedRewardSubmissions)),
+	)
+	result, err := rpc.rewardsCalculator.SimulateRewards(r.Context(), &simulation)
+	if err != nil {
+		var invalidErr *rewards.ErrInvalidRewardsSimulation
+		var inProgressErr *rewards.ErrRewardsSimulationInProgress
+		switch {
+		case errors.As(err, &invalidErr):
+			writeJsonError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &inProgressErr):
+			writeJsonError(w, http.StatusTooManyRequests, err.Error())
+		default:
+			writeJsonError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJson(w, http.StatusOK, result)
+}
@@ -0,0 +1,27 @@
+package rpcServer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SimulationRateLimiter(t *testing.T) {
+	t.Run("Should allow up to the limit within the window", func(t *testing.T) {
+		limiter := newSimulationRateLimiter(2, time.Hour)
+		now := time.Now()
+
+		assert.True(t, limiter.Allow(now))
+		assert.True(t, limiter.Allow(now.Add(time.Minute)))
+		assert.False(t, limiter.Allow(now.Add(2*time.Minute)))
+	})
+	t.Run("Should allow simulations again once the window has passed", func(t *testing.T) {
+		limiter := newSimulationRateLimiter(1, time.Hour)
+		now := time.Now()
+
+		assert.True(t, limiter.Allow(now))
+		assert.False(t, limiter.Allow(now.Add(59*time.Minute)))
+		assert.True(t, limiter.Allow(now.Add(time.Hour)))
+	})
+}
@@ -54,6 +54,7 @@ type RpcServer struct {
 	globalConfig        *config.Config
 	sidecarClient       *sidecarClient.SidecarClient
 	metricsSink         *metrics.MetricsSink
+	simulationLimiter   *simulationRateLimiter // nil when rewards simulations are disabled
 }
 
 func NewRpcServer(
@@ -84,6 +85,9 @@ func NewRpcServer(
 		sidecarClient:       scc,
 		metricsSink:         ms,
 	}
+	if cfg.Rewards.SimulationsPerHour > 0 {
+		server.simulationLimiter = newSimulationRateLimiter(cfg.Rewards.SimulationsPerHour, time.Hour)
+	}
 
 	return server
 }
@@ -119,6 +123,58 @@ func (s *RpcServer) registerHandlers(ctx context.Context, grpcServer *grpc.Serve
 		return err
 	}
 
+	grpcServer.RegisterService(&rewardsExportServiceDesc, s)
+
+	if err := s.registerDebugHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register debug handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerStateRootHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register state root handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerRewardsJobHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register rewards job handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerRewardsSimulationHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register rewards simulation handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerRewardsDiffHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register rewards diff handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerRewardsExportHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register rewards export handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerClaimProofBatchHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register claim proof batch handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerPendingRewardsHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register pending rewards handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerClaimProofVerifyHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register claim proof verify handlers", zap.Error(err))
+		return err
+	}
+
+	if err := s.registerSlashingHandlers(mux); err != nil {
+		s.Logger.Sugar().Errorw("Failed to register slashing handlers", zap.Error(err))
+		return err
+	}
+
 	return nil
 }
 
@@ -257,6 +313,9 @@ func injectGrpcHttpMetadata(ctx context.Context, r *http.Request) metadata.MD {
 		if requestMetadata != nil {
 			requestMetadata.Method = method
 		}
+		if isAttributableRewardsMethod(method) {
+			attributableRewardsMetadataFromQuery(r, md)
+		}
 	}
 	if pattern, ok := runtime.HTTPPathPattern(ctx); ok {
 		md["pattern"] = pattern
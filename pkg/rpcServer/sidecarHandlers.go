@@ -30,6 +30,9 @@ func (rpc *RpcServer) GetStateRoot(ctx context.Context, req *sidecarV1.GetStateR
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	if stateRoot == nil {
+		return nil, status.Errorf(codes.NotFound, "state root not found for block %d", blockNumber)
+	}
 	return &sidecarV1.GetStateRootResponse{
 		EthBlockHash:   stateRoot.EthBlockHash,
 		EthBlockNumber: stateRoot.EthBlockNumber,
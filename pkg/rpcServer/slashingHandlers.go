@@ -0,0 +1,148 @@
+package rpcServer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Layr-Labs/sidecar/pkg/service/protocolDataService"
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+)
+
+const (
+	operatorAllocationsPath    = "/protocol/v1/operators/{operator_address}/allocations"
+	operatorSlashableStakePath = "/protocol/v1/operators/{operator_address}/slashable-stake"
+)
+
+type OperatorSetAllocation struct {
+	Avs                string  `json:"avs"`
+	OperatorSetId      uint64  `json:"operatorSetId"`
+	Strategy           string  `json:"strategy"`
+	Magnitude          uint64  `json:"magnitude"`
+	PendingMagnitude   *uint64 `json:"pendingMagnitude,omitempty"`
+	PendingEffectBlock *uint64 `json:"pendingEffectBlock,omitempty"`
+}
+
+type ListOperatorAllocationsResponse struct {
+	Operator    string                   `json:"operator"`
+	BlockHeight uint64                   `json:"blockHeight,omitempty"`
+	Allocations []*OperatorSetAllocation `json:"allocations"`
+}
+
+type OperatorSetSlashableStake struct {
+	Avs             string `json:"avs"`
+	OperatorSetId   uint64 `json:"operatorSetId"`
+	Strategy        string `json:"strategy"`
+	Registered      bool   `json:"registered"`
+	Magnitude       uint64 `json:"magnitude"`
+	MaxMagnitude    uint64 `json:"maxMagnitude"`
+	OperatorShares  string `json:"operatorShares"`
+	SlashableShares string `json:"slashableShares"`
+}
+
+type ListOperatorSlashableStakeResponse struct {
+	Operator       string                       `json:"operator"`
+	BlockHeight    uint64                       `json:"blockHeight,omitempty"`
+	SlashableStake []*OperatorSetSlashableStake `json:"slashableStake"`
+}
+
+func (rpc *RpcServer) registerSlashingHandlers(mux *runtime.ServeMux) error {
+	if err := mux.HandlePath(http.MethodGet, operatorAllocationsPath, rpc.ListOperatorAllocations); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodGet, operatorSlashableStakePath, rpc.ListOperatorSlashableStake)
+}
+
+// parseBlockHeightQuery reads the optional blockHeight query param. Zero means the latest block.
+func parseBlockHeightQuery(r *http.Request) (uint64, bool) {
+	blockHeight := r.URL.Query().Get("blockHeight")
+	if blockHeight == "" {
+		return 0, true
+	}
+	parsed, err := strconv.ParseUint(blockHeight, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return parsed, true
+}
+
+// ListOperatorAllocations returns the magnitude the operator has allocated to each operator set, by strategy, along
+// with allocations that are queued but not in effect yet.
+//
+// GET /protocol/v1/operators/{operator_address}/allocations?blockHeight=
+func (rpc *RpcServer) ListOperatorAllocations(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	operator := pathParams["operator_address"]
+	if operator == "" {
+		writeJsonError(w, http.StatusBadRequest, "operator address is required")
+		return
+	}
+	blockHeight, ok := parseBlockHeightQuery(r)
+	if !ok {
+		writeJsonError(w, http.StatusBadRequest, "invalid blockHeight")
+		return
+	}
+
+	allocations, err := rpc.protocolDataService.ListOperatorAllocations(r.Context(), operator, blockHeight)
+	if err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to list operator allocations", zap.String("operator", operator), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJson(w, http.StatusOK, &ListOperatorAllocationsResponse{
+		Operator:    operator,
+		BlockHeight: blockHeight,
+		Allocations: utils.Map(allocations, func(a *protocolDataService.OperatorSetAllocation, i uint64) *OperatorSetAllocation {
+			return &OperatorSetAllocation{
+				Avs:                a.Avs,
+				OperatorSetId:      a.OperatorSetId,
+				Strategy:           a.Strategy,
+				Magnitude:          a.Magnitude,
+				PendingMagnitude:   a.PendingMagnitude,
+				PendingEffectBlock: a.PendingEffectBlock,
+			}
+		}),
+	})
+}
+
+// ListOperatorSlashableStake returns the shares of each strategy that the operator sets the operator has allocated
+// to can slash.
+//
+// GET /protocol/v1/operators/{operator_address}/slashable-stake?blockHeight=
+func (rpc *RpcServer) ListOperatorSlashableStake(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	operator := pathParams["operator_address"]
+	if operator == "" {
+		writeJsonError(w, http.StatusBadRequest, "operator address is required")
+		return
+	}
+	blockHeight, ok := parseBlockHeightQuery(r)
+	if !ok {
+		writeJsonError(w, http.StatusBadRequest, "invalid blockHeight")
+		return
+	}
+
+	stakes, err := rpc.protocolDataService.ListOperatorSlashableStake(r.Context(), operator, blockHeight)
+	if err != nil {
+		rpc.Logger.Sugar().Errorw("Failed to list operator slashable stake", zap.String("operator", operator), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJson(w, http.StatusOK, &ListOperatorSlashableStakeResponse{
+		Operator:    operator,
+		BlockHeight: blockHeight,
+		SlashableStake: utils.Map(stakes, func(s *protocolDataService.OperatorSetSlashableStake, i uint64) *OperatorSetSlashableStake {
+			return &OperatorSetSlashableStake{
+				Avs:             s.Avs,
+				OperatorSetId:   s.OperatorSetId,
+				Strategy:        s.Strategy,
+				Registered:      s.Registered,
+				Magnitude:       s.Magnitude,
+				MaxMagnitude:    s.MaxMagnitude,
+				OperatorShares:  s.OperatorShares,
+				SlashableShares: s.SlashableShares,
+			}
+		}),
+	})
+}
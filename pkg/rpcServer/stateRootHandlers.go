@@ -0,0 +1,147 @@
+package rpcServer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/utils"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/wealdtech/go-merkletree/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	modelStateRootsPath  = "/rpc/v1/state-roots/{blockNumber}/models"
+	stateChangeProofPath = "/rpc/v1/state-roots/{blockNumber}/models/{modelName}/proof"
+)
+
+type modelStateRootResponse struct {
+	ModelName string `json:"modelName"`
+	Root      string `json:"root"`
+}
+
+type modelStateRootsResponse struct {
+	BlockNumber uint64                    `json:"blockNumber"`
+	ModelRoots  []*modelStateRootResponse `json:"modelRoots"`
+}
+
+type merkleProofResponse struct {
+	Index  uint64   `json:"index"`
+	Hashes []string `json:"hashes"`
+}
+
+type stateChangeProofResponse struct {
+	BlockNumber uint64               `json:"blockNumber"`
+	BlockHash   string               `json:"blockHash"`
+	StateRoot   string               `json:"stateRoot"`
+	ModelName   string               `json:"modelName"`
+	ModelRoot   string               `json:"modelRoot"`
+	SlotID      string               `json:"slotId"`
+	Value       string               `json:"value"`
+	SlotProof   *merkleProofResponse `json:"slotProof"`
+	ModelProof  *merkleProofResponse `json:"modelProof"`
+}
+
+func newMerkleProofResponse(proof *merkletree.Proof) *merkleProofResponse {
+	hashes := make([]string, 0, len(proof.Hashes))
+	for _, h := range proof.Hashes {
+		hashes = append(hashes, utils.ConvertBytesToString(h))
+	}
+	return &merkleProofResponse{
+		Index:  proof.Index,
+		Hashes: hashes,
+	}
+}
+
+func newStateChangeProofResponse(proof *stateManager.StateChangeProof) *stateChangeProofResponse {
+	return &stateChangeProofResponse{
+		BlockNumber: proof.BlockNumber,
+		BlockHash:   proof.BlockHash,
+		StateRoot:   utils.ConvertBytesToString(proof.StateRoot),
+		ModelName:   proof.ModelName,
+		ModelRoot:   utils.ConvertBytesToString(proof.ModelRoot),
+		SlotID:      string(proof.SlotID),
+		Value:       utils.ConvertBytesToString(proof.Value),
+		SlotProof:   newMerkleProofResponse(proof.SlotProof),
+		ModelProof:  newMerkleProofResponse(proof.ModelProof),
+	}
+}
+
+func (s *RpcServer) registerStateRootHandlers(mux *runtime.ServeMux) error {
+	if err := mux.HandlePath(http.MethodGet, modelStateRootsPath, s.GetModelStateRoots); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodGet, stateChangeProofPath, s.GetStateChangeProof)
+}
+
+// GetModelStateRoots returns the persisted per-model roots that make up the state root for a block.
+//
+// GET /rpc/v1/state-roots/{blockNumber}/models
+func (s *RpcServer) GetModelStateRoots(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	blockNumber, err := strconv.ParseUint(pathParams["blockNumber"], 10, 64)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid block number")
+		return
+	}
+
+	roots, err := s.protocolDataService.GetModelStateRoots(r.Context(), blockNumber)
+	if err != nil {
+		s.Logger.Sugar().Errorw("Failed to get model state roots", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	res := &modelStateRootsResponse{
+		BlockNumber: blockNumber,
+		ModelRoots:  make([]*modelStateRootResponse, 0, len(roots)),
+	}
+	for _, root := range roots {
+		res.ModelRoots = append(res.ModelRoots, &modelStateRootResponse{
+			ModelName: root.ModelName,
+			Root:      root.Root,
+		})
+	}
+	writeJson(w, http.StatusOK, res)
+}
+
+// GetStateChangeProof returns a Merkle proof that a state change is part of a block's state root.
+//
+// The state change is identified by the slotId query parameter. If the value query parameter is provided,
+// it must match the hex encoded value of the state change.
+//
+// GET /rpc/v1/state-roots/{blockNumber}/models/{modelName}/proof?slotId=...&value=...
+func (s *RpcServer) GetStateChangeProof(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	blockNumber, err := strconv.ParseUint(pathParams["blockNumber"], 10, 64)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, "invalid block number")
+		return
+	}
+	modelName := pathParams["modelName"]
+	slotID := r.URL.Query().Get("slotId")
+	if slotID == "" {
+		writeJsonError(w, http.StatusBadRequest, "slotId is required")
+		return
+	}
+
+	proof, err := s.protocolDataService.GetStateChangeProof(r.Context(), blockNumber, modelName, types.SlotID(slotID))
+	if err != nil {
+		s.Logger.Sugar().Errorw("Failed to generate state change proof",
+			zap.Uint64("blockNumber", blockNumber),
+			zap.String("modelName", modelName),
+			zap.String("slotId", slotID),
+			zap.Error(err),
+		)
+		writeJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	res := newStateChangeProofResponse(proof)
+	if value := r.URL.Query().Get("value"); value != "" && !strings.EqualFold(value, res.Value) {
+		writeJsonError(w, http.StatusNotFound, "value does not match the state change for the slot")
+		return
+	}
+	writeJson(w, http.StatusOK, res)
+}
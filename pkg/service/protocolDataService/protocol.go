@@ -6,6 +6,8 @@ import (
 	"errors"
 	"github.com/Layr-Labs/sidecar/internal/config"
 	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	eigenStateTypes "github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/replay"
 	"github.com/Layr-Labs/sidecar/pkg/service/baseDataService"
 	"github.com/Layr-Labs/sidecar/pkg/service/types"
 	"github.com/Layr-Labs/sidecar/pkg/storage"
@@ -471,3 +473,37 @@ func (pds *ProtocolDataService) GetEigenStateChangesForBlock(ctx context.Context
 	}
 	return results, nil
 }
+
+// GetBlockStateRoots rebuilds the state for a block from its stored transaction logs and returns the per-model
+// roots and slots that make up its state root.
+//
+// The sidecar's EigenStateManager is reused; the rebuild waits for the pipeline to finish the block it is processing.
+func (pds *ProtocolDataService) GetBlockStateRoots(ctx context.Context, blockHeight uint64) (*replay.BlockStateRoots, error) {
+	return replay.NewReplayer(pds.stateManager, pds.db, pds.logger).RebuildBlockStateRoots(blockHeight)
+}
+
+// GetModelStateRoots returns the persisted per-model roots that make up the state root for a block.
+func (pds *ProtocolDataService) GetModelStateRoots(ctx context.Context, blockHeight uint64) ([]*stateManager.ModelRoot, error) {
+	roots := make([]*stateManager.ModelRoot, 0)
+	res := pds.db.Model(&stateManager.ModelRoot{}).
+		Where("eth_block_number = ?", blockHeight).
+		Order("model_name asc").
+		Find(&roots)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return roots, nil
+}
+
+// GetStateChangeProof rebuilds the state for a block from its stored transaction logs and returns a proof that the
+// state change for the slot in the model is part of the block's state root.
+//
+// The sidecar's EigenStateManager is reused; the rebuild waits for the pipeline to finish the block it is processing.
+func (pds *ProtocolDataService) GetStateChangeProof(
+	ctx context.Context,
+	blockHeight uint64,
+	modelName string,
+	slotID eigenStateTypes.SlotID,
+) (*stateManager.StateChangeProof, error) {
+	return replay.NewReplayer(pds.stateManager, pds.db, pds.logger).GenerateStateChangeProof(blockHeight, modelName, slotID)
+}
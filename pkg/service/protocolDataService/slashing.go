@@ -0,0 +1,178 @@
+package protocolDataService
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type OperatorSetAllocation struct {
+	Avs           string
+	OperatorSetId uint64
+	Strategy      string
+	// Magnitude is the allocation in effect at the block height
+	Magnitude uint64
+	// PendingMagnitude and PendingEffectBlock are set when an allocation or deallocation has been queued but does
+	// not take effect until after the block height
+	PendingMagnitude   *uint64
+	PendingEffectBlock *uint64
+}
+
+// ListOperatorAllocations returns the magnitude of every strategy the operator has allocated to an operator set at
+// the given block height, along with any allocation that has been made but not taken effect yet
+func (pds *ProtocolDataService) ListOperatorAllocations(ctx context.Context, operator string, blockHeight uint64) ([]*OperatorSetAllocation, error) {
+	blockHeight, err := pds.BaseDataService.GetCurrentBlockHeightIfNotPresent(ctx, blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		with effective_allocations as (
+			select distinct on (avs, operator_set_id, strategy)
+				avs,
+				operator_set_id,
+				strategy,
+				magnitude
+			from operator_allocations
+			where
+				operator = @operator
+				and block_number <= @blockHeight
+				and effect_block <= @blockHeight
+			order by avs, operator_set_id, strategy, block_number desc, log_index desc
+		),
+		latest_allocations as (
+			select distinct on (avs, operator_set_id, strategy)
+				avs,
+				operator_set_id,
+				strategy,
+				magnitude,
+				effect_block
+			from operator_allocations
+			where
+				operator = @operator
+				and block_number <= @blockHeight
+			order by avs, operator_set_id, strategy, block_number desc, log_index desc
+		)
+		select
+			la.avs,
+			la.operator_set_id,
+			la.strategy,
+			coalesce(ea.magnitude, 0) as magnitude,
+			case when la.effect_block > @blockHeight then la.magnitude else null end as pending_magnitude,
+			case when la.effect_block > @blockHeight then la.effect_block else null end as pending_effect_block
+		from latest_allocations as la
+		left join effective_allocations as ea on (
+			ea.avs = la.avs
+			and ea.operator_set_id = la.operator_set_id
+			and ea.strategy = la.strategy
+		)
+		order by la.avs, la.operator_set_id, la.strategy
+	`
+	allocations := make([]*OperatorSetAllocation, 0)
+	res := pds.db.Raw(query,
+		sql.Named("operator", strings.ToLower(operator)),
+		sql.Named("blockHeight", blockHeight),
+	).Scan(&allocations)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return allocations, nil
+}
+
+type OperatorSetSlashableStake struct {
+	Avs           string
+	OperatorSetId uint64
+	Strategy      string
+	Registered    bool
+	Magnitude     uint64
+	MaxMagnitude  uint64
+	// OperatorShares is the operator's total delegated shares in the strategy, after any slashing
+	OperatorShares string
+	// SlashableShares is the part of OperatorShares the operator set can slash, OperatorShares * Magnitude / MaxMagnitude
+	SlashableShares string
+}
+
+// ListOperatorSlashableStake returns the shares of each strategy that every operator set the operator has allocated
+// to can slash at the given block height
+func (pds *ProtocolDataService) ListOperatorSlashableStake(ctx context.Context, operator string, blockHeight uint64) ([]*OperatorSetSlashableStake, error) {
+	blockHeight, err := pds.BaseDataService.GetCurrentBlockHeightIfNotPresent(ctx, blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	// operators start with a max magnitude of 1e18 (WAD) in every strategy until they are first slashed
+	query := `
+		with effective_allocations as (
+			select distinct on (avs, operator_set_id, strategy)
+				avs,
+				operator_set_id,
+				strategy,
+				magnitude
+			from operator_allocations
+			where
+				operator = @operator
+				and block_number <= @blockHeight
+				and effect_block <= @blockHeight
+			order by avs, operator_set_id, strategy, block_number desc, log_index desc
+		),
+		max_magnitudes as (
+			select distinct on (strategy)
+				strategy,
+				max_magnitude
+			from operator_max_magnitudes
+			where
+				operator = @operator
+				and block_number <= @blockHeight
+			order by strategy, block_number desc, log_index desc
+		),
+		registrations as (
+			select distinct on (avs, operator_set_id)
+				avs,
+				operator_set_id,
+				is_active
+			from operator_set_operator_registrations
+			where
+				operator = @operator
+				and block_number <= @blockHeight
+			order by avs, operator_set_id, block_number desc, log_index desc
+		),
+		operator_shares as (
+			select
+				strategy,
+				sum(shares) as shares
+			from operator_share_deltas
+			where
+				operator = @operator
+				and block_number <= @blockHeight
+			group by strategy
+		)
+		select
+			ea.avs,
+			ea.operator_set_id,
+			ea.strategy,
+			coalesce(r.is_active, false) as registered,
+			ea.magnitude,
+			coalesce(mm.max_magnitude, 1000000000000000000) as max_magnitude,
+			cast(coalesce(os.shares, 0) as varchar) as operator_shares,
+			cast(
+				case when coalesce(mm.max_magnitude, 1000000000000000000) = 0 then 0
+				else floor(coalesce(os.shares, 0) * ea.magnitude / coalesce(mm.max_magnitude, 1000000000000000000))
+				end as varchar
+			) as slashable_shares
+		from effective_allocations as ea
+		left join max_magnitudes as mm on (mm.strategy = ea.strategy)
+		left join registrations as r on (r.avs = ea.avs and r.operator_set_id = ea.operator_set_id)
+		left join operator_shares as os on (os.strategy = ea.strategy)
+		where ea.magnitude > 0
+		order by ea.avs, ea.operator_set_id, ea.strategy
+	`
+	stakes := make([]*OperatorSetSlashableStake, 0)
+	res := pds.db.Raw(query,
+		sql.Named("operator", strings.ToLower(operator)),
+		sql.Named("blockHeight", blockHeight),
+	).Scan(&stakes)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return stakes, nil
+}
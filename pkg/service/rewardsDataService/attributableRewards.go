@@ -0,0 +1,192 @@
+package rewardsDataService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	eigenStateTypes "github.com/Layr-Labs/sidecar/pkg/eigenState/types"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/service/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AttributableRewardsFilters narrows down the attributable rewards returned for a snapshot.
+// Empty fields are not filtered on.
+type AttributableRewardsFilters struct {
+	Earner   string
+	Operator string
+	Avs      string
+	Strategy string
+	Token    string
+}
+
+type AttributableReward struct {
+	Earner     string
+	Operator   string
+	RewardType string
+	Avs        string
+	Token      string
+	Strategy   string
+	Multiplier string
+	Shares     string
+	Amount     string
+	RewardHash string
+	Snapshot   string
+}
+
+// StakerOperatorsSnapshot is the completed rewards snapshot whose staker-operator data covers a requested snapshot date
+type StakerOperatorsSnapshot struct {
+	// RewardsSnapshotDate is the snapshot date of the first completed rewards calculation on or after the requested date
+	RewardsSnapshotDate string
+
+	// Generated is true if the staker_operator table has been generated for RewardsSnapshotDate
+	Generated bool
+}
+
+// GetStakerOperatorsSnapshot finds the completed rewards snapshot that covers the snapshot date and whether
+// the staker-operator data has been generated for it. Nil is returned if rewards have not been calculated
+// up to the snapshot date yet.
+//
+// The staker_operator table is filled in from the date-suffixed staging table, so the staging table existing
+// for a rewards snapshot means that its rows have been inserted.
+func (rds *RewardsDataService) GetStakerOperatorsSnapshot(ctx context.Context, snapshotDate string) (*StakerOperatorsSnapshot, error) {
+	var generatedSnapshot *storage.GeneratedRewardsSnapshots
+	res := rds.db.Model(&storage.GeneratedRewardsSnapshots{}).
+		Where("snapshot_date >= ?", snapshotDate).
+		Where("status = ?", storage.RewardSnapshotStatusCompleted.String()).
+		Order("snapshot_date asc").
+		First(&generatedSnapshot)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, res.Error
+	}
+
+	schemaName := rds.globalConfig.DatabaseConfig.SchemaName
+	if schemaName == "" {
+		schemaName = "public"
+	}
+	var tableCount int64
+	res = rds.db.Raw(`
+		select count(*)
+		from information_schema.tables
+		where
+			table_schema = @schemaName
+			and table_name like @pattern
+	`,
+		sql.Named("schemaName", schemaName),
+		sql.Named("pattern", rewardsUtils.FormatTableName(
+			rewardsUtils.GoldTableNameSearchPattern[rewardsUtils.Sot_12_StakerOperatorStaging],
+			generatedSnapshot.SnapshotDate,
+		)),
+	).Scan(&tableCount)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return &StakerOperatorsSnapshot{
+		RewardsSnapshotDate: generatedSnapshot.SnapshotDate,
+		Generated:           tableCount > 0,
+	}, nil
+}
+
+// GetDistributionRoot returns the submitted distribution root with the given root hash, or nil if it does not exist
+func (rds *RewardsDataService) GetDistributionRoot(ctx context.Context, root string) (*eigenStateTypes.SubmittedDistributionRoot, error) {
+	var distributionRoot *eigenStateTypes.SubmittedDistributionRoot
+	query := `
+		select
+			*
+		from submitted_distribution_roots
+		where lower(root) = lower(@root)
+		order by block_number desc
+		limit 1
+	`
+	res := rds.db.Raw(query, sql.Named("root", root)).Scan(&distributionRoot)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, res.Error
+	}
+	return distributionRoot, nil
+}
+
+// ListAttributableRewardsForSnapshot lists the staker-operator breakdown of every reward up to and including the
+// snapshot date. Summing the amounts per earner and token gives the cumulative rewards of GetRewardsForSnapshot.
+//
+// Rows are ordered by the columns of the staker_operator unique constraint so that pages are stable.
+func (rds *RewardsDataService) ListAttributableRewardsForSnapshot(
+	ctx context.Context,
+	snapshotDate string,
+	filters *AttributableRewardsFilters,
+	pagination *types.Pagination,
+) ([]*AttributableReward, error) {
+	query := `
+		select
+			earner,
+			operator,
+			reward_type,
+			avs,
+			token,
+			strategy,
+			cast(multiplier as varchar) as multiplier,
+			cast(shares as varchar) as shares,
+			cast(amount as varchar) as amount,
+			reward_hash,
+			to_char(snapshot, 'YYYY-MM-DD') as snapshot
+		from staker_operator
+		where snapshot <= @snapshot
+	`
+	queryParams := []interface{}{
+		sql.Named("snapshot", snapshotDate),
+	}
+
+	if filters != nil {
+		columnFilters := []struct {
+			column string
+			value  string
+		}{
+			{"earner", filters.Earner},
+			{"operator", filters.Operator},
+			{"avs", filters.Avs},
+			{"strategy", filters.Strategy},
+			{"token", filters.Token},
+		}
+		for _, f := range columnFilters {
+			if f.value == "" {
+				continue
+			}
+			query += fmt.Sprintf(` and %s = @%s`, f.column, f.column)
+			queryParams = append(queryParams, sql.Named(f.column, strings.ToLower(f.value)))
+		}
+	}
+
+	query += ` order by snapshot, earner, operator, reward_hash, strategy, reward_type`
+
+	if pagination != nil {
+		query += ` LIMIT @limit`
+		queryParams = append(queryParams, sql.Named("limit", pagination.PageSize))
+
+		if pagination.Page > 0 {
+			query += ` OFFSET @offset`
+			queryParams = append(queryParams, sql.Named("offset", pagination.Page*pagination.PageSize))
+		}
+	}
+
+	rewards := make([]*AttributableReward, 0)
+	res := rds.db.Raw(query, queryParams...).Scan(&rewards)
+	if res.Error != nil {
+		rds.logger.Sugar().Errorw("Failed to list attributable rewards",
+			zap.String("snapshot", snapshotDate),
+			zap.Error(res.Error),
+		)
+		return nil, res.Error
+	}
+	return rewards, nil
+}
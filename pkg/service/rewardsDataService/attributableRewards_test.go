@@ -0,0 +1,117 @@
+package rewardsDataService
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsUtils"
+	"github.com/Layr-Labs/sidecar/pkg/service/types"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AttributableRewards(t *testing.T) {
+	dbName, cfg, grm, l, err := setupTestDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rds := NewRewardsDataService(grm, l, cfg, nil)
+
+	insertStakerOperatorRow := func(t *testing.T, earner string, operator string, strategy string, snapshot string) {
+		res := grm.Exec(`
+			insert into staker_operator (earner, operator, reward_type, avs, token, strategy, multiplier, shares, amount, reward_hash, snapshot)
+			values (?, ?, 'staker_reward', '0xavs', '0xtoken', ?, '1000000000000000000', '100', '10', ?, ?)
+		`, earner, operator, strategy, fmt.Sprintf("0xreward_%s", snapshot), snapshot)
+		assert.Nil(t, res.Error)
+	}
+
+	t.Run("Should not find a staker operators snapshot before rewards are calculated", func(t *testing.T) {
+		sos, err := rds.GetStakerOperatorsSnapshot(context.Background(), "2024-08-01")
+		assert.Nil(t, err)
+		assert.Nil(t, sos)
+	})
+	t.Run("Should find the first completed snapshot on or after the snapshot date", func(t *testing.T) {
+		for _, s := range []*storage.GeneratedRewardsSnapshots{
+			{SnapshotDate: "2024-08-02", Status: storage.RewardSnapshotStatusCompleted.String()},
+			{SnapshotDate: "2024-08-04", Status: storage.RewardSnapshotStatusCompleted.String()},
+			{SnapshotDate: "2024-08-03", Status: storage.RewardSnapshotStatusFailed.String()},
+		} {
+			res := grm.Create(s)
+			assert.Nil(t, res.Error)
+		}
+
+		sos, err := rds.GetStakerOperatorsSnapshot(context.Background(), "2024-08-03")
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-08-04", sos.RewardsSnapshotDate)
+		assert.False(t, sos.Generated)
+
+		stagingTableName := rewardsUtils.FormatTableName(rewardsUtils.Sot_12_StakerOperatorStaging, "2024-08-04")
+		res := grm.Exec(fmt.Sprintf(`create table %s (earner text)`, stagingTableName))
+		assert.Nil(t, res.Error)
+
+		sos, err = rds.GetStakerOperatorsSnapshot(context.Background(), "2024-08-03")
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-08-04", sos.RewardsSnapshotDate)
+		assert.True(t, sos.Generated)
+	})
+	t.Run("Should list attributable rewards up to the snapshot with filters", func(t *testing.T) {
+		insertStakerOperatorRow(t, "0xstaker1", "0xoperator1", "0xstrategy1", "2024-08-01")
+		insertStakerOperatorRow(t, "0xstaker1", "0xoperator1", "0xstrategy2", "2024-08-01")
+		insertStakerOperatorRow(t, "0xstaker2", "0xoperator2", "0xstrategy1", "2024-08-02")
+		insertStakerOperatorRow(t, "0xstaker1", "0xoperator1", "0xstrategy1", "2024-08-03")
+
+		rewards, err := rds.ListAttributableRewardsForSnapshot(context.Background(), "2024-08-02", nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(rewards))
+
+		rewards, err = rds.ListAttributableRewardsForSnapshot(context.Background(), "2024-08-03", &AttributableRewardsFilters{
+			Earner:   "0xSTAKER1",
+			Strategy: "0xstrategy1",
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(rewards))
+		for _, r := range rewards {
+			assert.Equal(t, "0xstaker1", r.Earner)
+			assert.Equal(t, "0xstrategy1", r.Strategy)
+		}
+		assert.Equal(t, "2024-08-01", rewards[0].Snapshot)
+		assert.Equal(t, "10", rewards[0].Amount)
+
+		rewards, err = rds.ListAttributableRewardsForSnapshot(context.Background(), "2024-08-03", &AttributableRewardsFilters{
+			Operator: "0xoperator2",
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(rewards))
+		assert.Equal(t, "0xstaker2", rewards[0].Earner)
+	})
+	t.Run("Should paginate attributable rewards", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for page := uint32(0); page < 2; page++ {
+			rewards, err := rds.ListAttributableRewardsForSnapshot(context.Background(), "2024-08-03", nil, &types.Pagination{
+				Page:     page,
+				PageSize: 2,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(rewards))
+			for _, r := range rewards {
+				key := fmt.Sprintf("%s_%s_%s_%s", r.Earner, r.Operator, r.Strategy, r.Snapshot)
+				assert.False(t, seen[key])
+				seen[key] = true
+			}
+		}
+
+		rewards, err := rds.ListAttributableRewardsForSnapshot(context.Background(), "2024-08-03", nil, &types.Pagination{
+			Page:     2,
+			PageSize: 2,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(rewards))
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
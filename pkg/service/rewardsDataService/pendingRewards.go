@@ -0,0 +1,65 @@
+package rewardsDataService
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+)
+
+// GetPendingRewardsForEarner returns the estimated rewards the earner has accrued since the snapshot of the last
+// submitted distribution root.
+//
+// The amounts are not part of any distribution root and are unverified. Estimates made before the latest completed
+// rewards calculation or based on an older distribution root are not returned, since the calculation or the root
+// may already include some of them.
+func (rds *RewardsDataService) GetPendingRewardsForEarner(ctx context.Context, earner string, tokens []string) ([]*rewards.PendingReward, error) {
+	if earner == "" {
+		return nil, fmt.Errorf("earner is required")
+	}
+	earner = strings.ToLower(earner)
+	tokens = lowercaseTokenList(tokens)
+
+	query := `
+		select
+			earner,
+			token,
+			cast(amount as varchar) as amount,
+			snapshot_date,
+			generated_rewards_snapshot_id,
+			calculated_at
+		from pending_rewards
+		where
+			earner = @earner
+			and generated_rewards_snapshot_id = (
+				select coalesce(max(id), 0)
+				from generated_rewards_snapshots
+				where status = 'complete'
+			)
+			and distribution_root_index is not distinct from (
+				select sdr.root_index
+				from submitted_distribution_roots as sdr
+				left join disabled_distribution_roots as ddr on (sdr.root_index = ddr.root_index)
+				where ddr.root_index is null
+				order by sdr.block_number desc, sdr.root_index desc
+				limit 1
+			)
+	`
+	args := []interface{}{
+		sql.Named("earner", earner),
+	}
+	if len(tokens) > 0 {
+		query += " and token in @tokens"
+		args = append(args, sql.Named("tokens", tokens))
+	}
+	query += " order by token"
+
+	pending := make([]*rewards.PendingReward, 0)
+	res := rds.db.WithContext(ctx).Raw(query, args...).Scan(&pending)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return pending, nil
+}
@@ -0,0 +1,109 @@
+package rewardsDataService
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewards"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PendingRewards(t *testing.T) {
+	dbName, cfg, grm, l, err := setupTestDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rds := NewRewardsDataService(grm, l, cfg, nil)
+
+	createGeneratedSnapshot := func(t *testing.T, snapshotDate string) *storage.GeneratedRewardsSnapshots {
+		snapshot := &storage.GeneratedRewardsSnapshots{SnapshotDate: snapshotDate, Status: storage.RewardSnapshotStatusCompleted.String()}
+		res := grm.Create(snapshot)
+		assert.Nil(t, res.Error)
+		return snapshot
+	}
+	insertPendingReward := func(t *testing.T, token string, amount string, generatedSnapshotId uint64, rootIndex *uint64) {
+		res := grm.Create(&rewards.PendingReward{
+			Earner:                     "0xearner",
+			Token:                      token,
+			Amount:                     amount,
+			SnapshotDate:               "2025-03-01",
+			GeneratedRewardsSnapshotId: generatedSnapshotId,
+			DistributionRootIndex:      rootIndex,
+			CalculatedAt:               time.Now(),
+		})
+		assert.Nil(t, res.Error)
+	}
+	submitDistributionRoot := func(t *testing.T, rootIndex uint64, calculationEnd time.Time) {
+		res := grm.Exec(`
+			insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+			values (?, 1, ?, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', ?)
+		`, fmt.Sprintf("0xroot%d", rootIndex), rootIndex, calculationEnd, calculationEnd, rootIndex)
+		assert.Nil(t, res.Error)
+	}
+
+	res := grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)})
+	assert.Nil(t, res.Error)
+
+	t.Run("Should return pending rewards estimated before any root was submitted", func(t *testing.T) {
+		snapshot := createGeneratedSnapshot(t, "2025-02-01")
+		insertPendingReward(t, "0xtoken1", "100", snapshot.Id, nil)
+		insertPendingReward(t, "0xtoken2", "200", snapshot.Id, nil)
+
+		pending, err := rds.GetPendingRewardsForEarner(context.Background(), "0xEARNER", nil)
+		assert.Nil(t, err)
+		assert.Len(t, pending, 2)
+		assert.Equal(t, "0xtoken1", pending[0].Token)
+		assert.Equal(t, "100", pending[0].Amount)
+
+		pending, err = rds.GetPendingRewardsForEarner(context.Background(), "0xearner", []string{"0xTOKEN2"})
+		assert.Nil(t, err)
+		assert.Len(t, pending, 1)
+		assert.Equal(t, "200", pending[0].Amount)
+	})
+	t.Run("Should not return pending rewards estimated before the latest root was submitted", func(t *testing.T) {
+		submitDistributionRoot(t, 0, time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC))
+
+		pending, err := rds.GetPendingRewardsForEarner(context.Background(), "0xearner", nil)
+		assert.Nil(t, err)
+		assert.Len(t, pending, 0)
+	})
+	t.Run("Should return pending rewards estimated since the latest root", func(t *testing.T) {
+		res := grm.Exec(`delete from pending_rewards`)
+		assert.Nil(t, res.Error)
+
+		var snapshotId uint64
+		res = grm.Raw(`select max(id) from generated_rewards_snapshots`).Scan(&snapshotId)
+		assert.Nil(t, res.Error)
+		rootIndex := uint64(0)
+		insertPendingReward(t, "0xtoken1", "300", snapshotId, &rootIndex)
+
+		pending, err := rds.GetPendingRewardsForEarner(context.Background(), "0xearner", nil)
+		assert.Nil(t, err)
+		assert.Len(t, pending, 1)
+		assert.Equal(t, "300", pending[0].Amount)
+	})
+	t.Run("Should ignore rewards calculations that did not complete", func(t *testing.T) {
+		res := grm.Create(&storage.GeneratedRewardsSnapshots{SnapshotDate: "2025-02-08", Status: storage.RewardSnapshotStatusFailed.String()})
+		assert.Nil(t, res.Error)
+
+		pending, err := rds.GetPendingRewardsForEarner(context.Background(), "0xearner", nil)
+		assert.Nil(t, err)
+		assert.Len(t, pending, 1)
+	})
+	t.Run("Should not return pending rewards estimated before the latest calculation", func(t *testing.T) {
+		createGeneratedSnapshot(t, "2025-02-09")
+
+		pending, err := rds.GetPendingRewardsForEarner(context.Background(), "0xearner", nil)
+		assert.Nil(t, err)
+		assert.Len(t, pending, 0)
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
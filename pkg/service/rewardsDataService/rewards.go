@@ -359,6 +359,11 @@ type SummarizedReward struct {
 	Active    string
 	Claimed   string
 	Claimable string
+
+	// Pending is the unverified estimate of the rewards accrued since the last submitted distribution root. It is
+	// only set for the current block height.
+	Pending             string
+	PendingSnapshotDate string
 }
 
 func setTokenValueInMap(tokenMap map[string]*SummarizedReward, values []*RewardAmount, fieldName string) {
@@ -386,6 +391,9 @@ func (rds *RewardsDataService) GetSummarizedRewards(ctx context.Context, earner
 	earner = strings.ToLower(earner)
 	tokens = lowercaseTokenList(tokens)
 
+	// pending rewards are estimated for the latest snapshot, so they only make sense at the current block height
+	includePending := blockHeight == 0
+
 	blockHeight, err := rds.BaseDataService.GetCurrentBlockHeightIfNotPresent(context.Background(), blockHeight)
 	if err != nil {
 		return nil, err
@@ -403,8 +411,9 @@ func (rds *RewardsDataService) GetSummarizedRewards(ctx context.Context, earner
 	activeRewardsChan := make(chan *ChanResult[[]*RewardAmount], 1)
 	claimableRewardsChan := make(chan *ChanResult[[]*RewardAmount], 1)
 	claimedRewardsChan := make(chan *ChanResult[[]*RewardAmount], 1)
+	pendingRewardsChan := make(chan *ChanResult[[]*rewards.PendingReward], 1)
 	wg := sync.WaitGroup{}
-	wg.Add(4)
+	wg.Add(5)
 
 	go func() {
 		defer wg.Done()
@@ -459,11 +468,21 @@ func (rds *RewardsDataService) GetSummarizedRewards(ctx context.Context, earner
 		}
 		claimedRewardsChan <- res
 	}()
+
+	go func() {
+		defer wg.Done()
+		res := &ChanResult[[]*rewards.PendingReward]{}
+		if includePending {
+			res.Data, res.Error = rds.GetPendingRewardsForEarner(ctx, earner, tokens)
+		}
+		pendingRewardsChan <- res
+	}()
 	wg.Wait()
 	close(earnedRewardsChan)
 	close(activeRewardsChan)
 	close(claimableRewardsChan)
 	close(claimedRewardsChan)
+	close(pendingRewardsChan)
 
 	earnedRewards := <-earnedRewardsChan
 	if earnedRewards.Error != nil {
@@ -489,6 +508,20 @@ func (rds *RewardsDataService) GetSummarizedRewards(ctx context.Context, earner
 	}
 	setTokenValueInMap(tokenMap, claimedRewards.Data, "Claimed")
 
+	pendingRewards := <-pendingRewardsChan
+	if pendingRewards.Error != nil {
+		return nil, pendingRewards.Error
+	}
+	setTokenValueInMap(tokenMap, utils.Map(pendingRewards.Data, func(pr *rewards.PendingReward, i uint64) *RewardAmount {
+		return &RewardAmount{
+			Token:  pr.Token,
+			Amount: pr.Amount,
+		}
+	}), "Pending")
+	for _, pr := range pendingRewards.Data {
+		tokenMap[pr.Token].PendingSnapshotDate = pr.SnapshotDate
+	}
+
 	tokenList := make([]*SummarizedReward, 0)
 	for _, v := range tokenMap {
 		tokenList = append(tokenList, v)
@@ -603,7 +636,7 @@ func (rds *RewardsDataService) GetRewardsByAvsForDistributionRoot(ctx context.Co
 	}
 
 	tablePattern := fmt.Sprintf("%s_%s",
-		rewardsUtils.GoldTableNameSearchPattern[rewardsUtils.Table_11_GoldStaging],
+		rewardsUtils.GoldTableNameSearchPattern[rewardsUtils.Table_15_GoldStaging],
 		utils.SnakeCase(root.GetSnapshotDate()),
 	)
 
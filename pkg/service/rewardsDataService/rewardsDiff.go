@@ -0,0 +1,194 @@
+package rewardsDataService
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reward types that the delta of a RewardsDiff is broken down by
+const (
+	RewardsDiffType_Avs              = "avs"
+	RewardsDiffType_AllStakers       = "all_stakers"
+	RewardsDiffType_AllEarners       = "all_earners"
+	RewardsDiffType_OperatorDirected = "operator_directed"
+	// RewardsDiffType_OperatorDirectedOperatorSet is an operator-directed reward submitted to an operator set
+	RewardsDiffType_OperatorDirectedOperatorSet = "operator_directed_operator_set"
+
+	// RewardsDiffType_Unknown is used for gold rows whose reward hash does not match any reward submission,
+	// which should never happen and is worth investigating if it does.
+	RewardsDiffType_Unknown = "unknown"
+)
+
+type EarnerRewardsDiff struct {
+	Earner         string `json:"earner"`
+	Token          string `json:"token"`
+	PreviousAmount string `json:"previousAmount"`
+	NewAmount      string `json:"newAmount"`
+	Delta          string `json:"delta"`
+
+	// DeltaByRewardType maps each reward type that contributed to the delta to its share of the delta
+	DeltaByRewardType map[string]string `json:"deltaByRewardType"`
+}
+
+type RewardsDiff struct {
+	FromSnapshot string               `json:"fromSnapshot"`
+	ToSnapshot   string               `json:"toSnapshot"`
+	Rewards      []*EarnerRewardsDiff `json:"rewards"`
+}
+
+type ErrInvalidRewardsDiff struct {
+	Message string
+}
+
+func (e *ErrInvalidRewardsDiff) Error() string {
+	return e.Message
+}
+
+type ErrDistributionRootNotFound struct {
+	RootIndex uint64
+}
+
+func (e *ErrDistributionRootNotFound) Error() string {
+	return fmt.Sprintf("no distribution root found for root index '%d'", e.RootIndex)
+}
+
+type rewardsDiffTotals struct {
+	Earner         string
+	Token          string
+	PreviousAmount string
+	NewAmount      string
+	Delta          string
+}
+
+type rewardsDiffTypeDelta struct {
+	Earner     string
+	Token      string
+	RewardType string
+	Amount     string
+}
+
+// GetRewardsDiffForSnapshots compares the cumulative rewards of every earner and token at two snapshots.
+//
+// Only earners and tokens whose cumulative amount changed between the snapshots are returned.
+func (rds *RewardsDataService) GetRewardsDiffForSnapshots(ctx context.Context, fromSnapshot string, toSnapshot string) (*RewardsDiff, error) {
+	for _, snapshot := range []string{fromSnapshot, toSnapshot} {
+		if _, err := time.Parse(time.DateOnly, snapshot); err != nil {
+			return nil, &ErrInvalidRewardsDiff{Message: fmt.Sprintf("invalid snapshot '%s', expected YYYY-MM-DD", snapshot)}
+		}
+	}
+	if fromSnapshot >= toSnapshot {
+		return nil, &ErrInvalidRewardsDiff{Message: fmt.Sprintf("from snapshot '%s' must be before to snapshot '%s'", fromSnapshot, toSnapshot)}
+	}
+
+	totalsQuery := `
+		select
+			earner,
+			token,
+			cast(sum(case when snapshot <= @fromSnapshot then amount else 0 end) as varchar) as previous_amount,
+			cast(sum(amount) as varchar) as new_amount,
+			cast(sum(case when snapshot > @fromSnapshot then amount else 0 end) as varchar) as delta
+		from gold_table
+		where snapshot <= @toSnapshot
+		group by earner, token
+		having sum(case when snapshot > @fromSnapshot then amount else 0 end) != 0
+		order by earner, token
+	`
+	totals := make([]*rewardsDiffTotals, 0)
+	res := rds.db.Raw(totalsQuery,
+		sql.Named("fromSnapshot", fromSnapshot),
+		sql.Named("toSnapshot", toSnapshot),
+	).Scan(&totals)
+	if res.Error != nil {
+		rds.logger.Sugar().Errorw("Failed to get rewards diff totals",
+			zap.String("fromSnapshot", fromSnapshot),
+			zap.String("toSnapshot", toSnapshot),
+			zap.Error(res.Error),
+		)
+		return nil, res.Error
+	}
+
+	typeDeltasQuery := `
+		with reward_types as (
+			select distinct reward_hash, reward_type from reward_submissions
+			union
+			select distinct reward_hash, @operatorDirected as reward_type from operator_directed_reward_submissions
+			union
+			select distinct reward_hash, @operatorDirectedOperatorSet as reward_type from operator_directed_operator_set_reward_submissions
+		)
+		select
+			g.earner,
+			g.token,
+			coalesce(rt.reward_type, @unknown) as reward_type,
+			cast(sum(g.amount) as varchar) as amount
+		from gold_table as g
+		left join reward_types as rt on (rt.reward_hash = g.reward_hash)
+		where
+			g.snapshot > @fromSnapshot
+			and g.snapshot <= @toSnapshot
+		group by 1, 2, 3
+	`
+	typeDeltas := make([]*rewardsDiffTypeDelta, 0)
+	res = rds.db.Raw(typeDeltasQuery,
+		sql.Named("fromSnapshot", fromSnapshot),
+		sql.Named("toSnapshot", toSnapshot),
+		sql.Named("operatorDirected", RewardsDiffType_OperatorDirected),
+		sql.Named("operatorDirectedOperatorSet", RewardsDiffType_OperatorDirectedOperatorSet),
+		sql.Named("unknown", RewardsDiffType_Unknown),
+	).Scan(&typeDeltas)
+	if res.Error != nil {
+		rds.logger.Sugar().Errorw("Failed to get rewards diff by reward type",
+			zap.String("fromSnapshot", fromSnapshot),
+			zap.String("toSnapshot", toSnapshot),
+			zap.Error(res.Error),
+		)
+		return nil, res.Error
+	}
+
+	diffs := make([]*EarnerRewardsDiff, 0, len(totals))
+	diffsByEarnerToken := make(map[string]*EarnerRewardsDiff, len(totals))
+	for _, t := range totals {
+		diff := &EarnerRewardsDiff{
+			Earner:            t.Earner,
+			Token:             t.Token,
+			PreviousAmount:    t.PreviousAmount,
+			NewAmount:         t.NewAmount,
+			Delta:             t.Delta,
+			DeltaByRewardType: make(map[string]string),
+		}
+		diffs = append(diffs, diff)
+		diffsByEarnerToken[fmt.Sprintf("%s_%s", t.Earner, t.Token)] = diff
+	}
+	for _, d := range typeDeltas {
+		// deltas of a reward type can cancel out, in which case the earner is not part of the diff
+		if diff, ok := diffsByEarnerToken[fmt.Sprintf("%s_%s", d.Earner, d.Token)]; ok {
+			diff.DeltaByRewardType[d.RewardType] = d.Amount
+		}
+	}
+
+	return &RewardsDiff{
+		FromSnapshot: fromSnapshot,
+		ToSnapshot:   toSnapshot,
+		Rewards:      diffs,
+	}, nil
+}
+
+// GetRewardsDiffForDistributionRoots compares the cumulative rewards of every earner and token at the snapshots of
+// two distribution roots.
+func (rds *RewardsDataService) GetRewardsDiffForDistributionRoots(ctx context.Context, fromRootIndex uint64, toRootIndex uint64) (*RewardsDiff, error) {
+	snapshots := make([]string, 0, 2)
+	for _, rootIndex := range []uint64{fromRootIndex, toRootIndex} {
+		root, err := rds.getDistributionRootByRootIndex(rootIndex)
+		if err != nil {
+			return nil, err
+		}
+		if root == nil {
+			return nil, &ErrDistributionRootNotFound{RootIndex: rootIndex}
+		}
+		snapshots = append(snapshots, root.GetSnapshotDate())
+	}
+	return rds.GetRewardsDiffForSnapshots(ctx, snapshots[0], snapshots[1])
+}
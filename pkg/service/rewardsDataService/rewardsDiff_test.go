@@ -0,0 +1,110 @@
+package rewardsDataService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RewardsDiff(t *testing.T) {
+	dbName, cfg, grm, l, err := setupTestDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rds := NewRewardsDataService(grm, l, cfg, nil)
+
+	insertGoldRow := func(t *testing.T, earner string, rewardHash string, amount string, snapshot string) {
+		res := grm.Exec(`
+			insert into gold_table (earner, snapshot, reward_hash, token, amount)
+			values (?, ?, ?, '0xtoken', ?)
+		`, earner, snapshot, rewardHash, amount)
+		assert.Nil(t, res.Error)
+	}
+
+	t.Run("Should reject invalid snapshots", func(t *testing.T) {
+		var invalidErr *ErrInvalidRewardsDiff
+
+		_, err := rds.GetRewardsDiffForSnapshots(context.Background(), "2024-08", "2024-08-02")
+		assert.True(t, errors.As(err, &invalidErr))
+
+		_, err = rds.GetRewardsDiffForSnapshots(context.Background(), "2024-08-02", "2024-08-01")
+		assert.True(t, errors.As(err, &invalidErr))
+	})
+	t.Run("Should diff the cumulative rewards of two snapshots by reward type", func(t *testing.T) {
+		res := grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)})
+		assert.Nil(t, res.Error)
+
+		res = grm.Exec(`
+			insert into reward_submissions (avs, reward_hash, token, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, block_number, reward_type, transaction_hash, log_index)
+			values
+				('0xavs', '0xavs_reward', '0xtoken', 100, '0xstrategy', 0, 1, '2024-08-01', '2024-08-05', 345600, 1, 'avs', '0xtx', 0),
+				('0xavs', '0xrfae_reward', '0xtoken', 100, '0xstrategy', 0, 1, '2024-08-01', '2024-08-05', 345600, 1, 'all_earners', '0xtx', 1)
+		`)
+		assert.Nil(t, res.Error)
+		res = grm.Exec(`
+			insert into operator_directed_reward_submissions (avs, reward_hash, token, operator, operator_index, amount, strategy, strategy_index, multiplier, start_timestamp, end_timestamp, duration, block_number, transaction_hash, log_index)
+			values ('0xavs', '0xod_reward', '0xtoken', '0xoperator', 0, 100, '0xstrategy', 0, 1, '2024-08-01', '2024-08-05', 345600, 1, '0xtx', 2)
+		`)
+		assert.Nil(t, res.Error)
+
+		insertGoldRow(t, "0xearner1", "0xavs_reward", "10", "2024-08-01")
+		insertGoldRow(t, "0xearner1", "0xavs_reward", "10", "2024-08-02")
+		insertGoldRow(t, "0xearner1", "0xrfae_reward", "5", "2024-08-02")
+		insertGoldRow(t, "0xearner1", "0xod_reward", "3", "2024-08-03")
+		insertGoldRow(t, "0xearner2", "0xavs_reward", "7", "2024-08-01")
+		insertGoldRow(t, "0xearner1", "0xavs_reward", "100", "2024-08-04")
+
+		diff, err := rds.GetRewardsDiffForSnapshots(context.Background(), "2024-08-01", "2024-08-03")
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-08-01", diff.FromSnapshot)
+		assert.Equal(t, "2024-08-03", diff.ToSnapshot)
+
+		// earner2 did not earn anything after the first snapshot
+		assert.Equal(t, 1, len(diff.Rewards))
+		r := diff.Rewards[0]
+		assert.Equal(t, "0xearner1", r.Earner)
+		assert.Equal(t, "0xtoken", r.Token)
+		assert.Equal(t, "10", r.PreviousAmount)
+		assert.Equal(t, "28", r.NewAmount)
+		assert.Equal(t, "18", r.Delta)
+		assert.Equal(t, map[string]string{
+			RewardsDiffType_Avs:              "10",
+			RewardsDiffType_AllEarners:       "5",
+			RewardsDiffType_OperatorDirected: "3",
+		}, r.DeltaByRewardType)
+	})
+	t.Run("Should diff the snapshots of two distribution roots", func(t *testing.T) {
+		for i, end := range []time.Time{
+			time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 8, 3, 0, 0, 0, 0, time.UTC),
+		} {
+			res := grm.Exec(`
+				insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+				values (?, 1, ?, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', ?)
+			`, fmt.Sprintf("0xroot_%d", i), i, end, end, i)
+			assert.Nil(t, res.Error)
+		}
+
+		diff, err := rds.GetRewardsDiffForDistributionRoots(context.Background(), 0, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-08-01", diff.FromSnapshot)
+		assert.Equal(t, "2024-08-03", diff.ToSnapshot)
+		assert.Equal(t, 1, len(diff.Rewards))
+		assert.Equal(t, "18", diff.Rewards[0].Delta)
+
+		var notFoundErr *ErrDistributionRootNotFound
+		_, err = rds.GetRewardsDiffForDistributionRoots(context.Background(), 0, 5)
+		assert.True(t, errors.As(err, &notFoundErr))
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
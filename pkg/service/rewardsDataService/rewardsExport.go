@@ -0,0 +1,152 @@
+package rewardsDataService
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/rewardsExport"
+	"go.uber.org/zap"
+)
+
+// Every column is cast to a string so that rows of any dataset can be scanned the same way and amounts keep
+// their full precision.
+var rewardsExportQueries = map[rewardsExport.Dataset]string{
+	rewardsExport.Dataset_Gold: `
+		select
+			earner,
+			to_char(snapshot, 'YYYY-MM-DD') as snapshot,
+			reward_hash,
+			token,
+			cast(amount as varchar) as amount
+		from gold_table
+		where snapshot <= @snapshot
+		order by snapshot, earner, token, reward_hash
+	`,
+	rewardsExport.Dataset_StakerOperator: `
+		select
+			earner,
+			operator,
+			reward_type,
+			avs,
+			token,
+			strategy,
+			cast(multiplier as varchar) as multiplier,
+			cast(shares as varchar) as shares,
+			cast(amount as varchar) as amount,
+			reward_hash,
+			to_char(snapshot, 'YYYY-MM-DD') as snapshot
+		from staker_operator
+		where snapshot <= @snapshot
+		order by snapshot, earner, operator, reward_hash, strategy, reward_type
+	`,
+	// Claims are cumulative, so only claims against roots up to the snapshot are counted. Claims against
+	// later roots may include rewards earned after the snapshot.
+	rewardsExport.Dataset_ClaimStatus: `
+		with earned as (
+			select earner, token, sum(amount) as amount
+			from gold_table
+			where snapshot <= @snapshot
+			group by earner, token
+		),
+		claimed as (
+			select rc.earner, rc.token, sum(rc.claimed_amount) as amount
+			from rewards_claimed as rc
+			where rc.root in (
+				select lower(root)
+				from submitted_distribution_roots
+				where cast(rewards_calculation_end as date) <= @snapshot
+			)
+			group by rc.earner, rc.token
+		)
+		select
+			e.earner,
+			e.token,
+			cast(e.amount as varchar) as cumulative_earned,
+			cast(coalesce(c.amount, 0) as varchar) as cumulative_claimed,
+			cast(e.amount - coalesce(c.amount, 0) as varchar) as unclaimed
+		from earned as e
+		left join claimed as c on (c.earner = e.earner and c.token = e.token)
+		order by e.earner, e.token
+	`,
+}
+
+// ExportRewards streams a dataset for the snapshot to w in the given format, returning the number of rows written.
+//
+// Rows are read from the database with a cursor and written as they are read, so exports of any size can be
+// written without holding them in memory.
+func (rds *RewardsDataService) ExportRewards(
+	ctx context.Context,
+	snapshot string,
+	dataset rewardsExport.Dataset,
+	format rewardsExport.Format,
+	compress bool,
+	w io.Writer,
+) (uint64, error) {
+	if _, err := time.Parse(time.DateOnly, snapshot); err != nil {
+		return 0, fmt.Errorf("invalid snapshot '%s', expected YYYY-MM-DD", snapshot)
+	}
+	query, ok := rewardsExportQueries[dataset]
+	if !ok {
+		return 0, fmt.Errorf("unsupported export dataset '%s'", dataset)
+	}
+
+	rows, err := rds.db.WithContext(ctx).Raw(query, sql.Named("snapshot", snapshot)).Rows()
+	if err != nil {
+		rds.logger.Sugar().Errorw("Failed to query rewards export",
+			zap.String("snapshot", snapshot),
+			zap.String("dataset", string(dataset)),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	rw, err := rewardsExport.NewRecordWriter(w, format, columns, compress)
+	if err != nil {
+		return 0, err
+	}
+
+	record := make([]string, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range record {
+		dest[i] = &record[i]
+	}
+
+	var count uint64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		if err := rw.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		rds.logger.Sugar().Errorw("Failed to read rewards export",
+			zap.String("snapshot", snapshot),
+			zap.String("dataset", string(dataset)),
+			zap.Error(err),
+		)
+		return count, err
+	}
+	if err := rw.Close(); err != nil {
+		return count, err
+	}
+
+	rds.logger.Sugar().Infow("Exported rewards",
+		zap.String("snapshot", snapshot),
+		zap.String("dataset", string(dataset)),
+		zap.String("format", string(format)),
+		zap.Uint64("rows", count),
+	)
+	return count, nil
+}
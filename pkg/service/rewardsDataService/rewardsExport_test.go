@@ -0,0 +1,76 @@
+package rewardsDataService
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/rewardsExport"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RewardsExport(t *testing.T) {
+	dbName, cfg, grm, l, err := setupTestDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rds := NewRewardsDataService(grm, l, cfg, nil)
+
+	t.Run("Should reject an invalid snapshot", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := rds.ExportRewards(context.Background(), "2024-08", rewardsExport.Dataset_Gold, rewardsExport.Format_Csv, false, &buf)
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, buf.Len())
+	})
+	t.Run("Should export the gold table up to the snapshot", func(t *testing.T) {
+		for _, snapshot := range []string{"2024-08-01", "2024-08-02", "2024-08-03"} {
+			res := grm.Exec(`
+				insert into gold_table (earner, snapshot, reward_hash, token, amount)
+				values ('0xearner', ?, '0xreward', '0xtoken', 100)
+			`, snapshot)
+			assert.Nil(t, res.Error)
+		}
+
+		var buf bytes.Buffer
+		count, err := rds.ExportRewards(context.Background(), "2024-08-02", rewardsExport.Dataset_Gold, rewardsExport.Format_Csv, false, &buf)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(2), count)
+		assert.Equal(t, "earner,snapshot,reward_hash,token,amount\n"+
+			"0xearner,2024-08-01,0xreward,0xtoken,100\n"+
+			"0xearner,2024-08-02,0xreward,0xtoken,100\n", buf.String())
+	})
+	t.Run("Should only count claims against roots up to the snapshot", func(t *testing.T) {
+		res := grm.Create(&storage.Block{Number: 1, Hash: "0x1", BlockTime: time.Date(2024, 8, 4, 0, 0, 0, 0, time.UTC)})
+		assert.Nil(t, res.Error)
+
+		for i, root := range []string{"0xroot1", "0xroot2"} {
+			end := time.Date(2024, 8, 2+i, 0, 0, 0, 0, time.UTC)
+			res = grm.Exec(`
+				insert into submitted_distribution_roots (root, block_number, root_index, rewards_calculation_end, rewards_calculation_end_unit, activated_at, activated_at_unit, created_at_block_number, transaction_hash, log_index)
+				values (?, 1, ?, ?, 'snapshot', ?, 'timestamp', 1, '0xtx', ?)
+			`, root, i, end, end, i)
+			assert.Nil(t, res.Error)
+
+			res = grm.Exec(`
+				insert into rewards_claimed (root, earner, claimer, recipient, token, claimed_amount, transaction_hash, block_number, log_index)
+				values (?, '0xearner', '0xearner', '0xearner', '0xtoken', ?, '0xtx', 1, ?)
+			`, root, 150+i*150, i+10)
+			assert.Nil(t, res.Error)
+		}
+
+		var buf bytes.Buffer
+		count, err := rds.ExportRewards(context.Background(), "2024-08-02", rewardsExport.Dataset_ClaimStatus, rewardsExport.Format_Csv, false, &buf)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		assert.Equal(t, "earner,token,cumulative_earned,cumulative_claimed,unclaimed\n"+
+			"0xearner,0xtoken,200,150,50\n", buf.String())
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -47,6 +47,27 @@ func setup() (
 	return grm, l, cfg, sink, nil
 }
 
+// setupTestDatabase creates an empty, migrated test database for tests that insert their own fixtures
+func setupTestDatabase() (
+	string,
+	*config.Config,
+	*gorm.DB,
+	*zap.Logger,
+	error,
+) {
+	cfg := tests.GetConfig()
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbname, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		return dbname, nil, nil, nil, err
+	}
+
+	return dbname, cfg, grm, l, nil
+}
+
 // Test_RewardsDataService tests the rewards data service. It assumes that there is a full
 // database to read data from, specifically a holesky database with rewards generated.
 func Test_RewardsDataService(t *testing.T) {
@@ -2,8 +2,9 @@ package sidecar
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/Layr-Labs/sidecar/pkg/pipeline"
 	"sync/atomic"
 	"time"
 
@@ -49,8 +50,8 @@ func (s *Sidecar) ProcessNewBlocks(ctx context.Context) error {
 			return errors.New("Failed to get last indexed block")
 		}
 
-		// Get the latest safe block from the Ethereum node
-		latestTip, err := s.EthereumClient.GetLatestSafeBlock(ctx)
+		// Get the latest safe (or unsafe, if following the head) block from the Ethereum node
+		latestTip, err := s.getLatestTip(ctx)
 		if err != nil {
 			s.Logger.Sugar().Fatalw("Failed to get latest tip", zap.Error(err))
 			return errors.New("Failed to get latest tip")
@@ -78,6 +79,21 @@ func (s *Sidecar) ProcessNewBlocks(ctx context.Context) error {
 
 		for i := uint64(latestIndexedBlock + 1); i <= latestTip; i++ {
 			if err := s.Pipeline.RunForBlock(ctx, i, false); err != nil {
+				var reorgErr *pipeline.ErrReorgDetected
+				if errors.As(err, &reorgErr) {
+					ancestor, err := s.Pipeline.HandleReorg(ctx, reorgErr)
+					if err != nil {
+						s.Logger.Sugar().Errorw("Failed to handle reorg",
+							zap.Uint64("blockNumber", i),
+							zap.Error(err),
+						)
+						return err
+					}
+					s.Logger.Sugar().Infow("Rolled back to common ancestor, re-indexing canonical chain",
+						zap.Uint64("commonAncestor", ancestor),
+					)
+					break
+				}
 				s.Logger.Sugar().Errorw("Failed to run pipeline for block",
 					zap.Uint64("blockNumber", i),
 					zap.Error(err),
@@ -90,6 +106,15 @@ func (s *Sidecar) ProcessNewBlocks(ctx context.Context) error {
 	}
 }
 
+// getLatestTip returns the block number that new blocks should be indexed up to. By default this is the
+// latest safe block; when following the latest block, the unsafe head is used instead.
+func (s *Sidecar) getLatestTip(ctx context.Context) (uint64, error) {
+	if s.GlobalConfig.IndexerConfig.FollowLatestBlock {
+		return s.EthereumClient.GetBlockNumberUint64(ctx)
+	}
+	return s.EthereumClient.GetLatestSafeBlock(ctx)
+}
+
 type Progress struct {
 	StartBlock         uint64
 	LastBlockProcessed uint64
@@ -159,83 +184,73 @@ func (s *Sidecar) IndexFromCurrentToTip(ctx context.Context) error {
 		s.Logger.Sugar().Infow("No blocks indexed, starting from genesis block", zap.Uint64("genesisBlock", s.Config.GenesisBlockNumber))
 		lastIndexedBlock = int64(s.Config.GenesisBlockNumber)
 	} else {
-		// if the latest state root is behind the latest block, delete the corrupted state and set the
-		// latest block to the latest state root + 1
-		if latestStateRoot != nil && latestStateRoot.EthBlockNumber < uint64(lastIndexedBlock) {
-			s.Logger.Sugar().Infow("Latest state root is behind latest block, deleting corrupted state",
+		// Each block is committed in the same database transaction as its state root, but databases written by
+		// older versions can have blocks without a state root. Roll those back so they are re-processed.
+		if latestStateRoot.EthBlockNumber < uint64(lastIndexedBlock) {
+			s.Logger.Sugar().Infow("Latest state root is behind latest block, rolling back to the latest state root",
 				zap.Uint64("latestStateRoot", latestStateRoot.EthBlockNumber),
 				zap.Int64("lastIndexedBlock", lastIndexedBlock),
 			)
-			if err := s.StateManager.DeleteCorruptedState(latestStateRoot.EthBlockNumber+1, uint64(lastIndexedBlock)); err != nil {
-				s.Logger.Sugar().Errorw("Failed to delete corrupted state", zap.Error(err))
-				return err
-			}
-			if err := s.RewardsCalculator.DeleteCorruptedRewardsFromBlockHeight(uint64(latestStateRoot.EthBlockNumber + 1)); err != nil {
-				s.Logger.Sugar().Errorw("Failed to purge corrupted rewards", zap.Error(err))
-				return err
-			}
-			if err := s.Storage.DeleteCorruptedState(uint64(latestStateRoot.EthBlockNumber+1), uint64(lastIndexedBlock)); err != nil {
-				s.Logger.Sugar().Errorw("Failed to delete corrupted state", zap.Error(err))
+			if err := s.Pipeline.RollbackToBlock(latestStateRoot.EthBlockNumber); err != nil {
+				s.Logger.Sugar().Errorw("Failed to roll back to the latest state root", zap.Error(err))
 				return err
 			}
 			lastIndexedBlock = int64(latestStateRoot.EthBlockNumber + 1)
-			s.Logger.Sugar().Infow("Deleted corrupted state, starting from latest state root + 1",
+			s.Logger.Sugar().Infow("Rolled back to the latest state root, starting from latest state root + 1",
 				zap.Uint64("latestStateRoot", latestStateRoot.EthBlockNumber),
 				zap.Int64("lastIndexedBlock", lastIndexedBlock),
 			)
 		} else {
-			// This should tehcnically never happen, but if the latest state root is ahead of the latest block,
+			// This should technically never happen, but if the latest state root is ahead of the latest block,
 			// something is very wrong and we should fail.
 			if latestStateRoot.EthBlockNumber > uint64(lastIndexedBlock) {
 				return fmt.Errorf("Latest state root (%d) is ahead of latest stored block (%d), which should never happen, so something is very wrong", latestStateRoot.EthBlockNumber, lastIndexedBlock)
 			}
-			if latestStateRoot.EthBlockNumber == uint64(lastIndexedBlock) {
-				s.Logger.Sugar().Infow("Latest block and latest state root are in sync, starting from latest block + 1",
-					zap.Int64("latestBlock", lastIndexedBlock),
-					zap.Uint64("latestStateRootBlock", latestStateRoot.EthBlockNumber),
-				)
-			}
+			s.Logger.Sugar().Infow("Latest block and latest state root are in sync, starting from latest block + 1",
+				zap.Int64("latestBlock", lastIndexedBlock),
+				zap.Uint64("latestStateRootBlock", latestStateRoot.EthBlockNumber),
+			)
 			lastIndexedBlock++
 		}
 	}
 
 	retryCount := 0
-	var latestSafeBlockNumber uint64
+	var latestTipBlockNumber uint64
 
 	for retryCount < 3 {
-		// Get the latest safe block as a starting point
-		latestSafe, err := s.EthereumClient.GetLatestSafeBlock(ctx)
+		// Get the latest tip as a starting point. When following the latest block, the stored blocks can be ahead
+		// of the safe block, so the tip has to be the unsafe head in that case.
+		latestTip, err := s.getLatestTip(ctx)
 		if err != nil {
 			s.Logger.Sugar().Fatalw("Failed to get current tip", zap.Error(err))
 		}
-		s.Logger.Sugar().Infow("Current tip", zap.Uint64("currentTip", latestSafe))
+		s.Logger.Sugar().Infow("Current tip", zap.Uint64("currentTip", latestTip))
 
-		if latestSafe >= uint64(lastIndexedBlock) {
-			s.Logger.Sugar().Infow("Current tip is greater than latest block, starting indexing process", zap.Uint64("currentTip", latestSafe))
-			latestSafeBlockNumber = latestSafe
+		// lastIndexedBlock is the next block to index, so a tip at the last stored block means we're caught up
+		if latestTip+1 >= uint64(lastIndexedBlock) {
+			s.Logger.Sugar().Infow("Current tip is at or past the latest block, starting indexing process", zap.Uint64("currentTip", latestTip))
+			latestTipBlockNumber = latestTip
 			break
 		}
 
-		if latestSafe < uint64(lastIndexedBlock) {
-			if retryCount == 2 {
-				s.Logger.Sugar().Fatalw("Current tip is less than latest block, but retry count is 2, exiting")
-				return errors.New("Current tip is less than latest block, but retry count is 2, exiting")
-			}
-			s.Logger.Sugar().Infow("Current tip is less than latest block sleeping for 7 minutes to allow for the node to catch up")
-			time.Sleep(7 * time.Minute)
+		if retryCount == 2 {
+			s.Logger.Sugar().Fatalw("Current tip is less than latest block, but retry count is 2, exiting")
+			return errors.New("Current tip is less than latest block, but retry count is 2, exiting")
 		}
+		s.Logger.Sugar().Infow("Current tip is less than latest block sleeping for 7 minutes to allow for the node to catch up")
+		time.Sleep(7 * time.Minute)
 		retryCount++
 	}
 
 	s.Logger.Sugar().Infow("Indexing from current to tip",
-		zap.Uint64("currentTip", latestSafeBlockNumber),
+		zap.Uint64("currentTip", latestTipBlockNumber),
 		zap.Int64("lastIndexedBlock", lastIndexedBlock),
-		zap.Uint64("difference", latestSafeBlockNumber-uint64(lastIndexedBlock)),
+		zap.Uint64("blocksBehind", latestTipBlockNumber+1-uint64(lastIndexedBlock)),
 	)
 
 	// Use an atomic variable to track the current tip
 	currentTip := atomic.Uint64{}
-	currentTip.Store(latestSafeBlockNumber)
+	currentTip.Store(latestTipBlockNumber)
 
 	indexComplete := atomic.Bool{}
 	indexComplete.Store(false)
@@ -255,7 +270,7 @@ func (s *Sidecar) IndexFromCurrentToTip(ctx context.Context) error {
 				s.Logger.Sugar().Infow("Indexing complete, shutting down tip listener")
 				return
 			}
-			latestTip, err := s.EthereumClient.GetLatestSafeBlock(ctx)
+			latestTip, err := s.getLatestTip(ctx)
 			if err != nil {
 				s.Logger.Sugar().Errorw("Failed to get latest tip", zap.Error(err))
 				continue
@@ -283,23 +298,36 @@ func (s *Sidecar) IndexFromCurrentToTip(ctx context.Context) error {
 			s.Logger.Sugar().Infow("Shutting down block processor")
 			return nil
 		}
-		tip := currentTip.Load()
-
-		batchEndBlock := int64(currentBlock + 100)
-		if batchEndBlock > int64(tip) {
-			batchEndBlock = int64(tip)
-		}
-		if err := s.Pipeline.RunForBlockBatch(ctx, uint64(currentBlock), uint64(batchEndBlock), true); err != nil {
-			s.Logger.Sugar().Errorw("Failed to run pipeline for block batch",
+		nextBlock, err := s.Pipeline.RunBackfill(ctx, uint64(currentBlock), &currentTip, func(endBlock uint64) bool {
+			progress.UpdateAndPrintProgress(endBlock)
+			return !s.shouldShutdown.Load()
+		})
+		if err != nil {
+			// Blocks indexed from the unsafe head before a restart can be reorged out while backfilling
+			var reorgErr *pipeline.ErrReorgDetected
+			if errors.As(err, &reorgErr) {
+				ancestor, err := s.Pipeline.HandleReorg(ctx, reorgErr)
+				if err != nil {
+					s.Logger.Sugar().Errorw("Failed to handle reorg",
+						zap.Uint64("blockNumber", reorgErr.BlockNumber),
+						zap.Error(err),
+					)
+					return err
+				}
+				s.Logger.Sugar().Infow("Rolled back to common ancestor, re-indexing canonical chain",
+					zap.Uint64("commonAncestor", ancestor),
+				)
+				currentBlock = int64(ancestor + 1)
+				continue
+			}
+			s.Logger.Sugar().Errorw("Failed to run backfill",
 				zap.Error(err),
 				zap.Uint64("startBlock", uint64(currentBlock)),
-				zap.Int64("batchEndBlock", batchEndBlock),
+				zap.Uint64("nextBlock", nextBlock),
 			)
 			return err
 		}
-		progress.UpdateAndPrintProgress(uint64(batchEndBlock))
-
-		currentBlock = batchEndBlock + 1
+		currentBlock = int64(nextBlock)
 	}
 
 	return nil
@@ -0,0 +1,90 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/Layr-Labs/sidecar/internal/tests"
+	"github.com/Layr-Labs/sidecar/pkg/clients/ethereum"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/postgres"
+	"github.com/Layr-Labs/sidecar/pkg/storage"
+	pgStorage "github.com/Layr-Labs/sidecar/pkg/storage/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRpcServer answers eth_blockNumber with the latest block and eth_getBlockByNumber with the safe block
+func newTestRpcServer(t *testing.T, latestBlock string, safeBlock string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &ethereum.RPCRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case "eth_blockNumber":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"` + latestBlock + `"}`))
+		case "eth_getBlockByNumber":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"` + safeBlock + `","hash":"0x1","transactions":[]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_IndexFromCurrentToTip(t *testing.T) {
+	cfg := tests.GetConfig()
+	cfg.DatabaseConfig = *tests.GetDbConfigFromEnv()
+	cfg.IndexerConfig.FollowLatestBlock = true
+
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: cfg.Debug})
+
+	dbName, _, grm, err := postgres.GetTestPostgresDatabase(cfg.DatabaseConfig, cfg, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should start from the latest block when the stored blocks are ahead of the safe block", func(t *testing.T) {
+		// block 100 was indexed from the unsafe head before the restart, while the safe block is still at 90
+		res := grm.Create(&storage.Block{Number: 100, Hash: "0x64", ParentHash: "0x63", BlockTime: time.Now()})
+		assert.Nil(t, res.Error)
+
+		sm := stateManager.NewEigenStateManager(l, grm)
+		_, err := sm.WriteStateRoot(100, "0x64", "0xroot", grm)
+		assert.Nil(t, err)
+
+		rpc := newTestRpcServer(t, "0x64", "0x5a")
+		ethConfig := ethereum.DefaultNativeCallEthereumClientConfig()
+		ethConfig.BaseUrl = rpc.URL
+
+		s := NewSidecar(&SidecarConfig{GenesisBlockNumber: cfg.GetGenesisBlockNumber()}, cfg,
+			pgStorage.NewPostgresBlockStore(grm, l, cfg), nil, sm, nil, nil, nil, nil, l,
+			ethereum.NewClient(ethConfig, l),
+		)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- s.IndexFromCurrentToTip(context.Background())
+		}()
+
+		// waiting for the safe block to catch up would take at least 7 minutes
+		select {
+		case err := <-done:
+			assert.Nil(t, err)
+		case <-time.After(30 * time.Second):
+			t.Fatal("IndexFromCurrentToTip waited for the safe block to catch up with the stored blocks")
+		}
+	})
+
+	t.Cleanup(func() {
+		postgres.TeardownTestDatabase(dbName, cfg, grm, l)
+	})
+}
@@ -0,0 +1,41 @@
+package sidecar
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartPendingRewardsCalculation estimates pending rewards every rewards.pending_rewards_interval_hours until the
+// context is cancelled or the sidecar shuts down.
+func (s *Sidecar) StartPendingRewardsCalculation(ctx context.Context) {
+	intervalHours := s.GlobalConfig.Rewards.PendingRewardsIntervalHours
+	if intervalHours <= 0 {
+		s.Logger.Sugar().Infow("Pending rewards calculation is disabled")
+		return
+	}
+	interval := time.Duration(intervalHours) * time.Hour
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if s.shouldShutdown.Load() {
+			return
+		}
+		snapshotDate, err := s.RewardsCalculator.CalculatePendingRewards(ctx)
+		if err != nil {
+			s.Logger.Sugar().Warnw("Failed to calculate pending rewards, retrying at the next interval",
+				zap.String("snapshotDate", snapshotDate),
+				zap.Duration("interval", interval),
+				zap.Error(err),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
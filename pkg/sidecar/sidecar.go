@@ -79,6 +79,8 @@ func (s *Sidecar) Start(ctx context.Context) {
 		}
 	}()
 
+	go s.StartPendingRewardsCalculation(ctx)
+
 	s.StartIndexing(ctx)
 	/*
 		Main loop:
@@ -0,0 +1,99 @@
+package stateRootDivergence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	sidecarV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/sidecar"
+	"github.com/Layr-Labs/sidecar/pkg/eigenState/stateManager"
+	"github.com/Layr-Labs/sidecar/pkg/replay"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// LocalStateRoots reads state roots from the local database.
+type LocalStateRoots struct {
+	stateManager *stateManager.EigenStateManager
+}
+
+func NewLocalStateRoots(sm *stateManager.EigenStateManager) *LocalStateRoots {
+	return &LocalStateRoots{stateManager: sm}
+}
+
+func (l *LocalStateRoots) GetStateRoot(ctx context.Context, blockNumber uint64) (string, error) {
+	root, err := l.stateManager.GetStateRootForBlock(blockNumber)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return root.StateRoot, nil
+}
+
+// RemoteStateRoots reads state roots from a remote sidecar using the GetStateRoot RPC.
+type RemoteStateRoots struct {
+	client sidecarV1.RpcClient
+}
+
+func NewRemoteStateRoots(client sidecarV1.RpcClient) *RemoteStateRoots {
+	return &RemoteStateRoots{client: client}
+}
+
+func (r *RemoteStateRoots) GetStateRoot(ctx context.Context, blockNumber uint64) (string, error) {
+	res, err := r.client.GetStateRoot(ctx, &sidecarV1.GetStateRootRequest{BlockNumber: blockNumber})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return res.GetStateRoot(), nil
+}
+
+// RemoteBlockStateRoots fetches the per-model roots and slots for a block from a remote sidecar's HTTP debug endpoint.
+type RemoteBlockStateRoots struct {
+	baseUrl    string
+	httpClient *http.Client
+}
+
+func NewRemoteBlockStateRoots(baseUrl string) *RemoteBlockStateRoots {
+	return &RemoteBlockStateRoots{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (r *RemoteBlockStateRoots) GetBlockStateRoots(ctx context.Context, blockNumber uint64) (*replay.BlockStateRoots, error) {
+	url := fmt.Sprintf("%s/rpc/v1/debug/state-roots/%d/models", r.baseUrl, blockNumber)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request block state roots: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block state roots response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get block state roots; status code %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	stateRoots := &replay.BlockStateRoots{}
+	if err := json.Unmarshal(body, stateRoots); err != nil {
+		return nil, fmt.Errorf("failed to decode block state roots: %w", err)
+	}
+	return stateRoots, nil
+}
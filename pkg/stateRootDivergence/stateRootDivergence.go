@@ -0,0 +1,169 @@
+package stateRootDivergence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/sidecar/pkg/replay"
+	"go.uber.org/zap"
+)
+
+// StateRootSource returns the state root for a block. An empty string means no state root exists for the block.
+type StateRootSource interface {
+	GetStateRoot(ctx context.Context, blockNumber uint64) (string, error)
+}
+
+// DivergenceScanner compares the state roots of two sidecars block by block to find the first block where they differ.
+type DivergenceScanner struct {
+	local       StateRootSource
+	remote      StateRootSource
+	windowSize  uint64
+	concurrency int
+	logger      *zap.Logger
+}
+
+// NewDivergenceScanner creates a scanner that compares windowSize blocks at a time, with up to concurrency blocks of
+// a window being compared at the same time.
+func NewDivergenceScanner(local StateRootSource, remote StateRootSource, windowSize uint64, concurrency int, l *zap.Logger) *DivergenceScanner {
+	if windowSize == 0 {
+		windowSize = 1
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &DivergenceScanner{
+		local:       local,
+		remote:      remote,
+		windowSize:  windowSize,
+		concurrency: concurrency,
+		logger:      l,
+	}
+}
+
+// Divergence describes a block where the local and remote state roots differ.
+type Divergence struct {
+	BlockNumber     uint64
+	LocalStateRoot  string
+	RemoteStateRoot string
+}
+
+func (ds *DivergenceScanner) compare(ctx context.Context, blockNumber uint64) (*Divergence, error) {
+	localRoot, err := ds.local.GetStateRoot(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local state root for block %d: %w", blockNumber, err)
+	}
+	remoteRoot, err := ds.remote.GetStateRoot(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote state root for block %d: %w", blockNumber, err)
+	}
+	if localRoot == "" || remoteRoot == "" {
+		return nil, fmt.Errorf("block %d has not been indexed by both sidecars (local: '%s', remote: '%s')", blockNumber, localRoot, remoteRoot)
+	}
+	ds.logger.Sugar().Debugw("Compared state roots",
+		zap.Uint64("blockNumber", blockNumber),
+		zap.String("localStateRoot", localRoot),
+		zap.String("remoteStateRoot", remoteRoot),
+	)
+	if strings.EqualFold(localRoot, remoteRoot) {
+		return nil, nil
+	}
+	return &Divergence{
+		BlockNumber:     blockNumber,
+		LocalStateRoot:  localRoot,
+		RemoteStateRoot: remoteRoot,
+	}, nil
+}
+
+// FindFirstDivergence compares every block in the inclusive range and returns the first block whose local and
+// remote state roots differ, or nil if every block matches.
+//
+// Each state root only covers the changes made in its own block, so a divergence does not necessarily persist
+// into later blocks and the range can't be binary searched; every block has to be compared. To bound the time that
+// takes, the range is compared in windows of windowSize blocks whose blocks are compared concurrently, and the scan
+// stops at the first window that contains a divergence.
+func (ds *DivergenceScanner) FindFirstDivergence(ctx context.Context, startBlock uint64, endBlock uint64) (*Divergence, error) {
+	if endBlock < startBlock {
+		return nil, fmt.Errorf("invalid block range; end block %d must be greater than or equal to start block %d", endBlock, startBlock)
+	}
+	for windowStart := startBlock; windowStart <= endBlock; windowStart += ds.windowSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		windowEnd := min(windowStart+ds.windowSize-1, endBlock)
+		d, err := ds.compareWindow(ctx, windowStart, windowEnd)
+		if err != nil || d != nil {
+			return d, err
+		}
+		ds.logger.Sugar().Infow("Compared state roots",
+			zap.Uint64("windowStart", windowStart),
+			zap.Uint64("windowEnd", windowEnd),
+			zap.Uint64("blocksRemaining", endBlock-windowEnd),
+		)
+		if windowEnd == endBlock {
+			break
+		}
+	}
+	return nil, nil
+}
+
+// compareWindow compares every block of the inclusive window concurrently. The result of the earliest block that
+// diverged or failed to be compared is returned, so that a failure in a later block doesn't hide a divergence.
+func (ds *DivergenceScanner) compareWindow(ctx context.Context, windowStart uint64, windowEnd uint64) (*Divergence, error) {
+	size := windowEnd - windowStart + 1
+	divergences := make([]*Divergence, size)
+	errs := make([]error, size)
+
+	sem := make(chan struct{}, ds.concurrency)
+	wg := sync.WaitGroup{}
+	for i := uint64(0); i < size; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i uint64) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			divergences[i], errs[i] = ds.compare(ctx, windowStart+i)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := uint64(0); i < size; i++ {
+		if errs[i] != nil || divergences[i] != nil {
+			return divergences[i], errs[i]
+		}
+	}
+	return nil, nil
+}
+
+// DivergenceReport describes the first diverging block along with the models and slots that differ.
+// When the remote per-model state roots are unavailable, Models is empty and LocalModels contains every local
+// model root and slot for the block so that it can be compared by hand.
+type DivergenceReport struct {
+	BlockNumber     uint64                   `json:"blockNumber"`
+	BlockHash       string                   `json:"blockHash"`
+	LocalStateRoot  string                   `json:"localStateRoot"`
+	RemoteStateRoot string                   `json:"remoteStateRoot"`
+	Models          []*replay.ModelDiff      `json:"models"`
+	LocalModels     []*replay.ModelStateRoot `json:"localModels,omitempty"`
+}
+
+// NewDivergenceReport builds a report for a diverging block. remote may be nil if the remote sidecar does not
+// expose its per-model state roots.
+func NewDivergenceReport(d *Divergence, local *replay.BlockStateRoots, remote *replay.BlockStateRoots) *DivergenceReport {
+	report := &DivergenceReport{
+		BlockNumber:     d.BlockNumber,
+		BlockHash:       local.BlockHash,
+		LocalStateRoot:  d.LocalStateRoot,
+		RemoteStateRoot: d.RemoteStateRoot,
+		Models:          make([]*replay.ModelDiff, 0),
+	}
+	if remote == nil {
+		report.LocalModels = local.Models
+		return report
+	}
+	report.Models = replay.DiffBlockStateRoots(local, remote)
+	return report
+}
@@ -0,0 +1,107 @@
+package stateRootDivergence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Layr-Labs/sidecar/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStateRoots struct {
+	mu       sync.Mutex
+	roots    map[uint64]string
+	requests int
+}
+
+func (f *fakeStateRoots) GetStateRoot(ctx context.Context, blockNumber uint64) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+	return f.roots[blockNumber], nil
+}
+
+func newFakeStateRoots(startBlock uint64, endBlock uint64, divergeAt uint64, persist bool) (*fakeStateRoots, *fakeStateRoots) {
+	local := &fakeStateRoots{roots: make(map[uint64]string)}
+	remote := &fakeStateRoots{roots: make(map[uint64]string)}
+	for blockNumber := startBlock; blockNumber <= endBlock; blockNumber++ {
+		root := fmt.Sprintf("0x%064x", blockNumber)
+		local.roots[blockNumber] = root
+		if blockNumber == divergeAt || (persist && divergeAt != 0 && blockNumber > divergeAt) {
+			remote.roots[blockNumber] = fmt.Sprintf("0x%064x", blockNumber+1_000_000)
+		} else {
+			remote.roots[blockNumber] = root
+		}
+	}
+	return local, remote
+}
+
+func Test_DivergenceScanner(t *testing.T) {
+	l, _ := logger.NewLogger(&logger.LoggerConfig{Debug: false})
+	ctx := context.Background()
+
+	t.Run("Should find the first diverging block", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 1100, 737, true)
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 1100)
+		assert.Nil(t, err)
+		assert.NotNil(t, d)
+		assert.Equal(t, uint64(737), d.BlockNumber)
+		assert.Equal(t, fmt.Sprintf("0x%064x", 737), d.LocalStateRoot)
+		// the scan stops after the window of 730-739
+		assert.Equal(t, 640, remote.requests)
+	})
+	t.Run("Should find divergence at the start of the range", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 100, true)
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 200)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(100), d.BlockNumber)
+	})
+	t.Run("Should return nil when every block matches", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 0, true)
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 200)
+		assert.Nil(t, err)
+		assert.Nil(t, d)
+	})
+	t.Run("Should find an isolated divergence when the end block matches", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 150, false)
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 200)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(150), d.BlockNumber)
+	})
+	t.Run("Should find the earliest of several isolated divergences", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 180, false)
+		remote.roots[120] = "0xdiverged"
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 200)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(120), d.BlockNumber)
+	})
+	t.Run("Should fail when a block is missing a state root", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 150, true)
+		delete(remote.roots, 120)
+		_, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 200)
+		assert.NotNil(t, err)
+	})
+	t.Run("Should return the earliest divergence when a later block in the window fails", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 122, false)
+		delete(remote.roots, 125)
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 200)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(122), d.BlockNumber)
+	})
+	t.Run("Should compare a partial last window", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 205, 204, false)
+		d, err := NewDivergenceScanner(local, remote, 10, 4, l).FindFirstDivergence(ctx, 100, 205)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(204), d.BlockNumber)
+		assert.Equal(t, 106, remote.requests)
+	})
+	t.Run("Should compare one block at a time without a window size or concurrency", func(t *testing.T) {
+		local, remote := newFakeStateRoots(100, 200, 150, false)
+		d, err := NewDivergenceScanner(local, remote, 0, 0, l).FindFirstDivergence(ctx, 100, 200)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(150), d.BlockNumber)
+		assert.Equal(t, 51, remote.requests)
+	})
+}
@@ -33,6 +33,14 @@ func NewPostgresBlockStore(db *gorm.DB, l *zap.Logger, cfg *config.Config) *Post
 	return bs
 }
 
+func (s *PostgresBlockStore) WithTransaction(tx *gorm.DB) storage.BlockStore {
+	return &PostgresBlockStore{
+		Db:           tx,
+		Logger:       s.Logger,
+		GlobalConfig: s.GlobalConfig,
+	}
+}
+
 func (s *PostgresBlockStore) InsertBlockAtHeight(
 	blockNumber uint64,
 	hash string,
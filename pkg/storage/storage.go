@@ -2,6 +2,7 @@ package storage
 
 import (
 	"github.com/Layr-Labs/sidecar/pkg/parser"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -17,6 +18,10 @@ type BlockStore interface {
 	// Less generic functions
 	GetLatestActiveAvsOperators(blockNumber uint64, avsDirectoryAddress string) ([]*ActiveAvsOperator, error)
 
+	// WithTransaction returns a copy of the BlockStore where all reads and writes are made using the given
+	// database transaction, allowing the writes for a block to be committed or rolled back together.
+	WithTransaction(tx *gorm.DB) BlockStore
+
 	// DeleteCorruptedState deletes all the corrupted state from the database
 	//
 	// @param startBlockNumber: The block number from which to start (inclusive)
@@ -102,12 +107,15 @@ var (
 	RewardSnapshotStatusProcessing RewardSnapshotStatus = "processing"
 	RewardSnapshotStatusCompleted  RewardSnapshotStatus = "complete"
 	RewardSnapshotStatusFailed     RewardSnapshotStatus = "failed"
+	RewardSnapshotStatusCancelled  RewardSnapshotStatus = "cancelled"
 )
 
 type GeneratedRewardsSnapshots struct {
 	Id           uint64 `gorm:"type:serial"`
 	SnapshotDate string
 	Status       string
+	RetryCount   int
+	Retryable    bool `gorm:"default:true"` // False when retrying would fail the same way, e.g. when invariants were violated
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }